@@ -0,0 +1,239 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/PagerDuty/go-pagerduty"
+	"github.com/charmbracelet/log"
+	"github.com/clcollins/srepd/pkg/alert"
+	pkgconfig "github.com/clcollins/srepd/pkg/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// defaultAlertRulesFile is read when alert_rules_file is unset. A missing
+// default file is not an error; a missing explicitly-configured one is.
+const defaultAlertRulesFile = "alert_rules.yaml"
+
+var (
+	alertsTestRules   string
+	alertsTestService string
+	alertsTestTitle   string
+)
+
+var alertsCmd = &cobra.Command{
+	Use:   "alerts",
+	Short: "Inspect alert normalization",
+}
+
+// alertsTestCmd shows how an alert payload normalizes under the configured
+// alert rules, so a new rule can be developed without a live incident.
+var alertsTestCmd = &cobra.Command{
+	Use:   "test <fixture>",
+	Short: "Show how an alert payload normalizes",
+	Long: `Normalize the PagerDuty alert(s) in a JSON fixture and print the result.
+
+The fixture may be a single alert object, an array of alerts, or a map of
+incident ID to alerts (the testdata/fixtures/alerts.json format). Rules are
+read from --rules, or alert_rules_file, or ~/.config/srepd/alert_rules.yaml.`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rulesPath := alertsTestRules
+		if rulesPath == "" {
+			rulesPath = viper.GetString("alert_rules_file")
+		}
+		rules, path, err := loadAlertRulesFile(rulesPath)
+		if err != nil {
+			return err
+		}
+		return runAlertsTest(cmd.OutOrStdout(), args[0], rules, path, alertsTestService, alertsTestTitle)
+	},
+}
+
+func init() {
+	alertsTestCmd.Flags().StringVar(&alertsTestRules, "rules", "", "alert rules file (default: alert_rules_file or ~/.config/srepd/alert_rules.yaml)")
+	alertsTestCmd.Flags().StringVar(&alertsTestService, "service", "", "override the alert's PagerDuty service name")
+	alertsTestCmd.Flags().StringVar(&alertsTestTitle, "title", "", "incident title to normalize with (default: the alert summary)")
+	alertsCmd.AddCommand(alertsTestCmd)
+	rootCmd.AddCommand(alertsCmd)
+}
+
+// loadAlertRulesFile loads the rules at path, or the default rules file when
+// path is empty. It returns the path actually used ("" when no file applies).
+func loadAlertRulesFile(path string) (*alert.RuleSet, string, error) {
+	path, err := userConfigFile(path, defaultAlertRulesFile)
+	if path == "" || err != nil {
+		return nil, "", err
+	}
+
+	rules, err := alert.LoadRules(path)
+	if err != nil {
+		return nil, "", err
+	}
+	return rules, path, nil
+}
+
+// userConfigFile resolves a configured file path, expanding "~/", or the
+// default file in the srepd config directory when path is empty. It
+// returns "" when the default file does not exist.
+func userConfigFile(path, defaultName string) (string, error) {
+	explicit := path != ""
+	if !explicit {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", nil
+		}
+		path = filepath.Join(home, pkgconfig.CfgFileDir, defaultName)
+	} else if strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("expanding %s: %w", path, err)
+		}
+		path = filepath.Join(home, path[2:])
+	}
+
+	if _, err := os.Stat(path); !explicit && errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	return path, nil
+}
+
+// installAlertRules loads the configured alert rules for the TUI. A broken
+// rules file must not keep srepd from starting, so errors are logged and
+// normalization falls back to the built-in parsers.
+func installAlertRules() {
+	rules, path, err := loadAlertRulesFile(viper.GetString("alert_rules_file"))
+	if err != nil {
+		log.Warn("Alert rules not loaded, using built-in parsers only", "error", err)
+		return
+	}
+	if rules == nil {
+		return
+	}
+	alert.SetRules(rules)
+	log.Info("Alert rules loaded", "path", path, "rules", len(rules.Rules))
+}
+
+// readAlertFixture decodes a single alert, an array of alerts, or a map of
+// incident ID to alerts. Map entries are returned in incident ID order.
+func readAlertFixture(data []byte) ([]pagerduty.IncidentAlert, error) {
+	trimmed := strings.TrimSpace(string(data))
+	switch {
+	case strings.HasPrefix(trimmed, "["):
+		var alerts []pagerduty.IncidentAlert
+		if err := json.Unmarshal(data, &alerts); err != nil {
+			return nil, fmt.Errorf("parsing alert array: %w", err)
+		}
+		return alerts, nil
+	case strings.HasPrefix(trimmed, "{"):
+		var byIncident map[string][]pagerduty.IncidentAlert
+		if err := json.Unmarshal(data, &byIncident); err == nil {
+			ids := make([]string, 0, len(byIncident))
+			for id := range byIncident {
+				ids = append(ids, id)
+			}
+			sort.Strings(ids)
+			var alerts []pagerduty.IncidentAlert
+			for _, id := range ids {
+				alerts = append(alerts, byIncident[id]...)
+			}
+			return alerts, nil
+		}
+		var single pagerduty.IncidentAlert
+		if err := json.Unmarshal(data, &single); err != nil {
+			return nil, fmt.Errorf("parsing alert object: %w", err)
+		}
+		return []pagerduty.IncidentAlert{single}, nil
+	default:
+		return nil, fmt.Errorf("fixture is not a JSON object or array")
+	}
+}
+
+// runAlertsTest normalizes every alert in the fixture and writes a
+// field-per-line report to w. Non-empty service and title override the
+// values taken from each alert.
+func runAlertsTest(w io.Writer, fixturePath string, rules *alert.RuleSet, rulesPath, serviceOverride, titleOverride string) error {
+	data, err := os.ReadFile(fixturePath)
+	if err != nil {
+		return fmt.Errorf("reading fixture: %w", err)
+	}
+	alerts, err := readAlertFixture(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", fixturePath, err)
+	}
+	if len(alerts) == 0 {
+		return fmt.Errorf("%s: no alerts found", fixturePath)
+	}
+
+	if rulesPath != "" {
+		fmt.Fprintf(w, "Rules: %s (%d)\n", rulesPath, len(rules.Rules))
+	} else {
+		fmt.Fprintln(w, "Rules: none (built-in parsers only)")
+	}
+
+	for i, a := range alerts {
+		service := a.Service.Summary
+		if serviceOverride != "" {
+			service = serviceOverride
+		}
+		title := a.Summary
+		if titleOverride != "" {
+			title = titleOverride
+		}
+
+		source := "built-in"
+		if r := rules.Match(service); r != nil {
+			source = "rule " + r.Name
+		}
+
+		n := rules.NormalizeAlert(service, title, a)
+		fmt.Fprintf(w, "\nAlert %d/%d %s (%s)\n", i+1, len(alerts), a.ID, source)
+		writeNormalizedAlert(w, n)
+	}
+	return nil
+}
+
+// writeNormalizedAlert prints the non-empty fields of n as aligned rows.
+func writeNormalizedAlert(w io.Writer, n alert.NormalizedAlert) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	rows := []struct{ name, value string }{
+		{"AlertType", n.AlertType},
+		{"AlertName", n.AlertName},
+		{"ClusterID", n.ClusterID},
+		{"Severity", n.Severity},
+		{"Title", n.Title},
+		{"Status", n.Status},
+		{"CreatedAt", n.CreatedAt},
+		{"IncidentID", n.IncidentID},
+		{"ServiceName", n.ServiceName},
+		{"SOPLink", n.SOPLink},
+		{"OCMLink", n.OCMLink},
+		{"DashboardLink", n.DashboardLink},
+		{"ClusterName", n.ClusterName},
+		{"Region", n.Region},
+		{"Namespace", n.Namespace},
+		{"Description", n.Description},
+		{"Condition", n.Condition},
+		{"Reason", n.Reason},
+		{"Tags", strings.Join(n.Tags, ", ")},
+	}
+	if n.FiringCount > 0 {
+		rows = append(rows, struct{ name, value string }{"FiringCount", fmt.Sprint(n.FiringCount)})
+	}
+	for _, r := range rows {
+		if r.value == "" {
+			continue
+		}
+		fmt.Fprintf(tw, "  %s\t%s\n", r.name, r.value)
+	}
+	_ = tw.Flush()
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/clcollins/srepd/pkg/alert"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testAlertFixture = `{
+  "id": "PALERT1",
+  "summary": "(critical) DiskFull on abc-123",
+  "status": "triggered",
+  "service": {"summary": "acme-prod"},
+  "body": {"details": {"firing": "Labels:\n - namespace = acme-storage\n"}}
+}`
+
+func TestReadAlertFixture_Formats(t *testing.T) {
+	single, err := readAlertFixture([]byte(`{"id": "A1", "status": "triggered"}`))
+	require.NoError(t, err)
+	require.Len(t, single, 1)
+	assert.Equal(t, "A1", single[0].ID)
+
+	list, err := readAlertFixture([]byte(`[{"id": "A1"}, {"id": "A2"}]`))
+	require.NoError(t, err)
+	assert.Len(t, list, 2)
+
+	byIncident, err := readAlertFixture([]byte(`{"PINC2": [{"id": "A2"}], "PINC1": [{"id": "A1"}]}`))
+	require.NoError(t, err)
+	require.Len(t, byIncident, 2)
+	assert.Equal(t, "A1", byIncident[0].ID, "incident map is flattened in ID order")
+
+	_, err = readAlertFixture([]byte(`nope`))
+	assert.Error(t, err)
+}
+
+func TestReadAlertFixture_DevFixtures(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("..", "testdata", "fixtures", "alerts.json"))
+	require.NoError(t, err)
+
+	alerts, err := readAlertFixture(data)
+	require.NoError(t, err)
+	assert.NotEmpty(t, alerts, "the dev fixture format must be accepted")
+}
+
+func TestRunAlertsTest_WithRule(t *testing.T) {
+	dir := t.TempDir()
+	fixture := filepath.Join(dir, "alert.json")
+	require.NoError(t, os.WriteFile(fixture, []byte(testAlertFixture), 0600))
+
+	rules, err := alert.ParseRules([]byte(`
+rules:
+  - name: acme
+    service: {prefix: acme-}
+    title_pattern: '^\((?P<severity>\w+)\) (?P<alert_name>\S+) on (?P<cluster_id>\S+)$'
+    fields:
+      namespace: [firing:namespace]
+`))
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	require.NoError(t, runAlertsTest(&out, fixture, rules, "rules.yaml", "", ""))
+
+	got := out.String()
+	assert.Contains(t, got, "Rules: rules.yaml (1)")
+	assert.Contains(t, got, "PALERT1 (rule acme)")
+	assert.Regexp(t, `AlertType\s+acme`, got)
+	assert.Regexp(t, `AlertName\s+DiskFull`, got)
+	assert.Regexp(t, `ClusterID\s+abc-123`, got)
+	assert.Regexp(t, `Namespace\s+acme-storage`, got)
+}
+
+func TestRunAlertsTest_BuiltinOnlyWithOverrides(t *testing.T) {
+	dir := t.TempDir()
+	fixture := filepath.Join(dir, "alert.json")
+	require.NoError(t, os.WriteFile(fixture, []byte(testAlertFixture), 0600))
+
+	var out bytes.Buffer
+	require.NoError(t, runAlertsTest(&out, fixture, nil, "", "app-sre-alertmanager", "[FIRING:1] Overridden"))
+
+	got := out.String()
+	assert.Contains(t, got, "built-in parsers only")
+	assert.Contains(t, got, "(built-in)")
+	assert.Regexp(t, `AlertType\s+appsre`, got)
+	assert.Regexp(t, `AlertName\s+Overridden`, got)
+}
+
+func TestRunAlertsTest_Errors(t *testing.T) {
+	dir := t.TempDir()
+	var out bytes.Buffer
+
+	assert.Error(t, runAlertsTest(&out, filepath.Join(dir, "missing.json"), nil, "", "", ""))
+
+	empty := filepath.Join(dir, "empty.json")
+	require.NoError(t, os.WriteFile(empty, []byte(`[]`), 0600))
+	err := runAlertsTest(&out, empty, nil, "", "", "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no alerts")
+}
+
+func TestLoadAlertRulesFile(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+
+	rules, path, err := loadAlertRulesFile("")
+	assert.NoError(t, err, "a missing default rules file is not an error")
+	assert.Nil(t, rules)
+	assert.Empty(t, path)
+
+	_, _, err = loadAlertRulesFile(filepath.Join(dir, "missing.yaml"))
+	assert.Error(t, err, "a missing explicitly configured file is an error")
+
+	cfgDir := filepath.Join(dir, ".config", "srepd")
+	require.NoError(t, os.MkdirAll(cfgDir, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(cfgDir, defaultAlertRulesFile),
+		[]byte("rules:\n  - name: x\n    service: {prefix: x-}\n"), 0600))
+
+	rules, path, err = loadAlertRulesFile("")
+	require.NoError(t, err)
+	assert.Len(t, rules.Rules, 1)
+	assert.Equal(t, filepath.Join(cfgDir, defaultAlertRulesFile), path)
+
+	rules, _, err = loadAlertRulesFile("~/.config/srepd/" + defaultAlertRulesFile)
+	require.NoError(t, err, "~/ is expanded")
+	assert.Len(t, rules.Rules, 1)
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	pkgconfig "github.com/clcollins/srepd/pkg/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// reviewConfirmFunc asks one yes/no question. Injectable for tests.
+type reviewConfirmFunc func(title, description, affirmative, negative string) (bool, error)
+
+var automergeCmd = &cobra.Command{
+	Use:   "automerge",
+	Short: "Manage auto-merge rules",
+}
+
+// automergeReviewCmd is the gate for auto_merge_rules: the rules merge
+// PagerDuty incidents with nobody confirming, so like preset-supplied
+// commands they must be shown to and affirmed by the user before they act.
+var automergeReviewCmd = &cobra.Command{
+	Use:   "review",
+	Short: "Review and approve the configured auto-merge rules",
+	Long: `Show the auto_merge_rules from the srepd config and record approval.
+
+Until the current rule set has been reviewed, auto-merge runs as a dry run
+and only logs what it would merge. Any edit to the rules requires a new
+review.`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rules, err := pkgconfig.ParseAutoMergeRules(viper.Get("auto_merge_rules"))
+		if err != nil {
+			return err
+		}
+		configFile, err := resolveConfigFilePath(os.UserHomeDir)
+		if err != nil {
+			return err
+		}
+		return runAutoMergeReview(cmd.OutOrStdout(), rules, configFile, viper.GetString("auto_merge_rules_reviewed"), huhConfirm)
+	},
+}
+
+func init() {
+	automergeCmd.AddCommand(automergeReviewCmd)
+	rootCmd.AddCommand(automergeCmd)
+}
+
+func huhConfirm(title, description, affirmative, negative string) (bool, error) {
+	var ok bool
+	err := huh.NewConfirm().
+		Title(title).
+		Description(description).
+		Affirmative(affirmative).
+		Negative(negative).
+		Value(&ok).
+		Run()
+	return ok, err
+}
+
+// runAutoMergeReview shows the rules and, after the same two confirmations
+// the config wizard asks for preset commands (rules are safe, source is
+// trusted), records the rule set's digest in configFile.
+func runAutoMergeReview(w io.Writer, rules []pkgconfig.AutoMergeRule, configFile, reviewed string, confirm reviewConfirmFunc) error {
+	if len(rules) == 0 {
+		return errors.New("no auto_merge_rules configured")
+	}
+	digest := pkgconfig.AutoMergeDigest(rules)
+	if digest == reviewed {
+		fmt.Fprintf(w, "The %d configured auto-merge rule(s) are already reviewed.\n", len(rules))
+		return nil
+	}
+
+	warn := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("196"))
+	fmt.Fprintln(w, warn.Render("⚠ These rules MERGE PagerDuty incidents automatically, with no confirmation:"))
+	fmt.Fprintln(w)
+	for _, r := range rules {
+		fmt.Fprintf(w, "  %s\n", r.Describe())
+	}
+	fmt.Fprintln(w)
+
+	safe, err := confirm(
+		warn.Render("Validate every rule only matches duplicates that are always safe to merge"),
+		"Answering No leaves auto-merge in dry-run mode.",
+		"They are safe", "No — keep dry run")
+	if err != nil {
+		return err
+	}
+	if !safe {
+		fmt.Fprintln(w, "Rules not approved; auto-merge stays in dry-run mode.")
+		return nil
+	}
+	trusted, err := confirm(
+		warn.Render("Are you sure you trust the source?"),
+		fmt.Sprintf("%s\n  %s\n\nOnly approve rules you or your team wrote.", warn.Render("These rules were read from:"), configFile),
+		"I trust this source", "No — keep dry run")
+	if err != nil {
+		return err
+	}
+	if !trusted {
+		fmt.Fprintln(w, "Rules not approved; auto-merge stays in dry-run mode.")
+		return nil
+	}
+
+	data, err := os.ReadFile(configFile)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	updated, err := pkgconfig.UpsertScalarInConfig(data, "auto_merge_rules_reviewed", digest)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(configFile, updated, 0600); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	fmt.Fprintf(w, "Approved %d auto-merge rule(s). They take effect the next time srepd starts", len(rules))
+	if viper.GetBool("auto_merge_dry_run") {
+		fmt.Fprint(w, " (auto_merge_dry_run is still set)")
+	}
+	fmt.Fprintln(w, ".")
+	return nil
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	pkgconfig "github.com/clcollins/srepd/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testAutoMergeRules = []pkgconfig.AutoMergeRule{
+	{Name: "co-down", AlertName: "ClusterOperatorDown", Within: time.Hour},
+}
+
+func answers(replies ...bool) (reviewConfirmFunc, *int) {
+	asked := 0
+	return func(_, _, _, _ string) (bool, error) {
+		r := replies[asked]
+		asked++
+		return r, nil
+	}, &asked
+}
+
+func writeTestConfig(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "srepd.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("token: abc\n"), 0600))
+	return path
+}
+
+func TestRunAutoMergeReview_Approve(t *testing.T) {
+	cfg := writeTestConfig(t)
+	confirm, asked := answers(true, true)
+	var out bytes.Buffer
+
+	require.NoError(t, runAutoMergeReview(&out, testAutoMergeRules, cfg, "", confirm))
+
+	assert.Equal(t, 2, *asked, "rules safe + source trusted")
+	assert.Contains(t, out.String(), "co-down: merge repeat ClusterOperatorDown")
+	assert.Contains(t, out.String(), "Approved 1 auto-merge rule(s)")
+	data, err := os.ReadFile(cfg)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "auto_merge_rules_reviewed: "+pkgconfig.AutoMergeDigest(testAutoMergeRules))
+	assert.Contains(t, string(data), "token: abc", "other keys are preserved")
+}
+
+func TestRunAutoMergeReview_Declined(t *testing.T) {
+	for name, replies := range map[string][]bool{
+		"rules not safe":     {false},
+		"source not trusted": {true, false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			cfg := writeTestConfig(t)
+			confirm, asked := answers(replies...)
+			var out bytes.Buffer
+
+			require.NoError(t, runAutoMergeReview(&out, testAutoMergeRules, cfg, "", confirm))
+
+			assert.Equal(t, len(replies), *asked)
+			assert.Contains(t, out.String(), "dry-run")
+			data, err := os.ReadFile(cfg)
+			require.NoError(t, err)
+			assert.NotContains(t, string(data), "auto_merge_rules_reviewed")
+		})
+	}
+}
+
+func TestRunAutoMergeReview_AlreadyReviewed(t *testing.T) {
+	confirm, asked := answers()
+	var out bytes.Buffer
+
+	require.NoError(t, runAutoMergeReview(&out, testAutoMergeRules, "unused", pkgconfig.AutoMergeDigest(testAutoMergeRules), confirm))
+	assert.Zero(t, *asked)
+	assert.Contains(t, out.String(), "already reviewed")
+}
+
+func TestRunAutoMergeReview_Errors(t *testing.T) {
+	var out bytes.Buffer
+	err := runAutoMergeReview(&out, nil, "unused", "", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no auto_merge_rules")
+
+	failing := func(_, _, _, _ string) (bool, error) { return false, errors.New("no tty") }
+	assert.Error(t, runAutoMergeReview(&out, testAutoMergeRules, "unused", "", failing))
+}
@@ -40,6 +40,10 @@ var safeToLogConfigKeys = map[string]bool{
 	"terminal":                           true,
 	"cluster_login_command":              true,
 	"toolbox_mode":                       true,
+	"host_exec_command":                  true,
+	"ssh_bastion":                        true,
+	"update_channel":                     true,
+	"update_skip_version":                true,
 	"rosa_boundary_command":              true,
 	"ignoredusers":                       true,
 	"colors":                             true,
@@ -51,6 +55,17 @@ var safeToLogConfigKeys = map[string]bool{
 	"agent_cli_command":                  true,
 	"reescalate_level":                   true,
 	"stream_responses":                   true,
+	"alert_rules_file":                   true,
+	"service_log_templates_file":         true,
+	"service_log_sent_tag":               true,
+	"ocm_environments":                   true,
+	"ocm_cache":                          true,
+	"ocm_cache_ttl":                      true,
+	"auto_merge_rules":                   true,
+	"auto_merge_dry_run":                 true,
+	"auto_merge_rules_reviewed":          true,
+	"custom_actions":                     true,
+	"embedded_terminal":                  true,
 }
 
 // maskConfigValue returns value if key is on the safe-to-log allowlist, otherwise
@@ -108,7 +123,7 @@ func runConfigWizard() error {
 // launchTUIWithConfig launches the TUI with configMode enabled, so it
 // enters the inline config wizard instead of normal incident view.
 func launchTUIWithConfig() {
-	l, err := launcher.NewClusterLauncher(viper.GetString("terminal"), viper.GetString("cluster_login_command"), viper.GetString("toolbox_mode"))
+	l, err := launcher.NewClusterLauncher(viper.GetString("terminal"), viper.GetString("cluster_login_command"), resolveHostExec())
 	if err != nil {
 		fmt.Println(err)
 		log.Fatal(err)
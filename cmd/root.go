@@ -40,6 +40,7 @@ import (
 	"github.com/clcollins/srepd/pkg/ai"
 	"github.com/clcollins/srepd/pkg/backplane"
 	pkgconfig "github.com/clcollins/srepd/pkg/config"
+	"github.com/clcollins/srepd/pkg/container"
 	"github.com/clcollins/srepd/pkg/launcher"
 	"github.com/clcollins/srepd/pkg/ocm"
 	"github.com/clcollins/srepd/pkg/pd"
@@ -222,12 +223,30 @@ func autoCommentOldPolicies(configFile string) (bool, error) {
 	return true, nil
 }
 
+// resolveHostExec returns how commands reach the host when srepd runs in a
+// container, from toolbox_mode and host_exec_command.
+func resolveHostExec() container.HostExec {
+	h, err := container.Resolve(viper.GetString("toolbox_mode"), viper.GetString("host_exec_command"))
+	if err != nil {
+		log.Warn("toolbox_mode is custom but host_exec_command is unusable; commands run directly", "error", err)
+	}
+	return h
+}
+
 func launchTUI() {
-	l, err := launcher.NewClusterLauncher(viper.GetString("terminal"), viper.GetString("cluster_login_command"), viper.GetString("toolbox_mode"))
+	hostExec := resolveHostExec()
+	l, err := launcher.NewClusterLauncher(viper.GetString("terminal"), viper.GetString("cluster_login_command"), hostExec)
 	if err != nil {
 		fmt.Println(err)
 		log.Fatal(err)
 	}
+	if bastion := viper.GetString("ssh_bastion"); bastion != "" {
+		l.UseBastion(bastion)
+		log.Info("Cluster logins run on the SSH bastion", "ssh_bastion", bastion)
+	}
+
+	installAlertRules()
+	installServiceLogTemplates()
 
 	ocmClient, asyncOCMClient, ocmAuthPending, cfg := setupOCM()
 
@@ -266,7 +285,7 @@ func launchTUI() {
 		bpConfig = bpCfg
 		if ocmClient != nil {
 			if bpCfg.URL == "" {
-				resolvedURL, urlErr := ocmClient.GetBackplaneURL()
+				resolvedURL, urlErr := ocmClient.GetBackplaneURL("")
 				if urlErr != nil {
 					log.Warn("Backplane URL resolution from OCM failed", "error", urlErr)
 				} else {
@@ -275,7 +294,7 @@ func launchTUI() {
 				}
 			}
 			if bpCfg.URL != "" {
-				bpClient = backplane.NewClient(bpCfg, ocmClient.GetAccessToken)
+				bpClient = tui.NewBackplaneClient(bpCfg, ocmClient)
 				log.Info("Backplane client initialized")
 			} else {
 				log.Warn("Backplane client not created: no URL available")
@@ -288,7 +307,7 @@ func launchTUI() {
 	var rbl launcher.ClusterLauncher
 	rbCmd := viper.GetString("rosa_boundary_command")
 	if rbCmd != "" {
-		rbl, err = launcher.NewClusterLauncher(viper.GetString("terminal"), rbCmd, viper.GetString("toolbox_mode"))
+		rbl, err = launcher.NewClusterLauncher(viper.GetString("terminal"), rbCmd, hostExec)
 		if err != nil {
 			log.Warn("rosa-boundary launcher disabled", "error", err)
 			rbl = launcher.ClusterLauncher{}
@@ -335,8 +354,9 @@ func launchTUI() {
 				p.Send(tui.OCMClientReadyMsg{Err: connErr})
 				return
 			}
-			asyncOCMClient = client
-			p.Send(tui.OCMClientReadyMsg{Client: client})
+			connected := tui.WrapOCMClient(client)
+			asyncOCMClient = connected
+			p.Send(tui.OCMClientReadyMsg{Client: connected})
 		}()
 	}
 
@@ -367,9 +387,9 @@ func launchTUI() {
 // complete that auth. Config-wizard mode deliberately skips this (OB-6): a
 // brand-new user must not see browser-auth prompts or OCM warnings before
 // they have even saved a PagerDuty token.
-func setupOCM() (ocm.OCMClient, *ocm.Client, bool, *ocmconfig.Config) {
+func setupOCM() (ocm.OCMClient, ocm.OCMClient, bool, *ocmconfig.Config) {
 	var ocmClient ocm.OCMClient
-	var concreteClient *ocm.Client
+	var concreteClient ocm.OCMClient
 	var authPending bool
 
 	cfg, armed, checkErr := ocm.CheckTokens()
@@ -380,8 +400,8 @@ func setupOCM() (ocm.OCMClient, *ocm.Client, bool, *ocmconfig.Config) {
 		if connErr != nil {
 			log.Warn("OCM connection failed", "error", connErr)
 		} else {
-			ocmClient = client
-			concreteClient = client
+			ocmClient = tui.WrapOCMClient(client)
+			concreteClient = ocmClient
 			log.Info("OCM connected")
 		}
 	} else {
@@ -496,6 +516,9 @@ func runDevMode() {
 
 	log.Info("Dev mode: loading fixtures", "dir", fixturesDir)
 
+	installAlertRules()
+	installServiceLogTemplates()
+
 	config, err := pd.NewDevConfig(fixturesDir)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to load dev fixtures: %v\n", err)
@@ -665,6 +688,8 @@ func configureLogging() {
 	// Check if user wants to log to journal (default: true)
 	viper.SetDefault("log_to_journal", true)
 	viper.SetDefault("emoji", true)
+	viper.SetDefault("service_log_sent_tag", true)
+	viper.SetDefault("ocm_cache", true)
 	viper.SetDefault("agent_system_prompt", pkgconfig.DefaultOptionalKeys["agent_system_prompt"])
 	viper.SetDefault("watcher_system_prompt", pkgconfig.DefaultOptionalKeys["watcher_system_prompt"])
 	logToJournal := viper.GetBool("log_to_journal")
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"github.com/charmbracelet/log"
+	"github.com/clcollins/srepd/pkg/servicelog"
+	"github.com/spf13/viper"
+)
+
+// defaultServiceLogTemplatesFile is read when service_log_templates_file is
+// unset. A missing default file is not an error; a missing explicitly
+// configured one is.
+const defaultServiceLogTemplatesFile = "servicelog_templates.yaml"
+
+// loadServiceLogTemplatesFile loads the templates at path, or the default
+// templates file when path is empty. It returns the path actually used (""
+// when no file applies).
+func loadServiceLogTemplatesFile(path string) (*servicelog.TemplateSet, string, error) {
+	path, err := userConfigFile(path, defaultServiceLogTemplatesFile)
+	if path == "" || err != nil {
+		return nil, "", err
+	}
+
+	templates, err := servicelog.LoadTemplates(path)
+	if err != nil {
+		return nil, "", err
+	}
+	return templates, path, nil
+}
+
+// installServiceLogTemplates loads the configured service log templates for
+// the TUI. A broken file must not keep srepd from starting, so errors are
+// logged and only the built-in templates are offered.
+func installServiceLogTemplates() {
+	templates, path, err := loadServiceLogTemplatesFile(viper.GetString("service_log_templates_file"))
+	if err != nil {
+		log.Warn("Service log templates not loaded, using built-in templates only", "error", err)
+		return
+	}
+	if templates == nil {
+		return
+	}
+	servicelog.SetTemplates(templates)
+	log.Info("Service log templates loaded", "path", path, "templates", len(templates.Templates), "limited_support", len(templates.LimitedSupport))
+}
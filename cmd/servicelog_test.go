@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadServiceLogTemplatesFile(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+
+	templates, path, err := loadServiceLogTemplatesFile("")
+	assert.NoError(t, err, "a missing default templates file is not an error")
+	assert.Nil(t, templates)
+	assert.Empty(t, path)
+
+	_, _, err = loadServiceLogTemplatesFile(filepath.Join(dir, "missing.yaml"))
+	assert.Error(t, err, "a missing explicitly configured file is an error")
+
+	cfgDir := filepath.Join(dir, ".config", "srepd")
+	require.NoError(t, os.MkdirAll(cfgDir, 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(cfgDir, defaultServiceLogTemplatesFile),
+		[]byte("templates:\n  - name: x\n    summary: X\n"), 0600))
+
+	templates, path, err = loadServiceLogTemplatesFile("")
+	require.NoError(t, err)
+	assert.Len(t, templates.Templates, 1)
+	assert.Equal(t, filepath.Join(cfgDir, defaultServiceLogTemplatesFile), path)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bad.yaml"), []byte("templates:\n  - name: x\n"), 0600))
+	_, _, err = loadServiceLogTemplatesFile("~/bad.yaml")
+	assert.ErrorContains(t, err, "summary is required")
+}
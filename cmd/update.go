@@ -6,6 +6,7 @@ import (
 
 	"github.com/clcollins/srepd/pkg/tui"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 var updateCmd = &cobra.Command{
@@ -13,11 +14,35 @@ var updateCmd = &cobra.Command{
 	Short: "Update srepd to the latest release",
 	Long: `Update srepd to the latest GitHub release in place.
 
-Downloads the latest release binary for the current OS and architecture
-from https://github.com/openshift-online/srepd/releases, extracts it, and
-replaces the current binary. Restart srepd after updating.`,
+Lists the releases newer than the running one on the update_channel
+(stable, or prerelease to include pre-releases) with their release notes
+and asks before installing the newest. --version installs a specific
+release instead, older ones included.
+
+Downloads the release binary for the current OS and architecture
+from https://github.com/openshift-online/srepd/releases, checks it against
+the release's checksums.txt (and its signature, when the build embeds a
+public key), extracts it, and replaces the current binary. The previous
+binary is kept next to it; --rollback puts it back. Restart srepd after
+updating.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		if err := tui.RunSelfUpdate(); err != nil {
+		if rollback, _ := cmd.Flags().GetBool("rollback"); rollback {
+			if err := tui.RunRollback(os.Stdout); err != nil {
+				fmt.Fprintf(os.Stderr, "Rollback failed: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+		version, _ := cmd.Flags().GetString("version")
+		yes, _ := cmd.Flags().GetBool("yes")
+		opts := tui.UpdateOptions{
+			Version: version,
+			Channel: viper.GetString("update_channel"),
+			Yes:     yes,
+			In:      os.Stdin,
+			Out:     os.Stdout,
+		}
+		if err := tui.RunSelfUpdate(opts); err != nil {
 			fmt.Fprintf(os.Stderr, "Update failed: %v\n", err)
 			os.Exit(1)
 		}
@@ -25,5 +50,8 @@ replaces the current binary. Restart srepd after updating.`,
 }
 
 func init() {
+	updateCmd.Flags().Bool("rollback", false, "Restore the binary the last update replaced")
+	updateCmd.Flags().String("version", "", "Install this release (e.g. v1.4.2) instead of the newest")
+	updateCmd.Flags().BoolP("yes", "y", false, "Install without asking")
 	rootCmd.AddCommand(updateCmd)
 }
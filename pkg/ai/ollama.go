@@ -50,11 +50,25 @@ type ollamaChatRequest struct {
 	Model    string              `json:"model"`
 	Messages []ollamaChatMessage `json:"messages"`
 	Stream   bool                `json:"stream"`
+	Tools    []functionTool      `json:"tools,omitempty"`
 }
 
 type ollamaChatMessage struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
+	Role      string           `json:"role"`
+	Content   string           `json:"content"`
+	ToolCalls []ollamaToolCall `json:"tool_calls,omitempty"`
+	ToolName  string           `json:"tool_name,omitempty"`
+}
+
+// ollamaToolCall is a function call in an assistant message. Unlike
+// OpenAI's, the arguments are a JSON object and there is no call ID.
+type ollamaToolCall struct {
+	Function ollamaFunctionCall `json:"function"`
+}
+
+type ollamaFunctionCall struct {
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments"`
 }
 
 type ollamaChatResponse struct {
@@ -102,6 +116,66 @@ func (p *ollamaProvider) Query(ctx context.Context, systemPrompt string, userPro
 	return chatResp.Message.Content, nil
 }
 
+// ToolTurn sends the conversation with tools as Ollama tool definitions
+// and returns the reply, with its tool calls.
+func (p *ollamaProvider) ToolTurn(ctx context.Context, systemPrompt string, messages []ToolMessage, tools []ToolSpec) (ToolMessage, error) {
+	log.Debug("ollama.ToolTurn", "endpoint", p.endpoint, "model", p.model, "messages", len(messages))
+	ctx, cancel := ensureTimeout(ctx, p.requestTimeout)
+	defer cancel()
+
+	var wire []ollamaChatMessage
+	if systemPrompt != "" {
+		wire = append(wire, ollamaChatMessage{Role: "system", Content: systemPrompt})
+	}
+	for _, m := range messages {
+		msg := ollamaChatMessage{Role: m.Role, Content: m.Content, ToolName: m.ToolName}
+		for _, c := range m.ToolCalls {
+			msg.ToolCalls = append(msg.ToolCalls, ollamaToolCall{
+				Function: ollamaFunctionCall{Name: c.Name, Arguments: toolInput(c.Input)},
+			})
+		}
+		wire = append(wire, msg)
+	}
+
+	body, err := json.Marshal(ollamaChatRequest{
+		Model:    p.model,
+		Messages: wire,
+		Stream:   false,
+		Tools:    functionTools(tools),
+	})
+	if err != nil {
+		return ToolMessage{}, fmt.Errorf("ollama: marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint+"/api/chat", bytes.NewReader(body))
+	if err != nil {
+		return ToolMessage{}, fmt.Errorf("ollama: create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return ToolMessage{}, fmt.Errorf("ollama: request failed: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		// Status code only — the body may echo request headers (see openai provider).
+		return ToolMessage{}, fmt.Errorf("ollama: server returned %d", resp.StatusCode)
+	}
+
+	var chatResp ollamaChatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&chatResp); err != nil {
+		return ToolMessage{}, fmt.Errorf("ollama: decode response: %w", err)
+	}
+
+	out := ToolMessage{Role: "assistant", Content: chatResp.Message.Content}
+	for _, c := range chatResp.Message.ToolCalls {
+		out.ToolCalls = append(out.ToolCalls, ToolCall{Name: c.Function.Name, Input: toolInput(c.Function.Arguments)})
+	}
+	return out, nil
+}
+
 func (p *ollamaProvider) StreamQuery(ctx context.Context, systemPrompt string, userPrompt string, ch chan<- string) error {
 	defer close(ch)
 	log.Debug("ollama.StreamQuery", "endpoint", p.endpoint, "model", p.model)
@@ -210,3 +210,57 @@ func TestOllamaProvider_ImplementsHealthChecker(t *testing.T) {
 	provider, _ := newOllamaProvider(Config{Model: "m"})
 	var _ HealthChecker = provider
 }
+
+func TestOllamaToolTurn_Tools(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/chat", r.URL.Path)
+		var req map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, false, req["stream"])
+		assert.Equal(t, []any{map[string]any{
+			"type": "function",
+			"function": map[string]any{
+				"name":       "get_incident",
+				"parameters": map[string]any{"type": "object"},
+			},
+		}}, req["tools"])
+
+		messages := req["messages"].([]any)
+		assert.Len(t, messages, 3)
+		assert.Equal(t, []any{map[string]any{
+			"function": map[string]any{"name": "get_incident", "arguments": map[string]any{"id": "P1"}},
+		}}, messages[1].(map[string]any)["tool_calls"])
+		assert.Equal(t, map[string]any{"role": "tool", "content": "ok", "tool_name": "get_incident"}, messages[2])
+
+		_, _ = fmt.Fprint(w, `{"message":{"role":"assistant","content":"","tool_calls":[
+			{"function":{"name":"get_cluster","arguments":{"id":"c1"}}}
+		]},"done":true}`)
+	}))
+	defer server.Close()
+
+	provider, err := newOllamaProvider(Config{Endpoint: server.URL})
+	assert.NoError(t, err)
+
+	reply, err := provider.ToolTurn(context.Background(), "", []ToolMessage{
+		{Role: "user", Content: "investigate"},
+		{Role: "assistant", ToolCalls: []ToolCall{{Name: "get_incident", Input: json.RawMessage(`{"id":"P1"}`)}}},
+		{Role: "tool", Content: "ok", ToolCallID: "call_0_0", ToolName: "get_incident"},
+	}, []ToolSpec{{Name: "get_incident", Schema: json.RawMessage(`{"type":"object"}`)}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []ToolCall{{Name: "get_cluster", Input: json.RawMessage(`{"id":"c1"}`)}}, reply.ToolCalls)
+}
+
+func TestAsToolCaller(t *testing.T) {
+	assert.Nil(t, AsToolCaller(nil))
+	assert.Nil(t, AsToolCaller(NewMockProvider("mock")))
+	for _, cfg := range []Config{
+		{Provider: "ollama"},
+		{Provider: "openai", Endpoint: "http://localhost:8000"},
+		{Provider: "ramalama"},
+	} {
+		p, err := NewProvider(cfg)
+		assert.NoError(t, err)
+		assert.NotNil(t, AsToolCaller(p), cfg.Provider)
+	}
+}
@@ -67,11 +67,27 @@ type openaiChatRequest struct {
 	Model    string              `json:"model"`
 	Messages []openaiChatMessage `json:"messages"`
 	Stream   bool                `json:"stream"`
+	Tools    []functionTool      `json:"tools,omitempty"`
 }
 
 type openaiChatMessage struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
+	Role       string           `json:"role"`
+	Content    string           `json:"content"`
+	ToolCalls  []openaiToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string           `json:"tool_call_id,omitempty"`
+}
+
+// openaiToolCall is a function call in an assistant message. Arguments is
+// the input as a JSON string.
+type openaiToolCall struct {
+	ID       string             `json:"id"`
+	Type     string             `json:"type"`
+	Function openaiFunctionCall `json:"function"`
+}
+
+type openaiFunctionCall struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
 }
 
 type openaiChatResponse struct {
@@ -89,24 +105,85 @@ func (p *openaiCompatProvider) Query(ctx context.Context, systemPrompt string, u
 	defer cancel()
 	messages := buildOpenAIMessages(systemPrompt, userPrompt)
 
-	body, err := json.Marshal(openaiChatRequest{
+	chatResp, err := p.chat(ctx, openaiChatRequest{
 		Model:    p.model,
 		Messages: messages,
 		Stream:   false,
 	})
 	if err != nil {
-		return "", fmt.Errorf("openai: marshal request: %w", err)
+		return "", err
+	}
+
+	if len(chatResp.Choices) == 0 {
+		return "", nil
+	}
+
+	return chatResp.Choices[0].Message.Content, nil
+}
+
+// ToolTurn sends the conversation with tools as OpenAI function-calling
+// definitions and returns the reply, with its function calls.
+func (p *openaiCompatProvider) ToolTurn(ctx context.Context, systemPrompt string, messages []ToolMessage, tools []ToolSpec) (ToolMessage, error) {
+	log.Debug("openai.ToolTurn", "endpoint", p.endpoint, "model", p.model, "messages", len(messages))
+	ctx, cancel := ensureTimeout(ctx, p.requestTimeout)
+	defer cancel()
+
+	var wire []openaiChatMessage
+	if systemPrompt != "" {
+		wire = append(wire, openaiChatMessage{Role: "system", Content: systemPrompt})
+	}
+	for _, m := range messages {
+		msg := openaiChatMessage{Role: m.Role, Content: m.Content, ToolCallID: m.ToolCallID}
+		for _, c := range m.ToolCalls {
+			msg.ToolCalls = append(msg.ToolCalls, openaiToolCall{
+				ID:       c.ID,
+				Type:     "function",
+				Function: openaiFunctionCall{Name: c.Name, Arguments: string(c.Input)},
+			})
+		}
+		wire = append(wire, msg)
+	}
+
+	chatResp, err := p.chat(ctx, openaiChatRequest{
+		Model:    p.model,
+		Messages: wire,
+		Tools:    functionTools(tools),
+	})
+	if err != nil {
+		return ToolMessage{}, err
+	}
+	if len(chatResp.Choices) == 0 {
+		return ToolMessage{Role: "assistant"}, nil
+	}
+
+	reply := chatResp.Choices[0].Message
+	out := ToolMessage{Role: "assistant", Content: reply.Content}
+	for _, c := range reply.ToolCalls {
+		out.ToolCalls = append(out.ToolCalls, ToolCall{
+			ID:    c.ID,
+			Name:  c.Function.Name,
+			Input: toolInput([]byte(c.Function.Arguments)),
+		})
+	}
+	return out, nil
+}
+
+// chat posts a non-streaming chat completion request.
+func (p *openaiCompatProvider) chat(ctx context.Context, chatReq openaiChatRequest) (openaiChatResponse, error) {
+	body, err := json.Marshal(chatReq)
+	if err != nil {
+		return openaiChatResponse{}, fmt.Errorf("openai: marshal request: %w", err)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint+"/v1/chat/completions", bytes.NewReader(body))
 	if err != nil {
-		return "", fmt.Errorf("openai: create request: %w", err)
+		return openaiChatResponse{}, fmt.Errorf("openai: create request: %w", err)
 	}
 	p.setHeaders(req)
 
 	resp, err := p.httpClient.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("openai: request failed: %w", err)
+		return openaiChatResponse{}, fmt.Errorf("openai: request failed: %w", err)
 	}
 	defer resp.Body.Close() //nolint:errcheck
 
@@ -114,19 +191,14 @@ func (p *openaiCompatProvider) Query(ctx context.Context, systemPrompt string, u
 		// Do NOT include the response body: a proxy/gateway may echo the
 		// Authorization header back in its error body, which would leak the API
 		// token into logs. Status code only (matches the Healthy method).
-		return "", fmt.Errorf("openai: server returned %d", resp.StatusCode)
+		return openaiChatResponse{}, fmt.Errorf("openai: server returned %d", resp.StatusCode)
 	}
 
 	var chatResp openaiChatResponse
 	if err := json.NewDecoder(resp.Body).Decode(&chatResp); err != nil {
-		return "", fmt.Errorf("openai: decode response: %w", err)
+		return openaiChatResponse{}, fmt.Errorf("openai: decode response: %w", err)
 	}
-
-	if len(chatResp.Choices) == 0 {
-		return "", nil
-	}
-
-	return chatResp.Choices[0].Message.Content, nil
+	return chatResp, nil
 }
 
 func (p *openaiCompatProvider) StreamQuery(ctx context.Context, systemPrompt string, userPrompt string, ch chan<- string) error {
@@ -326,3 +326,63 @@ func TestOpenAIProvider_ImplementsHealthChecker(t *testing.T) {
 	provider, _ := newOpenAICompatProvider(Config{Endpoint: "http://localhost", Model: "m"}, "")
 	var _ HealthChecker = provider
 }
+
+func TestOpenAIToolTurn_FunctionCalling(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, []any{map[string]any{
+			"type": "function",
+			"function": map[string]any{
+				"name":        "get_incident",
+				"description": "Get an incident",
+				"parameters":  map[string]any{"type": "object", "properties": map[string]any{"id": map[string]any{"type": "string"}}},
+			},
+		}}, req["tools"])
+
+		messages := req["messages"].([]any)
+		assert.Len(t, messages, 4)
+		assert.Equal(t, map[string]any{"role": "system", "content": "system prompt"}, messages[0])
+		assistant := messages[2].(map[string]any)
+		assert.Equal(t, []any{map[string]any{
+			"id": "call_1", "type": "function",
+			"function": map[string]any{"name": "get_incident", "arguments": `{"id":"P1"}`},
+		}}, assistant["tool_calls"])
+		assert.Equal(t, map[string]any{"role": "tool", "content": `{"title":"down"}`, "tool_call_id": "call_1"}, messages[3])
+
+		_, _ = fmt.Fprint(w, `{"choices":[{"message":{"role":"assistant","content":"checking","tool_calls":[
+			{"id":"call_2","type":"function","function":{"name":"get_cluster","arguments":"{\"id\":\"c1\"}"}},
+			{"id":"call_3","type":"function","function":{"name":"get_cluster","arguments":""}}
+		]}}]}`)
+	}))
+	defer server.Close()
+
+	provider, err := newOpenAICompatProvider(Config{Endpoint: server.URL, Model: "m"}, "")
+	assert.NoError(t, err)
+
+	reply, err := provider.ToolTurn(context.Background(), "system prompt", []ToolMessage{
+		{Role: "user", Content: "investigate"},
+		{Role: "assistant", ToolCalls: []ToolCall{{ID: "call_1", Name: "get_incident", Input: json.RawMessage(`{"id":"P1"}`)}}},
+		{Role: "tool", Content: `{"title":"down"}`, ToolCallID: "call_1", ToolName: "get_incident"},
+	}, []ToolSpec{{Name: "get_incident", Description: "Get an incident", Schema: json.RawMessage(`{"type":"object","properties":{"id":{"type":"string"}}}`)}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "checking", reply.Content)
+	assert.Equal(t, []ToolCall{
+		{ID: "call_2", Name: "get_cluster", Input: json.RawMessage(`{"id":"c1"}`)},
+		{ID: "call_3", Name: "get_cluster", Input: json.RawMessage(`{}`)},
+	}, reply.ToolCalls)
+}
+
+func TestOpenAIToolTurn_ServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = fmt.Fprint(w, "Authorization: Bearer secret")
+	}))
+	defer server.Close()
+
+	provider, err := newOpenAICompatProvider(Config{Endpoint: server.URL, Model: "m"}, "")
+	assert.NoError(t, err)
+	_, err = provider.ToolTurn(context.Background(), "", []ToolMessage{{Role: "user", Content: "hi"}}, nil)
+	assert.EqualError(t, err, "openai: server returned 400")
+}
@@ -37,6 +37,10 @@ func (p *ramalamaProvider) StreamQuery(ctx context.Context, systemPrompt string,
 	return p.inner.StreamQuery(ctx, systemPrompt, userPrompt, ch)
 }
 
+func (p *ramalamaProvider) ToolTurn(ctx context.Context, systemPrompt string, messages []ToolMessage, tools []ToolSpec) (ToolMessage, error) {
+	return p.inner.ToolTurn(ctx, systemPrompt, messages, tools)
+}
+
 func (p *ramalamaProvider) Healthy(ctx context.Context) error {
 	return p.inner.Healthy(ctx)
 }
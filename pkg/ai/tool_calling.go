@@ -0,0 +1,94 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// ToolSpec describes a tool offered to the model. Schema is the JSON Schema
+// of its input.
+type ToolSpec struct {
+	Name        string
+	Description string
+	Schema      json.RawMessage
+}
+
+// ToolCall is a model's request to run a tool. ID is the provider's call
+// ID, which the result must quote; Ollama has none.
+type ToolCall struct {
+	ID    string
+	Name  string
+	Input json.RawMessage
+}
+
+// ToolMessage is one message of a tool-using conversation: the user's
+// prompt, an assistant reply (which may carry ToolCalls), or, with Role
+// "tool", the result of the call ToolCallID to ToolName.
+type ToolMessage struct {
+	Role       string
+	Content    string
+	ToolCalls  []ToolCall
+	ToolCallID string
+	ToolName   string
+}
+
+// ToolCaller is an optional interface a Provider may implement to call
+// tools without the Anthropic SDK's tool runner. ToolTurn sends the
+// conversation so far with the tools on offer and returns the model's
+// reply; the caller runs any ToolCalls and sends their results in the
+// next turn.
+type ToolCaller interface {
+	ToolTurn(ctx context.Context, systemPrompt string, messages []ToolMessage, tools []ToolSpec) (ToolMessage, error)
+}
+
+// AsToolCaller returns p as a ToolCaller if it implements the interface,
+// or nil.
+func AsToolCaller(p Provider) ToolCaller {
+	if p == nil {
+		return nil
+	}
+	tc, ok := p.(ToolCaller)
+	if !ok {
+		return nil
+	}
+	return tc
+}
+
+// functionTool is the tool schema OpenAI function calling and Ollama share.
+type functionTool struct {
+	Type     string         `json:"type"`
+	Function functionSchema `json:"function"`
+}
+
+type functionSchema struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters"`
+}
+
+func functionTools(tools []ToolSpec) []functionTool {
+	if len(tools) == 0 {
+		return nil
+	}
+	out := make([]functionTool, 0, len(tools))
+	for _, t := range tools {
+		params := t.Schema
+		if len(params) == 0 {
+			params = json.RawMessage(`{"type":"object","properties":{}}`)
+		}
+		out = append(out, functionTool{
+			Type:     "function",
+			Function: functionSchema{Name: t.Name, Description: t.Description, Parameters: params},
+		})
+	}
+	return out
+}
+
+// toolInput returns raw as a tool input, or an empty object when the model
+// sent nothing usable: handlers and the policy gate expect a JSON object.
+func toolInput(raw []byte) json.RawMessage {
+	if len(raw) == 0 || !json.Valid(raw) {
+		return json.RawMessage(`{}`)
+	}
+	return json.RawMessage(raw)
+}
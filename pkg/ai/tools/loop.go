@@ -0,0 +1,110 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	anthropic "github.com/anthropics/anthropic-sdk-go"
+	"github.com/charmbracelet/log"
+	"github.com/clcollins/srepd/pkg/ai"
+)
+
+// Specs converts tools (normally from GatedBetaTools) to provider-neutral
+// specs for ai.ToolCaller.
+func Specs(betaTools []anthropic.BetaTool) []ai.ToolSpec {
+	specs := make([]ai.ToolSpec, 0, len(betaTools))
+	for _, t := range betaTools {
+		schema, err := json.Marshal(t.InputSchema())
+		if err != nil {
+			log.Debug("tools.Specs", "tool", t.Name(), "error", err)
+			continue
+		}
+		specs = append(specs, ai.ToolSpec{Name: t.Name(), Description: t.Description(), Schema: schema})
+	}
+	return specs
+}
+
+// RunLoop is the tool loop for providers without the Anthropic SDK's tool
+// runner (OpenAI-compatible servers and Ollama). Every turn offers the
+// tools; the calls the model makes run through their Execute, so the
+// policy gate of GatedBetaTools applies as it does for the runner, and
+// the results go back in the next turn. It stops when the model replies
+// without calling a tool or after maxTurns turns, and returns the text of
+// every reply. Calls made in the last turn are not run.
+func RunLoop(
+	ctx context.Context,
+	caller ai.ToolCaller,
+	betaTools []anthropic.BetaTool,
+	systemPrompt string,
+	userPrompt string,
+	maxTurns int,
+) (string, error) {
+	specs := Specs(betaTools)
+	byName := make(map[string]anthropic.BetaTool, len(betaTools))
+	for _, t := range betaTools {
+		byName[t.Name()] = t
+	}
+
+	messages := []ai.ToolMessage{{Role: "user", Content: userPrompt}}
+	var text strings.Builder
+	for turn := 0; turn < maxTurns; turn++ {
+		reply, err := caller.ToolTurn(ctx, systemPrompt, messages, specs)
+		if err != nil {
+			return text.String(), err
+		}
+		text.WriteString(reply.Content)
+		if len(reply.ToolCalls) == 0 {
+			break
+		}
+		// The model would never see these results, so running them (or
+		// asking to approve them) has no point.
+		if turn == maxTurns-1 {
+			names := make([]string, 0, len(reply.ToolCalls))
+			for _, call := range reply.ToolCalls {
+				names = append(names, call.Name)
+			}
+			log.Warn("tools.RunLoop: turn limit reached, tool calls not run", "max_turns", maxTurns, "tools", names)
+			break
+		}
+
+		// OpenAI-compatible servers match results to calls by ID; some
+		// omit it.
+		for i := range reply.ToolCalls {
+			if reply.ToolCalls[i].ID == "" {
+				reply.ToolCalls[i].ID = fmt.Sprintf("call_%d_%d", turn, i)
+			}
+		}
+		messages = append(messages, reply)
+		for _, call := range reply.ToolCalls {
+			messages = append(messages, ai.ToolMessage{
+				Role:       "tool",
+				Content:    executeCall(ctx, byName, call),
+				ToolCallID: call.ID,
+				ToolName:   call.Name,
+			})
+		}
+	}
+	return text.String(), nil
+}
+
+// executeCall runs call and returns its result as text. Errors become the
+// result, worded like the Anthropic tool runner's, so the model can react.
+func executeCall(ctx context.Context, byName map[string]anthropic.BetaTool, call ai.ToolCall) string {
+	tool, ok := byName[call.Name]
+	if !ok {
+		return fmt.Sprintf("Error: Tool '%s' not found", call.Name)
+	}
+	content, err := tool.Execute(ctx, call.Input)
+	if err != nil {
+		return fmt.Sprintf("Error: %v", err)
+	}
+	var b strings.Builder
+	for _, c := range content {
+		if c.OfText != nil {
+			b.WriteString(c.OfText.Text)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,132 @@
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/clcollins/srepd/pkg/ai"
+	"github.com/clcollins/srepd/pkg/ai/policy"
+	"github.com/clcollins/srepd/pkg/ai/tools"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptedCaller replies with one scripted message per turn and records
+// the conversation it was sent.
+type scriptedCaller struct {
+	replies []ai.ToolMessage
+	sent    [][]ai.ToolMessage
+	specs   []ai.ToolSpec
+}
+
+func (c *scriptedCaller) ToolTurn(_ context.Context, _ string, messages []ai.ToolMessage, specs []ai.ToolSpec) (ai.ToolMessage, error) {
+	c.sent = append(c.sent, append([]ai.ToolMessage(nil), messages...))
+	c.specs = specs
+	if len(c.sent) > len(c.replies) {
+		return ai.ToolMessage{}, fmt.Errorf("unexpected turn %d", len(c.sent))
+	}
+	return c.replies[len(c.sent)-1], nil
+}
+
+func loopRegistry(t *testing.T, calls *int) *tools.Registry {
+	t.Helper()
+	reg := tools.NewRegistry()
+	require.NoError(t, reg.Register(tools.Tool{
+		Name:        "get_incident",
+		Description: "Get an incident",
+		Class:       policy.ClassRead,
+		Schema:      []byte(`{"type":"object","properties":{"id":{"type":"string"}},"required":["id"]}`),
+		Handler: func(_ context.Context, input json.RawMessage) (string, error) {
+			*calls++
+			return "incident " + string(input), nil
+		},
+	}))
+	require.NoError(t, reg.Register(tools.Tool{
+		Name:   "write_note",
+		Class:  policy.ClassWriteLocal,
+		Schema: []byte(`{"type":"object","properties":{"content":{"type":"string"}}}`),
+		Handler: func(_ context.Context, _ json.RawMessage) (string, error) {
+			*calls++
+			return "written", nil
+		},
+	}))
+	return reg
+}
+
+func TestRunLoop_ExecutesCallsThroughTheGate(t *testing.T) {
+	var calls int
+	var asked []string
+	gated := loopRegistry(t, &calls).GatedBetaTools(
+		func(toolName string, class policy.Class, input json.RawMessage) policy.Decision {
+			return policy.Decide(policy.Config{Mode: policy.ModeInteractive}, toolName, class, input)
+		},
+		func(toolName string, _ json.RawMessage) { asked = append(asked, toolName) },
+	)
+	caller := &scriptedCaller{replies: []ai.ToolMessage{
+		{Role: "assistant", Content: "Looking. ", ToolCalls: []ai.ToolCall{
+			{Name: "get_incident", Input: json.RawMessage(`{"id":"P1"}`)},
+			{ID: "own-id", Name: "write_note", Input: json.RawMessage(`{"content":"x"}`)},
+			{Name: "delete_cluster", Input: json.RawMessage(`{}`)},
+		}},
+		{Role: "assistant", Content: "Done."},
+	}}
+
+	text, err := tools.RunLoop(context.Background(), caller, gated, "system", "investigate", 6)
+	require.NoError(t, err)
+	assert.Equal(t, "Looking. Done.", text)
+	assert.Equal(t, 1, calls, "only the read tool runs; the write is held for approval")
+	assert.Equal(t, []string{"write_note"}, asked)
+
+	require.Len(t, caller.specs, 2)
+	assert.Equal(t, "get_incident", caller.specs[0].Name)
+	assert.JSONEq(t, `{"type":"object","properties":{"id":{"type":"string"}},"required":["id"]}`, string(caller.specs[0].Schema))
+
+	require.Len(t, caller.sent, 2)
+	second := caller.sent[1]
+	require.Len(t, second, 5, "user, assistant, and one result per call")
+	assert.Equal(t, "call_0_0", second[1].ToolCalls[0].ID, "missing call IDs are filled in")
+	assert.Equal(t, ai.ToolMessage{Role: "tool", Content: `incident {"id":"P1"}`, ToolCallID: "call_0_0", ToolName: "get_incident"}, second[2])
+	assert.Equal(t, "own-id", second[3].ToolCallID)
+	assert.Contains(t, second[3].Content, "Awaiting user approval")
+	assert.Equal(t, "Error: Tool 'delete_cluster' not found", second[4].Content)
+}
+
+func TestRunLoop_DenyRunsNoHandler(t *testing.T) {
+	var calls int
+	gated := loopRegistry(t, &calls).GatedBetaTools(
+		func(string, policy.Class, json.RawMessage) policy.Decision { return policy.Deny },
+		nil,
+	)
+	caller := &scriptedCaller{replies: []ai.ToolMessage{
+		{Role: "assistant", ToolCalls: []ai.ToolCall{{Name: "get_incident", Input: json.RawMessage(`{"id":"P1"}`)}}},
+		{Role: "assistant", Content: "Denied."},
+	}}
+
+	_, err := tools.RunLoop(context.Background(), caller, gated, "", "investigate", 6)
+	require.NoError(t, err)
+	assert.Zero(t, calls)
+	assert.Contains(t, caller.sent[1][2].Content, "Permission denied")
+}
+
+func TestRunLoop_StopsAfterMaxTurns(t *testing.T) {
+	var calls int
+	gated := loopRegistry(t, &calls).GatedBetaTools(
+		func(string, policy.Class, json.RawMessage) policy.Decision { return policy.Allow },
+		nil,
+	)
+	call := ai.ToolMessage{Role: "assistant", ToolCalls: []ai.ToolCall{{Name: "get_incident", Input: json.RawMessage(`{"id":"P1"}`)}}}
+	caller := &scriptedCaller{replies: []ai.ToolMessage{call, call, call}}
+
+	_, err := tools.RunLoop(context.Background(), caller, gated, "", "investigate", 2)
+	require.NoError(t, err)
+	assert.Len(t, caller.sent, 2)
+	assert.Equal(t, 1, calls, "the last turn's calls are not run: the model would never see them")
+}
+
+func TestRunLoop_ProviderError(t *testing.T) {
+	caller := &scriptedCaller{}
+	_, err := tools.RunLoop(context.Background(), caller, nil, "", "investigate", 2)
+	assert.EqualError(t, err, "unexpected turn 1")
+}
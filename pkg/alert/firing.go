@@ -79,3 +79,180 @@ func parseRHOBSFiring(lines []string, result map[string]string) {
 		}
 	}
 }
+
+// FiringAlert is one alert from a firing text. Alertmanager groups several
+// alerts into one PagerDuty alert when num_firing > 1, and the firing text
+// then repeats the labels/annotations block once per alert.
+type FiringAlert struct {
+	Labels       map[string]string
+	Annotations  map[string]string
+	StartsAt     string
+	GeneratorURL string // Prometheus graph URL for the alert's expression
+}
+
+// flatAnnotationKeys are the keys filed under Annotations when a firing text
+// has no explicit Labels/Annotations sections (the flat RHOBS format).
+var flatAnnotationKeys = map[string]bool{
+	"description": true,
+	"summary":     true,
+	"message":     true,
+	"runbook":     true,
+	"runbook_url": true,
+	"link":        true,
+	"sop":         true,
+	"dashboard":   true,
+}
+
+// startsAtPattern matches a "StartsAt: <time>" line in Alertmanager firing text.
+var startsAtPattern = regexp.MustCompile(`(?i)^starts\s*at:\s*(.+)$`)
+
+// rhobsLinePattern matches "  - key: value", "  key: value" and "  key:"
+// lines, capturing indentation, the list dash, key, and value.
+var rhobsLinePattern = regexp.MustCompile(`^(\s*)(-\s+)?([^\s:]+):\s*(.*)$`)
+
+// ParseFiringAlerts splits firing text into its individual alerts with
+// labels and annotations kept apart. It detects the format like ParseFiring.
+// Returns nil for empty or unrecognized input.
+func ParseFiringAlerts(firing string) []FiringAlert {
+	if strings.TrimSpace(firing) == "" {
+		return nil
+	}
+	lines := strings.Split(firing, "\n")
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "Labels:" {
+			return parseAlertmanagerAlerts(lines)
+		}
+	}
+	return parseRHOBSAlerts(lines)
+}
+
+// parseFiringAlertsAs is ParseFiringAlerts with an explicit rule format.
+func parseFiringAlertsAs(format, firing string) []FiringAlert {
+	if strings.TrimSpace(firing) == "" {
+		return nil
+	}
+	switch format {
+	case FiringFormatNone:
+		return nil
+	case FiringFormatAlertmanager:
+		return parseAlertmanagerAlerts(strings.Split(firing, "\n"))
+	case FiringFormatRHOBS:
+		return parseRHOBSAlerts(strings.Split(firing, "\n"))
+	default:
+		return ParseFiringAlerts(firing)
+	}
+}
+
+func newFiringAlert() *FiringAlert {
+	return &FiringAlert{Labels: map[string]string{}, Annotations: map[string]string{}}
+}
+
+func (f *FiringAlert) empty() bool {
+	return len(f.Labels) == 0 && len(f.Annotations) == 0 && f.StartsAt == "" && f.GeneratorURL == ""
+}
+
+// parseAlertmanagerAlerts parses the PagerDuty Alertmanager template: each
+// alert is a "Labels:" block, an "Annotations:" block, and a "Source:" line.
+func parseAlertmanagerAlerts(lines []string) []FiringAlert {
+	var alerts []FiringAlert
+	var cur *FiringAlert
+	var section map[string]string
+	flush := func() {
+		if cur != nil && !cur.empty() {
+			alerts = append(alerts, *cur)
+		}
+	}
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			continue
+		case trimmed == "Labels:":
+			flush()
+			cur = newFiringAlert()
+			section = cur.Labels
+			continue
+		}
+		if cur == nil {
+			cur = newFiringAlert()
+			section = cur.Labels
+		}
+		switch {
+		case trimmed == "Annotations:":
+			section = cur.Annotations
+		case strings.HasPrefix(trimmed, "Source:"):
+			cur.GeneratorURL = strings.TrimSpace(strings.TrimPrefix(trimmed, "Source:"))
+		default:
+			if m := startsAtPattern.FindStringSubmatch(trimmed); m != nil {
+				cur.StartsAt = strings.TrimSpace(m[1])
+			} else if m := alertmanagerKVPattern.FindStringSubmatch(line); m != nil {
+				section[strings.TrimSpace(m[1])] = strings.TrimSpace(m[2])
+			}
+		}
+	}
+	flush()
+	return alerts
+}
+
+// parseRHOBSAlerts parses the RHOBS YAML-like format. A list item ("- ")
+// at the outermost list indentation starts a new alert; "labels:" and
+// "annotations:" open nested sections. Keys outside a section are sorted
+// into labels or annotations by name.
+func parseRHOBSAlerts(lines []string) []FiringAlert {
+	var alerts []FiringAlert
+	cur := newFiringAlert()
+	itemIndent := -1
+	var section map[string]string
+	sectionIndent := -1
+	flush := func() {
+		if !cur.empty() {
+			alerts = append(alerts, *cur)
+		}
+		cur = newFiringAlert()
+	}
+
+	for _, line := range lines {
+		m := rhobsLinePattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		indent, dash, key, value := len(m[1]), m[2] != "", m[3], strings.TrimSpace(m[4])
+		col := indent + len(m[2]) // column of the key itself
+
+		if dash && (itemIndent < 0 || indent <= itemIndent) {
+			if itemIndent >= 0 {
+				flush()
+			}
+			itemIndent = indent
+			section, sectionIndent = nil, -1
+		}
+		// Leaving a nested section once indentation returns to its level
+		if section != nil && col <= sectionIndent {
+			section, sectionIndent = nil, -1
+		}
+
+		switch {
+		case section != nil:
+			if value != "" {
+				section[key] = value
+			}
+		case value == "" && strings.EqualFold(key, "labels"):
+			section, sectionIndent = cur.Labels, col
+		case value == "" && strings.EqualFold(key, "annotations"):
+			section, sectionIndent = cur.Annotations, col
+		case value == "":
+			continue
+		case strings.EqualFold(key, "startsAt") || key == "starts_at":
+			cur.StartsAt = value
+		case strings.EqualFold(key, "generatorURL") || key == "generator_url":
+			cur.GeneratorURL = value
+		case flatAnnotationKeys[key]:
+			cur.Annotations[key] = value
+		default:
+			cur.Labels[key] = value
+		}
+	}
+	flush()
+	return alerts
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestParseFiring_AlertmanagerFormat(t *testing.T) {
@@ -80,3 +81,82 @@ func TestParseFiring_WhitespaceOnly(t *testing.T) {
 	assert.NotNil(t, result)
 	assert.Empty(t, result)
 }
+
+func TestParseFiringAlerts_AlertmanagerGroup(t *testing.T) {
+	firing := `Labels:
+ - alertname = KubePodCrashLooping
+ - namespace = openshift-monitoring
+ - pod = prometheus-k8s-0
+Annotations:
+ - description = Pod is crash looping
+StartsAt: 2026-05-29 10:00:00 +0000 UTC
+Source: https://prometheus.example.com/graph?g0.expr=up%3D%3D0
+Labels:
+ - alertname = KubePodCrashLooping
+ - namespace = openshift-monitoring
+ - pod = prometheus-k8s-1
+Annotations:
+ - description = Pod is crash looping
+Source: https://prometheus.example.com/graph?g0.expr=up%3D%3D0
+`
+
+	alerts := ParseFiringAlerts(firing)
+
+	require.Len(t, alerts, 2)
+	assert.Equal(t, map[string]string{
+		"alertname": "KubePodCrashLooping",
+		"namespace": "openshift-monitoring",
+		"pod":       "prometheus-k8s-0",
+	}, alerts[0].Labels)
+	assert.Equal(t, map[string]string{"description": "Pod is crash looping"}, alerts[0].Annotations)
+	assert.Equal(t, "2026-05-29 10:00:00 +0000 UTC", alerts[0].StartsAt)
+	assert.Equal(t, "https://prometheus.example.com/graph?g0.expr=up%3D%3D0", alerts[0].GeneratorURL)
+	assert.Equal(t, "prometheus-k8s-1", alerts[1].Labels["pod"])
+	assert.Empty(t, alerts[1].StartsAt)
+}
+
+func TestParseFiringAlerts_RHOBSNested(t *testing.T) {
+	firing := `
+  - labels:
+      alertname: ClusterOperatorDown
+      namespace: ocm-production
+    annotations:
+      description: The operator is down
+    startsAt: 2026-05-29T10:00:00Z
+    generatorURL: https://rhobs.example.com/graph?g0.expr=x
+  - labels:
+      alertname: ClusterOperatorDown
+      namespace: ocm-staging
+    startsAt: 2026-05-29T10:05:00Z
+`
+
+	alerts := ParseFiringAlerts(firing)
+
+	require.Len(t, alerts, 2)
+	assert.Equal(t, map[string]string{"alertname": "ClusterOperatorDown", "namespace": "ocm-production"}, alerts[0].Labels)
+	assert.Equal(t, map[string]string{"description": "The operator is down"}, alerts[0].Annotations)
+	assert.Equal(t, "2026-05-29T10:00:00Z", alerts[0].StartsAt)
+	assert.Equal(t, "https://rhobs.example.com/graph?g0.expr=x", alerts[0].GeneratorURL)
+	assert.Equal(t, "ocm-staging", alerts[1].Labels["namespace"])
+	assert.Empty(t, alerts[1].Annotations)
+}
+
+func TestParseFiringAlerts_RHOBSFlat(t *testing.T) {
+	firing := `
+  - alertname: ClusterOperatorDown
+    cluster_id: a4ba96fe
+    description: The ingress operator is unavailable
+`
+
+	alerts := ParseFiringAlerts(firing)
+
+	require.Len(t, alerts, 1)
+	assert.Equal(t, map[string]string{"alertname": "ClusterOperatorDown", "cluster_id": "a4ba96fe"}, alerts[0].Labels)
+	assert.Equal(t, map[string]string{"description": "The ingress operator is unavailable"}, alerts[0].Annotations, "well-known annotation keys are split out")
+}
+
+func TestParseFiringAlerts_Empty(t *testing.T) {
+	assert.Nil(t, ParseFiringAlerts(""))
+	assert.Nil(t, ParseFiringAlerts("random garbage"))
+	assert.Nil(t, parseFiringAlertsAs(FiringFormatNone, "Labels:\n - a = b\n"))
+}
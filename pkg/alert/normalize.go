@@ -12,7 +12,7 @@ import (
 // when available from the source data.
 type NormalizedAlert struct {
 	// Required fields
-	AlertType   string // "osd_hive", "appsre", "rhobs_hcp", "rhobs_infra", "deadmanssnitch", "cee_escalation", "cad", "unknown"
+	AlertType   string // "osd_hive", "appsre", "rhobs_hcp", "rhobs_infra", "deadmanssnitch", "cee_escalation", "cad", "unknown", or a user rule name
 	AlertName   string // Normalized alert name (e.g., "ClusterOperatorDown")
 	ClusterID   string // Cluster UUID or subscription ID (empty string if not applicable)
 	Severity    string // Normalized to lowercase: "critical", "warning", "high", "soaking"
@@ -34,8 +34,21 @@ type NormalizedAlert struct {
 	Reason        string   // Failure reason (e.g., "BootstrapFailed")
 	Tags          []string // SRE-added title tags: ["SL Sent", "OHSS-54318", ...]
 	FiringCount   int      // Number of firing alerts
+
+	// Structured firing text, when the alert has one. Labels, Annotations,
+	// StartsAt and GeneratorURL describe the first firing alert;
+	// FiringAlerts holds every alert of a grouped (num_firing > 1) alert.
+	Labels       map[string]string
+	Annotations  map[string]string
+	StartsAt     string
+	GeneratorURL string
+	FiringAlerts []FiringAlert
 }
 
+// builtinTypes are the alert types IdentifyType returns. User rules may not
+// take these names, so a rule's output never passes for a built-in parser's.
+var builtinTypes = []string{"osd_hive", "appsre", "rhobs_hcp", "rhobs_infra", "deadmanssnitch", "cee_escalation", "cad", "unknown"}
+
 // IdentifyType determines the alert type from the PagerDuty service name pattern.
 // Match order is specific-to-general to avoid ambiguity.
 func IdentifyType(serviceSummary string) string {
@@ -60,9 +73,20 @@ func IdentifyType(serviceSummary string) string {
 }
 
 // NormalizeAlert normalizes a PagerDuty alert into the canonical NormalizedAlert
-// structure. It dispatches to per-type parsers based on the service name.
+// structure. User-defined rules installed with SetRules are consulted first;
+// otherwise it dispatches to per-type parsers based on the service name.
 func NormalizeAlert(serviceSummary string, title string, alert pagerduty.IncidentAlert) NormalizedAlert {
+	return normalizeWith(activeRules.Load(), serviceSummary, title, alert)
+}
+
+// normalizeWith is NormalizeAlert against an explicit (possibly nil) RuleSet.
+func normalizeWith(rules *RuleSet, serviceSummary string, title string, alert pagerduty.IncidentAlert) NormalizedAlert {
+	rule := rules.Match(serviceSummary)
+
 	alertType := IdentifyType(serviceSummary)
+	if rule != nil {
+		alertType = rule.Name
+	}
 
 	// Build base normalized alert with common fields
 	normalized := NormalizedAlert{
@@ -80,6 +104,12 @@ func NormalizeAlert(serviceSummary string, title string, alert pagerduty.Inciden
 		normalized.Tags = tags
 	}
 
+	if rule != nil {
+		rule.apply(&normalized, title, alert)
+		normalized.setFiring(parseFiringAlertsAs(rule.Firing.Format, getDetail(rule.Firing.Field, alert)))
+		return normalized
+	}
+
 	switch alertType {
 	case "osd_hive":
 		parseOSDHive(&normalized, title, alert)
@@ -99,5 +129,19 @@ func NormalizeAlert(serviceSummary string, title string, alert pagerduty.Inciden
 		parseUnknown(&normalized, title, alert)
 	}
 
+	normalized.setFiring(ParseFiringAlerts(getDetail("firing", alert)))
 	return normalized
 }
+
+// setFiring records the structured firing alerts, surfacing the first one's
+// labels and annotations on the alert itself.
+func (n *NormalizedAlert) setFiring(alerts []FiringAlert) {
+	if len(alerts) == 0 {
+		return
+	}
+	n.FiringAlerts = alerts
+	n.Labels = alerts[0].Labels
+	n.Annotations = alerts[0].Annotations
+	n.StartsAt = alerts[0].StartsAt
+	n.GeneratorURL = alerts[0].GeneratorURL
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/PagerDuty/go-pagerduty"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // --- TestIdentifyType ---
@@ -515,3 +516,29 @@ func TestOSDHive_ExtractsNamespaceAndMessage(t *testing.T) {
 	assert.Equal(t, "openshift-sre-pruning", result.Namespace)
 	assert.Equal(t, "SRE Pruning Job openshift-sre-pruning/builds-pruner is taking more than thirty minutes to complete.", result.Description)
 }
+
+func TestNormalizeAlert_StructuredFiring(t *testing.T) {
+	alert := makeAlert(map[string]interface{}{
+		"alert_name": "KubePodCrashLooping",
+		"cluster_id": "c1",
+		"num_firing": "2",
+		"firing": "Labels:\n - alertname = KubePodCrashLooping\n - pod = p0\nAnnotations:\n - summary = crash\nSource: https://prom.example.com/graph?g0.expr=a\n" +
+			"Labels:\n - alertname = KubePodCrashLooping\n - pod = p1\nAnnotations:\n - summary = crash\nSource: https://prom.example.com/graph?g0.expr=a\n",
+	})
+	svc := "osd-c1-hive-cluster"
+
+	result := NormalizeAlert(svc, "KubePodCrashLooping WARNING (2)", alert)
+
+	assert.Equal(t, 2, result.FiringCount)
+	require.Len(t, result.FiringAlerts, 2)
+	assert.Equal(t, "p0", result.Labels["pod"], "first firing alert is surfaced")
+	assert.Equal(t, "crash", result.Annotations["summary"])
+	assert.Equal(t, "https://prom.example.com/graph?g0.expr=a", result.GeneratorURL)
+	assert.Equal(t, "p1", result.FiringAlerts[1].Labels["pod"])
+}
+
+func TestNormalizeAlert_NoFiringText(t *testing.T) {
+	result := NormalizeAlert("osd-c1-hive-cluster", "", makeAlert(map[string]interface{}{"alert_name": "X"}))
+	assert.Nil(t, result.Labels)
+	assert.Nil(t, result.FiringAlerts)
+}
@@ -0,0 +1,356 @@
+package alert
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/PagerDuty/go-pagerduty"
+	"gopkg.in/yaml.v3"
+)
+
+// Firing text formats a rule can declare. "auto" defers to ParseFiring's
+// detection; "none" skips firing parsing entirely.
+const (
+	FiringFormatAuto         = "auto"
+	FiringFormatAlertmanager = "alertmanager"
+	FiringFormatRHOBS        = "rhobs"
+	FiringFormatNone         = "none"
+)
+
+// Source prefixes for rule field mappings. A source is written as
+// "<kind>:<key>", e.g. "detail:cluster_id" or "title:alert_name".
+const (
+	sourceDetail  = "detail"  // alert.Body["details"][key]
+	sourceFiring  = "firing"  // key parsed from the firing text
+	sourceTitle   = "title"   // named group from TitlePattern
+	sourceService = "service" // named group from Service.Regex
+	sourceLiteral = "literal" // the key itself, verbatim
+)
+
+// ruleFields lists the NormalizedAlert fields a rule may populate, keyed by
+// the name used in the rules file.
+var ruleFields = map[string]func(n *NormalizedAlert, v string){
+	"alert_name":     func(n *NormalizedAlert, v string) { n.AlertName = v },
+	"cluster_id":     func(n *NormalizedAlert, v string) { n.ClusterID = v },
+	"severity":       func(n *NormalizedAlert, v string) { n.Severity = strings.ToLower(v) },
+	"cluster_name":   func(n *NormalizedAlert, v string) { n.ClusterName = v },
+	"region":         func(n *NormalizedAlert, v string) { n.Region = v },
+	"namespace":      func(n *NormalizedAlert, v string) { n.Namespace = v },
+	"description":    func(n *NormalizedAlert, v string) { n.Description = v },
+	"condition":      func(n *NormalizedAlert, v string) { n.Condition = v },
+	"reason":         func(n *NormalizedAlert, v string) { n.Reason = v },
+	"sop_link":       func(n *NormalizedAlert, v string) { n.SOPLink = v },
+	"ocm_link":       func(n *NormalizedAlert, v string) { n.OCMLink = v },
+	"dashboard_link": func(n *NormalizedAlert, v string) { n.DashboardLink = v },
+	"firing_count": func(n *NormalizedAlert, v string) {
+		if c, err := strconv.Atoi(v); err == nil {
+			n.FiringCount = c
+		}
+	},
+}
+
+// defaultDetailKeys maps rule fields to the detail key the built-in parsers
+// read them from, where that differs from the field name.
+var defaultDetailKeys = map[string]string{
+	"sop_link":     "link",
+	"firing_count": "num_firing",
+}
+
+// defaultFieldSources is used for fields a rule does not map explicitly:
+// a title group of the same name, then the conventional detail field.
+func defaultFieldSources(field string) []string {
+	detailKey := field
+	if k, ok := defaultDetailKeys[field]; ok {
+		detailKey = k
+	}
+	return []string{sourceTitle + ":" + field, sourceDetail + ":" + detailKey}
+}
+
+// ServiceMatch selects the PagerDuty services a rule applies to. Every
+// non-empty matcher must match; at least one must be set.
+type ServiceMatch struct {
+	Equals   string `yaml:"equals"`
+	Prefix   string `yaml:"prefix"`
+	Suffix   string `yaml:"suffix"`
+	Contains string `yaml:"contains"`
+	Regex    string `yaml:"regex"`
+
+	regex *regexp.Regexp
+}
+
+// FiringSpec describes where a rule's firing text lives and how to parse it.
+type FiringSpec struct {
+	Field  string `yaml:"field"`  // detail field holding the firing text (default "firing")
+	Format string `yaml:"format"` // auto, alertmanager, rhobs, none (default auto)
+}
+
+// Rule is a user-defined alert type. Rules are evaluated in file order
+// before the built-in parsers; the first rule whose Service matcher matches
+// produces the NormalizedAlert and its Name becomes the AlertType.
+type Rule struct {
+	Name         string              `yaml:"name"`
+	Service      ServiceMatch        `yaml:"service"`
+	TitlePattern string              `yaml:"title_pattern"`
+	Firing       FiringSpec          `yaml:"firing"`
+	Fields       map[string][]string `yaml:"fields"`
+
+	titlePattern *regexp.Regexp
+}
+
+// RuleSet is a validated, compiled set of user-defined alert rules.
+type RuleSet struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+// activeRules holds the RuleSet consulted by NormalizeAlert. It is set once
+// at startup but read from tea.Cmd goroutines, hence the atomic.
+var activeRules atomic.Pointer[RuleSet]
+
+// SetRules installs rs as the rule set consulted by NormalizeAlert.
+// Passing nil restores built-in-only behavior.
+func SetRules(rs *RuleSet) {
+	activeRules.Store(rs)
+}
+
+// LoadRules reads and validates a rules file.
+func LoadRules(path string) (*RuleSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading alert rules: %w", err)
+	}
+	rs, err := ParseRules(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return rs, nil
+}
+
+// ParseRules parses and validates rules YAML, compiling every regex so
+// mistakes surface at load time rather than while rendering alerts.
+func ParseRules(data []byte) (*RuleSet, error) {
+	var rs RuleSet
+	if err := yaml.Unmarshal(data, &rs); err != nil {
+		return nil, fmt.Errorf("parsing alert rules: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	for i := range rs.Rules {
+		r := &rs.Rules[i]
+		if err := r.compile(); err != nil {
+			return nil, fmt.Errorf("rule %d (%q): %w", i+1, r.Name, err)
+		}
+		if seen[r.Name] {
+			return nil, fmt.Errorf("rule %d: duplicate name %q", i+1, r.Name)
+		}
+		seen[r.Name] = true
+	}
+	return &rs, nil
+}
+
+// compile validates the rule and caches its compiled regexes.
+func (r *Rule) compile() error {
+	if strings.TrimSpace(r.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if slices.Contains(builtinTypes, r.Name) {
+		return fmt.Errorf("name %q is reserved", r.Name)
+	}
+
+	s := &r.Service
+	if s.Equals == "" && s.Prefix == "" && s.Suffix == "" && s.Contains == "" && s.Regex == "" {
+		return fmt.Errorf("service needs at least one of equals, prefix, suffix, contains, regex")
+	}
+	if s.Regex != "" {
+		re, err := regexp.Compile(s.Regex)
+		if err != nil {
+			return fmt.Errorf("service.regex: %w", err)
+		}
+		s.regex = re
+	}
+
+	if r.TitlePattern != "" {
+		re, err := regexp.Compile(r.TitlePattern)
+		if err != nil {
+			return fmt.Errorf("title_pattern: %w", err)
+		}
+		r.titlePattern = re
+	}
+
+	switch r.Firing.Format {
+	case "":
+		r.Firing.Format = FiringFormatAuto
+	case FiringFormatAuto, FiringFormatAlertmanager, FiringFormatRHOBS, FiringFormatNone:
+	default:
+		return fmt.Errorf("firing.format %q: must be auto, alertmanager, rhobs, or none", r.Firing.Format)
+	}
+	if r.Firing.Field == "" {
+		r.Firing.Field = "firing"
+	}
+
+	for field, sources := range r.Fields {
+		if _, ok := ruleFields[field]; !ok {
+			return fmt.Errorf("fields: unknown field %q", field)
+		}
+		for _, src := range sources {
+			if err := r.validateSource(src); err != nil {
+				return fmt.Errorf("fields.%s: %w", field, err)
+			}
+		}
+	}
+	return nil
+}
+
+// validateSource checks a "<kind>:<key>" source string, including that
+// title and service sources name a group that actually exists.
+func (r *Rule) validateSource(src string) error {
+	kind, key, ok := strings.Cut(src, ":")
+	if !ok || key == "" {
+		return fmt.Errorf("source %q: expected <kind>:<key>", src)
+	}
+	switch kind {
+	case sourceDetail, sourceFiring, sourceLiteral:
+		return nil
+	case sourceTitle:
+		if r.titlePattern == nil || r.titlePattern.SubexpIndex(key) < 0 {
+			return fmt.Errorf("source %q: title_pattern has no group %q", src, key)
+		}
+		return nil
+	case sourceService:
+		if r.Service.regex == nil || r.Service.regex.SubexpIndex(key) < 0 {
+			return fmt.Errorf("source %q: service.regex has no group %q", src, key)
+		}
+		return nil
+	default:
+		return fmt.Errorf("source %q: unknown kind %q (want detail, firing, title, service, literal)", src, kind)
+	}
+}
+
+// matchesService reports whether every configured service matcher matches.
+func (r *Rule) matchesService(serviceSummary string) bool {
+	s := r.Service
+	if s.Equals != "" && serviceSummary != s.Equals {
+		return false
+	}
+	if s.Prefix != "" && !strings.HasPrefix(serviceSummary, s.Prefix) {
+		return false
+	}
+	if s.Suffix != "" && !strings.HasSuffix(serviceSummary, s.Suffix) {
+		return false
+	}
+	if s.Contains != "" && !strings.Contains(serviceSummary, s.Contains) {
+		return false
+	}
+	if s.regex != nil && !s.regex.MatchString(serviceSummary) {
+		return false
+	}
+	return true
+}
+
+// Match returns the first rule matching serviceSummary, or nil.
+func (rs *RuleSet) Match(serviceSummary string) *Rule {
+	if rs == nil {
+		return nil
+	}
+	for i := range rs.Rules {
+		if rs.Rules[i].matchesService(serviceSummary) {
+			return &rs.Rules[i]
+		}
+	}
+	return nil
+}
+
+// NormalizeAlert normalizes alert using rs ahead of the built-in parsers,
+// independent of the rule set installed with SetRules.
+func (rs *RuleSet) NormalizeAlert(serviceSummary string, title string, alert pagerduty.IncidentAlert) NormalizedAlert {
+	return normalizeWith(rs, serviceSummary, title, alert)
+}
+
+// namedGroups returns the named capture groups of re matched against s.
+func namedGroups(re *regexp.Regexp, s string) map[string]string {
+	groups := make(map[string]string)
+	if re == nil {
+		return groups
+	}
+	matches := re.FindStringSubmatch(s)
+	if matches == nil {
+		return groups
+	}
+	for i, name := range re.SubexpNames() {
+		if name != "" && matches[i] != "" {
+			groups[name] = matches[i]
+		}
+	}
+	return groups
+}
+
+// parseFiringAs parses firing text in the given format.
+func parseFiringAs(format, firing string) map[string]string {
+	result := make(map[string]string)
+	if strings.TrimSpace(firing) == "" {
+		return result
+	}
+	switch format {
+	case FiringFormatNone:
+		return result
+	case FiringFormatAlertmanager:
+		parseAlertmanagerFiring(strings.Split(firing, "\n"), result)
+	case FiringFormatRHOBS:
+		parseRHOBSFiring(strings.Split(firing, "\n"), result)
+	default:
+		return ParseFiring(firing)
+	}
+	return result
+}
+
+// apply populates n from the rule's field mappings. For each field the
+// sources are tried in order and the first non-empty value wins.
+func (r *Rule) apply(n *NormalizedAlert, title string, alert pagerduty.IncidentAlert) {
+	// Like parseAppSRE: try the raw title first, then with SRE-added
+	// bracket tags stripped.
+	titleGroups := namedGroups(r.titlePattern, title)
+	if len(titleGroups) == 0 {
+		cleaned, _ := StripBracketPrefixes(title)
+		titleGroups = namedGroups(r.titlePattern, cleaned)
+	}
+	serviceGroups := namedGroups(r.Service.regex, n.ServiceName)
+	firingFields := parseFiringAs(r.Firing.Format, getDetail(r.Firing.Field, alert))
+
+	resolve := func(src string) string {
+		kind, key, _ := strings.Cut(src, ":")
+		switch kind {
+		case sourceDetail:
+			return getDetail(key, alert)
+		case sourceFiring:
+			return firingFields[key]
+		case sourceTitle:
+			return titleGroups[key]
+		case sourceService:
+			return serviceGroups[key]
+		case sourceLiteral:
+			return key
+		}
+		return ""
+	}
+
+	for field, set := range ruleFields {
+		sources, ok := r.Fields[field]
+		if !ok {
+			sources = defaultFieldSources(field)
+		}
+		for _, src := range sources {
+			if v := strings.TrimSpace(resolve(src)); v != "" {
+				set(n, v)
+				break
+			}
+		}
+	}
+
+	if n.AlertName == "" {
+		n.AlertName = ExtractAlertName(title)
+	}
+}
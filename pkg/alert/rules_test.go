@@ -0,0 +1,220 @@
+package alert
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/PagerDuty/go-pagerduty"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRulesYAML = `
+rules:
+  - name: acme_alertmanager
+    service:
+      prefix: acme-
+      regex: '^acme-(?P<env>[a-z]+)-(?P<region>[a-z]+-[a-z]+-\d)$'
+    title_pattern: '^\((?P<severity>\w+)\) (?P<alert_name>\S+) on (?P<cluster_id>\S+)$'
+    firing:
+      format: alertmanager
+    fields:
+      region: [service:region]
+      namespace: [firing:namespace]
+      description: [firing:description, firing:summary]
+      sop_link: [detail:runbook, firing:runbook_url]
+      dashboard_link: [firing:dashboard]
+      cluster_name: [literal:acme-fleet]
+`
+
+func acmeAlert(details map[string]interface{}) pagerduty.IncidentAlert {
+	return pagerduty.IncidentAlert{
+		Status:    "triggered",
+		CreatedAt: "2026-01-02T03:04:05Z",
+		Incident:  pagerduty.APIReference{ID: "PINC1"},
+		Body:      map[string]interface{}{"details": details},
+	}
+}
+
+func TestParseRules_Valid(t *testing.T) {
+	rs, err := ParseRules([]byte(testRulesYAML))
+	require.NoError(t, err)
+	require.Len(t, rs.Rules, 1)
+	assert.Equal(t, FiringFormatAlertmanager, rs.Rules[0].Firing.Format)
+	assert.Equal(t, "firing", rs.Rules[0].Firing.Field, "firing field defaults to \"firing\"")
+}
+
+func TestParseRules_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{"missing name", "rules:\n  - service: {prefix: a}\n", "name is required"},
+		{"reserved name", "rules:\n  - name: unknown\n    service: {prefix: a}\n", "reserved"},
+		{"built-in type name", "rules:\n  - name: osd_hive\n    service: {prefix: a}\n", "reserved"},
+		{"no service matcher", "rules:\n  - name: x\n", "service needs at least one"},
+		{"bad service regex", "rules:\n  - name: x\n    service: {regex: '('}\n", "service.regex"},
+		{"bad title regex", "rules:\n  - name: x\n    service: {prefix: a}\n    title_pattern: '('\n", "title_pattern"},
+		{"bad firing format", "rules:\n  - name: x\n    service: {prefix: a}\n    firing: {format: xml}\n", "firing.format"},
+		{"unknown field", "rules:\n  - name: x\n    service: {prefix: a}\n    fields: {colour: [detail:c]}\n", "unknown field"},
+		{"unknown source kind", "rules:\n  - name: x\n    service: {prefix: a}\n    fields: {region: [env:c]}\n", "unknown kind"},
+		{"malformed source", "rules:\n  - name: x\n    service: {prefix: a}\n    fields: {region: [detail]}\n", "expected <kind>:<key>"},
+		{"missing title group", "rules:\n  - name: x\n    service: {prefix: a}\n    title_pattern: '(?P<a>.)'\n    fields: {region: [title:b]}\n", "no group"},
+		{"missing service group", "rules:\n  - name: x\n    service: {prefix: a}\n    fields: {region: [service:r]}\n", "no group"},
+		{"duplicate name", "rules:\n  - name: x\n    service: {prefix: a}\n  - name: x\n    service: {prefix: b}\n", "duplicate"},
+		{"bad yaml", "rules: [", "parsing alert rules"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseRules([]byte(tt.yaml))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestRuleSet_NormalizeAlert(t *testing.T) {
+	rs, err := ParseRules([]byte(testRulesYAML))
+	require.NoError(t, err)
+
+	firing := "Labels:\n - alertname = DiskFull\n - namespace = acme-storage\nAnnotations:\n - summary = Disk is full\n - runbook_url = https://sop.example.com/DiskFull\n - dashboard = https://grafana.example.com/d/disk\n"
+	a := acmeAlert(map[string]interface{}{"firing": firing, "num_firing": "3"})
+
+	n := rs.NormalizeAlert("acme-prod-us-east-1", "[SL Sent] (CRITICAL) DiskFull on abc-123", a)
+
+	assert.Equal(t, "acme_alertmanager", n.AlertType)
+	assert.Equal(t, "DiskFull", n.AlertName)
+	assert.Equal(t, "abc-123", n.ClusterID)
+	assert.Equal(t, "critical", n.Severity, "severity is lowercased")
+	assert.Equal(t, "us-east-1", n.Region)
+	assert.Equal(t, "acme-storage", n.Namespace)
+	assert.Equal(t, "Disk is full", n.Description, "falls through to the second source")
+	assert.Equal(t, "https://sop.example.com/DiskFull", n.SOPLink)
+	assert.Equal(t, "https://grafana.example.com/d/disk", n.DashboardLink)
+	assert.Equal(t, "acme-fleet", n.ClusterName)
+	assert.Equal(t, 3, n.FiringCount, "firing_count defaults to detail:num_firing")
+	assert.Equal(t, []string{"SL Sent"}, n.Tags)
+	assert.Equal(t, "PINC1", n.IncidentID)
+	assert.Equal(t, "triggered", n.Status)
+}
+
+func TestRuleSet_NormalizeAlert_DetailSourceWins(t *testing.T) {
+	rs, err := ParseRules([]byte(testRulesYAML))
+	require.NoError(t, err)
+
+	firing := "Labels:\n - namespace = ns\nAnnotations:\n - runbook_url = https://firing.example.com\n"
+	a := acmeAlert(map[string]interface{}{"firing": firing, "runbook": "https://detail.example.com"})
+
+	n := rs.NormalizeAlert("acme-prod-us-east-1", "(warning) X on c1", a)
+	assert.Equal(t, "https://detail.example.com", n.SOPLink)
+}
+
+func TestRuleSet_NormalizeAlert_TitleMismatchFallsBack(t *testing.T) {
+	rs, err := ParseRules([]byte(testRulesYAML))
+	require.NoError(t, err)
+
+	a := acmeAlert(map[string]interface{}{"cluster_id": "from-details"})
+	n := rs.NormalizeAlert("acme-prod-us-east-1", "Something odd happened", a)
+
+	assert.Equal(t, "acme_alertmanager", n.AlertType)
+	assert.Equal(t, "from-details", n.ClusterID, "unmapped fields fall back to the same-named detail")
+	assert.Equal(t, "Something odd happened", n.AlertName, "alert name falls back to ExtractAlertName")
+}
+
+func TestRuleSet_NoMatchUsesBuiltins(t *testing.T) {
+	rs, err := ParseRules([]byte(testRulesYAML))
+	require.NoError(t, err)
+
+	a := pagerduty.IncidentAlert{Body: map[string]interface{}{"details": map[string]interface{}{
+		"alert_name": "ClusterOperatorDown",
+		"cluster_id": "uuid-1",
+	}}}
+	n := rs.NormalizeAlert("osd-foo-hive-cluster", "ClusterOperatorDown CRITICAL (1)", a)
+
+	assert.Equal(t, "osd_hive", n.AlertType)
+	assert.Equal(t, "ClusterOperatorDown", n.AlertName)
+	assert.Equal(t, "critical", n.Severity)
+}
+
+func TestRuleSet_FirstMatchWins(t *testing.T) {
+	rs, err := ParseRules([]byte(`
+rules:
+  - name: first
+    service: {contains: shared}
+  - name: second
+    service: {contains: shared}
+`))
+	require.NoError(t, err)
+	assert.Equal(t, "first", rs.Match("my-shared-service").Name)
+	assert.Nil(t, rs.Match("other"))
+}
+
+func TestRuleSet_RulesPrecedeBuiltins(t *testing.T) {
+	rs, err := ParseRules([]byte("rules:\n  - name: custom_hive\n    service: {suffix: -hive-cluster}\n"))
+	require.NoError(t, err)
+
+	n := rs.NormalizeAlert("osd-foo-hive-cluster", "X CRITICAL (1)", pagerduty.IncidentAlert{})
+	assert.Equal(t, "custom_hive", n.AlertType)
+}
+
+func TestServiceMatch_AllMatchersMustMatch(t *testing.T) {
+	rs, err := ParseRules([]byte("rules:\n  - name: x\n    service: {prefix: acme-, suffix: -prod}\n"))
+	require.NoError(t, err)
+	assert.NotNil(t, rs.Match("acme-db-prod"))
+	assert.Nil(t, rs.Match("acme-db-stage"))
+	assert.Nil(t, rs.Match("other-db-prod"))
+}
+
+func TestRuleSet_NilIsSafe(t *testing.T) {
+	var rs *RuleSet
+	assert.Nil(t, rs.Match("anything"))
+	n := rs.NormalizeAlert("app-sre-alertmanager", "[FIRING:1] Foo", pagerduty.IncidentAlert{})
+	assert.Equal(t, "appsre", n.AlertType)
+}
+
+func TestSetRules_AffectsNormalizeAlert(t *testing.T) {
+	rs, err := ParseRules([]byte(testRulesYAML))
+	require.NoError(t, err)
+
+	SetRules(rs)
+	t.Cleanup(func() { SetRules(nil) })
+
+	n := NormalizeAlert("acme-prod-us-east-1", "(warning) Foo on c1", pagerduty.IncidentAlert{})
+	assert.Equal(t, "acme_alertmanager", n.AlertType)
+
+	SetRules(nil)
+	n = NormalizeAlert("acme-prod-us-east-1", "(warning) Foo on c1", pagerduty.IncidentAlert{})
+	assert.Equal(t, "unknown", n.AlertType)
+}
+
+func TestParseFiringAs(t *testing.T) {
+	am := "Labels:\n - a = 1\n"
+	rhobs := "  - b: 2\n"
+
+	assert.Equal(t, "1", parseFiringAs(FiringFormatAlertmanager, am)["a"])
+	assert.Equal(t, "2", parseFiringAs(FiringFormatRHOBS, rhobs)["b"])
+	assert.Equal(t, "1", parseFiringAs(FiringFormatAuto, am)["a"])
+	assert.Empty(t, parseFiringAs(FiringFormatNone, am))
+	assert.Empty(t, parseFiringAs(FiringFormatAuto, "  "))
+}
+
+func TestLoadRules(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "rules.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(testRulesYAML), 0600))
+
+	rs, err := LoadRules(path)
+	require.NoError(t, err)
+	assert.Len(t, rs.Rules, 1)
+
+	_, err = LoadRules(filepath.Join(dir, "missing.yaml"))
+	assert.Error(t, err)
+
+	bad := filepath.Join(dir, "bad.yaml")
+	require.NoError(t, os.WriteFile(bad, []byte("rules:\n  - name: x\n"), 0600))
+	_, err = LoadRules(bad)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), bad, "errors name the offending file")
+}
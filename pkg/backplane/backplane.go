@@ -17,8 +17,66 @@ type Report struct {
 	Data      string `json:"data"`
 }
 
+// ManagedScript is a script backplane can run on a cluster as a managed
+// job.
+type ManagedScript struct {
+	CanonicalName string      `json:"canonicalName"`
+	Description   string      `json:"description"`
+	Author        string      `json:"author"`
+	Envs          []ScriptEnv `json:"envs"`
+}
+
+// ScriptEnv is a parameter of a managed script, passed to it as an
+// environment variable.
+type ScriptEnv struct {
+	Key         string `json:"key"`
+	Description string `json:"description"`
+	Optional    bool   `json:"optional"`
+}
+
+// Managed job statuses; Succeeded, Failed and Killed are final.
+const (
+	JobPending   = "Pending"
+	JobRunning   = "Running"
+	JobSucceeded = "Succeeded"
+	JobFailed    = "Failed"
+	JobKilled    = "Killed"
+)
+
+// ManagedJob is a run of a managed script.
+type ManagedJob struct {
+	JobID     string    `json:"jobId"`
+	JobStatus JobStatus `json:"jobStatus"`
+	ScriptRef ScriptRef `json:"scriptRef"`
+}
+
+// JobStatus is the state of a managed job.
+type JobStatus struct {
+	Status string `json:"status"`
+	Start  string `json:"start"`
+	End    string `json:"end"`
+}
+
+// ScriptRef names the script a job runs.
+type ScriptRef struct {
+	CanonicalName string `json:"canonicalName"`
+}
+
+// Finished reports whether the job has reached a final status.
+func (j ManagedJob) Finished() bool {
+	switch j.JobStatus.Status {
+	case JobSucceeded, JobFailed, JobKilled:
+		return true
+	}
+	return false
+}
+
 // BackplaneClient defines the interface for backplane API operations.
 type BackplaneClient interface {
 	ListReports(ctx context.Context, clusterID string) ([]ReportSummary, error)
 	GetReport(ctx context.Context, clusterID, reportID string) (*Report, error)
+	ListManagedScripts(ctx context.Context, clusterID string) ([]ManagedScript, error)
+	CreateManagedJob(ctx context.Context, clusterID, canonicalName string, params map[string]string) (*ManagedJob, error)
+	GetManagedJob(ctx context.Context, clusterID, jobID string) (*ManagedJob, error)
+	GetManagedJobLogs(ctx context.Context, clusterID, jobID string) (string, error)
 }
@@ -1,6 +1,7 @@
 package backplane
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -63,7 +64,7 @@ func (c *Client) ListReports(ctx context.Context, clusterID string) ([]ReportSum
 	endpoint := fmt.Sprintf("%s/backplane/cluster/%s/reports?last=10", c.config.URL, url.PathEscape(clusterID))
 	log.Debug("backplane.ListReports", "cluster_id", clusterID)
 
-	body, err := c.doRequest(ctx, endpoint)
+	body, err := c.doRequest(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
 		log.Warn("backplane.ListReports failed", "cluster_id", clusterID, "error", err)
 		return nil, fmt.Errorf("list reports for %s: %w", clusterID, err)
@@ -83,7 +84,7 @@ func (c *Client) GetReport(ctx context.Context, clusterID, reportID string) (*Re
 	endpoint := fmt.Sprintf("%s/backplane/cluster/%s/reports/%s", c.config.URL, url.PathEscape(clusterID), url.PathEscape(reportID))
 	log.Debug("backplane.GetReport", "cluster_id", clusterID, "report_id", reportID)
 
-	body, err := c.doRequest(ctx, endpoint)
+	body, err := c.doRequest(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
 		log.Warn("backplane.GetReport failed", "cluster_id", clusterID, "report_id", reportID, "error", err)
 		return nil, fmt.Errorf("get report %s for %s: %w", reportID, clusterID, err)
@@ -99,18 +100,99 @@ func (c *Client) GetReport(ctx context.Context, clusterID, reportID string) (*Re
 	return &report, nil
 }
 
-func (c *Client) doRequest(ctx context.Context, endpoint string) ([]byte, error) {
+type createJobRequest struct {
+	CanonicalName string            `json:"canonicalName"`
+	Parameters    map[string]string `json:"parameters"`
+}
+
+func (c *Client) ListManagedScripts(ctx context.Context, clusterID string) ([]ManagedScript, error) {
+	endpoint := fmt.Sprintf("%s/backplane/script/%s/list", c.config.URL, url.PathEscape(clusterID))
+	log.Debug("backplane.ListManagedScripts", "cluster_id", clusterID)
+
+	body, err := c.doRequest(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		log.Warn("backplane.ListManagedScripts failed", "cluster_id", clusterID, "error", err)
+		return nil, fmt.Errorf("list managed scripts for %s: %w", clusterID, err)
+	}
+
+	var scripts []ManagedScript
+	if err := json.Unmarshal(body, &scripts); err != nil {
+		return nil, fmt.Errorf("decode managed scripts for %s: %w", clusterID, err)
+	}
+	log.Debug("backplane.ListManagedScripts", "cluster_id", clusterID, "count", len(scripts))
+	return scripts, nil
+}
+
+func (c *Client) CreateManagedJob(ctx context.Context, clusterID, canonicalName string, params map[string]string) (*ManagedJob, error) {
+	endpoint := fmt.Sprintf("%s/backplane/script/%s/job", c.config.URL, url.PathEscape(clusterID))
+	log.Debug("backplane.CreateManagedJob", "cluster_id", clusterID, "script", canonicalName)
+
+	if params == nil {
+		params = map[string]string{}
+	}
+	payload, err := json.Marshal(createJobRequest{CanonicalName: canonicalName, Parameters: params})
+	if err != nil {
+		return nil, fmt.Errorf("encode managed job: %w", err)
+	}
+	body, err := c.doRequest(ctx, http.MethodPost, endpoint, payload)
+	if err != nil {
+		log.Warn("backplane.CreateManagedJob failed", "cluster_id", clusterID, "script", canonicalName, "error", err)
+		return nil, fmt.Errorf("create managed job %s on %s: %w", canonicalName, clusterID, err)
+	}
+
+	var job ManagedJob
+	if err := json.Unmarshal(body, &job); err != nil {
+		return nil, fmt.Errorf("decode managed job: %w", err)
+	}
+	log.Info("backplane.CreateManagedJob", "cluster_id", clusterID, "script", canonicalName, "job_id", job.JobID)
+	return &job, nil
+}
+
+func (c *Client) GetManagedJob(ctx context.Context, clusterID, jobID string) (*ManagedJob, error) {
+	endpoint := fmt.Sprintf("%s/backplane/script/%s/job/%s", c.config.URL, url.PathEscape(clusterID), url.PathEscape(jobID))
+
+	body, err := c.doRequest(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("get managed job %s on %s: %w", jobID, clusterID, err)
+	}
+
+	var job ManagedJob
+	if err := json.Unmarshal(body, &job); err != nil {
+		return nil, fmt.Errorf("decode managed job %s: %w", jobID, err)
+	}
+	log.Debug("backplane.GetManagedJob", "cluster_id", clusterID, "job_id", jobID, "status", job.JobStatus.Status)
+	return &job, nil
+}
+
+func (c *Client) GetManagedJobLogs(ctx context.Context, clusterID, jobID string) (string, error) {
+	endpoint := fmt.Sprintf("%s/backplane/script/%s/job/%s/logs", c.config.URL, url.PathEscape(clusterID), url.PathEscape(jobID))
+
+	body, err := c.doRequest(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", fmt.Errorf("get logs of managed job %s on %s: %w", jobID, clusterID, err)
+	}
+	return string(body), nil
+}
+
+func (c *Client) doRequest(ctx context.Context, method, endpoint string, payload []byte) ([]byte, error) {
 	token, err := c.tokenFunc()
 	if err != nil {
 		log.Warn("backplane.doRequest", "msg", "token acquisition failed", "error", err)
 		return nil, fmt.Errorf("get access token: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	var reqBody io.Reader
+	if payload != nil {
+		reqBody = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reqBody)
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
 	req.Header.Set("Authorization", "Bearer "+token)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
 
 	log.Debug("backplane.doRequest", "endpoint", endpoint)
 	resp, err := c.httpClient.Do(req)
@@ -127,7 +209,7 @@ func (c *Client) doRequest(ctx context.Context, endpoint string) ([]byte, error)
 
 	log.Debug("backplane.doRequest", "endpoint", endpoint, "status", resp.StatusCode, "body_bytes", len(body))
 
-	if resp.StatusCode != http.StatusOK {
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
 		log.Warn("backplane.doRequest", "msg", "unexpected status", "endpoint", endpoint, "status", resp.StatusCode)
 		return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(body))
 	}
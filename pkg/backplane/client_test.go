@@ -184,3 +184,69 @@ func TestClient_GetReport_ServerError(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "unexpected status 404")
 }
+
+// fakeJobsAPI is an httptest fake of the backplane managed job endpoints.
+func fakeJobsAPI(t *testing.T) *httptest.Server {
+	t.Helper()
+	var created createJobRequest
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /backplane/script/{cluster}/list", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+		assert.Equal(t, "c1", r.PathValue("cluster"))
+		_ = json.NewEncoder(w).Encode([]ManagedScript{
+			{CanonicalName: "SREP/drain-node", Description: "drain", Envs: []ScriptEnv{{Key: "NODE"}}},
+		})
+	})
+	mux.HandleFunc("POST /backplane/script/{cluster}/job", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&created))
+		if created.CanonicalName != "SREP/drain-node" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte("unknown script"))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(ManagedJob{JobID: "job-1", JobStatus: JobStatus{Status: JobPending}})
+	})
+	mux.HandleFunc("GET /backplane/script/{cluster}/job/{job}", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(ManagedJob{
+			JobID:     r.PathValue("job"),
+			JobStatus: JobStatus{Status: JobSucceeded, End: "2026-10-01T12:00:00Z"},
+			ScriptRef: ScriptRef{CanonicalName: created.CanonicalName},
+		})
+	})
+	mux.HandleFunc("GET /backplane/script/{cluster}/job/{job}/logs", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, "draining %s\ndone\n", created.Parameters["NODE"])
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClient_ManagedJobs(t *testing.T) {
+	server := fakeJobsAPI(t)
+	client := NewClient(&Config{URL: server.URL}, func() (string, error) { return "test-token", nil })
+	ctx := context.Background()
+
+	scripts, err := client.ListManagedScripts(ctx, "c1")
+	require.NoError(t, err)
+	require.Len(t, scripts, 1)
+	assert.Equal(t, "NODE", scripts[0].Envs[0].Key)
+
+	job, err := client.CreateManagedJob(ctx, "c1", "SREP/drain-node", map[string]string{"NODE": "worker-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "job-1", job.JobID)
+	assert.False(t, job.Finished())
+
+	job, err = client.GetManagedJob(ctx, "c1", "job-1")
+	require.NoError(t, err)
+	assert.True(t, job.Finished())
+	assert.Equal(t, "SREP/drain-node", job.ScriptRef.CanonicalName)
+
+	logs, err := client.GetManagedJobLogs(ctx, "c1", "job-1")
+	require.NoError(t, err)
+	assert.Equal(t, "draining worker-1\ndone\n", logs)
+
+	_, err = client.CreateManagedJob(ctx, "c1", "SREP/unknown", nil)
+	assert.ErrorContains(t, err, "unexpected status 400: unknown script")
+}
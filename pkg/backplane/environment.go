@@ -0,0 +1,101 @@
+package backplane
+
+import (
+	"context"
+	"sync"
+
+	"github.com/charmbracelet/log"
+)
+
+// EnvironmentClient sends each cluster's requests to the backplane of the
+// OCM environment the cluster belongs to. Clients for environments other
+// than the primary are connected on first use.
+type EnvironmentClient struct {
+	primary    BackplaneClient
+	primaryEnv string
+	envOf      func(clusterID string) string
+	connect    func(env string) (BackplaneClient, error)
+
+	mu      sync.Mutex
+	clients map[string]BackplaneClient
+}
+
+// NewEnvironmentClient routes clusters envOf places outside primaryEnv to
+// clients built by connect; everything else goes to primary.
+func NewEnvironmentClient(primary BackplaneClient, primaryEnv string, envOf func(clusterID string) string, connect func(env string) (BackplaneClient, error)) *EnvironmentClient {
+	return &EnvironmentClient{
+		primary:    primary,
+		primaryEnv: primaryEnv,
+		envOf:      envOf,
+		connect:    connect,
+		clients:    make(map[string]BackplaneClient),
+	}
+}
+
+func (c *EnvironmentClient) clientFor(clusterID string) (BackplaneClient, error) {
+	env := c.envOf(clusterID)
+	if env == "" || env == c.primaryEnv {
+		return c.primary, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if client, ok := c.clients[env]; ok {
+		return client, nil
+	}
+	client, err := c.connect(env)
+	if err != nil {
+		return nil, err
+	}
+	log.Debug("backplane.EnvironmentClient", "msg", "connected", "environment", env)
+	c.clients[env] = client
+	return client, nil
+}
+
+func (c *EnvironmentClient) ListReports(ctx context.Context, clusterID string) ([]ReportSummary, error) {
+	client, err := c.clientFor(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	return client.ListReports(ctx, clusterID)
+}
+
+func (c *EnvironmentClient) GetReport(ctx context.Context, clusterID, reportID string) (*Report, error) {
+	client, err := c.clientFor(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	return client.GetReport(ctx, clusterID, reportID)
+}
+
+func (c *EnvironmentClient) ListManagedScripts(ctx context.Context, clusterID string) ([]ManagedScript, error) {
+	client, err := c.clientFor(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	return client.ListManagedScripts(ctx, clusterID)
+}
+
+func (c *EnvironmentClient) CreateManagedJob(ctx context.Context, clusterID, canonicalName string, params map[string]string) (*ManagedJob, error) {
+	client, err := c.clientFor(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	return client.CreateManagedJob(ctx, clusterID, canonicalName, params)
+}
+
+func (c *EnvironmentClient) GetManagedJob(ctx context.Context, clusterID, jobID string) (*ManagedJob, error) {
+	client, err := c.clientFor(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	return client.GetManagedJob(ctx, clusterID, jobID)
+}
+
+func (c *EnvironmentClient) GetManagedJobLogs(ctx context.Context, clusterID, jobID string) (string, error) {
+	client, err := c.clientFor(clusterID)
+	if err != nil {
+		return "", err
+	}
+	return client.GetManagedJobLogs(ctx, clusterID, jobID)
+}
@@ -0,0 +1,73 @@
+package backplane
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvironmentClient_ImplementsInterface(t *testing.T) {
+	var _ BackplaneClient = &EnvironmentClient{}
+}
+
+func TestEnvironmentClient_Routing(t *testing.T) {
+	primary := NewMockClient()
+	primary.Reports["prod-1"] = []ReportSummary{{ReportID: "rpt-prod"}}
+	stage := NewMockClient()
+	stage.Reports["stage-1"] = []ReportSummary{{ReportID: "rpt-stage"}}
+
+	envs := map[string]string{"prod-1": "production", "stage-1": "staging", "int-1": "integration"}
+	connects := 0
+	client := NewEnvironmentClient(primary, "production", func(id string) string { return envs[id] }, func(env string) (BackplaneClient, error) {
+		connects++
+		if env == "staging" {
+			return stage, nil
+		}
+		return nil, errors.New("no backplane for " + env)
+	})
+
+	reports, err := client.ListReports(context.Background(), "prod-1")
+	require.NoError(t, err)
+	assert.Equal(t, "rpt-prod", reports[0].ReportID)
+
+	reports, err = client.ListReports(context.Background(), "stage-1")
+	require.NoError(t, err)
+	assert.Equal(t, "rpt-stage", reports[0].ReportID)
+
+	report, err := client.GetReport(context.Background(), "stage-1", "rpt-stage")
+	require.NoError(t, err)
+	assert.Equal(t, "rpt-stage", report.ReportID)
+	assert.Equal(t, 1, connects, "the staging client is connected once")
+
+	_, err = client.ListReports(context.Background(), "int-1")
+	assert.ErrorContains(t, err, "no backplane for integration")
+
+	reports, err = client.ListReports(context.Background(), "unknown")
+	require.NoError(t, err)
+	assert.Empty(t, reports, "clusters not looked up go to the primary")
+}
+
+func TestEnvironmentClient_RoutesManagedJobs(t *testing.T) {
+	primary := NewMockClient()
+	stage := NewMockClient()
+	stage.Scripts = []ManagedScript{{CanonicalName: "SREP/check"}}
+	client := NewEnvironmentClient(primary, "production", func(string) string { return "staging" }, func(string) (BackplaneClient, error) {
+		return stage, nil
+	})
+	ctx := context.Background()
+
+	scripts, err := client.ListManagedScripts(ctx, "stage-1")
+	require.NoError(t, err)
+	assert.Len(t, scripts, 1)
+
+	job, err := client.CreateManagedJob(ctx, "stage-1", "SREP/check", nil)
+	require.NoError(t, err)
+	_, err = client.GetManagedJob(ctx, "stage-1", job.JobID)
+	require.NoError(t, err)
+	logs, err := client.GetManagedJobLogs(ctx, "stage-1", job.JobID)
+	require.NoError(t, err)
+	assert.Contains(t, logs, "SREP/check")
+}
@@ -4,13 +4,35 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 )
 
 // MockClient implements BackplaneClient for testing and dev mode.
 type MockClient struct {
 	Reports map[string][]ReportSummary
+	// Scripts are offered for every cluster. A created job advances one
+	// status per GetManagedJob (Pending, Running, Succeeded) and logs a
+	// line each time.
+	Scripts []ManagedScript
+	// JobErr makes CreateManagedJob fail.
+	JobErr error
+
+	mu      sync.Mutex // guards jobs, polled from tea.Cmd goroutines
+	jobs    map[string]*mockJob
+	nextJob int
+}
+
+type mockJob struct {
+	clusterID string
+	job       ManagedJob
+	params    map[string]string
+	logs      []string
 }
 
 // NewMockClient creates a MockClient with initialized maps.
@@ -46,6 +68,78 @@ func (m *MockClient) GetReport(_ context.Context, clusterID, reportID string) (*
 	return nil, fmt.Errorf("report %q not found for cluster %q", reportID, clusterID)
 }
 
+func (m *MockClient) ListManagedScripts(_ context.Context, _ string) ([]ManagedScript, error) {
+	return slices.Clone(m.Scripts), nil
+}
+
+func (m *MockClient) CreateManagedJob(_ context.Context, clusterID, canonicalName string, params map[string]string) (*ManagedJob, error) {
+	if m.JobErr != nil {
+		return nil, m.JobErr
+	}
+	if !slices.ContainsFunc(m.Scripts, func(s ManagedScript) bool { return s.CanonicalName == canonicalName }) {
+		return nil, fmt.Errorf("managed script %q not found", canonicalName)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.jobs == nil {
+		m.jobs = make(map[string]*mockJob)
+	}
+	m.nextJob++
+	job := ManagedJob{
+		JobID:     fmt.Sprintf("job-mock-%d", m.nextJob),
+		JobStatus: JobStatus{Status: JobPending, Start: time.Now().UTC().Format(time.RFC3339)},
+		ScriptRef: ScriptRef{CanonicalName: canonicalName},
+	}
+	m.jobs[job.JobID] = &mockJob{
+		clusterID: clusterID,
+		job:       job,
+		params:    maps.Clone(params),
+		logs:      []string{fmt.Sprintf("running %s on %s", canonicalName, clusterID)},
+	}
+	return &job, nil
+}
+
+func (m *MockClient) GetManagedJob(_ context.Context, clusterID, jobID string) (*ManagedJob, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	j, ok := m.jobs[jobID]
+	if !ok || j.clusterID != clusterID {
+		return nil, fmt.Errorf("managed job %q not found for cluster %q", jobID, clusterID)
+	}
+	switch j.job.JobStatus.Status {
+	case JobPending:
+		j.job.JobStatus.Status = JobRunning
+		j.logs = append(j.logs, "collecting data...")
+	case JobRunning:
+		j.job.JobStatus.Status = JobSucceeded
+		j.job.JobStatus.End = time.Now().UTC().Format(time.RFC3339)
+		j.logs = append(j.logs, "done")
+	}
+	job := j.job
+	return &job, nil
+}
+
+func (m *MockClient) GetManagedJobLogs(_ context.Context, clusterID, jobID string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	j, ok := m.jobs[jobID]
+	if !ok || j.clusterID != clusterID {
+		return "", fmt.Errorf("managed job %q not found for cluster %q", jobID, clusterID)
+	}
+	return strings.Join(j.logs, "\n") + "\n", nil
+}
+
+// JobParams returns the parameters a job was created with.
+func (m *MockClient) JobParams(jobID string) map[string]string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if j, ok := m.jobs[jobID]; ok {
+		return maps.Clone(j.params)
+	}
+	return nil
+}
+
 type fixtureReport struct {
 	ReportID  string `json:"report_id"`
 	Summary   string `json:"summary"`
@@ -56,6 +150,10 @@ type fixtureReport struct {
 func LoadMockClientFromFixtures(dir string) (*MockClient, error) {
 	mock := NewMockClient()
 
+	if err := loadScriptFixtures(filepath.Join(dir, "managedscripts.json"), mock); err != nil {
+		return mock, err
+	}
+
 	path := filepath.Join(dir, "clusterreports.json")
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -80,3 +178,17 @@ func LoadMockClientFromFixtures(dir string) (*MockClient, error) {
 
 	return mock, nil
 }
+
+func loadScriptFixtures(path string, mock *MockClient) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("loading managed script fixtures: %w", err)
+	}
+	if err := json.Unmarshal(data, &mock.Scripts); err != nil {
+		return fmt.Errorf("parsing managed script fixtures: %w", err)
+	}
+	return nil
+}
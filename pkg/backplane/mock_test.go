@@ -62,3 +62,55 @@ func TestLoadMockClientFromFixtures_MissingFile(t *testing.T) {
 	require.NoError(t, err)
 	assert.Empty(t, mock.Reports)
 }
+
+func TestLoadMockClientFromFixtures_ManagedScripts(t *testing.T) {
+	mock, err := LoadMockClientFromFixtures(filepath.Join("..", "..", "testdata", "fixtures"))
+	require.NoError(t, err)
+
+	scripts, err := mock.ListManagedScripts(context.Background(), "any-cluster")
+	require.NoError(t, err)
+	require.NotEmpty(t, scripts)
+	assert.Equal(t, "SREP/must-gather-lite", scripts[0].CanonicalName)
+}
+
+func TestMockClient_ManagedJob(t *testing.T) {
+	mock := NewMockClient()
+	mock.Scripts = []ManagedScript{{CanonicalName: "SREP/check"}}
+	ctx := context.Background()
+
+	_, err := mock.CreateManagedJob(ctx, "cluster-1", "SREP/unknown", nil)
+	assert.Error(t, err)
+
+	job, err := mock.CreateManagedJob(ctx, "cluster-1", "SREP/check", map[string]string{"NODE": "n1"})
+	require.NoError(t, err)
+	assert.Equal(t, JobPending, job.JobStatus.Status)
+	assert.Equal(t, map[string]string{"NODE": "n1"}, mock.JobParams(job.JobID))
+
+	var statuses []string
+	for range 3 {
+		j, err := mock.GetManagedJob(ctx, "cluster-1", job.JobID)
+		require.NoError(t, err)
+		statuses = append(statuses, j.JobStatus.Status)
+	}
+	assert.Equal(t, []string{JobRunning, JobSucceeded, JobSucceeded}, statuses)
+
+	logs, err := mock.GetManagedJobLogs(ctx, "cluster-1", job.JobID)
+	require.NoError(t, err)
+	assert.Contains(t, logs, "running SREP/check on cluster-1")
+	assert.Contains(t, logs, "done")
+
+	_, err = mock.GetManagedJob(ctx, "cluster-2", job.JobID)
+	assert.Error(t, err, "jobs belong to their cluster")
+
+	mock.JobErr = assert.AnError
+	_, err = mock.CreateManagedJob(ctx, "cluster-1", "SREP/check", nil)
+	assert.ErrorIs(t, err, assert.AnError)
+}
+
+func TestManagedJob_Finished(t *testing.T) {
+	for status, finished := range map[string]bool{
+		JobPending: false, JobRunning: false, JobSucceeded: true, JobFailed: true, JobKilled: true,
+	} {
+		assert.Equal(t, finished, ManagedJob{JobStatus: JobStatus{Status: status}}.Finished(), status)
+	}
+}
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultAutoMergeWindow bounds how far apart two incidents may have been
+// created for a rule without an explicit window to merge them.
+const defaultAutoMergeWindow = time.Hour
+
+// AutoMergeRule describes duplicates that are always safe to merge without
+// asking: a newly arriving incident whose alert matches the rule is merged
+// into the oldest open incident carrying the same alert on the same cluster
+// (and namespace) created within Within of it.
+//
+// Auto-merge acts on PagerDuty with no human in the loop, so a rule set is
+// only live once it has been reviewed (see AutoMergeDigest); until then it
+// runs as a dry run.
+type AutoMergeRule struct {
+	Name      string        `json:"name"`
+	AlertName string        `json:"alert_name"`
+	AlertType string        `json:"alert_type,omitempty"`
+	Service   string        `json:"service,omitempty"`
+	Within    time.Duration `json:"within"`
+}
+
+type rawAutoMergeRule struct {
+	Name      string `yaml:"name"`
+	AlertName string `yaml:"alert_name"`
+	AlertType string `yaml:"alert_type"`
+	Service   string `yaml:"service"`
+	Within    string `yaml:"within"`
+}
+
+// ParseAutoMergeRules validates the auto_merge_rules config value as read by
+// viper (a list of maps). A nil value means no rules.
+func ParseAutoMergeRules(raw any) ([]AutoMergeRule, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	// Round-trip through YAML so viper's loosely-typed maps decode into the
+	// struct with the same key names a user writes in srepd.yaml.
+	data, err := yaml.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid auto_merge_rules: %w", err)
+	}
+	var entries []rawAutoMergeRule
+	if err := yaml.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("invalid auto_merge_rules: expected a list of rules: %w", err)
+	}
+
+	rules := make([]AutoMergeRule, 0, len(entries))
+	seen := make(map[string]bool, len(entries))
+	for i, e := range entries {
+		if e.Name == "" {
+			return nil, fmt.Errorf("auto_merge_rules[%d]: name is required", i)
+		}
+		if seen[e.Name] {
+			return nil, fmt.Errorf("auto_merge_rules: duplicate rule name %q", e.Name)
+		}
+		seen[e.Name] = true
+		if e.AlertName == "" {
+			return nil, fmt.Errorf("auto_merge_rules %q: alert_name is required", e.Name)
+		}
+		within := defaultAutoMergeWindow
+		if e.Within != "" {
+			d, err := time.ParseDuration(e.Within)
+			if err != nil {
+				return nil, fmt.Errorf("auto_merge_rules %q: invalid within %q: %w", e.Name, e.Within, err)
+			}
+			if d <= 0 {
+				return nil, fmt.Errorf("auto_merge_rules %q: within must be positive", e.Name)
+			}
+			within = d
+		}
+		rules = append(rules, AutoMergeRule{
+			Name:      e.Name,
+			AlertName: e.AlertName,
+			AlertType: e.AlertType,
+			Service:   e.Service,
+			Within:    within,
+		})
+	}
+	return rules, nil
+}
+
+// AutoMergeDigest fingerprints a parsed rule set. The digest recorded in
+// auto_merge_rules_reviewed must match before auto-merge acts, so editing
+// any rule — by hand or by a pasted config — needs a fresh review.
+func AutoMergeDigest(rules []AutoMergeRule) string {
+	if len(rules) == 0 {
+		return ""
+	}
+	data, err := json.Marshal(rules)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(data))
+}
+
+// Describe renders a rule for review and logging.
+func (r AutoMergeRule) Describe() string {
+	s := fmt.Sprintf("%s: merge repeat %s", r.Name, r.AlertName)
+	if r.AlertType != "" {
+		s += fmt.Sprintf(" (%s)", r.AlertType)
+	}
+	if r.Service != "" {
+		s += fmt.Sprintf(" on service %s", r.Service)
+	}
+	return s + fmt.Sprintf(" for the same cluster within %s", r.Within)
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func viperStyleRules(t *testing.T, doc string) any {
+	t.Helper()
+	var raw map[string]any
+	require.NoError(t, yaml.Unmarshal([]byte(doc), &raw))
+	return raw["auto_merge_rules"]
+}
+
+func TestParseAutoMergeRules(t *testing.T) {
+	rules, err := ParseAutoMergeRules(viperStyleRules(t, `
+auto_merge_rules:
+  - name: co-down
+    alert_name: ClusterOperatorDown
+  - name: etcd
+    alert_name: etcdMembersDown
+    alert_type: osd_hive
+    service: osd-prod-hive
+    within: 30m
+`))
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+
+	assert.Equal(t, AutoMergeRule{Name: "co-down", AlertName: "ClusterOperatorDown", Within: time.Hour}, rules[0], "within defaults to 1h")
+	assert.Equal(t, 30*time.Minute, rules[1].Within)
+	assert.Equal(t, "osd_hive", rules[1].AlertType)
+	assert.Equal(t, "osd-prod-hive", rules[1].Service)
+}
+
+func TestParseAutoMergeRules_Nil(t *testing.T) {
+	rules, err := ParseAutoMergeRules(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, rules)
+}
+
+func TestParseAutoMergeRules_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want string
+	}{
+		{"not a list", "auto_merge_rules: {name: x}", "expected a list"},
+		{"missing name", "auto_merge_rules: [{alert_name: X}]", "name is required"},
+		{"missing alert", "auto_merge_rules: [{name: x}]", "alert_name is required"},
+		{"duplicate", "auto_merge_rules: [{name: x, alert_name: X}, {name: x, alert_name: Y}]", "duplicate rule name"},
+		{"bad window", "auto_merge_rules: [{name: x, alert_name: X, within: soon}]", "invalid within"},
+		{"negative window", "auto_merge_rules: [{name: x, alert_name: X, within: -1h}]", "must be positive"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseAutoMergeRules(viperStyleRules(t, tt.doc))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestAutoMergeDigest(t *testing.T) {
+	a := []AutoMergeRule{{Name: "x", AlertName: "X", Within: time.Hour}}
+	b := []AutoMergeRule{{Name: "x", AlertName: "X", Within: 2 * time.Hour}}
+
+	assert.Empty(t, AutoMergeDigest(nil))
+	assert.Len(t, AutoMergeDigest(a), 64)
+	assert.Equal(t, AutoMergeDigest(a), AutoMergeDigest([]AutoMergeRule{{Name: "x", AlertName: "X", Within: time.Hour}}))
+	assert.NotEqual(t, AutoMergeDigest(a), AutoMergeDigest(b), "any edit invalidates the review")
+}
+
+func TestAutoMergeRuleDescribe(t *testing.T) {
+	r := AutoMergeRule{Name: "co", AlertName: "ClusterOperatorDown", AlertType: "osd_hive", Service: "hive", Within: time.Hour}
+	assert.Equal(t, "co: merge repeat ClusterOperatorDown (osd_hive) on service hive for the same cluster within 1h0m0s", r.Describe())
+}
@@ -48,7 +48,11 @@ var (
 		"editor":                             fmt.Sprintf("Editor to use for notes (default: %v)", DefaultOptionalKeys["editor"]),
 		"terminal":                           fmt.Sprintf("Terminal to use for exec commands (default: %v)", DefaultOptionalKeys["terminal"]),
 		"cluster_login_command":              fmt.Sprintf("Cluster login command (default: %v)", DefaultOptionalKeys["cluster_login_command"]),
-		"toolbox_mode":                       fmt.Sprintf("Toolbox detection mode: auto, true, false (default: %v)", DefaultOptionalKeys["toolbox_mode"]),
+		"toolbox_mode":                       fmt.Sprintf("Container host-exec mode: auto, toolbox (or true), distrobox, custom, false (default: %v)", DefaultOptionalKeys["toolbox_mode"]),
+		"host_exec_command":                  "Prefix that runs a command on the host with toolbox_mode: custom (e.g. podman-host)",
+		"ssh_bastion":                        "SSH host cluster logins and custom actions run on, with optional ssh options before it (e.g. -p 2222 sre@bastion)",
+		"update_channel":                     "Releases offered by srepd update and the update banner: stable or prerelease (default: stable)",
+		"update_skip_version":                "Release the update banner stays hidden for (set by :update skip)",
 		"chord_prefix":                       fmt.Sprintf("Chord prefix key for multi-key commands (default: %v)", DefaultOptionalKeys["chord_prefix"]),
 		"flag_marker":                        fmt.Sprintf("Prefix marker for flagged incidents (default: %v, alt: |►)", DefaultOptionalKeys["flag_marker"]),
 		"agent_cli_command":                  fmt.Sprintf("CLI agent command for :agent queries (default: %v)", DefaultOptionalKeys["agent_cli_command"]),
@@ -67,6 +71,17 @@ var (
 		"custom_service_escalation_policies": "Per-service silent policy overrides (service ID → policy ID)",
 		"watcher_max_tool_turns":             "Maximum tool-use turns per watcher investigation (default: 6). Values ≤ 0 are clamped to 6.",
 		"watcher_investigation_timeout":      "Timeout for a single watcher investigation (default: 90s)",
+		"alert_rules_file":                   "Path to user-defined alert type rules, evaluated before the built-in parsers (default: ~/.config/srepd/alert_rules.yaml)",
+		"service_log_templates_file":         "Path to user-defined service log templates for :sl, added to the built-in ones (default: ~/.config/srepd/servicelog_templates.yaml)",
+		"service_log_sent_tag":               "Tag the incident [SL Sent] after :sl posts a service log (default: true)",
+		"ocm_environments":                   "Extra OCM environments (staging, integration) clusters are looked up in when production does not know them (see docs/ocm-environments.md)",
+		"ocm_cache":                          "Keep OCM cluster data in a disk cache across restarts (default: true)",
+		"ocm_cache_ttl":                      "Per-kind OCM cache TTLs: cluster (24h), service_logs (5m), limited_support (15m), details (1h)",
+		"auto_merge_rules":                   "Rules for duplicates srepd merges automatically when a new incident arrives (see docs/auto-merge.md)",
+		"auto_merge_dry_run":                 "Only log what auto_merge_rules would merge (default: false; forced on until the rules are reviewed)",
+		"auto_merge_rules_reviewed":          "Digest of the reviewed auto_merge_rules, written by 'srepd automerge review'",
+		"custom_actions":                     "Per-incident commands run in a terminal, in the background or with captured output, optionally bound to a chord key (see docs/custom-actions.md)",
+		"embedded_terminal":                  "Open cluster logins in a terminal pane inside srepd instead of a terminal window; Linux only (default: false)",
 		"ai_permission_mode":                 "AI tool policy mode: plan (read-only), interactive (reads allowed, writes ask), auto (per allowlist), custom (default: interactive)",
 		"ai_auto_allow_tools":                "Tool names auto-allowed in auto/custom AI permission mode (empty = none)",
 		"ai_allowed_command_prefixes":        "Command prefixes allowed in auto mode (unused until phase 415, defined for schema stability)",
@@ -84,9 +99,11 @@ type ExistingConfig struct {
 	Terminal        string
 	Editor          string
 	AgentCLICommand string
-	// ClusterLoginCommand has no wizard step; it flows from an existing
-	// config or a team preset straight through to the write path.
+	// ClusterLoginCommand and CustomActions have no wizard step; they flow
+	// from an existing config or a team preset straight through to the
+	// write path.
 	ClusterLoginCommand string
+	CustomActions       []CustomAction
 }
 
 func ResolveExistingConfig(
@@ -184,6 +201,7 @@ type ResolvedValues struct {
 	AgentCLICommand     string
 	AgentTouched        bool
 	ClusterLoginCommand string
+	CustomActions       []CustomAction
 }
 
 func ResolveFinalValues(existing ExistingConfig, inputs WizardInputs) (ResolvedValues, error) {
@@ -230,24 +248,27 @@ func ResolveFinalValues(existing ExistingConfig, inputs WizardInputs) (ResolvedV
 		rv.AgentCLICommand = strings.TrimSpace(inputs.AgentInput)
 	}
 	rv.ClusterLoginCommand = existing.ClusterLoginCommand
+	rv.CustomActions = existing.CustomActions
 
 	return rv, nil
 }
 
 type ConfigChanges struct {
-	TokenChanged        bool
-	TeamsChanged        bool
-	SilentChanged       bool
-	CustomChanged       bool
-	TerminalChanged     bool
-	EditorChanged       bool
-	AgentChanged        bool
-	ClusterLoginChanged bool
+	TokenChanged         bool
+	TeamsChanged         bool
+	SilentChanged        bool
+	CustomChanged        bool
+	TerminalChanged      bool
+	EditorChanged        bool
+	AgentChanged         bool
+	ClusterLoginChanged  bool
+	CustomActionsChanged bool
 }
 
 func (c ConfigChanges) AnyChanged() bool {
 	return c.TokenChanged || c.TeamsChanged || c.SilentChanged || c.CustomChanged ||
-		c.TerminalChanged || c.EditorChanged || c.AgentChanged || c.ClusterLoginChanged
+		c.TerminalChanged || c.EditorChanged || c.AgentChanged || c.ClusterLoginChanged ||
+		c.CustomActionsChanged
 }
 
 func DetectChangesForNewFile(final ResolvedValues) ConfigChanges {
@@ -259,6 +280,8 @@ func DetectChangesForNewFile(final ResolvedValues) ConfigChanges {
 		TerminalChanged: final.Terminal != "",
 		EditorChanged:   final.Editor != "",
 		AgentChanged:    final.AgentTouched,
+		// Custom actions only come from a preset on a new file.
+		CustomActionsChanged: len(final.CustomActions) > 0,
 	}
 }
 
@@ -468,6 +491,13 @@ func MergeIntoExistingConfig(existingData []byte, final ResolvedValues, changes
 		}
 	}
 
+	if changes.CustomActionsChanged && len(final.CustomActions) > 0 {
+		data, err = UpsertCustomActionsInConfig(data, final.CustomActions)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update custom actions: %w", err)
+		}
+	}
+
 	if changes.SilentChanged || changes.CustomChanged {
 		data = CommentOutOldPolicies(data)
 	}
@@ -581,6 +611,13 @@ func BuildSummary(existing ExistingConfig, final ResolvedValues, changes ConfigC
 	if changes.EditorChanged && final.Editor != "" {
 		fmt.Fprintf(&sb, "  Editor:         %s (changed)\n", final.Editor)
 	}
+	if changes.CustomActionsChanged && len(final.CustomActions) > 0 {
+		names := make([]string, 0, len(final.CustomActions))
+		for _, a := range final.CustomActions {
+			names = append(names, a.Name)
+		}
+		fmt.Fprintf(&sb, "  Custom actions: %s (changed)\n", strings.Join(names, ", "))
+	}
 	if changes.AgentChanged {
 		agentDisplay := final.AgentCLICommand
 		if agentDisplay == "" {
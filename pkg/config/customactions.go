@@ -0,0 +1,144 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Custom action modes: how a custom action's command runs.
+const (
+	// CustomActionTerminal opens the command in a terminal window, like a
+	// cluster login.
+	CustomActionTerminal = "terminal"
+	// CustomActionBackground runs the command without a terminal and
+	// reports only whether it succeeded.
+	CustomActionBackground = "background"
+	// CustomActionCapture runs the command without a terminal and shows
+	// its output.
+	CustomActionCapture = "capture"
+)
+
+// CustomAction is a user-defined per-incident command, e.g. osdctl cluster
+// context or a must-gather helper. Command may use the launcher
+// placeholders (%%CLUSTER_ID%%, %%INCIDENT_ID%%) and those of the
+// incident's normalized alert (%%ALERT_NAME%% and friends). Key is an
+// optional chord key that runs the action.
+type CustomAction struct {
+	Name    string `yaml:"name"`
+	Command string `yaml:"command"`
+	Mode    string `yaml:"mode,omitempty"`
+	Key     string `yaml:"key,omitempty"`
+}
+
+// NeedsCluster reports whether the command refers to a cluster.
+func (a CustomAction) NeedsCluster() bool {
+	return strings.Contains(a.Command, "%%CLUSTER_ID%%")
+}
+
+// Describe renders an action for help text and the preset review.
+func (a CustomAction) Describe() string {
+	s := fmt.Sprintf("%s (%s): %s", a.Name, a.Mode, a.Command)
+	if a.Key != "" {
+		s += fmt.Sprintf(" [key %s]", a.Key)
+	}
+	return s
+}
+
+// ParseCustomActions validates the custom_actions config value as read by
+// viper (a list of maps). A nil value means no actions. The mode defaults
+// to terminal.
+func ParseCustomActions(raw any) ([]CustomAction, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	// Round-trip through YAML so viper's loosely-typed maps decode into the
+	// struct with the same key names a user writes in srepd.yaml.
+	data, err := yaml.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid custom_actions: %w", err)
+	}
+	var entries []CustomAction
+	if err := yaml.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("invalid custom_actions: expected a list of actions: %w", err)
+	}
+
+	names := make(map[string]bool, len(entries))
+	keys := make(map[string]string, len(entries))
+	for i := range entries {
+		a := &entries[i]
+		a.Name = strings.TrimSpace(a.Name)
+		a.Command = strings.TrimSpace(a.Command)
+		a.Mode = strings.ToLower(strings.TrimSpace(a.Mode))
+		a.Key = strings.TrimSpace(a.Key)
+
+		if a.Name == "" {
+			return nil, fmt.Errorf("custom_actions[%d]: name is required", i)
+		}
+		if strings.ContainsAny(a.Name, " \t") {
+			return nil, fmt.Errorf("custom_actions %q: name may not contain spaces", a.Name)
+		}
+		if names[a.Name] {
+			return nil, fmt.Errorf("custom_actions: duplicate action name %q", a.Name)
+		}
+		names[a.Name] = true
+		if a.Command == "" {
+			return nil, fmt.Errorf("custom_actions %q: command is required", a.Name)
+		}
+		if strings.Contains(strings.Fields(a.Command)[0], "%%") {
+			return nil, fmt.Errorf("custom_actions %q: the command itself cannot be a placeholder", a.Name)
+		}
+		switch a.Mode {
+		case "":
+			a.Mode = CustomActionTerminal
+		case CustomActionTerminal, CustomActionBackground, CustomActionCapture:
+		default:
+			return nil, fmt.Errorf("custom_actions %q: mode must be %s, %s or %s, not %q", a.Name, CustomActionTerminal, CustomActionBackground, CustomActionCapture, a.Mode)
+		}
+		if a.Key != "" {
+			if utf8.RuneCountInString(a.Key) != 1 {
+				return nil, fmt.Errorf("custom_actions %q: key must be a single character, not %q", a.Name, a.Key)
+			}
+			if other, ok := keys[a.Key]; ok {
+				return nil, fmt.Errorf("custom_actions %q: key %q is already used by %q", a.Name, a.Key, other)
+			}
+			keys[a.Key] = a.Name
+		}
+	}
+	return entries, nil
+}
+
+// UpsertCustomActionsInConfig sets custom_actions in a config document,
+// replacing any existing list.
+func UpsertCustomActionsInConfig(configData []byte, actions []CustomAction) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(configData, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse config YAML: %w", err)
+	}
+	if doc.Kind != yaml.DocumentNode {
+		return nil, fmt.Errorf("invalid YAML document structure")
+	}
+	root := ensureYAMLMapping(&doc)
+	if root == nil {
+		return nil, fmt.Errorf("invalid YAML document structure")
+	}
+
+	var list yaml.Node
+	if err := list.Encode(actions); err != nil {
+		return nil, fmt.Errorf("failed to encode custom actions: %w", err)
+	}
+
+	for i := 0; i < len(root.Content)-1; i += 2 {
+		if root.Content[i].Value == "custom_actions" {
+			root.Content[i+1] = &list
+			return encodeYAMLDoc(&doc)
+		}
+	}
+	root.Content = append(root.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "custom_actions"},
+		&list,
+	)
+	return encodeYAMLDoc(&doc)
+}
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func viperStyleActions(t *testing.T, doc string) any {
+	t.Helper()
+	var raw map[string]any
+	require.NoError(t, yaml.Unmarshal([]byte(doc), &raw))
+	return raw["custom_actions"]
+}
+
+func TestParseCustomActions(t *testing.T) {
+	actions, err := ParseCustomActions(viperStyleActions(t, `
+custom_actions:
+  - name: context
+    command: osdctl cluster context %%CLUSTER_ID%%
+    mode: Capture
+    key: c
+  - name: must-gather
+    command: ocm-container --cluster-id %%CLUSTER_ID%% -- must-gather.sh
+`))
+	require.NoError(t, err)
+	require.Len(t, actions, 2)
+
+	assert.Equal(t, CustomAction{Name: "context", Command: "osdctl cluster context %%CLUSTER_ID%%", Mode: CustomActionCapture, Key: "c"}, actions[0])
+	assert.Equal(t, CustomActionTerminal, actions[1].Mode, "mode defaults to terminal")
+	assert.True(t, actions[0].NeedsCluster())
+	assert.Equal(t, "context (capture): osdctl cluster context %%CLUSTER_ID%% [key c]", actions[0].Describe())
+}
+
+func TestParseCustomActions_Nil(t *testing.T) {
+	actions, err := ParseCustomActions(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, actions)
+}
+
+func TestParseCustomActions_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want string
+	}{
+		{"not a list", "custom_actions: {name: x}", "expected a list"},
+		{"missing name", "custom_actions: [{command: ls}]", "name is required"},
+		{"space in name", "custom_actions: [{name: a b, command: ls}]", "may not contain spaces"},
+		{"missing command", "custom_actions: [{name: x}]", "command is required"},
+		{"placeholder command", "custom_actions: [{name: x, command: '%%CLUSTER_ID%% ls'}]", "cannot be a placeholder"},
+		{"duplicate", "custom_actions: [{name: x, command: ls}, {name: x, command: pwd}]", "duplicate action name"},
+		{"bad mode", "custom_actions: [{name: x, command: ls, mode: detached}]", "mode must be"},
+		{"long key", "custom_actions: [{name: x, command: ls, key: ab}]", "single character"},
+		{"duplicate key", "custom_actions: [{name: x, command: ls, key: a}, {name: y, command: pwd, key: a}]", `already used by "x"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseCustomActions(viperStyleActions(t, tt.doc))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestUpsertCustomActionsInConfig(t *testing.T) {
+	actions := []CustomAction{{Name: "context", Command: "osdctl cluster context %%CLUSTER_ID%%", Mode: CustomActionCapture}}
+
+	out, err := UpsertCustomActionsInConfig([]byte("teams:\n  - T1\n"), actions)
+	require.NoError(t, err)
+	parsed, err := ParseCustomActions(viperStyleActions(t, string(out)))
+	require.NoError(t, err)
+	assert.Equal(t, actions, parsed)
+	assert.Contains(t, string(out), "teams:")
+
+	// An existing list is replaced, not appended to.
+	actions[0].Mode = CustomActionBackground
+	out, err = UpsertCustomActionsInConfig(out, actions)
+	require.NoError(t, err)
+	parsed, err = ParseCustomActions(viperStyleActions(t, string(out)))
+	require.NoError(t, err)
+	assert.Equal(t, actions, parsed)
+}
+
+func TestParsePreset_CustomActions(t *testing.T) {
+	p, err := ParsePreset([]byte("custom_actions:\n  - name: context\n    command: osdctl cluster context %%CLUSTER_ID%%\n"), "x")
+	require.NoError(t, err)
+	require.Len(t, p.CustomActions, 1)
+
+	_, err = ParsePreset([]byte("custom_actions:\n  - name: context\n"), "x")
+	assert.ErrorContains(t, err, "command is required")
+
+	// Preset actions are commands srepd runs: they go through the safety
+	// gate and are written even though the wizard has no step for them.
+	existing, applied := ApplyPreset(ExistingConfig{}, p)
+	assert.Equal(t, p.CustomActions, existing.CustomActions)
+	assert.True(t, applied.ExecutableAny())
+	assert.True(t, ForcePresetChanges(ConfigChanges{}, applied).CustomActionsChanged)
+
+	// A user's own actions win over the preset's.
+	mine := []CustomAction{{Name: "mine", Command: "ls", Mode: CustomActionTerminal}}
+	existing, applied = ApplyPreset(ExistingConfig{CustomActions: mine}, p)
+	assert.Equal(t, mine, existing.CustomActions)
+	assert.False(t, applied.CustomActions)
+}
+
+func TestMergeIntoExistingConfig_CustomActionsChanged(t *testing.T) {
+	final := ResolvedValues{
+		Token:         "existing-token",
+		CustomActions: []CustomAction{{Name: "context", Command: "osdctl cluster context %%CLUSTER_ID%%", Mode: CustomActionCapture, Key: "c"}},
+	}
+
+	result, err := MergeIntoExistingConfig([]byte(existingFullConfig), final, ConfigChanges{CustomActionsChanged: true}, nil, nil)
+
+	require.NoError(t, err)
+	output := string(result)
+	assert.Contains(t, output, "custom_actions:")
+	assert.Contains(t, output, "command: osdctl cluster context %%CLUSTER_ID%%")
+	assert.Contains(t, output, "editor: vim")
+	assert.Contains(t, output, "# Main config")
+}
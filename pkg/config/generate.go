@@ -80,7 +80,7 @@ func GenerateAnnotatedConfig(env *GenerateEnvironment) []byte {
 	sb.WriteString("\n")
 
 	for _, entry := range []struct{ key, comment string }{
-		{"toolbox_mode", "Fedora Toolbox detection: auto, true, or false."},
+		{"toolbox_mode", "Container host-exec: auto, toolbox, distrobox, custom, or false."},
 		{"chord_prefix", "Prefix key for chord commands."},
 		{"rosa_boundary_command", "rosa-boundary cluster login command."},
 	} {
@@ -93,7 +93,48 @@ func GenerateAnnotatedConfig(env *GenerateEnvironment) []byte {
 	sb.WriteString("# Escalation level ctrl+e re-escalates to, skipping placeholder tiers.\n")
 	sb.WriteString("reescalate_level: 2\n\n")
 	sb.WriteString("# Stream :watcher/LLM responses token-by-token when supported.\n")
-	sb.WriteString("stream_responses: true\n")
+	sb.WriteString("stream_responses: true\n\n")
+	sb.WriteString("# User-defined alert type rules (see docs/alert-rules.md).\n")
+	sb.WriteString("# alert_rules_file: ~/.config/srepd/alert_rules.yaml\n")
+	sb.WriteString("\n# Service log templates for :sl (see docs/service-logs.md), and whether\n")
+	sb.WriteString("# posting one tags the incident [SL Sent].\n")
+	sb.WriteString("# service_log_templates_file: ~/.config/srepd/servicelog_templates.yaml\n")
+	sb.WriteString("# service_log_sent_tag: true\n")
+	sb.WriteString("\n# Look clusters up in other OCM environments too (see docs/ocm-environments.md).\n")
+	sb.WriteString("# Each environment keeps its tokens in its own OCM config file.\n")
+	sb.WriteString("# ocm_environments:\n")
+	sb.WriteString("#   - name: staging\n")
+	sb.WriteString("#     config_file: ~/.config/ocm/ocm.staging.json\n")
+	sb.WriteString("#     services: [stage]\n")
+	sb.WriteString("\n# OCM data is cached on disk; stale entries are shown while they refresh.\n")
+	sb.WriteString("# ocm_cache: true\n")
+	sb.WriteString("# ocm_cache_ttl:\n")
+	sb.WriteString("#   cluster: 24h\n")
+	sb.WriteString("#   service_logs: 5m\n")
+	sb.WriteString("\n# Merge always-safe duplicates automatically (see docs/auto-merge.md).\n")
+	sb.WriteString("# Rules act only after 'srepd automerge review'; until then they dry-run.\n")
+	sb.WriteString("# auto_merge_dry_run: false\n")
+	sb.WriteString("# auto_merge_rules:\n")
+	sb.WriteString("#   - name: cluster-operator-down\n")
+	sb.WriteString("#     alert_name: ClusterOperatorDown\n")
+	sb.WriteString("#     within: 1h\n")
+	sb.WriteString("\n# Your own per-incident commands, run from :action or a chord key\n")
+	sb.WriteString("# (see docs/custom-actions.md). mode: terminal, background or capture.\n")
+	sb.WriteString("# custom_actions:\n")
+	sb.WriteString("#   - name: context\n")
+	sb.WriteString("#     command: osdctl cluster context %%CLUSTER_ID%%\n")
+	sb.WriteString("#     mode: capture\n")
+	sb.WriteString("#     key: c\n")
+	sb.WriteString("\n# Open cluster logins in a pane inside srepd instead of a terminal\n")
+	sb.WriteString("# window (Linux only, see docs/terminals.md#embedded-terminal).\n")
+	sb.WriteString("# embedded_terminal: false\n")
+	sb.WriteString("\n# With toolbox_mode: custom, the prefix that runs commands on the host\n")
+	sb.WriteString("# from a container (see docs/terminals.md#containers).\n")
+	sb.WriteString("# host_exec_command: podman-host\n")
+	sb.WriteString("\n# Run cluster logins on an SSH jump host (see docs/terminals.md#ssh-bastion).\n")
+	sb.WriteString("# ssh_bastion: sre@bastion.example.com\n")
+	sb.WriteString("\n# Offer pre-releases in `srepd update` and the update banner.\n")
+	sb.WriteString("# update_channel: prerelease\n")
 
 	sb.WriteString("\n# --- Escalation policies (optional — the wizard discovers these) ---\n\n")
 	sb.WriteString("# Policy incidents are reassigned to when silenced; use one that routes\n")
@@ -27,33 +27,44 @@ type Preset struct {
 	ClusterLoginCommand string
 	Terminal            string
 	Editor              string
+	Flags               []PresetFlag
+	CustomActions       []CustomAction
 	Source              string
 }
 
+// PresetFlag is a shared flag condition: the condition in `:flag` syntax
+// and an optional label. Presets never carry flag actions — those run
+// commands and post to PagerDuty, and stay a personal choice.
+type PresetFlag struct {
+	Condition string `yaml:"condition"`
+	Label     string `yaml:"label,omitempty"`
+}
+
 // PresetApplied records which fields a preset actually seeded, so the
 // wizard can tag them and force them into the write set.
 type PresetApplied struct {
-	Teams        bool
-	Silent       bool
-	Custom       bool
-	ClusterLogin bool
-	Terminal     bool
-	Editor       bool
-	Source       string
+	Teams         bool
+	Silent        bool
+	Custom        bool
+	ClusterLogin  bool
+	Terminal      bool
+	Editor        bool
+	CustomActions bool
+	Source        string
 }
 
 func (p PresetApplied) Any() bool {
-	return p.Teams || p.Silent || p.Custom || p.ClusterLogin || p.Terminal || p.Editor
+	return p.Teams || p.Silent || p.Custom || p.ClusterLogin || p.Terminal || p.Editor || p.CustomActions
 }
 
 // ExecutableAny reports whether the preset seeded any field that maps to a
-// command srepd executes (terminal, editor, cluster login). These get an
-// extra bold-red safety confirmation in the wizard: a preset fetched from a
-// URL is remote input, and a malicious one could otherwise plant arbitrary
-// commands. Team/policy IDs are only ever sent to the PagerDuty API, so
-// they are excluded.
+// command srepd executes (terminal, editor, cluster login, custom
+// actions). These get an extra bold-red safety confirmation in the wizard:
+// a preset fetched from a URL is remote input, and a malicious one could
+// otherwise plant arbitrary commands. Team/policy IDs are only ever sent
+// to the PagerDuty API, so they are excluded.
 func (p PresetApplied) ExecutableAny() bool {
-	return p.ClusterLogin || p.Terminal || p.Editor
+	return p.ClusterLogin || p.Terminal || p.Editor || p.CustomActions
 }
 
 // presetAllowedKeys is the strict allowlist of keys a preset may set.
@@ -71,6 +82,14 @@ var presetAllowedKeys = map[string]bool{
 	"cluster_login_command":              true,
 	"terminal":                           true,
 	"editor":                             true,
+	"flags":                              true,
+	"custom_actions":                     true,
+}
+
+// presetFlagKeys is the allowlist of keys of one `flags` entry.
+var presetFlagKeys = map[string]bool{
+	"condition": true,
+	"label":     true,
 }
 
 // ParsePreset parses and validates a preset document. source is recorded for
@@ -116,14 +135,73 @@ func ParsePreset(data []byte, source string) (*Preset, error) {
 	if v, ok := raw["editor"].(string); ok {
 		p.Editor = v
 	}
+	if flags, ok := raw["flags"]; ok {
+		parsed, err := parsePresetFlags(flags)
+		if err != nil {
+			return nil, err
+		}
+		p.Flags = parsed
+	}
+	if actions, ok := raw["custom_actions"]; ok {
+		parsed, err := ParseCustomActions(actions)
+		if err != nil {
+			return nil, fmt.Errorf("preset: %w", err)
+		}
+		p.CustomActions = parsed
+	}
 	return p, nil
 }
 
+func parsePresetFlags(v any) ([]PresetFlag, error) {
+	entries, ok := v.([]any)
+	if !ok {
+		return nil, fmt.Errorf("preset flags must be a list")
+	}
+	flags := make([]PresetFlag, 0, len(entries))
+	for i, e := range entries {
+		entry, ok := e.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("preset flag %d must be a mapping with condition and label", i+1)
+		}
+		for key := range entry {
+			if !presetFlagKeys[key] {
+				return nil, fmt.Errorf("preset flag %d may not set %s — shared flags carry a condition and label only, never actions", i+1, key)
+			}
+		}
+		condition, _ := entry["condition"].(string)
+		if strings.TrimSpace(condition) == "" {
+			return nil, fmt.Errorf("preset flag %d has no condition", i+1)
+		}
+		label, _ := entry["label"].(string)
+		flags = append(flags, PresetFlag{Condition: strings.TrimSpace(condition), Label: strings.TrimSpace(label)})
+	}
+	return flags, nil
+}
+
+// FormatPresetFlags renders flags as a preset fragment, which both
+// ParsePreset and `srepd config --preset` accept.
+func FormatPresetFlags(flags []PresetFlag) ([]byte, error) {
+	return yaml.Marshal(struct {
+		Flags []PresetFlag `yaml:"flags"`
+	}{flags})
+}
+
 // LoadPreset loads a preset from a local file path or an HTTPS URL.
 // URL fetches are HTTPS-only, size-capped, and never automatic — the user
 // passed --preset explicitly. client is injectable for tests; nil means
 // http.DefaultClient.
 func LoadPreset(ref string, client *http.Client) (*Preset, error) {
+	data, err := ReadPresetData(ref, client)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePreset(data, ref)
+}
+
+// ReadPresetData reads a preset, or another shared document such as exported
+// flags, from a local file path or an HTTPS URL, with the same rules as
+// LoadPreset.
+func ReadPresetData(ref string, client *http.Client) ([]byte, error) {
 	if strings.Contains(ref, "://") {
 		return fetchPresetURL(ref, client)
 	}
@@ -132,10 +210,10 @@ func LoadPreset(ref string, client *http.Client) (*Preset, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to read preset file: %w", err)
 	}
-	return ParsePreset(data, ref)
+	return data, nil
 }
 
-func fetchPresetURL(url string, client *http.Client) (*Preset, error) {
+func fetchPresetURL(url string, client *http.Client) ([]byte, error) {
 	if !strings.HasPrefix(url, "https://") {
 		return nil, fmt.Errorf("preset URLs must use https, got %q", url)
 	}
@@ -161,7 +239,7 @@ func fetchPresetURL(url string, client *http.Client) (*Preset, error) {
 		return nil, fmt.Errorf("preset exceeds the %d byte limit", presetMaxBytes)
 	}
 
-	return ParsePreset(data, url)
+	return data, nil
 }
 
 // ApplyPreset overlays preset values onto existing where existing is empty:
@@ -198,6 +276,10 @@ func ApplyPreset(existing ExistingConfig, p *Preset) (ExistingConfig, PresetAppl
 		existing.Editor = p.Editor
 		applied.Editor = true
 	}
+	if len(existing.CustomActions) == 0 && len(p.CustomActions) > 0 {
+		existing.CustomActions = p.CustomActions
+		applied.CustomActions = true
+	}
 
 	return existing, applied
 }
@@ -224,5 +306,8 @@ func ForcePresetChanges(changes ConfigChanges, applied PresetApplied) ConfigChan
 	if applied.Editor {
 		changes.EditorChanged = true
 	}
+	if applied.CustomActions {
+		changes.CustomActionsChanged = true
+	}
 	return changes
 }
@@ -178,3 +178,70 @@ func TestPresetApplied_ExecutableAny(t *testing.T) {
 	assert.True(t, PresetApplied{Editor: true}.ExecutableAny())
 	assert.True(t, PresetApplied{ClusterLogin: true}.ExecutableAny())
 }
+
+const flagsPreset = `
+teams:
+  - PTEAM99
+flags:
+  - condition: org Acme
+    label: "VIP: Acme"
+  - condition: hypershift AND NOT region us-east-1
+`
+
+func TestParsePreset_Flags(t *testing.T) {
+	p, err := ParsePreset([]byte(flagsPreset), "x")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"PTEAM99"}, p.Teams)
+	assert.Equal(t, []PresetFlag{
+		{Condition: "org Acme", Label: "VIP: Acme"},
+		{Condition: "hypershift AND NOT region us-east-1"},
+	}, p.Flags)
+}
+
+// Shared flags must never carry actions: those run commands and post to
+// PagerDuty.
+func TestParsePreset_FlagsRejectActions(t *testing.T) {
+	for name, doc := range map[string]string{
+		"actions":      "flags:\n  - condition: org Acme\n    actions: [{kind: notify}]\n",
+		"no condition": "flags:\n  - label: VIP\n",
+		"not a list":   "flags: org Acme\n",
+		"not a map":    "flags:\n  - org Acme\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParsePreset([]byte(doc), "x")
+			assert.Error(t, err)
+		})
+	}
+
+	_, err := ParsePreset([]byte("flags:\n  - condition: org Acme\n    actions: [{kind: notify}]\n"), "x")
+	assert.ErrorContains(t, err, "never actions")
+}
+
+func TestFormatPresetFlags_RoundTrip(t *testing.T) {
+	flags := []PresetFlag{{Condition: "org Acme", Label: "VIP: Acme"}, {Condition: "title ^etcd"}}
+
+	data, err := FormatPresetFlags(flags)
+	assert.NoError(t, err)
+
+	p, err := ParsePreset(data, "export.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, flags, p.Flags)
+}
+
+func TestReadPresetData(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, flagsPreset) //nolint:errcheck
+	}))
+	defer ts.Close()
+
+	data, err := ReadPresetData(ts.URL, ts.Client())
+	assert.NoError(t, err)
+	assert.Equal(t, flagsPreset, string(data))
+
+	_, err = ReadPresetData("http://example.com/flags.yaml", ts.Client())
+	assert.ErrorContains(t, err, "https")
+
+	_, err = ReadPresetData(filepath.Join(t.TempDir(), "missing.yaml"), nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,159 @@
+package container
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/log"
+)
+
+// HostExec runs commands on the host from inside a container, so that
+// terminals, tmux and cluster tooling installed on the host can be used
+// from a containerized srepd.
+type HostExec interface {
+	// Name identifies the strategy in logs and config: "toolbox",
+	// "distrobox" or "custom".
+	Name() string
+	// Prefix is prepended to a command to run it on the host.
+	Prefix() []string
+	// EnvArgs returns the arguments that set env ("VAR=VALUE" pairs) for
+	// the host command. They go right after Prefix.
+	EnvArgs(env []string) []string
+}
+
+// Toolbox runs commands on the host of a Fedora Toolbox container with
+// flatpak-spawn, which takes the environment as --env flags.
+type Toolbox struct{}
+
+func (Toolbox) Name() string { return "toolbox" }
+
+func (Toolbox) Prefix() []string { return []string{"flatpak-spawn", "--host"} }
+
+func (Toolbox) EnvArgs(env []string) []string {
+	if len(env) == 0 {
+		return nil
+	}
+	args := make([]string, 0, len(env))
+	for _, kv := range env {
+		args = append(args, "--env="+kv)
+	}
+	return args
+}
+
+// Distrobox runs commands on the host of a distrobox container with
+// distrobox-host-exec. It has no flag for the environment, so the
+// command runs under env(1) on the host.
+type Distrobox struct{}
+
+func (Distrobox) Name() string { return "distrobox" }
+
+func (Distrobox) Prefix() []string { return []string{"distrobox-host-exec"} }
+
+func (Distrobox) EnvArgs(env []string) []string { return envCommand(env) }
+
+// Custom runs commands on the host through a configured prefix, such as
+// "podman-host". Like Distrobox, the environment is set with env(1).
+//
+// The prefix must hand its arguments to the host command unchanged:
+// envCommand and the launcher's per-argument substitution rely on that to
+// keep PAGERDUTY_* values and alert data out of a shell. ssh joins its
+// arguments into one remote shell command, so it is refused; ssh_bastion
+// quotes for the remote shell instead.
+type Custom struct {
+	prefix []string
+}
+
+// NewCustom returns a Custom strategy running commands through prefix.
+func NewCustom(prefix string) (Custom, error) {
+	fields := strings.Fields(prefix)
+	if len(fields) == 0 {
+		return Custom{}, fmt.Errorf("host_exec_command is not set")
+	}
+	if filepath.Base(fields[0]) == "ssh" {
+		return Custom{}, fmt.Errorf("host_exec_command cannot be ssh: it runs its arguments through the remote shell (use ssh_bastion)")
+	}
+	return Custom{prefix: fields}, nil
+}
+
+func (Custom) Name() string { return "custom" }
+
+func (c Custom) Prefix() []string { return append([]string(nil), c.prefix...) }
+
+func (Custom) EnvArgs(env []string) []string { return envCommand(env) }
+
+// envCommand runs what follows it with env set. The values stay single
+// arguments, so spaces in them survive.
+func envCommand(env []string) []string {
+	if len(env) == 0 {
+		return nil
+	}
+	return append([]string{"env"}, env...)
+}
+
+// IsRunningInDistrobox detects whether the current process is running
+// inside a distrobox container: distrobox-enter sets
+// DISTROBOX_ENTER_PATH, and distrobox-init installs distrobox-host-exec
+// in containers that set CONTAINER_ID.
+func IsRunningInDistrobox() bool {
+	return checkDistrobox(os.Getenv, exec.LookPath)
+}
+
+func checkDistrobox(getenv func(string) string, lookPath func(string) (string, error)) bool {
+	if getenv("DISTROBOX_ENTER_PATH") != "" {
+		log.Debug("container.checkDistrobox", "detected", "DISTROBOX_ENTER_PATH env var set")
+		return true
+	}
+	if getenv("CONTAINER_ID") != "" {
+		if _, err := lookPath("distrobox-host-exec"); err == nil {
+			log.Debug("container.checkDistrobox", "detected", "CONTAINER_ID set and distrobox-host-exec found")
+			return true
+		}
+	}
+	return false
+}
+
+// Detect returns the strategy for the container srepd runs in, or nil
+// outside one. Distrobox is checked first: its containers may also look
+// like a toolbox.
+func Detect() HostExec {
+	switch {
+	case IsRunningInDistrobox():
+		return Distrobox{}
+	case IsRunningInToolbox():
+		return Toolbox{}
+	}
+	return nil
+}
+
+// Resolve returns the strategy for a toolbox_mode setting: "auto" (or
+// "") detects it, "true" or "toolbox" and "distrobox" pick one, "custom"
+// runs commands through customPrefix, and "false" or "none" runs them
+// directly (nil).
+func Resolve(mode, customPrefix string) (HostExec, error) {
+	return resolve(mode, customPrefix, Detect)
+}
+
+func resolve(mode, customPrefix string, detect func() HostExec) (HostExec, error) {
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case "true", "toolbox":
+		return Toolbox{}, nil
+	case "distrobox":
+		return Distrobox{}, nil
+	case "custom":
+		c, err := NewCustom(customPrefix)
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	case "false", "none":
+		return nil, nil
+	case "auto", "":
+		return detect(), nil
+	default:
+		log.Warn("Unknown toolbox_mode value, falling back to auto", "value", mode)
+		return detect(), nil
+	}
+}
@@ -0,0 +1,85 @@
+package container
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostExec_EnvArgs(t *testing.T) {
+	env := []string{"PAGERDUTY_INCIDENT_ID=P123", "PAGERDUTY_INCIDENT_TITLE=Cluster down"}
+
+	assert.Equal(t, []string{"--env=PAGERDUTY_INCIDENT_ID=P123", "--env=PAGERDUTY_INCIDENT_TITLE=Cluster down"}, Toolbox{}.EnvArgs(env))
+	assert.Equal(t, []string{"env", "PAGERDUTY_INCIDENT_ID=P123", "PAGERDUTY_INCIDENT_TITLE=Cluster down"}, Distrobox{}.EnvArgs(env))
+
+	custom, err := NewCustom("podman-host")
+	require.NoError(t, err)
+	assert.Equal(t, Distrobox{}.EnvArgs(env), custom.EnvArgs(env))
+
+	for _, h := range []HostExec{Toolbox{}, Distrobox{}, custom} {
+		assert.Nil(t, h.EnvArgs(nil), h.Name())
+	}
+}
+
+func TestNewCustom(t *testing.T) {
+	custom, err := NewCustom("  podman-host  --quiet ")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"podman-host", "--quiet"}, custom.Prefix())
+
+	// Callers append to the prefix; that must not reach the strategy.
+	_ = append(custom.Prefix(), "extra")
+	assert.Equal(t, []string{"podman-host", "--quiet"}, custom.Prefix())
+
+	_, err = NewCustom(" ")
+	assert.EqualError(t, err, "host_exec_command is not set")
+
+	for _, prefix := range []string{"ssh host.containers.internal", "/usr/bin/ssh -q host"} {
+		_, err = NewCustom(prefix)
+		assert.ErrorContains(t, err, "use ssh_bastion", prefix)
+	}
+}
+
+func TestCheckDistrobox(t *testing.T) {
+	env := func(vars map[string]string) func(string) string {
+		return func(key string) string { return vars[key] }
+	}
+	found := func(string) (string, error) { return "/usr/bin/distrobox-host-exec", nil }
+	missing := func(string) (string, error) { return "", errors.New("not found") }
+
+	assert.True(t, checkDistrobox(env(map[string]string{"DISTROBOX_ENTER_PATH": "/usr/bin/distrobox-enter"}), missing))
+	assert.True(t, checkDistrobox(env(map[string]string{"CONTAINER_ID": "dev"}), found))
+	assert.False(t, checkDistrobox(env(map[string]string{"CONTAINER_ID": "dev"}), missing), "any container may set CONTAINER_ID")
+	assert.False(t, checkDistrobox(env(nil), found))
+}
+
+func TestResolve(t *testing.T) {
+	detected := func() HostExec { return Distrobox{} }
+	tests := []struct {
+		mode string
+		want HostExec
+	}{
+		{"auto", Distrobox{}},
+		{"", Distrobox{}},
+		{"bogus", Distrobox{}},
+		{"true", Toolbox{}},
+		{"Toolbox", Toolbox{}},
+		{"distrobox", Distrobox{}},
+		{"false", nil},
+		{"none", nil},
+	}
+	for _, tt := range tests {
+		got, err := resolve(tt.mode, "", detected)
+		require.NoError(t, err, tt.mode)
+		assert.Equal(t, tt.want, got, tt.mode)
+	}
+
+	got, err := resolve("custom", "podman-host", detected)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"podman-host"}, got.Prefix())
+
+	got, err = resolve("custom", "", detected)
+	assert.Error(t, err)
+	assert.Nil(t, got, "an unusable prefix runs commands directly")
+}
@@ -0,0 +1,113 @@
+package launcher
+
+import (
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// CaptureLimit is how many bytes of output a Capture keeps. Past it the
+// oldest output is dropped.
+const CaptureLimit = 1 << 20
+
+// Capture is a command run without a terminal, with its stdout and stderr
+// kept in the order it printed them. Its methods are safe for concurrent
+// use.
+type Capture struct {
+	mu      sync.Mutex
+	out     []byte
+	dropped int
+
+	changed chan struct{}
+	done    chan struct{}
+	err     error
+
+	Started time.Time
+	exited  time.Time
+}
+
+// StartCapture starts c with its output captured. c's Stdout and Stderr
+// must be unset.
+func StartCapture(c *exec.Cmd) (*Capture, error) {
+	capture := &Capture{
+		changed: make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+	// One writer for both streams: exec.Cmd then shares a pipe, which
+	// keeps stdout and stderr interleaved as printed.
+	c.Stdout = captureWriter{capture}
+	c.Stderr = c.Stdout
+	if err := c.Start(); err != nil {
+		return nil, err
+	}
+	capture.Started = time.Now()
+	go func() {
+		err := c.Wait()
+		capture.mu.Lock()
+		capture.err = err
+		capture.exited = time.Now()
+		capture.mu.Unlock()
+		close(capture.done)
+	}()
+	return capture, nil
+}
+
+type captureWriter struct {
+	c *Capture
+}
+
+func (w captureWriter) Write(p []byte) (int, error) {
+	c := w.c
+	c.mu.Lock()
+	c.out = append(c.out, p...)
+	if over := len(c.out) - CaptureLimit; over > 0 {
+		c.out = append([]byte(nil), c.out[over:]...)
+		c.dropped += over
+	}
+	c.mu.Unlock()
+	select {
+	case c.changed <- struct{}{}:
+	default:
+	}
+	return len(p), nil
+}
+
+// Output returns the output kept so far, and how many bytes before it
+// were dropped.
+func (c *Capture) Output() (output string, dropped int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return string(c.out), c.dropped
+}
+
+// Changed receives when the command printed something since the last
+// receive.
+func (c *Capture) Changed() <-chan struct{} {
+	return c.changed
+}
+
+// Done is closed once the command has exited.
+func (c *Capture) Done() <-chan struct{} {
+	return c.done
+}
+
+// Running reports whether the command has not exited yet.
+func (c *Capture) Running() bool {
+	select {
+	case <-c.done:
+		return false
+	default:
+		return true
+	}
+}
+
+// Result returns how the command exited and how long it ran, once it
+// has.
+func (c *Capture) Result() (code int, duration time.Duration, err error) {
+	if c.Running() {
+		return 0, 0, nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return exitCode(c.err), c.exited.Sub(c.Started), c.err
+}
@@ -0,0 +1,68 @@
+package launcher
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func waitCapture(t *testing.T, c *Capture) {
+	t.Helper()
+	select {
+	case <-c.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("captured command did not exit")
+	}
+}
+
+func TestStartCapture(t *testing.T) {
+	c, err := StartCapture(exec.Command("sh", "-c", "echo out; echo err >&2; exit 3"))
+	require.NoError(t, err)
+	assert.False(t, c.Started.IsZero())
+	waitCapture(t, c)
+
+	out, dropped := c.Output()
+	assert.Equal(t, "out\nerr\n", out, "stdout and stderr are kept in order")
+	assert.Zero(t, dropped)
+	assert.False(t, c.Running())
+	code, _, err := c.Result()
+	assert.Equal(t, 3, code)
+	require.Error(t, err)
+}
+
+func TestStartCapture_Streams(t *testing.T) {
+	c, err := StartCapture(exec.Command("sh", "-c", "echo first; sleep 1; echo second"))
+	require.NoError(t, err)
+	select {
+	case <-c.Changed():
+	case <-time.After(5 * time.Second):
+		t.Fatal("no output before exit")
+	}
+	out, _ := c.Output()
+	assert.Equal(t, "first\n", out)
+	assert.True(t, c.Running())
+
+	waitCapture(t, c)
+	out, _ = c.Output()
+	assert.Equal(t, "first\nsecond\n", out)
+}
+
+func TestStartCapture_Limit(t *testing.T) {
+	c, err := StartCapture(exec.Command("sh", "-c", "head -c 1048676 /dev/zero | tr '\\0' a; echo end"))
+	require.NoError(t, err)
+	waitCapture(t, c)
+
+	out, dropped := c.Output()
+	assert.Len(t, out, CaptureLimit)
+	assert.Equal(t, 104, dropped)
+	assert.True(t, strings.HasSuffix(out, "aend\n"), "the newest output is kept")
+}
+
+func TestStartCapture_Failures(t *testing.T) {
+	_, err := StartCapture(exec.Command("/nonexistent/command"))
+	require.Error(t, err)
+}
@@ -2,6 +2,7 @@ package launcher
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/charmbracelet/log"
@@ -15,21 +16,22 @@ type ClusterLauncher struct {
 	Enabled             bool
 	terminal            []string
 	clusterLoginCommand []string
-	runInToolbox        bool
+	hostExec            container.HostExec
+	bastion             []string
 	profile             TerminalProfile
 	settings            launcherSettings
+	sessions            *SessionRegistry
 }
 
 type launcherSettings struct {
 	// Future Usage Possibly
 }
 
-// NewClusterLauncher creates a new ClusterLauncher with automatic toolbox
-// detection using the default "auto" mode. When running inside a Fedora
-// Toolbox container, terminal commands are automatically prefixed with
-// "flatpak-spawn --host" so they execute on the host system.
-func NewClusterLauncher(terminal string, clusterLoginCommand string, toolboxMode string) (ClusterLauncher, error) {
-	return NewClusterLauncherWithToolbox(terminal, clusterLoginCommand, toolboxMode, container.IsRunningInToolbox)
+// NewClusterLauncher creates a new ClusterLauncher. When srepd runs in a
+// container, hostExec (see container.Resolve) prefixes terminal commands
+// so they execute on the host; nil runs them directly.
+func NewClusterLauncher(terminal string, clusterLoginCommand string, hostExec container.HostExec) (ClusterLauncher, error) {
+	return newClusterLauncher(terminal, clusterLoginCommand, hostExec)
 }
 
 // NewClusterLauncherWithToolbox creates a new ClusterLauncher with an
@@ -38,8 +40,14 @@ func NewClusterLauncher(terminal string, clusterLoginCommand string, toolboxMode
 // behavior: "auto" (or "") uses detectFn, "true" forces toolbox mode on,
 // "false" forces it off.
 func NewClusterLauncherWithToolbox(terminal string, clusterLoginCommand string, toolboxMode string, detectFn func() bool) (ClusterLauncher, error) {
-	inToolbox := resolveToolboxMode(toolboxMode, detectFn)
+	var hostExec container.HostExec
+	if resolveToolboxMode(toolboxMode, detectFn) {
+		hostExec = container.Toolbox{}
+	}
+	return newClusterLauncher(terminal, clusterLoginCommand, hostExec)
+}
 
+func newClusterLauncher(terminal string, clusterLoginCommand string, hostExec container.HostExec) (ClusterLauncher, error) {
 	// Feature 2: Warn on redundant "flatpak run" prefix when the user
 	// could simplify to just the app ID.
 	if appID, redundant := detectRedundantFlatpakPrefix(terminal); redundant {
@@ -64,13 +72,14 @@ func NewClusterLauncherWithToolbox(terminal string, clusterLoginCommand string,
 	launcher := ClusterLauncher{
 		terminal:            strings.Split(terminalForExec, " "),
 		clusterLoginCommand: strings.Split(clusterLoginCommand, " "),
-		runInToolbox:        inToolbox,
+		hostExec:            hostExec,
 		profile:             DetectTerminalProfile(terminal),
 		settings:            launcherSettings{},
+		sessions:            NewSessionRegistry(),
 	}
 
-	if inToolbox {
-		log.Debug("Toolbox detected: terminal commands will be prefixed with flatpak-spawn --host")
+	if hostExec != nil {
+		log.Debug("Running in a container: terminal commands will run on the host", "strategy", hostExec.Name(), "prefix", hostExec.Prefix())
 	}
 
 	err := launcher.validate()
@@ -97,59 +106,41 @@ func resolveToolboxMode(mode string, detectFn func() bool) bool {
 	}
 }
 
-// IsToolbox returns whether this launcher is configured to run inside a
-// Fedora Toolbox container, meaning commands are prefixed with flatpak-spawn.
-func (l *ClusterLauncher) IsToolbox() bool {
-	return l.runInToolbox
+// HostExec returns the strategy that runs this launcher's commands on the
+// host, or nil when srepd does not run in a container.
+func (l *ClusterLauncher) HostExec() container.HostExec {
+	return l.hostExec
 }
 
-// ToolboxEnvFlags converts a slice of "-e", "VAR=VALUE" pairs (the format
-// produced by buildPagerDutyEnvVars) into "--env=VAR=VALUE" flags suitable
-// for flatpak-spawn. Returns nil if the launcher is not in toolbox mode,
-// or if envFlags is nil/empty.
-func (l *ClusterLauncher) ToolboxEnvFlags(envFlags []string) []string {
-	if !l.runInToolbox {
-		return nil
-	}
-	if len(envFlags) == 0 {
+// HostEnvArgs converts a slice of "-e", "VAR=VALUE" pairs (the format
+// produced by buildPagerDutyEnvVars) into the arguments that pass them to
+// a host command, e.g. "--env=VAR=VALUE" flags for flatpak-spawn. Returns
+// nil if the launcher does not run commands on the host, or if envFlags
+// is nil/empty.
+func (l *ClusterLauncher) HostEnvArgs(envFlags []string) []string {
+	if l.hostExec == nil {
 		return nil
 	}
-
-	var toolboxFlags []string
-	for i := 0; i < len(envFlags)-1; i += 2 {
-		if envFlags[i] == "-e" {
-			toolboxFlags = append(toolboxFlags, fmt.Sprintf("--env=%s", envFlags[i+1]))
-		}
-	}
-	return toolboxFlags
+	return l.hostExec.EnvArgs(envFlagPairs(envFlags))
 }
 
-// InsertToolboxEnvFlags inserts --env= flags into a command slice at the
-// correct position for flatpak-spawn: after "flatpak-spawn" and "--host"
-// but before the terminal command. Returns the original command if no
-// flatpak-spawn is found or toolboxFlags is empty.
-func InsertToolboxEnvFlags(command []string, toolboxFlags []string) []string {
-	if len(toolboxFlags) == 0 {
+// InsertHostEnvArgs inserts host env arguments into a command slice at the
+// correct position: after the host-exec prefix (e.g. "flatpak-spawn
+// --host") but before the terminal command. Returns the original command
+// if it does not start with the prefix or hostArgs is empty.
+func (l *ClusterLauncher) InsertHostEnvArgs(command []string, hostArgs []string) []string {
+	if len(hostArgs) == 0 || l.hostExec == nil {
 		return command
 	}
-
-	// Find the insertion point: right after "--host" in the flatpak-spawn prefix
-	insertIdx := -1
-	for i, arg := range command {
-		if arg == "--host" {
-			insertIdx = i + 1
-			break
-		}
-	}
-
-	if insertIdx < 0 || insertIdx > len(command) {
+	prefix := l.hostExec.Prefix()
+	if len(command) < len(prefix) || !slices.Equal(command[:len(prefix)], prefix) {
 		return command
 	}
 
-	result := make([]string, 0, len(command)+len(toolboxFlags))
-	result = append(result, command[:insertIdx]...)
-	result = append(result, toolboxFlags...)
-	result = append(result, command[insertIdx:]...)
+	result := make([]string, 0, len(command)+len(hostArgs))
+	result = append(result, prefix...)
+	result = append(result, hostArgs...)
+	result = append(result, command[len(prefix):]...)
 	return result
 }
 
@@ -185,6 +176,13 @@ func (l *ClusterLauncher) Profile() TerminalProfile {
 	return l.profile
 }
 
+// IsTmux reports whether logins open tmux windows, which are named after
+// the incident and cluster.
+func (l *ClusterLauncher) IsTmux() bool {
+	_, ok := l.profile.(*TmuxProfile)
+	return ok
+}
+
 // LoginCommandContainsOCMContainer checks the raw clusterLoginCommand
 // (before profile wrapping) for the presence of "ocm-container". This
 // determines whether -e flags should be spliced into the login command
@@ -215,8 +213,9 @@ func (l *ClusterLauncher) NeedsWrapperScript() bool {
 
 // buildTerminalCommand is the shared core for BuildLoginCommand,
 // BuildLoginCommandWithEnv, and BuildLoginCommandForScript. It resolves
-// the profile, builds terminal args with variable substitution, applies
-// the profile's command syntax, and prepends flatpak-spawn for toolbox.
+// the profile, builds terminal args with variable substitution, names
+// tmux windows, applies the profile's command syntax, and prepends the
+// host-exec prefix in a container.
 func (l *ClusterLauncher) buildTerminalCommand(vars map[string]string, loginCmd []string) []string {
 	profile := l.profile
 	if profile == nil {
@@ -228,6 +227,10 @@ func (l *ClusterLauncher) buildTerminalCommand(vars map[string]string, loginCmd
 		terminalArgs = append(terminalArgs, replaceVars(l.terminal[1:], vars)...)
 	}
 
+	if _, ok := profile.(*TmuxProfile); ok {
+		terminalArgs = tmuxWindowArgs(terminalArgs, vars)
+	}
+
 	var command []string
 	if alreadyHasSeparator(profile, terminalArgs) {
 		command = append(terminalArgs, loginCmd...)
@@ -240,8 +243,8 @@ func (l *ClusterLauncher) buildTerminalCommand(vars map[string]string, loginCmd
 		}
 	}
 
-	if l.runInToolbox {
-		command = append([]string{"flatpak-spawn", "--host"}, command...)
+	if l.hostExec != nil {
+		command = append(l.hostExec.Prefix(), command...)
 	}
 
 	l.logCommand(command)
@@ -297,6 +300,28 @@ func (l *ClusterLauncher) BuildRawLoginCommand(vars map[string]string) []string
 	return replaceVars(l.clusterLoginCommand, vars)
 }
 
+// WithCommand returns a copy of the launcher that runs command in place of
+// the cluster login command, with the same terminal, profile, host-exec
+// strategy, bastion and session registry. Custom actions use it; their
+// commands need not refer to a cluster, so the copy is not validated.
+func (l *ClusterLauncher) WithCommand(command string) ClusterLauncher {
+	c := *l
+	c.clusterLoginCommand = strings.Fields(command)
+	return c
+}
+
+// BuildDirectCommand returns the command with variables replaced, to run
+// without a terminal. In a container it runs on the host through the
+// host-exec prefix, like a terminal would.
+func (l *ClusterLauncher) BuildDirectCommand(vars map[string]string) []string {
+	command := replaceVars(l.clusterLoginCommand, vars)
+	if l.hostExec != nil {
+		command = append(l.hostExec.Prefix(), command...)
+	}
+	l.logCommand(command)
+	return command
+}
+
 // BuildLoginCommandForScript builds a terminal command that executes a
 // wrapper script instead of the original login command. The script path
 // is passed as the login command to the terminal profile.
@@ -4,6 +4,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/clcollins/srepd/pkg/container"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -61,7 +62,7 @@ func TestNewClusterLauncher_ToolboxDetection(t *testing.T) {
 				func() bool { return test.detectToolbox },
 			)
 			assert.NoError(t, err, "expected no error creating launcher")
-			assert.Equal(t, test.expectToolbox, launcher.runInToolbox, "toolbox mode mismatch")
+			assert.Equal(t, test.expectToolbox, launcher.HostExec() != nil, "toolbox mode mismatch")
 		})
 	}
 }
@@ -70,7 +71,7 @@ func TestBuildLoginCommand_ToolboxWrapping(t *testing.T) {
 	launcher := ClusterLauncher{
 		terminal:            []string{"gnome-terminal", "--"},
 		clusterLoginCommand: []string{"ocm-container", "-C", "%%CLUSTER_ID%%"},
-		runInToolbox:        true,
+		hostExec:            container.Toolbox{},
 	}
 
 	vars := map[string]string{
@@ -87,7 +88,6 @@ func TestBuildLoginCommand_NoWrappingNormal(t *testing.T) {
 	launcher := ClusterLauncher{
 		terminal:            []string{"gnome-terminal", "--"},
 		clusterLoginCommand: []string{"ocm-container", "-C", "%%CLUSTER_ID%%"},
-		runInToolbox:        false,
 	}
 
 	vars := map[string]string{
@@ -104,7 +104,7 @@ func TestBuildLoginCommand_ToolboxWrappingWithTmux(t *testing.T) {
 	launcher := ClusterLauncher{
 		terminal:            []string{"tmux", "new-window", "-n", "%%CLUSTER_ID%%"},
 		clusterLoginCommand: []string{"ocm-container", "-C", "%%CLUSTER_ID%%"},
-		runInToolbox:        true,
+		hostExec:            container.Toolbox{},
 	}
 
 	vars := map[string]string{
@@ -117,105 +117,136 @@ func TestBuildLoginCommand_ToolboxWrappingWithTmux(t *testing.T) {
 	assert.Equal(t, expected, cmd, "toolbox wrapping should work with tmux terminal commands")
 }
 
-func TestIsToolbox(t *testing.T) {
+func TestHostExec(t *testing.T) {
 	tests := []struct {
 		name     string
-		toolbox  bool
-		expected bool
+		hostExec container.HostExec
+		expected string
 	}{
-		{"toolbox mode on", true, true},
-		{"toolbox mode off", false, false},
+		{"toolbox", container.Toolbox{}, "toolbox"},
+		{"distrobox", container.Distrobox{}, "distrobox"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			l := ClusterLauncher{
 				terminal:            []string{"gnome-terminal", "--"},
 				clusterLoginCommand: []string{"ocm-container", "-C", "%%CLUSTER_ID%%"},
-				runInToolbox:        tt.toolbox,
+				hostExec:            tt.hostExec,
 			}
-			assert.Equal(t, tt.expected, l.IsToolbox())
+			assert.Equal(t, tt.expected, l.HostExec().Name())
 		})
 	}
+	assert.Nil(t, (&ClusterLauncher{}).HostExec(), "no container")
 }
 
-func TestToolboxEnvFlags_AddsEnvFlags(t *testing.T) {
+func TestHostEnvArgs_AddsEnvFlags(t *testing.T) {
 	l := ClusterLauncher{
 		terminal:            []string{"gnome-terminal", "--"},
 		clusterLoginCommand: []string{"ocm-container", "-C", "%%CLUSTER_ID%%"},
-		runInToolbox:        true,
+		hostExec:            container.Toolbox{},
 	}
 	envFlags := []string{"-e", "KEY1=val1", "-e", "KEY2=val2"}
-	result := l.ToolboxEnvFlags(envFlags)
+	result := l.HostEnvArgs(envFlags)
 	expected := []string{"--env=KEY1=val1", "--env=KEY2=val2"}
 	assert.Equal(t, expected, result)
 }
 
-func TestToolboxEnvFlags_NoFlagsWhenNotToolbox(t *testing.T) {
+func TestHostEnvArgs_NoFlagsWhenNotToolbox(t *testing.T) {
 	l := ClusterLauncher{
 		terminal:            []string{"gnome-terminal", "--"},
 		clusterLoginCommand: []string{"ocm-container", "-C", "%%CLUSTER_ID%%"},
-		runInToolbox:        false,
 	}
 	envFlags := []string{"-e", "KEY1=val1", "-e", "KEY2=val2"}
-	result := l.ToolboxEnvFlags(envFlags)
+	result := l.HostEnvArgs(envFlags)
 	assert.Nil(t, result)
 }
 
-func TestToolboxEnvFlags_EmptyEnvFlags(t *testing.T) {
+func TestHostEnvArgs_EmptyEnvFlags(t *testing.T) {
 	l := ClusterLauncher{
 		terminal:            []string{"gnome-terminal", "--"},
 		clusterLoginCommand: []string{"ocm-container", "-C", "%%CLUSTER_ID%%"},
-		runInToolbox:        true,
+		hostExec:            container.Toolbox{},
 	}
-	result := l.ToolboxEnvFlags([]string{})
+	result := l.HostEnvArgs([]string{})
 	assert.Nil(t, result)
 }
 
-func TestToolboxEnvFlags_NilEnvFlags(t *testing.T) {
+func TestHostEnvArgs_NilEnvFlags(t *testing.T) {
 	l := ClusterLauncher{
 		terminal:            []string{"gnome-terminal", "--"},
 		clusterLoginCommand: []string{"ocm-container", "-C", "%%CLUSTER_ID%%"},
-		runInToolbox:        true,
+		hostExec:            container.Toolbox{},
 	}
-	result := l.ToolboxEnvFlags(nil)
+	result := l.HostEnvArgs(nil)
 	assert.Nil(t, result)
 }
 
-func TestToolboxEnvFlags_OddLengthEnvFlags(t *testing.T) {
+func TestHostEnvArgs_OddLengthEnvFlags(t *testing.T) {
 	l := ClusterLauncher{
 		terminal:            []string{"gnome-terminal", "--"},
 		clusterLoginCommand: []string{"ocm-container", "-C", "%%CLUSTER_ID%%"},
-		runInToolbox:        true,
+		hostExec:            container.Toolbox{},
 	}
 	// Odd-length slice: trailing "-e" without a value should be ignored
 	envFlags := []string{"-e", "KEY1=val1", "-e"}
-	result := l.ToolboxEnvFlags(envFlags)
+	result := l.HostEnvArgs(envFlags)
 	expected := []string{"--env=KEY1=val1"}
 	assert.Equal(t, expected, result)
 }
 
-func TestInsertToolboxEnvFlags_PositionAfterFlatpakSpawn(t *testing.T) {
+func TestInsertHostEnvArgs_PositionAfterFlatpakSpawn(t *testing.T) {
+	l := ClusterLauncher{hostExec: container.Toolbox{}}
 	command := []string{"flatpak-spawn", "--host", "gnome-terminal", "--", "ocm", "backplane", "login", "abc123"}
 	toolboxFlags := []string{"--env=KEY1=val1", "--env=KEY2=val2"}
-	result := InsertToolboxEnvFlags(command, toolboxFlags)
+	result := l.InsertHostEnvArgs(command, toolboxFlags)
 	expected := []string{"flatpak-spawn", "--host", "--env=KEY1=val1", "--env=KEY2=val2", "gnome-terminal", "--", "ocm", "backplane", "login", "abc123"}
 	assert.Equal(t, expected, result)
 }
 
-func TestInsertToolboxEnvFlags_EmptyFlags(t *testing.T) {
+func TestInsertHostEnvArgs_EmptyFlags(t *testing.T) {
+	l := ClusterLauncher{hostExec: container.Toolbox{}}
 	command := []string{"flatpak-spawn", "--host", "gnome-terminal", "--", "ocm", "backplane", "login"}
-	result := InsertToolboxEnvFlags(command, []string{})
+	result := l.InsertHostEnvArgs(command, []string{})
 	assert.Equal(t, command, result)
 }
 
-func TestInsertToolboxEnvFlags_NoFlatpakSpawn(t *testing.T) {
-	// No --host in command; should return command unchanged
+func TestInsertHostEnvArgs_NoFlatpakSpawn(t *testing.T) {
+	// No host-exec prefix in command; should return command unchanged
+	l := ClusterLauncher{hostExec: container.Toolbox{}}
 	command := []string{"gnome-terminal", "--", "ocm-container", "-C", "abc123"}
 	toolboxFlags := []string{"--env=KEY=val"}
-	result := InsertToolboxEnvFlags(command, toolboxFlags)
+	result := l.InsertHostEnvArgs(command, toolboxFlags)
 	assert.Equal(t, command, result)
 }
 
+// distrobox-host-exec and custom prefixes take no env flags, so the
+// variables ride on env(1) after the prefix.
+func TestInsertHostEnvArgs_Distrobox(t *testing.T) {
+	l := ClusterLauncher{
+		terminal:            []string{"gnome-terminal", "--"},
+		clusterLoginCommand: []string{"ocm", "backplane", "login", "%%CLUSTER_ID%%"},
+		profile:             &SeparatorProfile{terminalName: "gnome-terminal"},
+		hostExec:            container.Distrobox{},
+	}
+	command := l.BuildLoginCommand(map[string]string{"%%CLUSTER_ID%%": "abc123"})
+	args := l.HostEnvArgs([]string{"-e", "PAGERDUTY_INCIDENT_TITLE=Cluster down", "-e", "K=V"})
+	assert.Equal(t, []string{"env", "PAGERDUTY_INCIDENT_TITLE=Cluster down", "K=V"}, args)
+	assert.Equal(t, []string{"distrobox-host-exec", "env", "PAGERDUTY_INCIDENT_TITLE=Cluster down", "K=V", "gnome-terminal", "--", "ocm", "backplane", "login", "abc123"},
+		l.InsertHostEnvArgs(command, args))
+}
+
+func TestInsertHostEnvArgs_Custom(t *testing.T) {
+	custom, err := container.NewCustom("podman-host --quiet")
+	assert.NoError(t, err)
+	l := ClusterLauncher{
+		clusterLoginCommand: []string{"osdctl", "cluster", "context", "%%CLUSTER_ID%%"},
+		hostExec:            custom,
+	}
+	command := l.BuildDirectCommand(map[string]string{"%%CLUSTER_ID%%": "c1"})
+	assert.Equal(t, []string{"podman-host", "--quiet", "env", "K=V", "osdctl", "cluster", "context", "c1"},
+		l.InsertHostEnvArgs(command, l.HostEnvArgs([]string{"-e", "K=V"})))
+}
+
 func TestClusterLauncherValidation(t *testing.T) {
 	tests := []struct {
 		name            string
@@ -257,7 +288,7 @@ func TestClusterLauncherValidation(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			_, err := NewClusterLauncher(test.terminalArg, test.loginCommandArg, "auto")
+			_, err := NewClusterLauncher(test.terminalArg, test.loginCommandArg, nil)
 			if test.expectErr && err == nil {
 				t.Fatalf("Expected error but none fired")
 			}
@@ -282,10 +313,10 @@ func TestProfile_ReturnsDetectedProfile(t *testing.T) {
 			expectedProfile: "separator (gnome-terminal)",
 		},
 		{
-			name:            "tmux detected as GenericProfile",
+			name:            "tmux detected as TmuxProfile",
 			terminal:        "tmux new-window -n %%CLUSTER_ID%%",
 			loginCommand:    "ocm-container -C %%CLUSTER_ID%%",
-			expectedProfile: "generic",
+			expectedProfile: "tmux",
 		},
 		{
 			name:            "konsole detected as FlagProfile",
@@ -622,7 +653,7 @@ func TestBuildLoginCommandWithEnv_ToolboxWrapping(t *testing.T) {
 		terminal:            []string{"gnome-terminal", "--"},
 		clusterLoginCommand: []string{"ocm-container", "-C", "%%CLUSTER_ID%%"},
 		profile:             &SeparatorProfile{terminalName: "gnome-terminal"},
-		runInToolbox:        true,
+		hostExec:            container.Toolbox{},
 	}
 	vars := map[string]string{"%%CLUSTER_ID%%": "test-tb"}
 	envFlags := []string{"-e", "K=V"}
@@ -703,7 +734,6 @@ func TestBuildLoginCommand_RosaBoundary(t *testing.T) {
 	launcher := ClusterLauncher{
 		terminal:            []string{"gnome-terminal", "--"},
 		clusterLoginCommand: []string{"rosa-boundary", "start-task", "--cluster-id", "%%CLUSTER_ID%%", "--connect"},
-		runInToolbox:        false,
 		profile:             &GenericProfile{},
 	}
 
@@ -716,3 +746,39 @@ func TestBuildLoginCommand_RosaBoundary(t *testing.T) {
 	assert.Contains(t, cmd, "rosa-boundary")
 	assert.Contains(t, cmd, "test-cluster-123")
 }
+
+// ---------------------------------------------------------------------------
+// WithCommand / BuildDirectCommand
+// ---------------------------------------------------------------------------
+
+// Custom actions run their own command through the cluster login's
+// terminal, toolbox setting and session registry.
+func TestWithCommand(t *testing.T) {
+	l := ClusterLauncher{
+		terminal:            []string{"gnome-terminal", "--"},
+		clusterLoginCommand: []string{"ocm-container", "--cluster-id", "%%CLUSTER_ID%%"},
+		profile:             &GenericProfile{},
+		sessions:            NewSessionRegistry(),
+	}
+
+	action := l.WithCommand("osdctl  cluster context %%CLUSTER_ID%%")
+	vars := map[string]string{"%%CLUSTER_ID%%": "c1"}
+
+	assert.Equal(t, []string{"gnome-terminal", "--", "osdctl", "cluster", "context", "c1"}, action.BuildLoginCommand(vars))
+	assert.Same(t, l.Sessions(), action.Sessions())
+	assert.Equal(t, []string{"ocm-container", "--cluster-id", "c1"}, l.BuildRawLoginCommand(vars), "the original is unchanged")
+}
+
+func TestBuildDirectCommand(t *testing.T) {
+	l := ClusterLauncher{
+		terminal:            []string{"gnome-terminal", "--"},
+		clusterLoginCommand: []string{"osdctl", "cluster", "context", "%%CLUSTER_ID%%"},
+		profile:             &GenericProfile{},
+	}
+	vars := map[string]string{"%%CLUSTER_ID%%": "c1"}
+
+	assert.Equal(t, []string{"osdctl", "cluster", "context", "c1"}, l.BuildDirectCommand(vars), "no terminal")
+
+	l.hostExec = container.Toolbox{}
+	assert.Equal(t, []string{"flatpak-spawn", "--host", "osdctl", "cluster", "context", "c1"}, l.BuildDirectCommand(vars))
+}
@@ -269,6 +269,9 @@ func profileForExecName(execName string) TerminalProfile {
 			appName:      appName,
 		}
 	}
+	if execName == "tmux" {
+		return &TmuxProfile{}
+	}
 	if separatorTerminals[execName] {
 		return &SeparatorProfile{terminalName: execName}
 	}
@@ -0,0 +1,84 @@
+package launcher
+
+import (
+	"strings"
+)
+
+// UseBastion makes logins run on an SSH host: the terminal opens locally
+// and connects with `ssh -t`, and the login command runs on the host.
+// target is the ssh destination, optionally preceded by ssh options
+// (e.g. "-p 2222 sre@bastion.example.com"). An empty target runs logins
+// locally again.
+func (l *ClusterLauncher) UseBastion(target string) {
+	l.bastion = strings.Fields(target)
+}
+
+// IsRemote reports whether logins run on an SSH bastion.
+func (l *ClusterLauncher) IsRemote() bool {
+	return len(l.bastion) > 0
+}
+
+// RemoteCommand returns the ssh command that runs command on the bastion
+// with envPairs ("VAR=VALUE") set. ssh hands the remote shell a single
+// string, so every word of it is quoted with shQuote: values with spaces
+// or quotes reach the command as they are.
+func (l *ClusterLauncher) RemoteCommand(command []string, envPairs []string) []string {
+	var b strings.Builder
+	b.WriteString("exec")
+	if len(envPairs) > 0 {
+		b.WriteString(" env")
+		for _, pair := range envPairs {
+			key, value, ok := strings.Cut(pair, "=")
+			if !ok {
+				continue
+			}
+			b.WriteString(" " + key + "=" + shQuote(value))
+		}
+	}
+	for _, arg := range command {
+		b.WriteString(" " + shQuote(arg))
+	}
+
+	remote := append([]string{"ssh", "-t"}, l.bastion...)
+	return append(remote, b.String())
+}
+
+// RemoteLoginCommand returns the ssh command running the login command on
+// the bastion. The PAGERDUTY_* variables in envFlags ride as ocm-container
+// -e flags when it runs ocm-container, and are set with env otherwise.
+func (l *ClusterLauncher) RemoteLoginCommand(vars map[string]string, envFlags []string) []string {
+	loginCmd := replaceVars(l.clusterLoginCommand, vars)
+	if l.LoginCommandContainsOCMContainer() {
+		return l.RemoteCommand(InsertEnvFlagsAfterOCMContainer(loginCmd, envFlags), nil)
+	}
+	return l.RemoteCommand(loginCmd, envFlagPairs(envFlags))
+}
+
+// BuildRemoteLoginCommand builds the terminal command that connects to
+// the bastion and runs the login there. The terminal profile, tmux window
+// naming and host-exec prefix apply as for a local login.
+func (l *ClusterLauncher) BuildRemoteLoginCommand(vars map[string]string, envFlags []string) []string {
+	return l.buildTerminalCommand(vars, l.RemoteLoginCommand(vars, envFlags))
+}
+
+// BuildRemoteDirectCommand is BuildRemoteLoginCommand without a terminal,
+// like BuildDirectCommand.
+func (l *ClusterLauncher) BuildRemoteDirectCommand(vars map[string]string, envFlags []string) []string {
+	command := l.RemoteLoginCommand(vars, envFlags)
+	if l.hostExec != nil {
+		command = append(l.hostExec.Prefix(), command...)
+	}
+	l.logCommand(command)
+	return command
+}
+
+// envFlagPairs returns the "VAR=VALUE" pairs of "-e", "VAR=VALUE" flags.
+func envFlagPairs(envFlags []string) []string {
+	var pairs []string
+	for i := 0; i < len(envFlags)-1; i += 2 {
+		if envFlags[i] == "-e" {
+			pairs = append(pairs, envFlags[i+1])
+		}
+	}
+	return pairs
+}
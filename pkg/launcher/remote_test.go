@@ -0,0 +1,82 @@
+package launcher
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/clcollins/srepd/pkg/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUseBastion(t *testing.T) {
+	l := ClusterLauncher{}
+	assert.False(t, l.IsRemote())
+	l.UseBastion(" -p 2222  sre@bastion ")
+	assert.True(t, l.IsRemote())
+	assert.Equal(t, []string{"ssh", "-t", "-p", "2222", "sre@bastion", "exec 'true'"}, l.RemoteCommand([]string{"true"}, nil))
+
+	action := l.WithCommand("osdctl cluster context %%CLUSTER_ID%%")
+	assert.True(t, action.IsRemote(), "custom actions run on the bastion too")
+
+	l.UseBastion("")
+	assert.False(t, l.IsRemote())
+}
+
+// The remote shell gets one string; whatever the values hold, the
+// command must see them unchanged and run nothing else.
+func TestRemoteCommand_Quoting(t *testing.T) {
+	l := ClusterLauncher{}
+	l.UseBastion("bastion")
+	title := `it's "down" $(touch /tmp/pwned) ; rm -rf ~`
+	command := []string{"sh", "-c", `printf '%s|%s' "$PAGERDUTY_INCIDENT_TITLE" "$1"`, "sh", "a b'c"}
+
+	remote := l.RemoteCommand(command, []string{"PAGERDUTY_INCIDENT_TITLE=" + title, "MALFORMED"})
+	require.Equal(t, []string{"ssh", "-t", "bastion"}, remote[:3])
+	require.Len(t, remote, 4)
+
+	out, err := exec.Command("sh", "-c", remote[3]).Output()
+	require.NoError(t, err)
+	assert.Equal(t, title+"|a b'c", string(out))
+}
+
+func TestBuildRemoteLoginCommand(t *testing.T) {
+	l := ClusterLauncher{
+		terminal:            []string{"gnome-terminal", "--"},
+		clusterLoginCommand: []string{"ocm", "backplane", "login", "%%CLUSTER_ID%%"},
+		profile:             &SeparatorProfile{terminalName: "gnome-terminal"},
+	}
+	l.UseBastion("bastion")
+	vars := map[string]string{"%%CLUSTER_ID%%": "c1"}
+	envFlags := []string{"-e", "PAGERDUTY_CLUSTER_ID=c1"}
+
+	assert.Equal(t,
+		[]string{"gnome-terminal", "--", "ssh", "-t", "bastion", "exec env PAGERDUTY_CLUSTER_ID='c1' 'ocm' 'backplane' 'login' 'c1'"},
+		l.BuildRemoteLoginCommand(vars, envFlags))
+
+	// ocm-container on the bastion takes the variables as its -e flags.
+	ocm := l.WithCommand("ocm-container --cluster-id %%CLUSTER_ID%%")
+	assert.Equal(t,
+		[]string{"ssh", "-t", "bastion", "exec 'ocm-container' '-e' 'PAGERDUTY_CLUSTER_ID=c1' '--cluster-id' 'c1'"},
+		ocm.RemoteLoginCommand(vars, envFlags))
+
+	// In a container, ssh runs on the host.
+	l.hostExec = container.Toolbox{}
+	assert.Equal(t,
+		[]string{"flatpak-spawn", "--host", "ssh", "-t", "bastion", "exec env PAGERDUTY_CLUSTER_ID='c1' 'ocm' 'backplane' 'login' 'c1'"},
+		l.BuildRemoteDirectCommand(vars, envFlags))
+}
+
+// Every terminal profile opens ssh as the command it runs.
+func TestBuildRemoteLoginCommand_Profiles(t *testing.T) {
+	for _, terminal := range []string{"gnome-terminal", "konsole", "alacritty", "tmux new-window", "Terminal.app"} {
+		l, err := NewClusterLauncherWithToolbox(terminal, "ocm backplane login %%CLUSTER_ID%%", "false", func() bool { return false })
+		require.NoError(t, err, terminal)
+		l.UseBastion("bastion")
+		command := l.BuildRemoteLoginCommand(map[string]string{"%%CLUSTER_ID%%": "c1"}, nil)
+		assert.Contains(t, command[len(command)-1], "'ocm' 'backplane' 'login' 'c1'", terminal)
+		if _, isAppleScript := l.Profile().(*AppleScriptProfile); !isAppleScript {
+			assert.Contains(t, command, "ssh", terminal)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package launcher
+
+import (
+	"errors"
+	"os/exec"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// Session is a command a ClusterLauncher started for an incident's
+// cluster, e.g. the terminal window of a cluster login.
+type Session struct {
+	ID         int
+	IncidentID string
+	ClusterID  string
+	Command    []string
+	PID        int
+	Started    time.Time
+	Exited     time.Time // zero while the process runs
+	ExitCode   int       // -1 when the process did not exit normally
+	// TmuxWindow names the window of a tmux login. The tmux command
+	// exits as soon as the window is open, so the window, not the
+	// process, tells whether the session is still open.
+	TmuxWindow string
+	// Embedded marks a session shown in srepd's own terminal pane.
+	Embedded bool
+}
+
+// Running reports whether the launched process has not exited yet.
+func (s Session) Running() bool {
+	return s.Exited.IsZero()
+}
+
+// SessionRegistry records the sessions launched during this run of srepd.
+// It is safe for concurrent use: logins start and exit in the background.
+type SessionRegistry struct {
+	mu       sync.Mutex
+	sessions []Session
+	now      func() time.Time
+}
+
+// NewSessionRegistry returns an empty registry.
+func NewSessionRegistry() *SessionRegistry {
+	return NewSessionRegistryWithClock(time.Now)
+}
+
+// NewSessionRegistryWithClock returns an empty registry that timestamps
+// sessions with now.
+func NewSessionRegistryWithClock(now func() time.Time) *SessionRegistry {
+	return &SessionRegistry{now: now}
+}
+
+func (r *SessionRegistry) add(s Session) Session {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s.ID = len(r.sessions) + 1
+	s.Started = r.now()
+	r.sessions = append(r.sessions, s)
+	return s
+}
+
+// finish records the exit of a session from the error its process's
+// Wait returned.
+func (r *SessionRegistry) finish(id int, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := slices.IndexFunc(r.sessions, func(s Session) bool { return s.ID == id })
+	if i < 0 {
+		return
+	}
+	r.sessions[i].Exited = r.now()
+	r.sessions[i].ExitCode = exitCode(err)
+}
+
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
+
+// List returns the sessions in the order they were launched.
+func (r *SessionRegistry) List() []Session {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.sessions)
+}
+
+// ForIncident returns the sessions launched for an incident.
+func (r *SessionRegistry) ForIncident(incidentID string) []Session {
+	var sessions []Session
+	for _, s := range r.List() {
+		if s.IncidentID == incidentID {
+			sessions = append(sessions, s)
+		}
+	}
+	return sessions
+}
+
+// Sessions returns the registry the launcher records its sessions in.
+func (l *ClusterLauncher) Sessions() *SessionRegistry {
+	return l.sessions
+}
+
+// RecordSessions makes the launcher record its sessions in r, so several
+// launchers can share one registry.
+func (l *ClusterLauncher) RecordSessions(r *SessionRegistry) {
+	l.sessions = r
+}
+
+// Start starts c and records it as a session of the incident's cluster.
+// The returned wait function waits for c like c.Wait and records how it
+// exited. A launcher without a registry starts c without recording it.
+func (l *ClusterLauncher) Start(c *exec.Cmd, incidentID, clusterID string) (wait func() error, err error) {
+	s := Session{IncidentID: incidentID, ClusterID: clusterID}
+	if l.IsTmux() && incidentID != "" && clusterID != "" {
+		s.TmuxWindow = TmuxWindowName(incidentID, clusterID)
+	}
+	return l.start(c, s)
+}
+
+// StartEmbedded starts c like Start, for a session srepd shows in its own
+// terminal pane rather than a terminal window.
+func (l *ClusterLauncher) StartEmbedded(c *exec.Cmd, incidentID, clusterID string) (wait func() error, err error) {
+	return l.start(c, Session{IncidentID: incidentID, ClusterID: clusterID, Embedded: true})
+}
+
+func (l *ClusterLauncher) start(c *exec.Cmd, s Session) (wait func() error, err error) {
+	if err := c.Start(); err != nil {
+		return nil, err
+	}
+	if l.sessions == nil {
+		return c.Wait, nil
+	}
+
+	s.Command = slices.Clone(c.Args)
+	s.PID = c.Process.Pid
+	s = l.sessions.add(s)
+	log.Debug("launcher.ClusterLauncher.Start(): session started", "session", s.ID, "incident_id", s.IncidentID, "cluster_id", s.ClusterID, "pid", s.PID, "embedded", s.Embedded)
+
+	sessions := l.sessions
+	return func() error {
+		err := c.Wait()
+		sessions.finish(s.ID, err)
+		log.Debug("launcher.ClusterLauncher.Start(): session exited", "session", s.ID, "exit_code", exitCode(err))
+		return err
+	}, nil
+}
@@ -0,0 +1,104 @@
+package launcher
+
+import (
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusterLauncherStart_RecordsSession(t *testing.T) {
+	clock := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	r := NewSessionRegistryWithClock(func() time.Time { return clock })
+	l := ClusterLauncher{}
+	l.RecordSessions(r)
+
+	wait, err := l.Start(exec.Command("sh", "-c", "exit 3"), "Q1AAA", "cluster-a")
+	require.NoError(t, err)
+
+	sessions := r.List()
+	require.Len(t, sessions, 1)
+	s := sessions[0]
+	assert.Equal(t, 1, s.ID)
+	assert.Equal(t, "Q1AAA", s.IncidentID)
+	assert.Equal(t, "cluster-a", s.ClusterID)
+	assert.Equal(t, []string{"sh", "-c", "exit 3"}, s.Command)
+	assert.NotZero(t, s.PID)
+	assert.Equal(t, clock, s.Started)
+	assert.True(t, s.Running())
+	assert.Empty(t, s.TmuxWindow)
+
+	clock = clock.Add(5 * time.Minute)
+	require.Error(t, wait())
+
+	s = r.List()[0]
+	assert.False(t, s.Running())
+	assert.Equal(t, clock, s.Exited)
+	assert.Equal(t, 3, s.ExitCode)
+}
+
+func TestClusterLauncherStart_TmuxWindow(t *testing.T) {
+	l := ClusterLauncher{profile: &TmuxProfile{}}
+	l.RecordSessions(NewSessionRegistry())
+
+	wait, err := l.Start(exec.Command("true"), "Q1AAA", "cluster-a")
+	require.NoError(t, err)
+	require.NoError(t, wait())
+
+	s := l.Sessions().List()[0]
+	assert.Equal(t, "srepd/Q1AAA/cluster-a", s.TmuxWindow)
+	assert.Equal(t, 0, s.ExitCode)
+	assert.False(t, s.Running())
+}
+
+func TestClusterLauncherStartEmbedded(t *testing.T) {
+	l := ClusterLauncher{profile: &TmuxProfile{}}
+	l.RecordSessions(NewSessionRegistry())
+
+	wait, err := l.StartEmbedded(exec.Command("true"), "Q1AAA", "cluster-a")
+	require.NoError(t, err)
+	require.NoError(t, wait())
+
+	s := l.Sessions().List()[0]
+	assert.True(t, s.Embedded)
+	assert.Empty(t, s.TmuxWindow, "an embedded login has no tmux window, even under tmux")
+	assert.False(t, s.Running())
+}
+
+func TestClusterLauncherStart_Failures(t *testing.T) {
+	l := ClusterLauncher{}
+	l.RecordSessions(NewSessionRegistry())
+
+	_, err := l.Start(exec.Command("/nonexistent/terminal"), "Q1AAA", "cluster-a")
+	require.Error(t, err)
+	assert.Empty(t, l.Sessions().List(), "a command that never started is not a session")
+
+	unrecorded := ClusterLauncher{}
+	wait, err := unrecorded.Start(exec.Command("true"), "Q1AAA", "cluster-a")
+	require.NoError(t, err)
+	require.NoError(t, wait())
+	assert.Nil(t, unrecorded.Sessions().List())
+}
+
+func TestSessionRegistry_ForIncident(t *testing.T) {
+	r := NewSessionRegistry()
+	r.add(Session{IncidentID: "Q1AAA", ClusterID: "cluster-a"})
+	r.add(Session{IncidentID: "Q2BBB", ClusterID: "cluster-b"})
+	r.add(Session{IncidentID: "Q1AAA", ClusterID: "cluster-c"})
+
+	sessions := r.ForIncident("Q1AAA")
+	require.Len(t, sessions, 2)
+	assert.Equal(t, []int{1, 3}, []int{sessions[0].ID, sessions[1].ID})
+	assert.Empty(t, r.ForIncident("Q9ZZZ"))
+
+	r.finish(99, nil) // unknown sessions are ignored
+	assert.True(t, r.List()[0].Running())
+}
+
+func TestNewClusterLauncher_HasRegistry(t *testing.T) {
+	l, err := NewClusterLauncherWithToolbox("xterm -e", "ocm-container --cluster-id %%CLUSTER_ID%%", "false", func() bool { return false })
+	require.NoError(t, err)
+	assert.NotNil(t, l.Sessions())
+}
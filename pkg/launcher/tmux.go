@@ -0,0 +1,185 @@
+package launcher
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/charmbracelet/log"
+)
+
+// tmuxWindowPrefix marks the windows srepd opened, so they can be told
+// apart from the user's own.
+const tmuxWindowPrefix = "srepd/"
+
+// tmuxListFormat is the list-windows format parsed by parseTmuxWindows:
+// window ID, session name, window name, and whether the window is the
+// active one of its session.
+const tmuxListFormat = "#{window_id}\t#{session_name}\t#{window_name}\t#{window_active}"
+
+// TmuxProfile is used for tmux. Like GenericProfile it appends the login
+// command to the terminal args, but the launcher also names the window
+// after the incident and cluster so it can be found again.
+type TmuxProfile struct{}
+
+func (p *TmuxProfile) Name() string {
+	return "tmux"
+}
+
+func (p *TmuxProfile) BuildCommand(terminalArgs []string, loginCmd []string) ([]string, error) {
+	return (&GenericProfile{}).BuildCommand(terminalArgs, loginCmd)
+}
+
+// TmuxWindowName returns the name srepd gives the window of a cluster
+// login, e.g. "srepd/Q1234/2a3b4c".
+func TmuxWindowName(incidentID, clusterID string) string {
+	return tmuxWindowPrefix + incidentID + "/" + clusterID
+}
+
+// parseTmuxWindowName returns the incident and cluster IDs from a name
+// made by TmuxWindowName.
+func parseTmuxWindowName(name string) (incidentID, clusterID string, ok bool) {
+	rest, found := strings.CutPrefix(name, tmuxWindowPrefix)
+	if !found {
+		return "", "", false
+	}
+	incidentID, clusterID, found = strings.Cut(rest, "/")
+	if !found || incidentID == "" || clusterID == "" {
+		return "", "", false
+	}
+	return incidentID, clusterID, true
+}
+
+// tmuxWindowArgs names the window a tmux terminal command opens after the
+// incident and cluster in vars, replacing any -n the user configured. A
+// bare "tmux" gets "new-window", since tmux needs a command to run one.
+// Commands that do not open a window are returned unchanged.
+func tmuxWindowArgs(terminalArgs []string, vars map[string]string) []string {
+	incidentID, clusterID := vars["%%INCIDENT_ID%%"], vars["%%CLUSTER_ID%%"]
+	if incidentID == "" || clusterID == "" {
+		return terminalArgs
+	}
+	if len(terminalArgs) == 1 {
+		terminalArgs = []string{terminalArgs[0], "new-window"}
+	}
+	switch terminalArgs[1] {
+	case "new-window", "neww", "new-session", "new":
+	default:
+		return terminalArgs
+	}
+
+	args := make([]string, 0, len(terminalArgs)+2)
+	args = append(args, terminalArgs[:2]...)
+	args = append(args, "-n", TmuxWindowName(incidentID, clusterID))
+	for i := 2; i < len(terminalArgs); i++ {
+		if terminalArgs[i] == "-n" {
+			i++ // drop the configured name as well
+			continue
+		}
+		args = append(args, terminalArgs[i])
+	}
+	return args
+}
+
+// TmuxWindow is a window srepd opened for a cluster login.
+type TmuxWindow struct {
+	ID         string // tmux window ID, e.g. "@3"
+	Session    string
+	Name       string
+	IncidentID string
+	ClusterID  string
+	Active     bool
+}
+
+// Tmux lists, selects and closes the login windows of the tmux server
+// srepd runs under.
+type Tmux struct {
+	run func(args ...string) ([]byte, error)
+}
+
+// NewTmux returns a Tmux for the session srepd runs in, or nil outside
+// tmux. prefix is prepended to every tmux command (e.g. flatpak-spawn
+// --host inside a toolbox).
+func NewTmux(getenv func(string) string, prefix ...string) *Tmux {
+	if getenv("TMUX") == "" {
+		return nil
+	}
+	return NewTmuxWithRunner(func(args ...string) ([]byte, error) {
+		argv := append(append(append([]string{}, prefix...), "tmux"), args...)
+		out, err := exec.Command(argv[0], argv[1:]...).CombinedOutput()
+		if err != nil {
+			return out, fmt.Errorf("tmux %s: %w: %s", args[0], err, strings.TrimSpace(string(out)))
+		}
+		return out, nil
+	})
+}
+
+// NewTmuxWithRunner returns a Tmux that runs tmux commands through run,
+// which receives the arguments after "tmux".
+func NewTmuxWithRunner(run func(args ...string) ([]byte, error)) *Tmux {
+	return &Tmux{run: run}
+}
+
+// Windows lists the login windows across all sessions, in tmux's order.
+func (t *Tmux) Windows() ([]TmuxWindow, error) {
+	out, err := t.run("list-windows", "-a", "-F", tmuxListFormat)
+	if err != nil {
+		return nil, err
+	}
+	return parseTmuxWindows(string(out)), nil
+}
+
+func parseTmuxWindows(out string) []TmuxWindow {
+	var windows []TmuxWindow
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 4 {
+			continue
+		}
+		incidentID, clusterID, ok := parseTmuxWindowName(fields[2])
+		if !ok {
+			continue
+		}
+		windows = append(windows, TmuxWindow{
+			ID:         fields[0],
+			Session:    fields[1],
+			Name:       fields[2],
+			IncidentID: incidentID,
+			ClusterID:  clusterID,
+			Active:     fields[3] == "1",
+		})
+	}
+	return windows
+}
+
+// FindCluster returns the first login window open for a cluster, for any
+// incident.
+func (t *Tmux) FindCluster(clusterID string) (TmuxWindow, bool, error) {
+	windows, err := t.Windows()
+	if err != nil {
+		return TmuxWindow{}, false, err
+	}
+	for _, w := range windows {
+		if w.ClusterID == clusterID {
+			return w, true, nil
+		}
+	}
+	return TmuxWindow{}, false, nil
+}
+
+// Select switches the client to a window's session and selects the window.
+func (t *Tmux) Select(w TmuxWindow) error {
+	log.Debug("launcher.Tmux.Select()", "window", w.ID, "session", w.Session)
+	if _, err := t.run("switch-client", "-t", w.Session); err != nil {
+		return err
+	}
+	_, err := t.run("select-window", "-t", w.ID)
+	return err
+}
+
+// Kill closes a window and the login running in it.
+func (t *Tmux) Kill(w TmuxWindow) error {
+	log.Debug("launcher.Tmux.Kill()", "window", w.ID, "name", w.Name)
+	_, err := t.run("kill-window", "-t", w.ID)
+	return err
+}
@@ -0,0 +1,133 @@
+package launcher
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTmux records tmux invocations and answers list-windows with out.
+type fakeTmux struct {
+	calls [][]string
+	out   string
+	err   error
+}
+
+func (f *fakeTmux) run(args ...string) ([]byte, error) {
+	f.calls = append(f.calls, args)
+	if args[0] == "list-windows" {
+		return []byte(f.out), f.err
+	}
+	return nil, f.err
+}
+
+const tmuxListOutput = "@1\twork\tvim\t1\n" +
+	"@4\twork\tsrepd/Q1AAA/cluster-a\t0\n" +
+	"@7\toncall\tsrepd/Q2BBB/cluster-b\t1\n"
+
+func TestTmuxWindowName_RoundTrip(t *testing.T) {
+	name := TmuxWindowName("Q1AAA", "2a3b-4c5d")
+	assert.Equal(t, "srepd/Q1AAA/2a3b-4c5d", name)
+
+	incidentID, clusterID, ok := parseTmuxWindowName(name)
+	require.True(t, ok)
+	assert.Equal(t, "Q1AAA", incidentID)
+	assert.Equal(t, "2a3b-4c5d", clusterID)
+
+	for _, name := range []string{"vim", "srepd/", "srepd/Q1AAA", "srepd//c1", "srepd/Q1AAA/"} {
+		_, _, ok := parseTmuxWindowName(name)
+		assert.False(t, ok, name)
+	}
+}
+
+func TestTmuxWindowArgs(t *testing.T) {
+	vars := map[string]string{"%%INCIDENT_ID%%": "Q1AAA", "%%CLUSTER_ID%%": "c1"}
+
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"replaces a configured name", []string{"tmux", "new-window", "-n", "c1", "-d"}, []string{"tmux", "new-window", "-n", "srepd/Q1AAA/c1", "-d"}},
+		{"names an unnamed window", []string{"tmux", "neww"}, []string{"tmux", "neww", "-n", "srepd/Q1AAA/c1"}},
+		{"adds new-window to a bare tmux", []string{"tmux"}, []string{"tmux", "new-window", "-n", "srepd/Q1AAA/c1"}},
+		{"leaves other commands alone", []string{"tmux", "split-window", "-h"}, []string{"tmux", "split-window", "-h"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tmuxWindowArgs(tt.args, vars))
+		})
+	}
+
+	assert.Equal(t, []string{"tmux", "new-window", "-n", "c1"},
+		tmuxWindowArgs([]string{"tmux", "new-window", "-n", "c1"}, map[string]string{"%%CLUSTER_ID%%": "c1"}),
+		"without an incident the configured name stays")
+}
+
+func TestBuildLoginCommand_NamesTmuxWindow(t *testing.T) {
+	l, err := NewClusterLauncherWithToolbox("tmux new-window -n %%CLUSTER_ID%%", "ocm-container -C %%CLUSTER_ID%%", "false", func() bool { return false })
+	require.NoError(t, err)
+	assert.True(t, l.IsTmux())
+
+	cmd := l.BuildLoginCommand(map[string]string{"%%INCIDENT_ID%%": "Q1AAA", "%%CLUSTER_ID%%": "c1"})
+	assert.Equal(t, []string{"tmux", "new-window", "-n", "srepd/Q1AAA/c1", "ocm-container", "-C", "c1"}, cmd)
+
+	gnome, err := NewClusterLauncherWithToolbox("gnome-terminal", "ocm-container -C %%CLUSTER_ID%%", "false", func() bool { return false })
+	require.NoError(t, err)
+	assert.False(t, gnome.IsTmux())
+}
+
+func TestNewTmux_OnlyInsideTmux(t *testing.T) {
+	assert.Nil(t, NewTmux(func(string) string { return "" }))
+	assert.NotNil(t, NewTmux(func(k string) string {
+		if k == "TMUX" {
+			return "/tmp/tmux-1000/default,1,0"
+		}
+		return ""
+	}))
+}
+
+func TestTmux_Windows(t *testing.T) {
+	fake := &fakeTmux{out: tmuxListOutput}
+	windows, err := NewTmuxWithRunner(fake.run).Windows()
+	require.NoError(t, err)
+
+	require.Len(t, windows, 2, "windows srepd did not open are skipped")
+	assert.Equal(t, TmuxWindow{ID: "@4", Session: "work", Name: "srepd/Q1AAA/cluster-a", IncidentID: "Q1AAA", ClusterID: "cluster-a"}, windows[0])
+	assert.True(t, windows[1].Active)
+	assert.Equal(t, []string{"list-windows", "-a", "-F", tmuxListFormat}, fake.calls[0])
+}
+
+func TestTmux_FindCluster(t *testing.T) {
+	tmux := NewTmuxWithRunner((&fakeTmux{out: tmuxListOutput}).run)
+
+	w, found, err := tmux.FindCluster("cluster-b")
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, "@7", w.ID)
+
+	_, found, err = tmux.FindCluster("cluster-z")
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	_, _, err = NewTmuxWithRunner((&fakeTmux{err: errors.New("no server running")}).run).FindCluster("c1")
+	assert.ErrorContains(t, err, "no server running")
+}
+
+func TestTmux_SelectAndKill(t *testing.T) {
+	fake := &fakeTmux{}
+	tmux := NewTmuxWithRunner(fake.run)
+	w := TmuxWindow{ID: "@7", Session: "oncall"}
+
+	require.NoError(t, tmux.Select(w))
+	require.NoError(t, tmux.Kill(w))
+
+	var got []string
+	for _, c := range fake.calls {
+		got = append(got, strings.Join(c, " "))
+	}
+	assert.Equal(t, []string{"switch-client -t oncall", "select-window -t @7", "kill-window -t @7"}, got)
+}
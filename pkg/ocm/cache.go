@@ -0,0 +1,445 @@
+package ocm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// Cached data kinds, also the keys of the ocm_cache_ttl config map.
+const (
+	cacheKindCluster        = "cluster"
+	cacheKindServiceLogs    = "service_logs"
+	cacheKindLimitedSupport = "limited_support"
+	cacheKindDetails        = "details"
+)
+
+const (
+	// cacheMaxAge is how long past its TTL an entry is still served while
+	// it is refreshed. Older entries are fetched again before use.
+	cacheMaxAge = 7 * 24 * time.Hour
+	// cacheRefreshTimeout bounds a background refresh.
+	cacheRefreshTimeout = 30 * time.Second
+	// cacheFlushInterval is how often changes are written to disk and the
+	// hit rate logged.
+	cacheFlushInterval = 30 * time.Second
+)
+
+// CacheTTLs are how long each kind of OCM data is fresh. Cluster info
+// rarely changes; service logs are what an SRE is waiting for.
+type CacheTTLs struct {
+	Cluster        time.Duration
+	ServiceLogs    time.Duration
+	LimitedSupport time.Duration
+	Details        time.Duration
+}
+
+// DefaultCacheTTLs are the TTLs of kinds ocm_cache_ttl does not set.
+var DefaultCacheTTLs = CacheTTLs{
+	Cluster:        24 * time.Hour,
+	ServiceLogs:    5 * time.Minute,
+	LimitedSupport: 15 * time.Minute,
+	Details:        time.Hour,
+}
+
+// ParseCacheTTLs applies the ocm_cache_ttl config map (kind → duration) to
+// the defaults.
+func ParseCacheTTLs(raw map[string]string) (CacheTTLs, error) {
+	ttls := DefaultCacheTTLs
+	fields := map[string]*time.Duration{
+		cacheKindCluster:        &ttls.Cluster,
+		cacheKindServiceLogs:    &ttls.ServiceLogs,
+		cacheKindLimitedSupport: &ttls.LimitedSupport,
+		cacheKindDetails:        &ttls.Details,
+	}
+	for kind, v := range raw {
+		field, ok := fields[kind]
+		if !ok {
+			return DefaultCacheTTLs, fmt.Errorf("ocm_cache_ttl: unknown kind %q (known: cluster, service_logs, limited_support, details)", kind)
+		}
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return DefaultCacheTTLs, fmt.Errorf("ocm_cache_ttl %s: %q is not a positive duration", kind, v)
+		}
+		*field = d
+	}
+	return ttls, nil
+}
+
+func (t CacheTTLs) of(kind string) time.Duration {
+	switch kind {
+	case cacheKindCluster:
+		return t.Cluster
+	case cacheKindServiceLogs:
+		return t.ServiceLogs
+	case cacheKindLimitedSupport:
+		return t.LimitedSupport
+	}
+	return t.Details
+}
+
+// DefaultCachePath is the cache file under the user cache directory.
+func DefaultCachePath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("determining cache directory: %w", err)
+	}
+	return filepath.Join(dir, "srepd", "ocm-cache.json"), nil
+}
+
+type cacheEntry struct {
+	Data    json.RawMessage `json:"data"`
+	Fetched time.Time       `json:"fetched"`
+}
+
+// CacheStats counts reads since startup or the last Clear.
+type CacheStats struct {
+	Hits   int // fresh entries
+	Stale  int // entries served while refreshed
+	Misses int // fetched before returning
+}
+
+// HitRate is the share of reads served from the cache, fresh or stale.
+func (s CacheStats) HitRate() float64 {
+	total := s.Hits + s.Stale + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits+s.Stale) / float64(total)
+}
+
+// CachingClient keeps OCM reads on disk across restarts. A fresh entry is
+// returned as is; a stale one is returned at once and refreshed in the
+// background, so the next read is fresh. Writes go to the wrapped client
+// and drop the entries they change.
+type CachingClient struct {
+	inner OCMClient
+	path  string
+	ttls  CacheTTLs
+	now   func() time.Time
+
+	mu       sync.Mutex
+	entries  map[string]cacheEntry
+	inFlight map[string]bool
+	dirty    bool
+	closed   bool
+	stats    CacheStats
+	logged   CacheStats
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewCachingClient wraps inner with the cache file at path. A missing or
+// unreadable file starts an empty cache.
+func NewCachingClient(inner OCMClient, path string, ttls CacheTTLs) *CachingClient {
+	return newCachingClient(inner, path, ttls, time.Now)
+}
+
+func newCachingClient(inner OCMClient, path string, ttls CacheTTLs, now func() time.Time) *CachingClient {
+	c := &CachingClient{
+		inner:    inner,
+		path:     path,
+		ttls:     ttls,
+		now:      now,
+		entries:  make(map[string]cacheEntry),
+		inFlight: make(map[string]bool),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	c.load()
+	go c.flushLoop()
+	return c
+}
+
+// Unwrap returns the wrapped client.
+func (c *CachingClient) Unwrap() OCMClient {
+	return c.inner
+}
+
+func cacheKey(kind, id string) string {
+	return kind + "/" + id
+}
+
+// load reads the cache file, dropping entries too old to serve.
+func (c *CachingClient) load() {
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Warn("OCM cache not loaded", "path", c.path, "error", err)
+		}
+		return
+	}
+	var entries map[string]cacheEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		log.Warn("OCM cache not loaded", "path", c.path, "error", err)
+		return
+	}
+	for key, e := range entries {
+		kind, _, _ := strings.Cut(key, "/")
+		if c.now().Sub(e.Fetched) < c.ttls.of(kind)+cacheMaxAge {
+			c.entries[key] = e
+		}
+	}
+	log.Debug("ocm.CachingClient", "msg", "loaded", "path", c.path, "entries", len(c.entries))
+}
+
+// get returns a cached value for key, fetching it when it is missing or
+// too old, and refreshing it in the background when it is stale.
+func get[T any](ctx context.Context, c *CachingClient, kind, id string, fetch func(context.Context) (T, error)) (T, error) {
+	key := cacheKey(kind, id)
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	age := c.now().Sub(e.Fetched)
+	ttl := c.ttls.of(kind)
+	var cached T
+	if ok && age < ttl+cacheMaxAge && json.Unmarshal(e.Data, &cached) == nil {
+		if age < ttl {
+			c.stats.Hits++
+			c.mu.Unlock()
+			return cached, nil
+		}
+		c.stats.Stale++
+		start := !c.inFlight[key]
+		c.inFlight[key] = true
+		c.mu.Unlock()
+		if start {
+			go refresh(WithService(context.Background(), serviceFromContext(ctx)), c, key, fetch)
+		}
+		return cached, nil
+	}
+	c.stats.Misses++
+	c.mu.Unlock()
+
+	v, err := fetch(ctx)
+	if err != nil {
+		return v, err
+	}
+	c.store(key, v)
+	return v, nil
+}
+
+// refresh fetches a stale entry again. A failed refresh keeps the stale
+// entry, so an OCM outage degrades to old data rather than none.
+func refresh[T any](ctx context.Context, c *CachingClient, key string, fetch func(context.Context) (T, error)) {
+	ctx, cancel := context.WithTimeout(ctx, cacheRefreshTimeout)
+	defer cancel()
+	v, err := fetch(ctx)
+
+	c.mu.Lock()
+	delete(c.inFlight, key)
+	c.mu.Unlock()
+	if err != nil {
+		log.Debug("ocm.CachingClient", "msg", "refresh failed", "key", key, "error", err)
+		return
+	}
+	c.store(key, v)
+}
+
+func (c *CachingClient) store(key string, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		log.Warn("OCM cache entry not stored", "key", key, "error", err)
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return
+	}
+	c.entries[key] = cacheEntry{Data: data, Fetched: c.now()}
+	c.dirty = true
+}
+
+func (c *CachingClient) invalidate(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; ok {
+		delete(c.entries, key)
+		c.dirty = true
+	}
+}
+
+// Stats returns the read counts since startup or the last Clear.
+func (c *CachingClient) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+// Len returns the number of cached entries.
+func (c *CachingClient) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// Clear drops every entry and deletes the cache file.
+func (c *CachingClient) Clear() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]cacheEntry)
+	c.stats, c.logged = CacheStats{}, CacheStats{}
+	c.dirty = false
+	if err := os.Remove(c.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing OCM cache: %w", err)
+	}
+	log.Info("OCM cache cleared", "path", c.path)
+	return nil
+}
+
+func (c *CachingClient) flushLoop() {
+	defer close(c.done)
+	ticker := time.NewTicker(cacheFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			c.flush()
+		case <-c.stop:
+			return
+		}
+	}
+}
+
+// flush writes the cache file when it changed and logs the hit rate when
+// there were reads.
+func (c *CachingClient) flush() {
+	c.mu.Lock()
+	if c.stats != c.logged {
+		log.Debug("ocm.CachingClient", "hits", c.stats.Hits, "stale", c.stats.Stale, "misses", c.stats.Misses,
+			"hit_rate", fmt.Sprintf("%.0f%%", 100*c.stats.HitRate()), "entries", len(c.entries))
+		c.logged = c.stats
+	}
+	if !c.dirty {
+		c.mu.Unlock()
+		return
+	}
+	data, err := json.Marshal(c.entries)
+	c.dirty = false
+	c.mu.Unlock()
+	if err == nil {
+		err = writeCacheFile(c.path, data)
+	}
+	if err != nil {
+		log.Warn("OCM cache not saved", "path", c.path, "error", err)
+	}
+}
+
+// writeCacheFile replaces the cache file atomically. Service logs can hold
+// customer data, so the file is private to the user.
+func writeCacheFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".ocm-cache-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// rememberer is implemented by clients that route later calls by where a
+// cluster was found; a cached GetCluster must still tell them.
+type rememberer interface {
+	remember(clusterID string, info *ClusterInfo)
+}
+
+func (c *CachingClient) GetCluster(ctx context.Context, clusterID string) (*ClusterInfo, error) {
+	info, err := get(ctx, c, cacheKindCluster, clusterID, func(ctx context.Context) (*ClusterInfo, error) {
+		info, err := c.inner.GetCluster(ctx, clusterID)
+		if err != nil || info.Details == nil {
+			return info, err
+		}
+		// Details are cached on their own TTL.
+		stripped := *info
+		stripped.Details = nil
+		return &stripped, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if r, ok := c.inner.(rememberer); ok {
+		r.remember(clusterID, info)
+	}
+	return info, nil
+}
+
+func (c *CachingClient) GetServiceLogs(ctx context.Context, clusterID, externalID string) ([]ServiceLog, error) {
+	return get(ctx, c, cacheKindServiceLogs, clusterID, func(ctx context.Context) ([]ServiceLog, error) {
+		return c.inner.GetServiceLogs(ctx, clusterID, externalID)
+	})
+}
+
+func (c *CachingClient) GetLimitedSupportHistory(ctx context.Context, clusterID string) ([]LimitedSupportReason, error) {
+	return get(ctx, c, cacheKindLimitedSupport, clusterID, func(ctx context.Context) ([]LimitedSupportReason, error) {
+		return c.inner.GetLimitedSupportHistory(ctx, clusterID)
+	})
+}
+
+func (c *CachingClient) GetClusterDetails(ctx context.Context, info *ClusterInfo) (*ClusterDetails, error) {
+	return get(ctx, c, cacheKindDetails, info.ID, func(ctx context.Context) (*ClusterDetails, error) {
+		return c.inner.GetClusterDetails(ctx, info)
+	})
+}
+
+func (c *CachingClient) PostServiceLog(ctx context.Context, entry ServiceLog) error {
+	err := c.inner.PostServiceLog(ctx, entry)
+	if err == nil {
+		c.invalidate(cacheKey(cacheKindServiceLogs, entry.ClusterID))
+	}
+	return err
+}
+
+func (c *CachingClient) AddLimitedSupportReason(ctx context.Context, clusterID string, reason LimitedSupportReason) (*LimitedSupportReason, error) {
+	added, err := c.inner.AddLimitedSupportReason(ctx, clusterID, reason)
+	if err == nil {
+		c.invalidate(cacheKey(cacheKindLimitedSupport, clusterID))
+	}
+	return added, err
+}
+
+func (c *CachingClient) RemoveLimitedSupportReason(ctx context.Context, clusterID, reasonID string) error {
+	err := c.inner.RemoveLimitedSupportReason(ctx, clusterID, reasonID)
+	if err == nil {
+		c.invalidate(cacheKey(cacheKindLimitedSupport, clusterID))
+	}
+	return err
+}
+
+func (c *CachingClient) GetAccessToken(env string) (string, error) {
+	return c.inner.GetAccessToken(env)
+}
+
+func (c *CachingClient) GetBackplaneURL(env string) (string, error) {
+	return c.inner.GetBackplaneURL(env)
+}
+
+// Close saves the cache and closes the wrapped client.
+func (c *CachingClient) Close() {
+	c.mu.Lock()
+	alreadyClosed := c.closed
+	c.closed = true
+	c.mu.Unlock()
+	if !alreadyClosed {
+		close(c.stop)
+		<-c.done
+		c.flush()
+	}
+	c.inner.Close()
+}
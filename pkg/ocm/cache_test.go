@@ -0,0 +1,272 @@
+package ocm
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingClient counts reads and lets a test change what OCM returns
+// while background refreshes run.
+type countingClient struct {
+	*MockClient
+
+	mu       sync.Mutex
+	calls    map[string]int
+	summary  string
+	fetchErr error
+}
+
+func newCountingClient() *countingClient {
+	mock := NewMockClient()
+	mock.Clusters["c1"] = &ClusterInfo{ID: "id-1", ExternalID: "ext-1", Name: "acme", Details: &ClusterDetails{SupportLevel: "Premium"}}
+	return &countingClient{MockClient: mock, calls: make(map[string]int), summary: "first"}
+}
+
+func (c *countingClient) count(method string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls[method]
+}
+
+func (c *countingClient) set(summary string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.summary, c.fetchErr = summary, err
+}
+
+func (c *countingClient) GetCluster(ctx context.Context, clusterID string) (*ClusterInfo, error) {
+	c.mu.Lock()
+	c.calls["GetCluster"]++
+	c.mu.Unlock()
+	return c.MockClient.GetCluster(ctx, clusterID)
+}
+
+func (c *countingClient) GetServiceLogs(_ context.Context, clusterID, _ string) ([]ServiceLog, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls["GetServiceLogs"]++
+	if c.fetchErr != nil {
+		return nil, c.fetchErr
+	}
+	return []ServiceLog{{ClusterID: clusterID, Summary: c.summary}}, nil
+}
+
+type testClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *testClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func cacheTestClient(t *testing.T) (*CachingClient, *countingClient, *testClock) {
+	t.Helper()
+	inner := newCountingClient()
+	clock := &testClock{now: time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)}
+	c := newCachingClient(inner, filepath.Join(t.TempDir(), "srepd", "ocm-cache.json"), DefaultCacheTTLs, clock.Now)
+	t.Cleanup(c.Close)
+	return c, inner, clock
+}
+
+func TestParseCacheTTLs(t *testing.T) {
+	ttls, err := ParseCacheTTLs(nil)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultCacheTTLs, ttls)
+
+	ttls, err = ParseCacheTTLs(map[string]string{"service_logs": "1m", "cluster": "48h"})
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, ttls.ServiceLogs)
+	assert.Equal(t, 48*time.Hour, ttls.Cluster)
+	assert.Equal(t, DefaultCacheTTLs.Details, ttls.Details)
+
+	_, err = ParseCacheTTLs(map[string]string{"reports": "1m"})
+	assert.ErrorContains(t, err, "unknown kind")
+	_, err = ParseCacheTTLs(map[string]string{"cluster": "-1h"})
+	assert.Error(t, err)
+	_, err = ParseCacheTTLs(map[string]string{"cluster": "soon"})
+	assert.Error(t, err)
+}
+
+func TestCachingClient_ImplementsInterface(t *testing.T) {
+	var _ OCMClient = (*CachingClient)(nil)
+}
+
+func TestCachingClient_FreshHit(t *testing.T) {
+	c, inner, _ := cacheTestClient(t)
+
+	for range 3 {
+		info, err := c.GetCluster(context.Background(), "c1")
+		require.NoError(t, err)
+		assert.Equal(t, "acme", info.Name)
+		assert.Nil(t, info.Details, "details are cached on their own TTL")
+	}
+	assert.Equal(t, 1, inner.count("GetCluster"))
+	assert.Equal(t, CacheStats{Hits: 2, Misses: 1}, c.Stats())
+	assert.InDelta(t, 2.0/3, c.Stats().HitRate(), 0.001)
+}
+
+func TestCachingClient_StaleWhileRevalidate(t *testing.T) {
+	c, inner, clock := cacheTestClient(t)
+
+	logs, err := c.GetServiceLogs(context.Background(), "id-1", "ext-1")
+	require.NoError(t, err)
+	assert.Equal(t, "first", logs[0].Summary)
+
+	inner.set("second", nil)
+	clock.Advance(DefaultCacheTTLs.ServiceLogs + time.Second)
+
+	logs, err = c.GetServiceLogs(context.Background(), "id-1", "ext-1")
+	require.NoError(t, err)
+	assert.Equal(t, "first", logs[0].Summary, "stale data is returned at once")
+	assert.Equal(t, 1, c.Stats().Stale)
+
+	require.Eventually(t, func() bool { return inner.count("GetServiceLogs") == 2 }, time.Second, 5*time.Millisecond)
+	require.Eventually(t, func() bool {
+		logs, _ := c.GetServiceLogs(context.Background(), "id-1", "ext-1")
+		return logs[0].Summary == "second"
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestCachingClient_FailedRefreshKeepsStaleData(t *testing.T) {
+	c, inner, clock := cacheTestClient(t)
+
+	_, err := c.GetServiceLogs(context.Background(), "id-1", "ext-1")
+	require.NoError(t, err)
+	inner.set("second", errors.New("OCM unavailable"))
+	clock.Advance(DefaultCacheTTLs.ServiceLogs + time.Second)
+
+	_, err = c.GetServiceLogs(context.Background(), "id-1", "ext-1")
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return inner.count("GetServiceLogs") == 2 }, time.Second, 5*time.Millisecond)
+
+	logs, err := c.GetServiceLogs(context.Background(), "id-1", "ext-1")
+	require.NoError(t, err)
+	assert.Equal(t, "first", logs[0].Summary)
+}
+
+func TestCachingClient_TooOldIsFetched(t *testing.T) {
+	c, inner, clock := cacheTestClient(t)
+
+	_, err := c.GetServiceLogs(context.Background(), "id-1", "ext-1")
+	require.NoError(t, err)
+	inner.set("second", nil)
+	clock.Advance(DefaultCacheTTLs.ServiceLogs + cacheMaxAge)
+
+	logs, err := c.GetServiceLogs(context.Background(), "id-1", "ext-1")
+	require.NoError(t, err)
+	assert.Equal(t, "second", logs[0].Summary)
+	assert.Equal(t, 2, c.Stats().Misses)
+}
+
+func TestCachingClient_ErrorsAreNotCached(t *testing.T) {
+	c, inner, _ := cacheTestClient(t)
+
+	_, err := c.GetCluster(context.Background(), "missing")
+	require.Error(t, err)
+	_, err = c.GetCluster(context.Background(), "missing")
+	require.Error(t, err)
+	assert.Equal(t, 2, inner.count("GetCluster"))
+}
+
+func TestCachingClient_WritesInvalidate(t *testing.T) {
+	c, inner, _ := cacheTestClient(t)
+
+	_, err := c.GetServiceLogs(context.Background(), "id-1", "ext-1")
+	require.NoError(t, err)
+	require.NoError(t, c.PostServiceLog(context.Background(), ServiceLog{ClusterID: "id-1", Summary: "posted"}))
+	_, err = c.GetServiceLogs(context.Background(), "id-1", "ext-1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, inner.count("GetServiceLogs"))
+
+	_, err = c.GetLimitedSupportHistory(context.Background(), "id-1")
+	require.NoError(t, err)
+	_, err = c.AddLimitedSupportReason(context.Background(), "id-1", LimitedSupportReason{Summary: "s"})
+	require.NoError(t, err)
+	reasons, err := c.GetLimitedSupportHistory(context.Background(), "id-1")
+	require.NoError(t, err)
+	assert.Len(t, reasons, 1, "the added reason is not hidden by the cache")
+}
+
+func TestCachingClient_Persistence(t *testing.T) {
+	inner := newCountingClient()
+	path := filepath.Join(t.TempDir(), "srepd", "ocm-cache.json")
+	c := newCachingClient(inner, path, DefaultCacheTTLs, time.Now)
+	_, err := c.GetCluster(context.Background(), "c1")
+	require.NoError(t, err)
+	c.Close()
+
+	st, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), st.Mode().Perm())
+
+	restarted := newCachingClient(newCountingClient(), path, DefaultCacheTTLs, time.Now)
+	defer restarted.Close()
+	info, err := restarted.GetCluster(context.Background(), "c1")
+	require.NoError(t, err)
+	assert.Equal(t, "acme", info.Name)
+	assert.Equal(t, CacheStats{Hits: 1}, restarted.Stats())
+}
+
+func TestCachingClient_CorruptFileStartsEmpty(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ocm-cache.json")
+	require.NoError(t, os.WriteFile(path, []byte("{not json"), 0600))
+
+	c := newCachingClient(newCountingClient(), path, DefaultCacheTTLs, time.Now)
+	defer c.Close()
+	assert.Zero(t, c.Len())
+}
+
+func TestCachingClient_Clear(t *testing.T) {
+	c, inner, _ := cacheTestClient(t)
+
+	_, err := c.GetCluster(context.Background(), "c1")
+	require.NoError(t, err)
+	c.flush()
+	require.FileExists(t, c.path)
+
+	require.NoError(t, c.Clear())
+	assert.NoFileExists(t, c.path)
+	assert.Zero(t, c.Len())
+	assert.Equal(t, CacheStats{}, c.Stats())
+
+	_, err = c.GetCluster(context.Background(), "c1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, inner.count("GetCluster"))
+}
+
+func TestCachingClient_RemembersEnvironmentOnHit(t *testing.T) {
+	multi, _, stage := multiTestClient()
+	c := newCachingClient(multi, filepath.Join(t.TempDir(), "ocm-cache.json"), DefaultCacheTTLs, time.Now)
+	defer c.Close()
+	_, err := c.GetCluster(context.Background(), "stage-ext")
+	require.NoError(t, err)
+
+	// A restart forgets where clusters were found; a cached lookup must
+	// still route the cluster's writes to its environment.
+	restarted := newMultiClient(multi.clients, multi.services)
+	c.inner = restarted
+	info, err := c.GetCluster(context.Background(), "stage-ext")
+	require.NoError(t, err)
+	assert.Equal(t, "staging", info.Environment)
+	assert.Equal(t, "staging", restarted.EnvironmentOf("stage-id"))
+
+	require.NoError(t, c.PostServiceLog(context.Background(), ServiceLog{ClusterID: "stage-id", Summary: "s"}))
+	assert.Len(t, stage.Posted, 1)
+}
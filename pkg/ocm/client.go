@@ -2,6 +2,7 @@ package ocm
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"regexp"
@@ -37,9 +38,11 @@ func ValidClusterID(id string) bool {
 	return clusterIDPattern.MatchString(id)
 }
 
-// Client wraps the OCM SDK connection for cluster enrichment.
+// Client wraps the OCM SDK connection to one environment for cluster
+// enrichment.
 type Client struct {
 	conn *sdk.Connection
+	env  Environment
 }
 
 func sanitizeSearchValue(s string) string {
@@ -126,33 +129,37 @@ func ApplyAuthToken(cfg *ocmconfig.Config, token string) {
 	cfg.AccessToken = token
 }
 
-// NewClientFromConfig builds an OCM client from a pre-loaded config.
-// The config must already have valid tokens set.
+// NewClientFromConfig builds a production OCM client from a pre-loaded
+// config. The config must already have valid tokens set.
 func NewClientFromConfig(cfg *ocmconfig.Config, agentVersion string) (*Client, error) {
 	if cfg == nil {
 		return nil, fmt.Errorf("ocm config is nil")
 	}
+	return newClient(cfg, Environment{Name: ProductionEnvironment, URL: productionURL}, agentVersion, ocmconfig.Save)
+}
 
+// newClient connects to env and saves refreshed tokens with save.
+func newClient(cfg *ocmconfig.Config, env Environment, agentVersion string, save func(*ocmconfig.Config) error) (*Client, error) {
 	conn, err := ocmconn.NewConnection().
 		Config(cfg).
 		AsAgent("srepd/" + agentVersion).
-		WithApiUrl(productionURL).
+		WithApiUrl(env.URL).
 		Build()
 	if err != nil {
-		return nil, fmt.Errorf("ocm connection failed: %w", err)
+		return nil, fmt.Errorf("ocm %s connection failed: %w", env.Name, err)
 	}
 
 	accessToken, refreshToken, err := conn.Tokens()
 	if err == nil {
 		cfg.AccessToken = accessToken
 		cfg.RefreshToken = refreshToken
-		if saveErr := ocmconfig.Save(cfg); saveErr != nil {
-			log.Debug("ocm.NewClientFromConfig", "msg", "failed to save OCM tokens", "error", saveErr)
+		if saveErr := save(cfg); saveErr != nil {
+			log.Debug("ocm.newClient", "msg", "failed to save OCM tokens", "environment", env.Name, "error", saveErr)
 		}
 	}
 
-	log.Debug("ocm.NewClientFromConfig", "msg", "connected to OCM", "url", productionURL)
-	return &Client{conn: conn}, nil
+	log.Debug("ocm.newClient", "msg", "connected to OCM", "environment", env.Name, "url", env.URL)
+	return &Client{conn: conn, env: env}, nil
 }
 
 func applyConfigDefaults(cfg *ocmconfig.Config) {
@@ -228,6 +235,7 @@ func (c *Client) GetCluster(ctx context.Context, key string) (*ClusterInfo, erro
 				clusterResponse, clusterErr := clustersResource.Cluster(internalID).Get().SendContext(ctx)
 				if clusterErr == nil {
 					info := clusterFromResponse(clusterResponse.Body())
+					info.Environment = c.env.Name
 					c.enrichOrganization(ctx, info, orgID)
 					return info, nil
 				}
@@ -254,6 +262,7 @@ func (c *Client) GetCluster(ctx context.Context, key string) (*ClusterInfo, erro
 	}
 
 	info := clusterFromResponse(clustersResponse.Items().Slice()[0])
+	info.Environment = c.env.Name
 	c.enrichOrganization(ctx, info, orgID)
 	return info, nil
 }
@@ -283,6 +292,9 @@ func clusterFromResponse(cluster *cmv1.Cluster) *ClusterInfo {
 	if cluster.CCS() != nil {
 		info.CCS = cluster.CCS().Enabled()
 	}
+	if cluster.Subscription() != nil {
+		info.SubscriptionID = cluster.Subscription().ID()
+	}
 
 	return info
 }
@@ -335,6 +347,37 @@ func (c *Client) GetServiceLogs(ctx context.Context, clusterID, externalID strin
 	return logs, nil
 }
 
+// PostServiceLog sends a service log for the cluster in entry.ClusterUUID,
+// as `osdctl servicelog post` does.
+func (c *Client) PostServiceLog(ctx context.Context, entry ServiceLog) error {
+	log.Debug("ocm.PostServiceLog", "cluster_id", entry.ClusterID, "cluster_uuid", entry.ClusterUUID,
+		"severity", entry.Severity, "internal_only", entry.InternalOnly)
+
+	body, err := logEntryFromServiceLog(entry)
+	if err != nil {
+		return fmt.Errorf("service log invalid: %w", err)
+	}
+	if _, err := c.conn.ServiceLogs().V1().ClusterLogs().Add().Body(body).SendContext(ctx); err != nil {
+		return fmt.Errorf("service log post failed for %s: %w", entry.ClusterID, err)
+	}
+	return nil
+}
+
+func logEntryFromServiceLog(entry ServiceLog) (*slv1.LogEntry, error) {
+	if entry.ClusterUUID == "" {
+		return nil, fmt.Errorf("cluster %s has no external ID", entry.ClusterID)
+	}
+	return slv1.NewLogEntry().
+		ClusterUUID(entry.ClusterUUID).
+		ClusterID(entry.ClusterID).
+		Severity(slv1.Severity(entry.Severity)).
+		ServiceName(entry.ServiceName).
+		Summary(entry.Summary).
+		Description(entry.Description).
+		InternalOnly(entry.InternalOnly).
+		Build()
+}
+
 func (c *Client) GetLimitedSupportHistory(ctx context.Context, clusterID string) ([]LimitedSupportReason, error) {
 	log.Debug("ocm.GetLimitedSupportHistory", "cluster_id", clusterID)
 
@@ -348,13 +391,7 @@ func (c *Client) GetLimitedSupportHistory(ctx context.Context, clusterID string)
 
 	var reasons []LimitedSupportReason
 	response.Items().Each(func(reason *cmv1.LimitedSupportReason) bool {
-		reasons = append(reasons, LimitedSupportReason{
-			ID:            reason.ID(),
-			Summary:       reason.Summary(),
-			Details:       reason.Details(),
-			DetectionType: string(reason.DetectionType()),
-			CreatedAt:     reason.CreationTimestamp().String(),
-		})
+		reasons = append(reasons, limitedSupportReasonFromResponse(reason))
 		return true
 	})
 
@@ -362,7 +399,239 @@ func (c *Client) GetLimitedSupportHistory(ctx context.Context, clusterID string)
 	return reasons, nil
 }
 
-func (c *Client) GetBackplaneURL() (string, error) {
+// AddLimitedSupportReason places the cluster into limited support with a
+// manually detected reason, as `osdctl cluster support post` does. OCM
+// notifies the cluster owner.
+func (c *Client) AddLimitedSupportReason(ctx context.Context, clusterID string, reason LimitedSupportReason) (*LimitedSupportReason, error) {
+	log.Debug("ocm.AddLimitedSupportReason", "cluster_id", clusterID, "summary", reason.Summary)
+
+	body, err := cmv1.NewLimitedSupportReason().
+		Summary(reason.Summary).
+		Details(reason.Details).
+		DetectionType(cmv1.DetectionTypeManual).
+		Build()
+	if err != nil {
+		return nil, fmt.Errorf("limited support reason invalid: %w", err)
+	}
+	response, err := c.conn.ClustersMgmt().V1().Clusters().Cluster(clusterID).
+		LimitedSupportReasons().
+		Add().
+		Body(body).
+		SendContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("limited support add failed for %s: %w", clusterID, err)
+	}
+	added := limitedSupportReasonFromResponse(response.Body())
+	return &added, nil
+}
+
+// RemoveLimitedSupportReason deletes one limited support reason. The
+// cluster leaves limited support once it has none left.
+func (c *Client) RemoveLimitedSupportReason(ctx context.Context, clusterID, reasonID string) error {
+	log.Debug("ocm.RemoveLimitedSupportReason", "cluster_id", clusterID, "reason_id", reasonID)
+
+	_, err := c.conn.ClustersMgmt().V1().Clusters().Cluster(clusterID).
+		LimitedSupportReasons().
+		LimitedSupportReason(reasonID).
+		Delete().
+		SendContext(ctx)
+	if err != nil {
+		return fmt.Errorf("limited support remove failed for %s: %w", clusterID, err)
+	}
+	return nil
+}
+
+func limitedSupportReasonFromResponse(reason *cmv1.LimitedSupportReason) LimitedSupportReason {
+	return LimitedSupportReason{
+		ID:            reason.ID(),
+		Summary:       reason.Summary(),
+		Details:       reason.Details(),
+		DetectionType: string(reason.DetectionType()),
+		CreatedAt:     reason.CreationTimestamp().String(),
+	}
+}
+
+// GetClusterDetails fetches the machine pools (node pools on Hypershift),
+// add-ons, upgrade policies and subscription support level of a cluster.
+// Each is fetched independently: whatever loaded is returned along with
+// the errors of the rest.
+func (c *Client) GetClusterDetails(ctx context.Context, info *ClusterInfo) (*ClusterDetails, error) {
+	log.Debug("ocm.GetClusterDetails", "cluster_id", info.ID, "hypershift", info.Hypershift)
+
+	cluster := c.conn.ClustersMgmt().V1().Clusters().Cluster(info.ID)
+	details := &ClusterDetails{}
+	var errs []error
+
+	if info.Hypershift {
+		pools, err := cluster.NodePools().List().SendContext(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("node pools: %w", err))
+		} else {
+			pools.Items().Each(func(np *cmv1.NodePool) bool {
+				details.MachinePools = append(details.MachinePools, nodePoolFromResponse(np))
+				return true
+			})
+		}
+		policies, err := cluster.ControlPlane().UpgradePolicies().List().SendContext(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("upgrade policies: %w", err))
+		} else {
+			policies.Items().Each(func(p *cmv1.ControlPlaneUpgradePolicy) bool {
+				details.UpgradePolicies = append(details.UpgradePolicies, controlPlaneUpgradePolicyFromResponse(p))
+				return true
+			})
+		}
+	} else {
+		pools, err := cluster.MachinePools().List().SendContext(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("machine pools: %w", err))
+		} else {
+			pools.Items().Each(func(mp *cmv1.MachinePool) bool {
+				details.MachinePools = append(details.MachinePools, machinePoolFromResponse(mp))
+				return true
+			})
+		}
+		policies, err := cluster.UpgradePolicies().List().SendContext(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("upgrade policies: %w", err))
+		} else {
+			policies.Items().Each(func(p *cmv1.UpgradePolicy) bool {
+				policy := upgradePolicyFromResponse(p)
+				// Classic policies keep their state in a subresource
+				state, stateErr := cluster.UpgradePolicies().UpgradePolicy(p.ID()).State().Get().SendContext(ctx)
+				if stateErr != nil {
+					log.Debug("ocm.GetClusterDetails", "msg", "upgrade policy state lookup failed", "policy_id", p.ID(), "error", stateErr)
+				} else {
+					policy.State = string(state.Body().Value())
+				}
+				details.UpgradePolicies = append(details.UpgradePolicies, policy)
+				return true
+			})
+		}
+	}
+
+	addons, err := cluster.Addons().List().SendContext(ctx)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("add-ons: %w", err))
+	} else {
+		addons.Items().Each(func(a *cmv1.AddOnInstallation) bool {
+			details.AddOns = append(details.AddOns, addOnFromResponse(a))
+			return true
+		})
+	}
+
+	if info.SubscriptionID != "" {
+		sub, err := c.conn.AccountsMgmt().V1().Subscriptions().Subscription(info.SubscriptionID).Get().SendContext(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("subscription: %w", err))
+		} else {
+			details.SupportLevel = sub.Body().SupportLevel()
+		}
+	}
+
+	log.Debug("ocm.GetClusterDetails", "cluster_id", info.ID,
+		"machine_pools", len(details.MachinePools), "addons", len(details.AddOns),
+		"upgrade_policies", len(details.UpgradePolicies), "support_level", details.SupportLevel)
+	return details, errors.Join(errs...)
+}
+
+func machinePoolFromResponse(mp *cmv1.MachinePool) MachinePool {
+	pool := MachinePool{
+		ID:                mp.ID(),
+		InstanceType:      mp.InstanceType(),
+		Replicas:          mp.Replicas(),
+		AvailabilityZones: mp.AvailabilityZones(),
+	}
+	if as := mp.Autoscaling(); as != nil {
+		pool.MinReplicas = as.MinReplicas()
+		pool.MaxReplicas = as.MaxReplicas()
+	}
+	return pool
+}
+
+func nodePoolFromResponse(np *cmv1.NodePool) MachinePool {
+	pool := MachinePool{
+		ID:       np.ID(),
+		Replicas: np.Replicas(),
+	}
+	if np.AvailabilityZone() != "" {
+		pool.AvailabilityZones = []string{np.AvailabilityZone()}
+	}
+	if aws := np.AWSNodePool(); aws != nil {
+		pool.InstanceType = aws.InstanceType()
+	}
+	if as := np.Autoscaling(); as != nil {
+		pool.MinReplicas = as.MinReplica()
+		pool.MaxReplicas = as.MaxReplica()
+	}
+	// Autoscaled node pools report their size in the status
+	if st := np.Status(); st != nil && pool.Replicas == 0 {
+		pool.Replicas = st.CurrentReplicas()
+	}
+	if v := np.Version(); v != nil {
+		pool.Version = v.RawID()
+	}
+	return pool
+}
+
+func addOnFromResponse(a *cmv1.AddOnInstallation) AddOn {
+	addon := AddOn{
+		ID:    a.ID(),
+		State: string(a.State()),
+	}
+	if a.Addon() != nil {
+		addon.Name = a.Addon().Name()
+	}
+	if addon.Name == "" {
+		addon.Name = a.ID()
+	}
+	if a.AddonVersion() != nil {
+		addon.Version = a.AddonVersion().ID()
+	}
+	return addon
+}
+
+func upgradePolicyFromResponse(p *cmv1.UpgradePolicy) UpgradePolicy {
+	return UpgradePolicy{
+		ID:           p.ID(),
+		Version:      p.Version(),
+		ScheduleType: string(p.ScheduleType()),
+		Schedule:     p.Schedule(),
+		NextRun:      formatTimestamp(p.NextRun()),
+	}
+}
+
+func controlPlaneUpgradePolicyFromResponse(p *cmv1.ControlPlaneUpgradePolicy) UpgradePolicy {
+	policy := UpgradePolicy{
+		ID:           p.ID(),
+		Version:      p.Version(),
+		ScheduleType: string(p.ScheduleType()),
+		Schedule:     p.Schedule(),
+		NextRun:      formatTimestamp(p.NextRun()),
+	}
+	if p.State() != nil {
+		policy.State = string(p.State().Value())
+	}
+	return policy
+}
+
+func formatTimestamp(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// GetBackplaneURL returns the backplane URL of the client's environment:
+// the configured override, or the one OCM reports. env is the environment
+// asked for; "" means the client's own.
+func (c *Client) GetBackplaneURL(env string) (string, error) {
+	if err := c.checkEnvironment(env); err != nil {
+		return "", err
+	}
+	if c.env.BackplaneURL != "" {
+		return c.env.BackplaneURL, nil
+	}
 	ctx, cancel := context.WithTimeout(context.Background(), ocmRequestTimeout)
 	defer cancel()
 	resp, err := c.conn.ClustersMgmt().V1().Environment().Get().SendContext(ctx)
@@ -377,7 +646,12 @@ func (c *Client) GetBackplaneURL() (string, error) {
 	return url, nil
 }
 
-func (c *Client) GetAccessToken() (string, error) {
+// GetAccessToken returns a current access token for env ("" for the
+// client's own environment).
+func (c *Client) GetAccessToken(env string) (string, error) {
+	if err := c.checkEnvironment(env); err != nil {
+		return "", err
+	}
 	ctx, cancel := context.WithTimeout(context.Background(), ocmRequestTimeout)
 	defer cancel()
 	accessToken, _, err := c.conn.TokensContext(ctx)
@@ -387,6 +661,18 @@ func (c *Client) GetAccessToken() (string, error) {
 	return accessToken, nil
 }
 
+// Environment returns the name of the environment the client connects to.
+func (c *Client) Environment() string {
+	return c.env.Name
+}
+
+func (c *Client) checkEnvironment(env string) error {
+	if env != "" && env != c.env.Name {
+		return fmt.Errorf("OCM environment %q not connected", env)
+	}
+	return nil
+}
+
 func (c *Client) Close() {
 	if c.conn != nil {
 		c.conn.Close() //nolint:errcheck
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -228,3 +229,138 @@ func TestClusterFromResponse(t *testing.T) {
 		assert.Equal(t, "aws", info.CloudProvider)
 	})
 }
+
+func TestClusterFromResponse_SubscriptionID(t *testing.T) {
+	cluster, err := cmv1.NewCluster().
+		ID("id-1").
+		Subscription(cmv1.NewSubscription().ID("sub-123")).
+		Build()
+	require.NoError(t, err)
+
+	assert.Equal(t, "sub-123", clusterFromResponse(cluster).SubscriptionID)
+}
+
+func TestMachinePoolFromResponse(t *testing.T) {
+	t.Run("fixed size", func(t *testing.T) {
+		mp, err := cmv1.NewMachinePool().
+			ID("worker").
+			InstanceType("m5.xlarge").
+			Replicas(3).
+			AvailabilityZones("us-east-1a", "us-east-1b").
+			Build()
+		require.NoError(t, err)
+
+		pool := machinePoolFromResponse(mp)
+
+		assert.Equal(t, MachinePool{ID: "worker", InstanceType: "m5.xlarge", Replicas: 3, AvailabilityZones: []string{"us-east-1a", "us-east-1b"}}, pool)
+		assert.False(t, pool.Autoscaling())
+	})
+
+	t.Run("autoscaling", func(t *testing.T) {
+		mp, err := cmv1.NewMachinePool().
+			ID("gpu").
+			Autoscaling(cmv1.NewMachinePoolAutoscaling().MinReplicas(1).MaxReplicas(4)).
+			Build()
+		require.NoError(t, err)
+
+		pool := machinePoolFromResponse(mp)
+
+		assert.True(t, pool.Autoscaling())
+		assert.Equal(t, 1, pool.MinReplicas)
+		assert.Equal(t, 4, pool.MaxReplicas)
+	})
+}
+
+func TestNodePoolFromResponse(t *testing.T) {
+	np, err := cmv1.NewNodePool().
+		ID("workers-0").
+		AvailabilityZone("us-west-2a").
+		AWSNodePool(cmv1.NewAWSNodePool().InstanceType("m5.2xlarge")).
+		Autoscaling(cmv1.NewNodePoolAutoscaling().MinReplica(2).MaxReplica(6)).
+		Status(cmv1.NewNodePoolStatus().CurrentReplicas(3)).
+		Version(cmv1.NewVersion().RawID("4.16.3")).
+		Build()
+	require.NoError(t, err)
+
+	pool := nodePoolFromResponse(np)
+
+	assert.Equal(t, MachinePool{
+		ID:                "workers-0",
+		InstanceType:      "m5.2xlarge",
+		Replicas:          3,
+		MinReplicas:       2,
+		MaxReplicas:       6,
+		AvailabilityZones: []string{"us-west-2a"},
+		Version:           "4.16.3",
+	}, pool, "an autoscaled pool reports its current size")
+}
+
+func TestAddOnFromResponse(t *testing.T) {
+	a, err := cmv1.NewAddOnInstallation().
+		ID("managed-odh").
+		Addon(cmv1.NewAddOn().Name("OpenShift Data Science")).
+		AddonVersion(cmv1.NewAddOnVersion().ID("2.8.1")).
+		State(cmv1.AddOnInstallationStateFailed).
+		Build()
+	require.NoError(t, err)
+
+	assert.Equal(t, AddOn{ID: "managed-odh", Name: "OpenShift Data Science", Version: "2.8.1", State: "failed"}, addOnFromResponse(a))
+
+	bare, err := cmv1.NewAddOnInstallation().ID("cluster-logging").Build()
+	require.NoError(t, err)
+	assert.Equal(t, "cluster-logging", addOnFromResponse(bare).Name, "the ID stands in for a missing name")
+}
+
+func TestUpgradePolicyFromResponse(t *testing.T) {
+	next := time.Date(2026, 6, 13, 2, 0, 0, 0, time.UTC)
+
+	p, err := cmv1.NewUpgradePolicy().
+		ID("up-1").
+		Version("4.16.12").
+		ScheduleType(cmv1.ScheduleTypeAutomatic).
+		Schedule("0 2 * * 6").
+		NextRun(next).
+		Build()
+	require.NoError(t, err)
+	assert.Equal(t, UpgradePolicy{
+		ID:           "up-1",
+		Version:      "4.16.12",
+		ScheduleType: "automatic",
+		Schedule:     "0 2 * * 6",
+		NextRun:      "2026-06-13T02:00:00Z",
+	}, upgradePolicyFromResponse(p))
+
+	cp, err := cmv1.NewControlPlaneUpgradePolicy().
+		ID("cp-1").
+		Version("4.16.9").
+		ScheduleType(cmv1.ScheduleTypeManual).
+		State(cmv1.NewUpgradePolicyState().Value(cmv1.UpgradePolicyStateValuePending)).
+		Build()
+	require.NoError(t, err)
+	policy := controlPlaneUpgradePolicyFromResponse(cp)
+	assert.Equal(t, "pending", policy.State)
+	assert.Empty(t, policy.NextRun, "an unset next run stays empty")
+}
+
+func TestLogEntryFromServiceLog(t *testing.T) {
+	entry, err := logEntryFromServiceLog(ServiceLog{
+		ClusterID:    "cluster-1",
+		ClusterUUID:  "uuid-1",
+		Severity:     "Warning",
+		ServiceName:  "SREManualAction",
+		Summary:      "SRE investigating",
+		Description:  "details",
+		InternalOnly: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "uuid-1", entry.ClusterUUID())
+	assert.Equal(t, "cluster-1", entry.ClusterID())
+	assert.Equal(t, "Warning", string(entry.Severity()))
+	assert.Equal(t, "SREManualAction", entry.ServiceName())
+	assert.Equal(t, "SRE investigating", entry.Summary())
+	assert.Equal(t, "details", entry.Description())
+	assert.True(t, entry.InternalOnly())
+
+	_, err = logEntryFromServiceLog(ServiceLog{ClusterID: "cluster-1", Summary: "x"})
+	assert.ErrorContains(t, err, "cluster cluster-1 has no external ID")
+}
@@ -0,0 +1,198 @@
+package ocm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	ocmconfig "github.com/openshift-online/ocm-common/pkg/ocm/config"
+	"gopkg.in/yaml.v3"
+)
+
+// ProductionEnvironment is the environment of the default OCM connection,
+// which uses the standard OCM config file (or keyring).
+const ProductionEnvironment = "production"
+
+// environmentURLs are the API URLs of the OCM environments `ocm login
+// --url` accepts by alias.
+var environmentURLs = map[string]string{
+	ProductionEnvironment: productionURL,
+	"staging":             "https://api.stage.openshift.com",
+	"integration":         "https://api.integration.openshift.com",
+}
+
+// Environment is an OCM API srepd looks clusters up in. Environments other
+// than production keep their tokens in their own OCM config file, as
+// written by `OCM_CONFIG=<file> ocm login --url <env>`.
+type Environment struct {
+	Name       string
+	URL        string
+	ConfigFile string
+	// Services are PagerDuty service name substrings whose clusters are
+	// looked up in this environment first.
+	Services []string
+	// BackplaneURL overrides the URL OCM reports for the environment.
+	BackplaneURL string
+}
+
+type rawEnvironment struct {
+	Name         string   `yaml:"name"`
+	URL          string   `yaml:"url"`
+	ConfigFile   string   `yaml:"config_file"`
+	Services     []string `yaml:"services"`
+	BackplaneURL string   `yaml:"backplane_url"`
+}
+
+// ParseEnvironments validates the ocm_environments config value as read by
+// viper (a list of maps). A nil value means production only.
+func ParseEnvironments(raw any) ([]Environment, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	// Round-trip through YAML so viper's loosely-typed maps decode with the
+	// key names a user writes in srepd.yaml.
+	data, err := yaml.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ocm_environments: %w", err)
+	}
+	var entries []rawEnvironment
+	if err := yaml.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("invalid ocm_environments: expected a list of environments: %w", err)
+	}
+
+	envs := make([]Environment, 0, len(entries))
+	seen := map[string]bool{ProductionEnvironment: true}
+	for i, e := range entries {
+		switch {
+		case e.Name == "":
+			return nil, fmt.Errorf("ocm_environments[%d]: name is required", i)
+		case e.Name == ProductionEnvironment:
+			return nil, fmt.Errorf("ocm_environments: %s is always connected through the default OCM config", ProductionEnvironment)
+		case seen[e.Name]:
+			return nil, fmt.Errorf("ocm_environments: duplicate environment %q", e.Name)
+		}
+		seen[e.Name] = true
+
+		url := e.URL
+		if url == "" {
+			url = environmentURLs[e.Name]
+		}
+		if url == "" {
+			return nil, fmt.Errorf("ocm_environments %q: url is required (known names: staging, integration)", e.Name)
+		}
+		if e.ConfigFile == "" {
+			return nil, fmt.Errorf("ocm_environments %q: config_file is required", e.Name)
+		}
+		var services []string
+		for _, s := range e.Services {
+			if s = strings.ToLower(strings.TrimSpace(s)); s != "" {
+				services = append(services, s)
+			}
+		}
+		envs = append(envs, Environment{
+			Name:         e.Name,
+			URL:          strings.TrimSuffix(url, "/"),
+			ConfigFile:   expandHome(e.ConfigFile),
+			Services:     services,
+			BackplaneURL: e.BackplaneURL,
+		})
+	}
+	return envs, nil
+}
+
+// matchesService reports whether the environment claims a PagerDuty service.
+func (e Environment) matchesService(service string) bool {
+	service = strings.ToLower(service)
+	return service != "" && slices.ContainsFunc(e.Services, func(s string) bool { return strings.Contains(service, s) })
+}
+
+func expandHome(path string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	return path
+}
+
+type serviceKey struct{}
+
+// WithService records the PagerDuty service a cluster lookup is for, so a
+// multi-environment client tries the environment claiming it first.
+func WithService(ctx context.Context, service string) context.Context {
+	return context.WithValue(ctx, serviceKey{}, service)
+}
+
+func serviceFromContext(ctx context.Context) string {
+	s, _ := ctx.Value(serviceKey{}).(string)
+	return s
+}
+
+// ConnectEnvironment connects to a non-production environment with the
+// tokens in its config file. There is no browser login here: expired
+// tokens are refreshed with `ocm login`, and the environment is skipped
+// until then.
+func ConnectEnvironment(env Environment, agentVersion string) (*Client, error) {
+	cfg, err := loadConfigFile(env.ConfigFile)
+	if err != nil {
+		return nil, err
+	}
+	applyConfigDefaults(cfg)
+	cfg.URL = env.URL
+
+	armed, reason, err := cfg.Armed()
+	if err != nil {
+		return nil, fmt.Errorf("ocm %s config check failed: %w", env.Name, err)
+	}
+	if !armed {
+		return nil, fmt.Errorf("ocm %s tokens not valid (%s) — run: OCM_CONFIG=%s ocm login --use-auth-code --url %s", env.Name, reason, env.ConfigFile, env.Name)
+	}
+	return newClient(cfg, env, agentVersion, func(c *ocmconfig.Config) error { return saveConfigFile(env.ConfigFile, c) })
+}
+
+// loadConfigFile reads an OCM config file directly; ocmconfig.Load only
+// reads the file named by OCM_CONFIG, which is process-wide.
+func loadConfigFile(path string) (*ocmconfig.Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading OCM config: %w", err)
+	}
+	cfg := new(ocmconfig.Config)
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("parsing OCM config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+func saveConfigFile(path string, cfg *ocmconfig.Config) error {
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// WithEnvironments adds the configured environments to the production
+// client. It returns primary itself when there are none or none connect,
+// so single-environment setups are unchanged.
+func WithEnvironments(primary *Client, envs []Environment, agentVersion string) OCMClient {
+	clients := []*Client{primary}
+	for _, env := range envs {
+		c, err := ConnectEnvironment(env, agentVersion)
+		if err != nil {
+			log.Warn("OCM environment not connected", "environment", env.Name, "error", err)
+			continue
+		}
+		log.Info("OCM environment connected", "environment", env.Name, "url", env.URL)
+		clients = append(clients, c)
+	}
+	if len(clients) == 1 {
+		return primary
+	}
+	return NewMultiClient(clients...)
+}
@@ -0,0 +1,88 @@
+package ocm
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEnvironments(t *testing.T) {
+	t.Run("nil means production only", func(t *testing.T) {
+		envs, err := ParseEnvironments(nil)
+		require.NoError(t, err)
+		assert.Empty(t, envs)
+	})
+
+	t.Run("known names default their URL", func(t *testing.T) {
+		envs, err := ParseEnvironments([]any{
+			map[string]any{"name": "staging", "config_file": "/tmp/ocm-stage.json", "services": []any{" Stage ", ""}},
+			map[string]any{"name": "int", "url": "https://api.integration.openshift.com/", "config_file": "/tmp/ocm-int.json", "backplane_url": "https://bp.example.org"},
+		})
+		require.NoError(t, err)
+		require.Len(t, envs, 2)
+		assert.Equal(t, "https://api.stage.openshift.com", envs[0].URL)
+		assert.Equal(t, []string{"stage"}, envs[0].Services)
+		assert.Equal(t, "https://api.integration.openshift.com", envs[1].URL)
+		assert.Equal(t, "https://bp.example.org", envs[1].BackplaneURL)
+	})
+
+	t.Run("expands the home directory", func(t *testing.T) {
+		home, err := os.UserHomeDir()
+		require.NoError(t, err)
+		envs, err := ParseEnvironments([]any{map[string]any{"name": "staging", "config_file": "~/.config/ocm/stage.json"}})
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(home, ".config/ocm/stage.json"), envs[0].ConfigFile)
+	})
+
+	for name, raw := range map[string]any{
+		"missing name":        []any{map[string]any{"config_file": "/tmp/x.json"}},
+		"production":          []any{map[string]any{"name": "production", "config_file": "/tmp/x.json"}},
+		"duplicate":           []any{map[string]any{"name": "staging", "config_file": "/tmp/a.json"}, map[string]any{"name": "staging", "config_file": "/tmp/b.json"}},
+		"unknown without url": []any{map[string]any{"name": "lab", "config_file": "/tmp/x.json"}},
+		"missing config file": []any{map[string]any{"name": "staging"}},
+		"not a list":          "staging",
+	} {
+		t.Run("rejects "+name, func(t *testing.T) {
+			_, err := ParseEnvironments(raw)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestEnvironment_MatchesService(t *testing.T) {
+	env := Environment{Services: []string{"stage", "integration"}}
+	assert.True(t, env.matchesService("OSD Stage Cluster Alerts"))
+	assert.False(t, env.matchesService("OSD Production Alerts"))
+	assert.False(t, env.matchesService(""))
+}
+
+func TestWithService(t *testing.T) {
+	assert.Equal(t, "", serviceFromContext(context.Background()))
+	assert.Equal(t, "svc", serviceFromContext(WithService(context.Background(), "svc")))
+}
+
+func TestConnectEnvironment(t *testing.T) {
+	t.Run("missing config file", func(t *testing.T) {
+		_, err := ConnectEnvironment(Environment{Name: "staging", ConfigFile: filepath.Join(t.TempDir(), "missing.json")}, "test")
+		assert.ErrorContains(t, err, "reading OCM config")
+	})
+
+	t.Run("config without tokens suggests ocm login", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "stage.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{}`), 0600))
+		_, err := ConnectEnvironment(Environment{Name: "staging", URL: "https://api.stage.openshift.com", ConfigFile: path}, "test")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "OCM_CONFIG="+path+" ocm login")
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "stage.json")
+		require.NoError(t, os.WriteFile(path, []byte(`not json`), 0600))
+		_, err := ConnectEnvironment(Environment{Name: "staging", ConfigFile: path}, "test")
+		assert.ErrorContains(t, err, "parsing OCM config")
+	})
+}
@@ -20,6 +20,39 @@ type fixtureCluster struct {
 	CCS            bool   `json:"ccs"`
 	Organization   string `json:"organization"`
 	OrganizationID string `json:"organization_id"`
+	SubscriptionID string `json:"subscription_id"`
+
+	// Details served by GetClusterDetails
+	MachinePools    []fixtureMachinePool   `json:"machine_pools"`
+	AddOns          []fixtureAddOn         `json:"addons"`
+	UpgradePolicies []fixtureUpgradePolicy `json:"upgrade_policies"`
+	SupportLevel    string                 `json:"support_level"`
+}
+
+type fixtureMachinePool struct {
+	ID                string   `json:"id"`
+	InstanceType      string   `json:"instance_type"`
+	Replicas          int      `json:"replicas"`
+	MinReplicas       int      `json:"min_replicas"`
+	MaxReplicas       int      `json:"max_replicas"`
+	AvailabilityZones []string `json:"availability_zones"`
+	Version           string   `json:"version"`
+}
+
+type fixtureAddOn struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	State   string `json:"state"`
+}
+
+type fixtureUpgradePolicy struct {
+	ID           string `json:"id"`
+	Version      string `json:"version"`
+	ScheduleType string `json:"schedule_type"`
+	Schedule     string `json:"schedule"`
+	NextRun      string `json:"next_run"`
+	State        string `json:"state"`
 }
 
 type fixtureServiceLog struct {
@@ -84,11 +117,34 @@ func loadClusterFixtures(path string, mock *MockClient) error {
 			CCS:            fc.CCS,
 			Organization:   fc.Organization,
 			OrganizationID: fc.OrganizationID,
+			SubscriptionID: fc.SubscriptionID,
+		}
+		if details := fc.details(); details != nil {
+			mock.Details[fc.ID] = details
 		}
 	}
 	return nil
 }
 
+// details returns the fixture's GetClusterDetails data, or nil when it
+// has none.
+func (fc fixtureCluster) details() *ClusterDetails {
+	if len(fc.MachinePools) == 0 && len(fc.AddOns) == 0 && len(fc.UpgradePolicies) == 0 && fc.SupportLevel == "" {
+		return nil
+	}
+	details := &ClusterDetails{SupportLevel: fc.SupportLevel}
+	for _, mp := range fc.MachinePools {
+		details.MachinePools = append(details.MachinePools, MachinePool(mp))
+	}
+	for _, a := range fc.AddOns {
+		details.AddOns = append(details.AddOns, AddOn(a))
+	}
+	for _, up := range fc.UpgradePolicies {
+		details.UpgradePolicies = append(details.UpgradePolicies, UpgradePolicy(up))
+	}
+	return details
+}
+
 func loadServiceLogFixtures(path string, mock *MockClient) error {
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -3,6 +3,9 @@ package ocm
 import (
 	"context"
 	"fmt"
+	"slices"
+	"sync"
+	"time"
 )
 
 // MockClient implements OCMClient for testing and dev mode.
@@ -10,6 +13,17 @@ type MockClient struct {
 	Clusters       map[string]*ClusterInfo
 	ServiceLogs    map[string][]ServiceLog
 	LimitedSupport map[string][]LimitedSupportReason
+	Details        map[string]*ClusterDetails // keyed by internal cluster ID
+	// Posted records PostServiceLog calls; PostErr makes them fail.
+	Posted  []ServiceLog
+	PostErr error
+	// LimitedSupportErr makes limited support changes fail.
+	LimitedSupportErr error
+	// Env is the environment the mock stands in for; "" is production.
+	Env string
+
+	mu         sync.Mutex // guards LimitedSupport against dev-mode changes
+	nextReason int
 }
 
 // NewMockClient creates a MockClient with initialized maps.
@@ -18,6 +32,7 @@ func NewMockClient() *MockClient {
 		Clusters:       make(map[string]*ClusterInfo),
 		ServiceLogs:    make(map[string][]ServiceLog),
 		LimitedSupport: make(map[string][]LimitedSupportReason),
+		Details:        make(map[string]*ClusterDetails),
 	}
 }
 
@@ -38,19 +53,74 @@ func (m *MockClient) GetServiceLogs(_ context.Context, clusterID, _ string) ([]S
 }
 
 func (m *MockClient) GetLimitedSupportHistory(_ context.Context, clusterID string) ([]LimitedSupportReason, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	reasons, ok := m.LimitedSupport[clusterID]
 	if !ok {
 		return []LimitedSupportReason{}, nil
 	}
-	return reasons, nil
+	return slices.Clone(reasons), nil
+}
+
+func (m *MockClient) GetClusterDetails(_ context.Context, info *ClusterInfo) (*ClusterDetails, error) {
+	details, ok := m.Details[info.ID]
+	if !ok {
+		return &ClusterDetails{}, nil
+	}
+	return details, nil
+}
+
+func (m *MockClient) PostServiceLog(_ context.Context, entry ServiceLog) error {
+	if m.PostErr != nil {
+		return m.PostErr
+	}
+	m.Posted = append(m.Posted, entry)
+	return nil
+}
+
+func (m *MockClient) AddLimitedSupportReason(_ context.Context, clusterID string, reason LimitedSupportReason) (*LimitedSupportReason, error) {
+	if m.LimitedSupportErr != nil {
+		return nil, m.LimitedSupportErr
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nextReason++
+	reason.ID = fmt.Sprintf("ls-mock-%d", m.nextReason)
+	reason.DetectionType = "manual"
+	reason.CreatedAt = time.Now().UTC().Format(time.RFC3339)
+	m.LimitedSupport[clusterID] = append(m.LimitedSupport[clusterID], reason)
+	return &reason, nil
 }
 
-func (m *MockClient) GetAccessToken() (string, error) {
+func (m *MockClient) RemoveLimitedSupportReason(_ context.Context, clusterID, reasonID string) error {
+	if m.LimitedSupportErr != nil {
+		return m.LimitedSupportErr
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	reasons := m.LimitedSupport[clusterID]
+	i := slices.IndexFunc(reasons, func(r LimitedSupportReason) bool { return r.ID == reasonID })
+	if i < 0 {
+		return fmt.Errorf("limited support reason %q not found on cluster %q", reasonID, clusterID)
+	}
+	m.LimitedSupport[clusterID] = slices.Delete(slices.Clone(reasons), i, i+1)
+	return nil
+}
+
+func (m *MockClient) GetAccessToken(_ string) (string, error) {
 	return "mock-access-token", nil
 }
 
-func (m *MockClient) GetBackplaneURL() (string, error) {
+func (m *MockClient) GetBackplaneURL(_ string) (string, error) {
 	return "https://mock-backplane.example.com", nil
 }
 
+// Environment returns Env, or production when it is unset.
+func (m *MockClient) Environment() string {
+	if m.Env == "" {
+		return ProductionEnvironment
+	}
+	return m.Env
+}
+
 func (m *MockClient) Close() {}
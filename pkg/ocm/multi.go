@@ -0,0 +1,184 @@
+package ocm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/charmbracelet/log"
+)
+
+// environmentClient is the part of Client a MultiClient routes to; tests
+// substitute mocks.
+type environmentClient interface {
+	OCMClient
+	Environment() string
+}
+
+// MultiClient looks clusters up across OCM environments. GetCluster tries
+// each environment until one knows the cluster and remembers where it was
+// found; every other call goes to that environment.
+type MultiClient struct {
+	clients  []environmentClient // primary first
+	services map[string][]string // environment → PagerDuty service substrings
+
+	mu         sync.RWMutex
+	clusterEnv map[string]string // internal and external cluster ID → environment
+}
+
+// NewMultiClient routes between clients; the first is the primary, used
+// for clusters not yet looked up.
+func NewMultiClient(clients ...*Client) *MultiClient {
+	envClients := make([]environmentClient, 0, len(clients))
+	services := make(map[string][]string)
+	for _, c := range clients {
+		envClients = append(envClients, c)
+		services[c.env.Name] = c.env.Services
+	}
+	return newMultiClient(envClients, services)
+}
+
+func newMultiClient(clients []environmentClient, services map[string][]string) *MultiClient {
+	return &MultiClient{clients: clients, services: services, clusterEnv: make(map[string]string)}
+}
+
+// Environments lists the connected environments, primary first.
+func (m *MultiClient) Environments() []string {
+	names := make([]string, 0, len(m.clients))
+	for _, c := range m.clients {
+		names = append(names, c.Environment())
+	}
+	return names
+}
+
+// lookupOrder is the order GetCluster tries environments in: those
+// claiming the incident's service first, then the rest in configured order.
+func (m *MultiClient) lookupOrder(service string) []environmentClient {
+	var claimed, rest []environmentClient
+	for _, c := range m.clients {
+		if (Environment{Services: m.services[c.Environment()]}).matchesService(service) {
+			claimed = append(claimed, c)
+		} else {
+			rest = append(rest, c)
+		}
+	}
+	return append(claimed, rest...)
+}
+
+func (m *MultiClient) GetCluster(ctx context.Context, clusterID string) (*ClusterInfo, error) {
+	var errs []error
+	for _, c := range m.lookupOrder(serviceFromContext(ctx)) {
+		info, err := c.GetCluster(ctx, clusterID)
+		if err != nil {
+			log.Debug("ocm.MultiClient.GetCluster", "cluster_id", clusterID, "environment", c.Environment(), "error", err)
+			errs = append(errs, fmt.Errorf("%s: %w", c.Environment(), err))
+			continue
+		}
+		tagged := *info
+		tagged.Environment = c.Environment()
+		m.remember(clusterID, &tagged)
+		return &tagged, nil
+	}
+	return nil, errors.Join(errs...)
+}
+
+// remember routes a cluster's later calls to the environment it was found
+// in, by every ID it is known by.
+func (m *MultiClient) remember(clusterID string, info *ClusterInfo) {
+	if info.Environment == "" {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, id := range []string{clusterID, info.ID, info.ExternalID} {
+		if id != "" {
+			m.clusterEnv[id] = info.Environment
+		}
+	}
+}
+
+// clientFor returns the client of the environment a cluster was found in,
+// or the primary for a cluster not yet looked up.
+func (m *MultiClient) clientFor(clusterID string) environmentClient {
+	m.mu.RLock()
+	env := m.clusterEnv[clusterID]
+	m.mu.RUnlock()
+	return m.clientForEnvironment(env)
+}
+
+func (m *MultiClient) clientForEnvironment(env string) environmentClient {
+	if c, err := m.environment(env); err == nil {
+		return c
+	}
+	return m.clients[0]
+}
+
+// EnvironmentOf returns the environment a cluster was found in ("" when it
+// has not been looked up).
+func (m *MultiClient) EnvironmentOf(clusterID string) string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.clusterEnv[clusterID]
+}
+
+func (m *MultiClient) GetServiceLogs(ctx context.Context, clusterID, externalID string) ([]ServiceLog, error) {
+	return m.clientFor(clusterID).GetServiceLogs(ctx, clusterID, externalID)
+}
+
+func (m *MultiClient) GetLimitedSupportHistory(ctx context.Context, clusterID string) ([]LimitedSupportReason, error) {
+	return m.clientFor(clusterID).GetLimitedSupportHistory(ctx, clusterID)
+}
+
+func (m *MultiClient) GetClusterDetails(ctx context.Context, info *ClusterInfo) (*ClusterDetails, error) {
+	if info.Environment != "" {
+		return m.clientForEnvironment(info.Environment).GetClusterDetails(ctx, info)
+	}
+	return m.clientFor(info.ID).GetClusterDetails(ctx, info)
+}
+
+func (m *MultiClient) PostServiceLog(ctx context.Context, entry ServiceLog) error {
+	return m.clientFor(entry.ClusterID).PostServiceLog(ctx, entry)
+}
+
+func (m *MultiClient) AddLimitedSupportReason(ctx context.Context, clusterID string, reason LimitedSupportReason) (*LimitedSupportReason, error) {
+	return m.clientFor(clusterID).AddLimitedSupportReason(ctx, clusterID, reason)
+}
+
+func (m *MultiClient) RemoveLimitedSupportReason(ctx context.Context, clusterID, reasonID string) error {
+	return m.clientFor(clusterID).RemoveLimitedSupportReason(ctx, clusterID, reasonID)
+}
+
+func (m *MultiClient) GetAccessToken(env string) (string, error) {
+	c, err := m.environment(env)
+	if err != nil {
+		return "", err
+	}
+	return c.GetAccessToken("")
+}
+
+func (m *MultiClient) GetBackplaneURL(env string) (string, error) {
+	c, err := m.environment(env)
+	if err != nil {
+		return "", err
+	}
+	return c.GetBackplaneURL("")
+}
+
+// environment returns the client for env, the primary for "".
+func (m *MultiClient) environment(env string) (environmentClient, error) {
+	if env == "" {
+		return m.clients[0], nil
+	}
+	if i := slices.IndexFunc(m.clients, func(c environmentClient) bool { return c.Environment() == env }); i >= 0 {
+		return m.clients[i], nil
+	}
+	return nil, fmt.Errorf("OCM environment %q not connected", env)
+}
+
+func (m *MultiClient) Close() {
+	for _, c := range m.clients {
+		c.Close()
+	}
+}
@@ -0,0 +1,100 @@
+package ocm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func multiTestClient() (*MultiClient, *MockClient, *MockClient) {
+	prod := NewMockClient()
+	prod.Clusters["prod-ext"] = &ClusterInfo{ID: "prod-id", ExternalID: "prod-ext"}
+	stage := NewMockClient()
+	stage.Env = "staging"
+	stage.Clusters["stage-ext"] = &ClusterInfo{ID: "stage-id", ExternalID: "stage-ext"}
+	multi := newMultiClient([]environmentClient{prod, stage}, map[string][]string{"staging": {"stage"}})
+	return multi, prod, stage
+}
+
+func TestMultiClient_ImplementsInterface(t *testing.T) {
+	var _ OCMClient = (*MultiClient)(nil)
+}
+
+func TestMultiClient_GetCluster(t *testing.T) {
+	t.Run("falls back to later environments", func(t *testing.T) {
+		multi, _, _ := multiTestClient()
+
+		info, err := multi.GetCluster(context.Background(), "stage-ext")
+		require.NoError(t, err)
+		assert.Equal(t, "staging", info.Environment)
+		assert.Equal(t, "staging", multi.EnvironmentOf("stage-id"))
+		assert.Equal(t, "staging", multi.EnvironmentOf("stage-ext"))
+	})
+
+	t.Run("tags production clusters", func(t *testing.T) {
+		multi, prod, _ := multiTestClient()
+
+		info, err := multi.GetCluster(context.Background(), "prod-ext")
+		require.NoError(t, err)
+		assert.Equal(t, ProductionEnvironment, info.Environment)
+		assert.Empty(t, prod.Clusters["prod-ext"].Environment, "the environment client's info is not modified")
+	})
+
+	t.Run("tries the environment claiming the service first", func(t *testing.T) {
+		multi, prod, _ := multiTestClient()
+		// A cluster ID known to both environments resolves to the one
+		// owning the incident's service.
+		multi.clients[1].(*MockClient).Clusters["prod-ext"] = &ClusterInfo{ID: "dup-id", ExternalID: "prod-ext"}
+
+		info, err := multi.GetCluster(WithService(context.Background(), "OSD Stage Alerts"), "prod-ext")
+		require.NoError(t, err)
+		assert.Equal(t, "staging", info.Environment)
+
+		info, err = multi.GetCluster(context.Background(), "prod-ext")
+		require.NoError(t, err)
+		assert.Equal(t, ProductionEnvironment, info.Environment)
+		assert.Equal(t, prod.Clusters["prod-ext"].ID, info.ID)
+	})
+
+	t.Run("joins the errors of every environment", func(t *testing.T) {
+		multi, _, _ := multiTestClient()
+
+		_, err := multi.GetCluster(context.Background(), "nowhere")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "production:")
+		assert.Contains(t, err.Error(), "staging:")
+	})
+}
+
+func TestMultiClient_RoutesByCluster(t *testing.T) {
+	multi, prod, stage := multiTestClient()
+	_, err := multi.GetCluster(context.Background(), "stage-ext")
+	require.NoError(t, err)
+
+	require.NoError(t, multi.PostServiceLog(context.Background(), ServiceLog{ClusterID: "stage-id", Summary: "s"}))
+	assert.Len(t, stage.Posted, 1)
+	assert.Empty(t, prod.Posted)
+
+	_, err = multi.AddLimitedSupportReason(context.Background(), "stage-id", LimitedSupportReason{Summary: "s"})
+	require.NoError(t, err)
+	assert.Len(t, stage.LimitedSupport["stage-id"], 1)
+
+	require.NoError(t, multi.PostServiceLog(context.Background(), ServiceLog{ClusterID: "unknown", Summary: "s"}))
+	assert.Len(t, prod.Posted, 1, "clusters not looked up go to the primary")
+}
+
+func TestMultiClient_Environments(t *testing.T) {
+	multi, _, _ := multiTestClient()
+	assert.Equal(t, []string{ProductionEnvironment, "staging"}, multi.Environments())
+
+	url, err := multi.GetBackplaneURL("staging")
+	require.NoError(t, err)
+	assert.NotEmpty(t, url)
+
+	_, err = multi.GetBackplaneURL("integration")
+	assert.ErrorContains(t, err, "not connected")
+	_, err = multi.GetAccessToken("integration")
+	assert.Error(t, err)
+}
@@ -16,6 +16,56 @@ type ClusterInfo struct {
 	CCS            bool
 	Organization   string
 	OrganizationID string
+	SubscriptionID string
+	// Environment is the OCM environment the cluster was found in.
+	Environment string
+	// Details is nil until fetched with GetClusterDetails; it is too slow
+	// to load for every cluster in the incident table.
+	Details *ClusterDetails
+}
+
+// ClusterDetails is the triage data fetched when a cluster is inspected.
+type ClusterDetails struct {
+	MachinePools    []MachinePool
+	AddOns          []AddOn
+	UpgradePolicies []UpgradePolicy
+	SupportLevel    string
+}
+
+// MachinePool is a machine pool, or a node pool on a Hypershift cluster.
+// MinReplicas and MaxReplicas are set when the pool autoscales.
+type MachinePool struct {
+	ID                string
+	InstanceType      string
+	Replicas          int
+	MinReplicas       int
+	MaxReplicas       int
+	AvailabilityZones []string
+	Version           string
+}
+
+// Autoscaling reports whether the pool has an autoscaling range.
+func (p MachinePool) Autoscaling() bool {
+	return p.MaxReplicas > 0
+}
+
+// AddOn is an add-on installed on a cluster.
+type AddOn struct {
+	ID      string
+	Name    string
+	Version string
+	State   string
+}
+
+// UpgradePolicy is a scheduled cluster, or Hypershift control plane,
+// upgrade.
+type UpgradePolicy struct {
+	ID           string
+	Version      string
+	ScheduleType string
+	Schedule     string
+	NextRun      string
+	State        string
 }
 
 // ServiceLog represents a single service log entry.
@@ -44,7 +94,13 @@ type OCMClient interface {
 	GetCluster(ctx context.Context, clusterID string) (*ClusterInfo, error)
 	GetServiceLogs(ctx context.Context, clusterID, externalID string) ([]ServiceLog, error)
 	GetLimitedSupportHistory(ctx context.Context, clusterID string) ([]LimitedSupportReason, error)
-	GetAccessToken() (string, error)
-	GetBackplaneURL() (string, error)
+	GetClusterDetails(ctx context.Context, info *ClusterInfo) (*ClusterDetails, error)
+	PostServiceLog(ctx context.Context, entry ServiceLog) error
+	AddLimitedSupportReason(ctx context.Context, clusterID string, reason LimitedSupportReason) (*LimitedSupportReason, error)
+	RemoveLimitedSupportReason(ctx context.Context, clusterID, reasonID string) error
+	// GetAccessToken and GetBackplaneURL take an environment name; ""
+	// means the primary (production) environment.
+	GetAccessToken(env string) (string, error)
+	GetBackplaneURL(env string) (string, error)
 	Close()
 }
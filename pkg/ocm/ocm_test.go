@@ -110,6 +110,56 @@ func TestMockClient_GetLimitedSupportHistory(t *testing.T) {
 	})
 }
 
+func TestMockClient_GetClusterDetails(t *testing.T) {
+	mock := NewMockClient()
+	mock.Details["cluster-1"] = &ClusterDetails{SupportLevel: "Premium"}
+
+	details, err := mock.GetClusterDetails(context.Background(), &ClusterInfo{ID: "cluster-1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Premium", details.SupportLevel)
+
+	details, err = mock.GetClusterDetails(context.Background(), &ClusterInfo{ID: "other"})
+	assert.NoError(t, err)
+	assert.Equal(t, &ClusterDetails{}, details, "a cluster without fixture details has none")
+}
+
+func TestMockClient_PostServiceLog(t *testing.T) {
+	mock := NewMockClient()
+	entry := ServiceLog{ClusterUUID: "uuid-1", Summary: "SRE investigating"}
+
+	assert.NoError(t, mock.PostServiceLog(context.Background(), entry))
+	assert.Equal(t, []ServiceLog{entry}, mock.Posted)
+
+	mock.PostErr = assert.AnError
+	assert.ErrorIs(t, mock.PostServiceLog(context.Background(), entry), assert.AnError)
+	assert.Len(t, mock.Posted, 1, "a failed post is not recorded")
+}
+
+func TestMockClient_LimitedSupportChanges(t *testing.T) {
+	mock := NewMockClient()
+	ctx := context.Background()
+
+	added, err := mock.AddLimitedSupportReason(ctx, "cluster-1", LimitedSupportReason{Summary: "s", Details: "d"})
+	require.NoError(t, err)
+	assert.Equal(t, "ls-mock-1", added.ID)
+	assert.Equal(t, "manual", added.DetectionType)
+	assert.NotEmpty(t, added.CreatedAt)
+
+	reasons, err := mock.GetLimitedSupportHistory(ctx, "cluster-1")
+	require.NoError(t, err)
+	assert.Equal(t, []LimitedSupportReason{*added}, reasons)
+
+	assert.ErrorContains(t, mock.RemoveLimitedSupportReason(ctx, "cluster-1", "ls-404"), "not found")
+	require.NoError(t, mock.RemoveLimitedSupportReason(ctx, "cluster-1", added.ID))
+	reasons, err = mock.GetLimitedSupportHistory(ctx, "cluster-1")
+	require.NoError(t, err)
+	assert.Empty(t, reasons)
+
+	mock.LimitedSupportErr = assert.AnError
+	_, err = mock.AddLimitedSupportReason(ctx, "cluster-1", LimitedSupportReason{})
+	assert.ErrorIs(t, err, assert.AnError)
+}
+
 func TestClientInterface(t *testing.T) {
 	t.Run("mock implements OCMClient interface", func(t *testing.T) {
 		var _ OCMClient = (*MockClient)(nil)
@@ -235,4 +285,18 @@ func TestLoadMockClientFromFixtures(t *testing.T) {
 		assert.Equal(t, "us-east-1", info.Region)
 		assert.True(t, info.CCS)
 	})
+
+	t.Run("cluster details fixtures are keyed by internal ID", func(t *testing.T) {
+		mock, err := LoadMockClientFromFixtures("../../testdata/fixtures")
+		require.NoError(t, err)
+
+		info, err := mock.GetCluster(context.Background(), "a4ba96fe-fake-uuid-test-17d38eeaab99")
+		require.NoError(t, err)
+		details, err := mock.GetClusterDetails(context.Background(), info)
+		require.NoError(t, err)
+		assert.Equal(t, "Standard", details.SupportLevel)
+		assert.Len(t, details.MachinePools, 2)
+		assert.True(t, details.MachinePools[1].Autoscaling())
+		assert.Equal(t, "pending", details.UpgradePolicies[0].State)
+	})
 }
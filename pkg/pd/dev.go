@@ -661,6 +661,81 @@ func (d *DevPagerDutyClient) MergeIncidentsWithContext(_ context.Context, _ stri
 	return &copy, nil
 }
 
+func (d *DevPagerDutyClient) CreateIncidentWithContext(_ context.Context, _ string, o *pagerduty.CreateIncidentOptions) (*pagerduty.Incident, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var service pagerduty.APIObject
+	for _, inc := range d.incidents {
+		if o.Service != nil && inc.Service.ID == o.Service.ID {
+			service = inc.Service
+			break
+		}
+	}
+	if service.ID == "" {
+		return nil, fmt.Errorf("DevPagerDutyClient: service %v not found", o.Service)
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	incident := &pagerduty.Incident{
+		APIObject: pagerduty.APIObject{
+			ID:   rand.ID("Q"),
+			Type: "incident",
+		},
+		Title:              o.Title,
+		Status:             "triggered",
+		Urgency:            o.Urgency,
+		CreatedAt:          now,
+		LastStatusChangeAt: now,
+		Service:            service,
+	}
+	d.incidents[incident.ID] = incident
+	log.Debug("DevPagerDutyClient.CreateIncident", "id", incident.ID, "service", service.ID)
+
+	copy := *incident
+	return &copy, nil
+}
+
+func (d *DevPagerDutyClient) ManageIncidentAlerts(_ context.Context, incidentID string, _ string, alerts *pagerduty.IncidentAlertList) (*pagerduty.ListAlertsResponse, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, ok := d.incidents[incidentID]; !ok {
+		return nil, fmt.Errorf("DevPagerDutyClient: incident %q not found", incidentID)
+	}
+
+	var managed []pagerduty.IncidentAlert
+	for _, opt := range alerts.Alerts {
+		current := d.alerts[incidentID]
+		idx := -1
+		for i, a := range current {
+			if a.ID == opt.ID {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			return nil, fmt.Errorf("DevPagerDutyClient: alert %q not found on incident %q", opt.ID, incidentID)
+		}
+		alert := current[idx]
+
+		if opt.Status != "" {
+			alert.Status = opt.Status
+			current[idx] = alert
+			log.Debug("DevPagerDutyClient.ManageIncidentAlerts", "action", "status", "alert", opt.ID, "status", opt.Status)
+		}
+		if opt.Incident.ID != "" && opt.Incident.ID != incidentID {
+			alert.Incident = opt.Incident
+			d.alerts[incidentID] = append(current[:idx:idx], current[idx+1:]...)
+			d.alerts[opt.Incident.ID] = append(d.alerts[opt.Incident.ID], alert)
+			log.Debug("DevPagerDutyClient.ManageIncidentAlerts", "action", "move", "alert", opt.ID, "to", opt.Incident.ID)
+		}
+		managed = append(managed, alert)
+	}
+
+	return &pagerduty.ListAlertsResponse{Alerts: managed}, nil
+}
+
 // NewDevConfig creates a pd.Config using the DevPagerDutyClient, bypassing live PD API calls.
 // This is used when --dev mode is active.
 func NewDevConfig(fixturesDir string) (*Config, error) {
@@ -267,6 +267,58 @@ func TestDevClient_ListIncidentAlerts(t *testing.T) {
 	})
 }
 
+func TestDevClient_ManageIncidentAlerts(t *testing.T) {
+	client := newTestDevClient(t)
+	ctx := context.Background()
+
+	t.Run("resolves a single alert", func(t *testing.T) {
+		_, err := client.ManageIncidentAlerts(ctx, "PDEV_INC_002", "", &pagerduty.IncidentAlertList{
+			Alerts: []pagerduty.IncidentAlert{{APIObject: pagerduty.APIObject{ID: "PDEV_ALERT_002A"}, Status: "resolved"}},
+		})
+		require.NoError(t, err)
+
+		resp, err := client.ListIncidentAlertsWithContext(ctx, "PDEV_INC_002", pagerduty.ListIncidentAlertsOptions{})
+		require.NoError(t, err)
+		require.Len(t, resp.Alerts, 3)
+		assert.Equal(t, "resolved", resp.Alerts[0].Status)
+		assert.Equal(t, "triggered", resp.Alerts[1].Status)
+	})
+
+	t.Run("moves an alert into a new incident", func(t *testing.T) {
+		source, err := client.GetIncidentWithContext(ctx, "PDEV_INC_002")
+		require.NoError(t, err)
+		created, err := client.CreateIncidentWithContext(ctx, "", &pagerduty.CreateIncidentOptions{
+			Title:   "split",
+			Service: &pagerduty.APIReference{ID: source.Service.ID, Type: "service_reference"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, source.Service.Summary, created.Service.Summary)
+
+		_, err = client.ManageIncidentAlerts(ctx, "PDEV_INC_002", "", &pagerduty.IncidentAlertList{
+			Alerts: []pagerduty.IncidentAlert{{
+				APIObject: pagerduty.APIObject{ID: "PDEV_ALERT_002C"},
+				Incident:  pagerduty.APIReference{ID: created.ID, Type: "incident_reference"},
+			}},
+		})
+		require.NoError(t, err)
+
+		resp, err := client.ListIncidentAlertsWithContext(ctx, "PDEV_INC_002", pagerduty.ListIncidentAlertsOptions{})
+		require.NoError(t, err)
+		assert.Len(t, resp.Alerts, 2)
+		resp, err = client.ListIncidentAlertsWithContext(ctx, created.ID, pagerduty.ListIncidentAlertsOptions{})
+		require.NoError(t, err)
+		require.Len(t, resp.Alerts, 1)
+		assert.Equal(t, "PDEV_ALERT_002C", resp.Alerts[0].ID)
+	})
+
+	t.Run("unknown alert", func(t *testing.T) {
+		_, err := client.ManageIncidentAlerts(ctx, "PDEV_INC_002", "", &pagerduty.IncidentAlertList{
+			Alerts: []pagerduty.IncidentAlert{{APIObject: pagerduty.APIObject{ID: "NOPE"}, Status: "resolved"}},
+		})
+		assert.Error(t, err)
+	})
+}
+
 func TestDevClient_ListIncidentNotes(t *testing.T) {
 	client := newTestDevClient(t)
 	ctx := context.Background()
@@ -50,6 +50,11 @@ type MockPagerDutyClient struct {
 	// in order. Useful for verifying pagination offset reset behavior.
 	ListMembersOffsets []uint
 
+	// IncidentResponses maps incident IDs to specific responses for
+	// GetIncidentWithContext. When non-nil and a matching key exists, that
+	// incident is returned. Otherwise falls back to the default response.
+	IncidentResponses map[string]*pagerduty.Incident
+
 	// EscalationPolicyResponses maps policy IDs to specific responses for
 	// GetEscalationPolicyWithContext. When non-nil and a matching key exists,
 	// that policy is returned. Otherwise falls back to the default response.
@@ -89,6 +94,15 @@ type MockPagerDutyClient struct {
 	// RecordedListOnCallOpts records the options from every
 	// ListOnCallsWithContext call, in order.
 	RecordedListOnCallOpts []pagerduty.ListOnCallOptions
+
+	// LastCreateIncidentOpts records the options from the most recent
+	// CreateIncidentWithContext call.
+	LastCreateIncidentOpts *pagerduty.CreateIncidentOptions
+
+	// LastManageIncidentAlerts records the alert list from the most recent
+	// ManageIncidentAlerts call, so tests can assert the status or incident
+	// reference sent for each alert.
+	LastManageIncidentAlerts *pagerduty.IncidentAlertList
 }
 
 // recordCall increments the call count for the named method, lazily
@@ -111,6 +125,11 @@ func (m *MockPagerDutyClient) GetIncidentWithContext(ctx context.Context, id str
 	if id == "err" {
 		return &pagerduty.Incident{}, ErrMockError
 	}
+	if m.IncidentResponses != nil {
+		if incident, ok := m.IncidentResponses[id]; ok {
+			return incident, nil
+		}
+	}
 	return &pagerduty.Incident{
 		APIObject: pagerduty.APIObject{
 			ID: id, // Incidents will always come back with the same ID as the request
@@ -352,3 +371,29 @@ func (m *MockPagerDutyClient) MergeIncidentsWithContext(ctx context.Context, fro
 		APIObject: pagerduty.APIObject{ID: id},
 	}, nil
 }
+
+// CreateIncidentWithContext returns an incident with ID "PNEWINC", or an
+// error when the title is "err".
+func (m *MockPagerDutyClient) CreateIncidentWithContext(ctx context.Context, from string, o *pagerduty.CreateIncidentOptions) (*pagerduty.Incident, error) {
+	m.recordCall("CreateIncidentWithContext")
+	m.LastCreateIncidentOpts = o
+	if o.Title == "err" {
+		return nil, ErrMockError
+	}
+	return &pagerduty.Incident{
+		APIObject: pagerduty.APIObject{ID: "PNEWINC"},
+		Title:     o.Title,
+		Service:   pagerduty.APIObject{ID: o.Service.ID},
+	}, nil
+}
+
+// ManageIncidentAlerts echoes the managed alerts back, or returns an error
+// when the incident ID is "err".
+func (m *MockPagerDutyClient) ManageIncidentAlerts(ctx context.Context, incidentID string, from string, alerts *pagerduty.IncidentAlertList) (*pagerduty.ListAlertsResponse, error) {
+	m.recordCall("ManageIncidentAlerts")
+	m.LastManageIncidentAlerts = alerts
+	if incidentID == "err" {
+		return nil, ErrMockError
+	}
+	return &pagerduty.ListAlertsResponse{Alerts: alerts.Alerts}, nil
+}
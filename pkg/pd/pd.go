@@ -30,6 +30,7 @@ func contextWithTimeout() (context.Context, context.CancelFunc) {
 // PagerDutyClientInterface is an interface that defines the methods used by the pd package and makes it easier to mock
 // calls to PagerDuty in tests
 type PagerDutyClientInterface interface {
+	CreateIncidentWithContext(ctx context.Context, from string, o *pagerduty.CreateIncidentOptions) (*pagerduty.Incident, error)
 	CreateIncidentNoteWithContext(ctx context.Context, id string, note pagerduty.IncidentNote) (*pagerduty.IncidentNote, error)
 	GetCurrentUserWithContext(ctx context.Context, opts pagerduty.GetCurrentUserOptions) (*pagerduty.User, error)
 	GetEscalationPolicyWithContext(ctx context.Context, id string, opts *pagerduty.GetEscalationPolicyOptions) (*pagerduty.EscalationPolicy, error)
@@ -43,6 +44,7 @@ type PagerDutyClientInterface interface {
 	ListEscalationPoliciesWithContext(ctx context.Context, opts pagerduty.ListEscalationPoliciesOptions) (*pagerduty.ListEscalationPoliciesResponse, error)
 	ListOnCallsWithContext(ctx context.Context, opts pagerduty.ListOnCallOptions) (*pagerduty.ListOnCallsResponse, error)
 	ManageIncidentsWithContext(ctx context.Context, email string, opts []pagerduty.ManageIncidentsOptions) (*pagerduty.ListIncidentsResponse, error)
+	ManageIncidentAlerts(ctx context.Context, incidentID string, from string, alerts *pagerduty.IncidentAlertList) (*pagerduty.ListAlertsResponse, error)
 	MergeIncidentsWithContext(ctx context.Context, from string, id string, o []pagerduty.MergeIncidentsOptions) (*pagerduty.Incident, error)
 }
 
@@ -562,6 +564,73 @@ func UpdateIncidentTitle(client PagerDutyClient, incidentID string, newTitle str
 	return loopManageIncidents(client, ctx, currentUser.Email, opts)
 }
 
+// ResolveIncidentAlerts resolves individual alerts of an incident, leaving
+// the incident and its other alerts open.
+func ResolveIncidentAlerts(client PagerDutyClient, incidentID string, alertIDs []string, currentUser *pagerduty.User) ([]pagerduty.IncidentAlert, error) {
+	if currentUser == nil {
+		return nil, fmt.Errorf("pd.ResolveIncidentAlerts(): user is nil")
+	}
+	if incidentID == "" || len(alertIDs) == 0 {
+		return nil, fmt.Errorf("pd.ResolveIncidentAlerts(): incident ID and alert IDs are required")
+	}
+
+	ctx, cancel := contextWithTimeout()
+	defer cancel()
+
+	list := &pagerduty.IncidentAlertList{}
+	for _, id := range alertIDs {
+		list.Alerts = append(list.Alerts, pagerduty.IncidentAlert{
+			APIObject: pagerduty.APIObject{ID: id, Type: "alert"},
+			Status:    "resolved",
+		})
+	}
+
+	resp, err := client.ManageIncidentAlerts(ctx, incidentID, currentUser.Email, list)
+	if err != nil {
+		return nil, fmt.Errorf("pd.ResolveIncidentAlerts(): failed to resolve alerts of incident %v: %w", incidentID, err)
+	}
+	return resp.Alerts, nil
+}
+
+// MoveAlertsToNewIncident creates an incident on the source incident's
+// service and moves the given alerts into it.
+func MoveAlertsToNewIncident(client PagerDutyClient, source *pagerduty.Incident, alertIDs []string, title string, currentUser *pagerduty.User) (*pagerduty.Incident, error) {
+	if currentUser == nil {
+		return nil, fmt.Errorf("pd.MoveAlertsToNewIncident(): user is nil")
+	}
+	if source == nil || source.ID == "" || len(alertIDs) == 0 {
+		return nil, fmt.Errorf("pd.MoveAlertsToNewIncident(): incident and alert IDs are required")
+	}
+	if title == "" {
+		return nil, fmt.Errorf("pd.MoveAlertsToNewIncident(): title is empty")
+	}
+
+	ctx, cancel := contextWithTimeout()
+	defer cancel()
+
+	created, err := client.CreateIncidentWithContext(ctx, currentUser.Email, &pagerduty.CreateIncidentOptions{
+		Title:   title,
+		Service: &pagerduty.APIReference{ID: source.Service.ID, Type: "service_reference"},
+		Urgency: source.Urgency,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("pd.MoveAlertsToNewIncident(): failed to create incident on service %v: %w", source.Service.ID, err)
+	}
+
+	list := &pagerduty.IncidentAlertList{}
+	for _, id := range alertIDs {
+		list.Alerts = append(list.Alerts, pagerduty.IncidentAlert{
+			APIObject: pagerduty.APIObject{ID: id, Type: "alert"},
+			Incident:  pagerduty.APIReference{ID: created.ID, Type: "incident_reference"},
+		})
+	}
+
+	if _, err := client.ManageIncidentAlerts(ctx, source.ID, currentUser.Email, list); err != nil {
+		return created, fmt.Errorf("pd.MoveAlertsToNewIncident(): created incident %v but failed to move alerts from %v: %w", created.ID, source.ID, err)
+	}
+	return created, nil
+}
+
 func PostNote(client PagerDutyClient, id string, user *pagerduty.User, content string) (*pagerduty.IncidentNote, error) {
 	ctx, cancel := contextWithTimeout()
 	defer cancel()
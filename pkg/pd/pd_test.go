@@ -1515,3 +1515,83 @@ func TestUpdateIncidentTitle_EmptyIncidentID(t *testing.T) {
 	assert.Error(t, err)
 	assert.Nil(t, result)
 }
+
+func TestResolveIncidentAlerts(t *testing.T) {
+	mockClient := &MockPagerDutyClient{}
+	currentUser := &pagerduty.User{Email: "user@example.com"}
+
+	alerts, err := ResolveIncidentAlerts(mockClient, "INCIDENT1", []string{"A1", "A2"}, currentUser)
+
+	require.NoError(t, err)
+	assert.Len(t, alerts, 2)
+	require.NotNil(t, mockClient.LastManageIncidentAlerts)
+	for _, a := range mockClient.LastManageIncidentAlerts.Alerts {
+		assert.Equal(t, "alert", a.Type)
+		assert.Equal(t, "resolved", a.Status)
+		assert.Empty(t, a.Incident.ID, "resolving does not move the alert")
+	}
+}
+
+func TestResolveIncidentAlerts_Errors(t *testing.T) {
+	mockClient := &MockPagerDutyClient{}
+	currentUser := &pagerduty.User{Email: "user@example.com"}
+
+	_, err := ResolveIncidentAlerts(mockClient, "INCIDENT1", []string{"A1"}, nil)
+	assert.Error(t, err)
+	_, err = ResolveIncidentAlerts(mockClient, "INCIDENT1", nil, currentUser)
+	assert.Error(t, err)
+	_, err = ResolveIncidentAlerts(mockClient, "err", []string{"A1"}, currentUser)
+	assert.ErrorContains(t, err, "pd.ResolveIncidentAlerts()")
+}
+
+func TestMoveAlertsToNewIncident(t *testing.T) {
+	mockClient := &MockPagerDutyClient{}
+	currentUser := &pagerduty.User{Email: "user@example.com"}
+	source := &pagerduty.Incident{
+		APIObject: pagerduty.APIObject{ID: "INCIDENT1"},
+		Service:   pagerduty.APIObject{ID: "SVC1"},
+		Urgency:   "high",
+	}
+
+	created, err := MoveAlertsToNewIncident(mockClient, source, []string{"A1"}, "ClusterOperatorDown on c2", currentUser)
+
+	require.NoError(t, err)
+	assert.Equal(t, "PNEWINC", created.ID)
+	require.NotNil(t, mockClient.LastCreateIncidentOpts)
+	assert.Equal(t, "SVC1", mockClient.LastCreateIncidentOpts.Service.ID)
+	assert.Equal(t, "high", mockClient.LastCreateIncidentOpts.Urgency)
+	require.Len(t, mockClient.LastManageIncidentAlerts.Alerts, 1)
+	assert.Equal(t, pagerduty.APIReference{ID: "PNEWINC", Type: "incident_reference"}, mockClient.LastManageIncidentAlerts.Alerts[0].Incident)
+	assert.Empty(t, mockClient.LastManageIncidentAlerts.Alerts[0].Status)
+}
+
+func TestMoveAlertsToNewIncident_Errors(t *testing.T) {
+	currentUser := &pagerduty.User{Email: "user@example.com"}
+	source := &pagerduty.Incident{APIObject: pagerduty.APIObject{ID: "INCIDENT1"}}
+
+	t.Run("create fails", func(t *testing.T) {
+		mockClient := &MockPagerDutyClient{}
+		_, err := MoveAlertsToNewIncident(mockClient, source, []string{"A1"}, "err", currentUser)
+		assert.Error(t, err)
+		assert.Zero(t, mockClient.CallCounts["ManageIncidentAlerts"])
+	})
+
+	t.Run("move fails after create", func(t *testing.T) {
+		mockClient := &MockPagerDutyClient{}
+		errSource := &pagerduty.Incident{APIObject: pagerduty.APIObject{ID: "err"}}
+		created, err := MoveAlertsToNewIncident(mockClient, errSource, []string{"A1"}, "title", currentUser)
+		assert.ErrorContains(t, err, "created incident PNEWINC")
+		require.NotNil(t, created, "the created incident is returned so the caller can report it")
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		mockClient := &MockPagerDutyClient{}
+		_, err := MoveAlertsToNewIncident(mockClient, source, []string{"A1"}, "", currentUser)
+		assert.Error(t, err)
+		_, err = MoveAlertsToNewIncident(mockClient, nil, []string{"A1"}, "title", currentUser)
+		assert.Error(t, err)
+		_, err = MoveAlertsToNewIncident(mockClient, source, []string{"A1"}, "title", nil)
+		assert.Error(t, err)
+		assert.Empty(t, mockClient.CallCounts)
+	})
+}
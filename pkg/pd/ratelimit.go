@@ -263,6 +263,26 @@ func (c *RateLimitedClient) ManageIncidentsWithContext(ctx context.Context, emai
 	return result, err
 }
 
+func (c *RateLimitedClient) CreateIncidentWithContext(ctx context.Context, from string, o *pagerduty.CreateIncidentOptions) (*pagerduty.Incident, error) {
+	var result *pagerduty.Incident
+	err := c.withRetry(ctx, func() error {
+		var innerErr error
+		result, innerErr = c.inner.CreateIncidentWithContext(ctx, from, o)
+		return innerErr
+	})
+	return result, err
+}
+
+func (c *RateLimitedClient) ManageIncidentAlerts(ctx context.Context, incidentID string, from string, alerts *pagerduty.IncidentAlertList) (*pagerduty.ListAlertsResponse, error) {
+	var result *pagerduty.ListAlertsResponse
+	err := c.withRetry(ctx, func() error {
+		var innerErr error
+		result, innerErr = c.inner.ManageIncidentAlerts(ctx, incidentID, from, alerts)
+		return innerErr
+	})
+	return result, err
+}
+
 func (c *RateLimitedClient) MergeIncidentsWithContext(ctx context.Context, from string, id string, o []pagerduty.MergeIncidentsOptions) (*pagerduty.Incident, error) {
 	var result *pagerduty.Incident
 	err := c.withRetry(ctx, func() error {
@@ -379,6 +379,34 @@ func TestRateLimitedWrapper_ManageIncidentsWithContext(t *testing.T) {
 	assert.Equal(t, 1, mock.CallCounts["ManageIncidentsWithContext"])
 }
 
+func TestRateLimitedWrapper_ManageIncidentAlerts(t *testing.T) {
+	mock := &MockPagerDutyClient{}
+	client := NewRateLimitedClient(mock)
+	ctx := context.Background()
+
+	alerts := &pagerduty.IncidentAlertList{Alerts: []pagerduty.IncidentAlert{{APIObject: pagerduty.APIObject{ID: "A1"}, Status: "resolved"}}}
+	result, err := client.ManageIncidentAlerts(ctx, "INCIDENT1", "user@example.com", alerts)
+
+	assert.NoError(t, err)
+	assert.Len(t, result.Alerts, 1)
+	assert.Equal(t, 1, mock.CallCounts["ManageIncidentAlerts"])
+}
+
+func TestRateLimitedWrapper_CreateIncidentWithContext(t *testing.T) {
+	mock := &MockPagerDutyClient{}
+	client := NewRateLimitedClient(mock)
+	ctx := context.Background()
+
+	result, err := client.CreateIncidentWithContext(ctx, "user@example.com", &pagerduty.CreateIncidentOptions{
+		Title:   "split",
+		Service: &pagerduty.APIReference{ID: "SVC1"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "PNEWINC", result.ID)
+	assert.Equal(t, 1, mock.CallCounts["CreateIncidentWithContext"])
+}
+
 func TestRateLimitedWrapper_ManageIncidentsWithContext_Error(t *testing.T) {
 	mock := &MockPagerDutyClient{}
 	client := NewRateLimitedClient(mock)
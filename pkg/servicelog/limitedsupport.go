@@ -0,0 +1,114 @@
+package servicelog
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/clcollins/srepd/pkg/ocm"
+)
+
+// ReasonTemplate is a limited support reason with text/template
+// placeholders filled from Data. Limited support reasons are always
+// customer-visible.
+type ReasonTemplate struct {
+	Name    string `yaml:"name"`
+	Summary string `yaml:"summary"`
+	Details string `yaml:"details"`
+
+	summary *template.Template
+	details *template.Template
+}
+
+// builtinReasons are the reasons SREs place clusters into limited support
+// for most often. Teams add their own in the templates file.
+var builtinReasons = []ReasonTemplate{
+	{
+		Name:    "cloud-credentials",
+		Summary: "Cluster is in Limited Support due to unsupported cloud provider credentials",
+		Details: "Red Hat SRE cannot manage cluster {{.ClusterName}} because its cloud provider credentials were removed or lack required permissions. Restore the credentials, then open a support case so SRE can remove this limited support reason.",
+	},
+	{
+		Name:    "unsupported-config",
+		Summary: "Cluster is in Limited Support due to an unsupported configuration",
+		Details: "Red Hat SRE found a configuration change on cluster {{.ClusterName}} that is not supported and that causes {{.AlertName}}. Revert the change, then open a support case so SRE can remove this limited support reason.",
+	},
+}
+
+func init() {
+	for i := range builtinReasons {
+		if err := builtinReasons[i].compile(); err != nil {
+			panic(fmt.Sprintf("built-in limited support template %q: %v", builtinReasons[i].Name, err))
+		}
+	}
+}
+
+// Reasons returns the limited support library: user templates first, then
+// the built-in ones a user template does not replace by name.
+func Reasons() []ReasonTemplate {
+	var reasons []ReasonTemplate
+	if ts := activeTemplates.Load(); ts != nil {
+		reasons = append(reasons, ts.LimitedSupport...)
+	}
+	for _, r := range builtinReasons {
+		if !slices.ContainsFunc(reasons, func(u ReasonTemplate) bool { return u.Name == r.Name }) {
+			reasons = append(reasons, r)
+		}
+	}
+	return reasons
+}
+
+// LookupReason returns the limited support template called name.
+func LookupReason(name string) (ReasonTemplate, bool) {
+	for _, r := range Reasons() {
+		if r.Name == name {
+			return r, true
+		}
+	}
+	return ReasonTemplate{}, false
+}
+
+// compile validates the template and parses its text.
+func (r *ReasonTemplate) compile() error {
+	if strings.TrimSpace(r.Name) == "" || strings.ContainsAny(r.Name, " \t") {
+		return fmt.Errorf("name is required and may not contain spaces")
+	}
+	if strings.TrimSpace(r.Summary) == "" {
+		return fmt.Errorf("summary is required")
+	}
+	if strings.TrimSpace(r.Details) == "" {
+		return fmt.Errorf("details are required")
+	}
+
+	var err error
+	if r.summary, err = template.New("summary").Option("missingkey=error").Parse(r.Summary); err != nil {
+		return fmt.Errorf("summary: %w", err)
+	}
+	if r.details, err = template.New("details").Option("missingkey=error").Parse(r.Details); err != nil {
+		return fmt.Errorf("details: %w", err)
+	}
+	return nil
+}
+
+// Render fills the template for a cluster. The result is ready for
+// ocm.OCMClient.AddLimitedSupportReason.
+func (r ReasonTemplate) Render(d Data) (ocm.LimitedSupportReason, error) {
+	if r.summary == nil {
+		if err := r.compile(); err != nil {
+			return ocm.LimitedSupportReason{}, err
+		}
+	}
+	summary, err := execute(r.summary, d)
+	if err != nil {
+		return ocm.LimitedSupportReason{}, fmt.Errorf("summary: %w", err)
+	}
+	details, err := execute(r.details, d)
+	if err != nil {
+		return ocm.LimitedSupportReason{}, fmt.Errorf("details: %w", err)
+	}
+	return ocm.LimitedSupportReason{
+		Summary: strings.TrimSpace(summary),
+		Details: strings.TrimSpace(details),
+	}, nil
+}
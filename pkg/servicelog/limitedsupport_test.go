@@ -0,0 +1,63 @@
+package servicelog
+
+import (
+	"testing"
+
+	"github.com/clcollins/srepd/pkg/ocm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTemplates_LimitedSupport(t *testing.T) {
+	ts, err := ParseTemplates([]byte(`
+limited_support:
+  - name: etcd-quota
+    summary: Cluster is in Limited Support due to etcd quota exhaustion
+    details: "Cluster {{.ClusterName}} exceeded its etcd quota."
+`))
+	require.NoError(t, err)
+	require.Len(t, ts.LimitedSupport, 1)
+	assert.Empty(t, ts.Templates)
+
+	for input, want := range map[string]string{
+		"limited_support:\n  - summary: x\n    details: y\n":                                                           "name is required",
+		"limited_support:\n  - name: x\n    details: y\n":                                                              "summary is required",
+		"limited_support:\n  - name: x\n    summary: y\n":                                                              "details are required",
+		"limited_support:\n  - name: x\n    summary: y\n    details: '{{.Nope'\n":                                      "details:",
+		"limited_support:\n  - name: x\n    summary: a\n    details: a\n  - name: x\n    summary: b\n    details: b\n": `duplicate name "x"`,
+	} {
+		t.Run(want, func(t *testing.T) {
+			_, err := ParseTemplates([]byte(input))
+			assert.ErrorContains(t, err, want)
+		})
+	}
+}
+
+func TestReasonTemplate_Render(t *testing.T) {
+	r, ok := LookupReason("unsupported-config")
+	require.True(t, ok)
+
+	reason, err := r.Render(testData)
+	require.NoError(t, err)
+	assert.Equal(t, ocm.LimitedSupportReason{
+		Summary: "Cluster is in Limited Support due to an unsupported configuration",
+		Details: "Red Hat SRE found a configuration change on cluster acme-prod.abc1.p1.example.org that is not supported and that causes ClusterOperatorDown. Revert the change, then open a support case so SRE can remove this limited support reason.",
+	}, reason)
+}
+
+func TestReasons_UserOverridesBuiltin(t *testing.T) {
+	t.Cleanup(func() { SetTemplates(nil) })
+
+	builtins := Reasons()
+	require.NotEmpty(t, builtins)
+
+	ts, err := ParseTemplates([]byte("limited_support:\n  - name: cloud-credentials\n    summary: Team summary\n    details: Team details\n"))
+	require.NoError(t, err)
+	SetTemplates(ts)
+
+	all := Reasons()
+	assert.Len(t, all, len(builtins))
+	assert.Equal(t, "Team summary", all[0].Summary)
+	_, ok := LookupReason("unknown")
+	assert.False(t, ok)
+}
@@ -0,0 +1,241 @@
+// Package servicelog holds the template libraries srepd posts OCM service
+// logs and limited support reasons from.
+package servicelog
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"text/template"
+
+	"github.com/clcollins/srepd/pkg/ocm"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultServiceName is the service name of a template that does not set
+// one; it is what `osdctl servicelog post` uses for manual SRE logs.
+const DefaultServiceName = "SREManualAction"
+
+// Severities OCM accepts for a service log.
+var Severities = []string{"Debug", "Info", "Warning", "Error", "Fatal", "Low", "Moderate", "Important", "Major", "Critical"}
+
+// Template is a service log with text/template placeholders filled from
+// Data.
+type Template struct {
+	Name         string `yaml:"name"`
+	Summary      string `yaml:"summary"`
+	Description  string `yaml:"description"`
+	Severity     string `yaml:"severity"`
+	ServiceName  string `yaml:"service_name"`
+	InternalOnly bool   `yaml:"internal_only"`
+
+	summary     *template.Template
+	description *template.Template
+}
+
+// Data is what a template can reference: the incident, its alert and the
+// cluster the log is posted to.
+type Data struct {
+	IncidentID    string
+	IncidentTitle string
+	IncidentURL   string
+	Service       string
+	AlertName     string
+	ClusterID     string
+	ExternalID    string
+	ClusterName   string
+	Organization  string
+	Version       string
+	Region        string
+}
+
+// TemplateSet is a validated set of user-defined templates.
+type TemplateSet struct {
+	Templates      []Template       `yaml:"templates"`
+	LimitedSupport []ReasonTemplate `yaml:"limited_support"`
+}
+
+// builtinTemplates cover the logs SREs post for nearly every incident.
+// They are internal-only: a customer-visible log is a team decision, and
+// belongs in the user's templates file.
+var builtinTemplates = []Template{
+	{
+		Name:         "investigating",
+		Summary:      "SRE investigating {{.AlertName}}",
+		Description:  "SRE is investigating {{.AlertName}} on cluster {{.ClusterName}} for PagerDuty incident {{.IncidentID}}: {{.IncidentTitle}}\n\n{{.IncidentURL}}",
+		Severity:     "Info",
+		InternalOnly: true,
+	},
+	{
+		Name:         "resolved",
+		Summary:      "SRE resolved {{.AlertName}}",
+		Description:  "SRE resolved {{.AlertName}} on cluster {{.ClusterName}}. PagerDuty incident {{.IncidentID}}: {{.IncidentTitle}}\n\n{{.IncidentURL}}",
+		Severity:     "Info",
+		InternalOnly: true,
+	},
+	{
+		Name:         "handover",
+		Summary:      "SRE handover: {{.AlertName}}",
+		Description:  "Handing over {{.AlertName}} on cluster {{.ClusterName}} (OpenShift {{.Version}}, {{.Region}}). PagerDuty incident {{.IncidentID}}: {{.IncidentTitle}}\n\n{{.IncidentURL}}",
+		Severity:     "Info",
+		InternalOnly: true,
+	},
+}
+
+func init() {
+	for i := range builtinTemplates {
+		if err := builtinTemplates[i].compile(); err != nil {
+			panic(fmt.Sprintf("built-in service log template %q: %v", builtinTemplates[i].Name, err))
+		}
+	}
+}
+
+// activeTemplates holds the user templates. It is set once at startup but
+// read from tea.Cmd goroutines, hence the atomic.
+var activeTemplates atomic.Pointer[TemplateSet]
+
+// SetTemplates installs the user templates. Passing nil restores the
+// built-in library.
+func SetTemplates(ts *TemplateSet) {
+	activeTemplates.Store(ts)
+}
+
+// Templates returns the library: user templates first, then the built-in
+// ones a user template does not replace by name.
+func Templates() []Template {
+	var templates []Template
+	if ts := activeTemplates.Load(); ts != nil {
+		templates = append(templates, ts.Templates...)
+	}
+	for _, t := range builtinTemplates {
+		if !slices.ContainsFunc(templates, func(u Template) bool { return u.Name == t.Name }) {
+			templates = append(templates, t)
+		}
+	}
+	return templates
+}
+
+// Lookup returns the library template called name.
+func Lookup(name string) (Template, bool) {
+	for _, t := range Templates() {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return Template{}, false
+}
+
+// LoadTemplates reads and validates a templates file.
+func LoadTemplates(path string) (*TemplateSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading service log templates: %w", err)
+	}
+	ts, err := ParseTemplates(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return ts, nil
+}
+
+// ParseTemplates parses and validates templates YAML, compiling every
+// template so mistakes surface at load time rather than when posting.
+func ParseTemplates(data []byte) (*TemplateSet, error) {
+	var ts TemplateSet
+	if err := yaml.Unmarshal(data, &ts); err != nil {
+		return nil, fmt.Errorf("parsing service log templates: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	for i := range ts.Templates {
+		t := &ts.Templates[i]
+		if err := t.compile(); err != nil {
+			return nil, fmt.Errorf("template %d (%q): %w", i+1, t.Name, err)
+		}
+		if seen[t.Name] {
+			return nil, fmt.Errorf("template %d: duplicate name %q", i+1, t.Name)
+		}
+		seen[t.Name] = true
+	}
+
+	seen = make(map[string]bool)
+	for i := range ts.LimitedSupport {
+		r := &ts.LimitedSupport[i]
+		if err := r.compile(); err != nil {
+			return nil, fmt.Errorf("limited support template %d (%q): %w", i+1, r.Name, err)
+		}
+		if seen[r.Name] {
+			return nil, fmt.Errorf("limited support template %d: duplicate name %q", i+1, r.Name)
+		}
+		seen[r.Name] = true
+	}
+	return &ts, nil
+}
+
+// compile validates the template, fills defaults and parses its text.
+func (t *Template) compile() error {
+	if strings.TrimSpace(t.Name) == "" || strings.ContainsAny(t.Name, " \t") {
+		return fmt.Errorf("name is required and may not contain spaces")
+	}
+	if strings.TrimSpace(t.Summary) == "" {
+		return fmt.Errorf("summary is required")
+	}
+	if t.Severity == "" {
+		t.Severity = "Info"
+	}
+	i := slices.IndexFunc(Severities, func(s string) bool { return strings.EqualFold(s, t.Severity) })
+	if i < 0 {
+		return fmt.Errorf("severity %q: must be one of %s", t.Severity, strings.Join(Severities, ", "))
+	}
+	t.Severity = Severities[i]
+	if t.ServiceName == "" {
+		t.ServiceName = DefaultServiceName
+	}
+
+	var err error
+	if t.summary, err = template.New("summary").Option("missingkey=error").Parse(t.Summary); err != nil {
+		return fmt.Errorf("summary: %w", err)
+	}
+	if t.description, err = template.New("description").Option("missingkey=error").Parse(t.Description); err != nil {
+		return fmt.Errorf("description: %w", err)
+	}
+	return nil
+}
+
+// Render fills the template for a cluster. The result is ready for
+// ocm.OCMClient.PostServiceLog.
+func (t Template) Render(d Data) (ocm.ServiceLog, error) {
+	if t.summary == nil {
+		if err := t.compile(); err != nil {
+			return ocm.ServiceLog{}, err
+		}
+	}
+	summary, err := execute(t.summary, d)
+	if err != nil {
+		return ocm.ServiceLog{}, fmt.Errorf("summary: %w", err)
+	}
+	description, err := execute(t.description, d)
+	if err != nil {
+		return ocm.ServiceLog{}, fmt.Errorf("description: %w", err)
+	}
+	return ocm.ServiceLog{
+		Severity:     t.Severity,
+		ServiceName:  t.ServiceName,
+		Summary:      strings.TrimSpace(summary),
+		Description:  strings.TrimSpace(description),
+		ClusterID:    d.ClusterID,
+		ClusterUUID:  d.ExternalID,
+		InternalOnly: t.InternalOnly,
+	}, nil
+}
+
+func execute(tmpl *template.Template, d Data) (string, error) {
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, d); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,109 @@
+package servicelog
+
+import (
+	"testing"
+
+	"github.com/clcollins/srepd/pkg/ocm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testData = Data{
+	IncidentID:    "Q1INC",
+	IncidentTitle: "ClusterOperatorDown CRITICAL (1)",
+	IncidentURL:   "https://example.pagerduty.com/incidents/Q1INC",
+	AlertName:     "ClusterOperatorDown",
+	ClusterID:     "internal-1",
+	ExternalID:    "ext-uuid-1",
+	ClusterName:   "acme-prod.abc1.p1.example.org",
+	Version:       "4.16.5",
+	Region:        "us-east-1",
+}
+
+func TestParseTemplates(t *testing.T) {
+	ts, err := ParseTemplates([]byte(`
+templates:
+  - name: ingress-degraded
+    summary: "Action required: {{.AlertName}} on {{.ClusterName}}"
+    description: |
+      Your cluster's default IngressController is degraded.
+    severity: warning
+    internal_only: false
+  - name: note
+    summary: SRE note
+`))
+	require.NoError(t, err)
+	require.Len(t, ts.Templates, 2)
+
+	assert.Equal(t, "Warning", ts.Templates[0].Severity, "severity is normalized to OCM's spelling")
+	assert.False(t, ts.Templates[0].InternalOnly)
+	assert.Equal(t, "Info", ts.Templates[1].Severity, "severity defaults to Info")
+	assert.Equal(t, DefaultServiceName, ts.Templates[1].ServiceName)
+}
+
+func TestParseTemplates_Errors(t *testing.T) {
+	tests := map[string]string{
+		"templates:\n  - summary: x\n":                                           "name is required",
+		"templates:\n  - name: two words\n    summary: x\n":                      "may not contain spaces",
+		"templates:\n  - name: x\n":                                              "summary is required",
+		"templates:\n  - name: x\n    summary: x\n    severity: meh\n":           `severity "meh": must be one of`,
+		"templates:\n  - name: x\n    summary: '{{.AlertName'\n":                 "summary:",
+		"templates:\n  - name: x\n    summary: a\n  - name: x\n    summary: b\n": `duplicate name "x"`,
+		"templates: [": "parsing service log templates",
+	}
+	for input, want := range tests {
+		t.Run(want, func(t *testing.T) {
+			_, err := ParseTemplates([]byte(input))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), want)
+		})
+	}
+}
+
+func TestTemplate_Render(t *testing.T) {
+	tmpl, ok := Lookup("investigating")
+	require.True(t, ok)
+
+	entry, err := tmpl.Render(testData)
+	require.NoError(t, err)
+	assert.Equal(t, ocm.ServiceLog{
+		Severity:     "Info",
+		ServiceName:  DefaultServiceName,
+		Summary:      "SRE investigating ClusterOperatorDown",
+		Description:  "SRE is investigating ClusterOperatorDown on cluster acme-prod.abc1.p1.example.org for PagerDuty incident Q1INC: ClusterOperatorDown CRITICAL (1)\n\nhttps://example.pagerduty.com/incidents/Q1INC",
+		ClusterID:    "internal-1",
+		ClusterUUID:  "ext-uuid-1",
+		InternalOnly: true,
+	}, entry)
+}
+
+func TestTemplate_RenderUnknownField(t *testing.T) {
+	ts, err := ParseTemplates([]byte("templates:\n  - name: x\n    summary: '{{.Nope}}'\n"))
+	require.NoError(t, err)
+
+	_, err = ts.Templates[0].Render(testData)
+	assert.ErrorContains(t, err, "summary:")
+}
+
+func TestTemplates_UserOverridesBuiltin(t *testing.T) {
+	t.Cleanup(func() { SetTemplates(nil) })
+
+	builtins := Templates()
+	require.NotEmpty(t, builtins)
+	for _, tmpl := range builtins {
+		assert.True(t, tmpl.InternalOnly, "built-in %s is internal only", tmpl.Name)
+	}
+
+	ts, err := ParseTemplates([]byte("templates:\n  - name: resolved\n    summary: Team resolved {{.AlertName}}\n  - name: custom\n    summary: Custom\n"))
+	require.NoError(t, err)
+	SetTemplates(ts)
+
+	all := Templates()
+	assert.Len(t, all, len(builtins)+1, "a user template replaces the built-in one of the same name")
+	assert.Equal(t, "resolved", all[0].Name)
+	resolved, ok := Lookup("resolved")
+	require.True(t, ok)
+	assert.Equal(t, "Team resolved {{.AlertName}}", resolved.Summary)
+	_, ok = Lookup("custom")
+	assert.True(t, ok)
+}
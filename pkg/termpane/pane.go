@@ -0,0 +1,286 @@
+// Package termpane runs a command on a pseudo-terminal and keeps its
+// screen, so a TUI can show a shell, such as a cluster login, in one of its
+// own panes.
+package termpane
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hinshun/vt10x"
+)
+
+// ScrollbackLines is how many lines of output a pane keeps for scrolling
+// back.
+const ScrollbackLines = 5000
+
+// readerGrace is how long a pane waits after its command exits for the
+// last output. A background process still holding the terminal open keeps
+// the reader going indefinitely.
+const readerGrace = time.Second
+
+// StartFunc starts a command and returns a function that waits for it,
+// like launcher.ClusterLauncher.Start.
+type StartFunc func(c *exec.Cmd) (wait func() error, err error)
+
+// Pane is a command running on a pseudo-terminal, with the terminal's
+// screen and the output it has printed. Its methods are safe for
+// concurrent use.
+type Pane struct {
+	// Title names the pane in a tab bar.
+	Title string
+
+	cmd        *exec.Cmd
+	pty        *os.File
+	term       vt10x.Terminal
+	scrollback *scrollback
+	changed    chan struct{}
+	done       chan struct{}
+	err        error
+
+	mu         sync.Mutex
+	cols, rows int
+}
+
+// Start runs c on a new pseudo-terminal of the given size. start starts
+// it; nil runs c.Start and c.Wait.
+func Start(c *exec.Cmd, title string, cols, rows int, start StartFunc) (*Pane, error) {
+	if start == nil {
+		start = func(c *exec.Cmd) (func() error, error) {
+			if err := c.Start(); err != nil {
+				return nil, err
+			}
+			return c.Wait, nil
+		}
+	}
+	cols, rows = max(cols, 1), max(rows, 1)
+	pty, wait, err := openPTY(c, cols, rows, start)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Pane{
+		Title: title,
+		cmd:   c,
+		pty:   pty,
+		// Answers to terminal queries (cursor position, device
+		// attributes) go back to the program.
+		term:       vt10x.New(vt10x.WithWriter(pty), vt10x.WithSize(cols, rows)),
+		scrollback: newScrollback(ScrollbackLines),
+		changed:    make(chan struct{}, 1),
+		done:       make(chan struct{}),
+		cols:       cols,
+		rows:       rows,
+	}
+	readDone := make(chan struct{})
+	go func() {
+		defer close(readDone)
+		p.read()
+	}()
+	go func() {
+		err := wait()
+		select {
+		case <-readDone:
+		case <-time.After(readerGrace):
+		}
+		p.err = err
+		close(p.done)
+	}()
+	return p, nil
+}
+
+func (p *Pane) read() {
+	buf := make([]byte, 32*1024)
+	var pending []byte
+	for {
+		n, err := p.pty.Read(buf)
+		if n > 0 {
+			data := append(pending, buf[:n]...)
+			written, _ := p.term.Write(data)
+			// A rune split across reads is finished by the next one.
+			pending = append([]byte(nil), data[written:]...)
+			p.scrollback.Write(buf[:n])
+			select {
+			case p.changed <- struct{}{}:
+			default:
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// PID returns the process ID of the command.
+func (p *Pane) PID() int {
+	return p.cmd.Process.Pid
+}
+
+// Changed receives when the pane printed something since the last receive.
+func (p *Pane) Changed() <-chan struct{} {
+	return p.changed
+}
+
+// Done is closed once the command has exited.
+func (p *Pane) Done() <-chan struct{} {
+	return p.done
+}
+
+// Exited reports whether the command has exited.
+func (p *Pane) Exited() bool {
+	select {
+	case <-p.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Err returns how the command exited, once it has.
+func (p *Pane) Err() error {
+	if !p.Exited() {
+		return nil
+	}
+	return p.err
+}
+
+// Write sends keyboard input to the command.
+func (p *Pane) Write(b []byte) (int, error) {
+	if p.Exited() {
+		return 0, errors.New("the session has exited")
+	}
+	return p.pty.Write(b)
+}
+
+// Size returns the pane's size in cells.
+func (p *Pane) Size() (cols, rows int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.cols, p.rows
+}
+
+// Resize changes the pane's size; the command is told with a SIGWINCH.
+func (p *Pane) Resize(cols, rows int) error {
+	cols, rows = max(cols, 1), max(rows, 1)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if cols == p.cols && rows == p.rows {
+		return nil
+	}
+	p.cols, p.rows = cols, rows
+	p.term.Resize(cols, rows)
+	if p.Exited() {
+		return nil
+	}
+	return setSize(p.pty, cols, rows)
+}
+
+// Close ends the session: the command gets a hangup, and is killed if it
+// is still running shortly after.
+func (p *Pane) Close() error {
+	err := p.pty.Close()
+	if p.Exited() || p.cmd.Process == nil {
+		return err
+	}
+	go func() {
+		select {
+		case <-p.done:
+		case <-time.After(2 * time.Second):
+			_ = p.cmd.Process.Kill()
+		}
+	}()
+	return err
+}
+
+// Scrollback returns the lines the pane has printed, oldest first, without
+// escape sequences.
+func (p *Pane) Scrollback() []string {
+	return p.scrollback.Lines()
+}
+
+// Glyph attribute bits, as vt10x sets them.
+const (
+	attrReverse = 1 << iota
+	attrUnderline
+	attrBold
+	attrGfx
+	attrItalic
+)
+
+// Render draws the screen as lines of text with ANSI colors. With cursor
+// set, the cursor cell is shown in reverse video.
+func (p *Pane) Render(cursor bool) string {
+	p.term.Lock()
+	defer p.term.Unlock()
+	cols, rows := p.term.Size()
+	cur := p.term.Cursor()
+	showCursor := cursor && p.term.CursorVisible()
+
+	var b strings.Builder
+	for y := 0; y < rows; y++ {
+		if y > 0 {
+			b.WriteByte('\n')
+		}
+		var last string
+		for x := 0; x < cols; x++ {
+			g := p.term.Cell(x, y)
+			if showCursor && x == cur.X && y == cur.Y {
+				g.Mode ^= attrReverse
+			}
+			if sgr := glyphSGR(g); sgr != last {
+				b.WriteString(sgr)
+				last = sgr
+			}
+			if g.Char == 0 {
+				g.Char = ' '
+			}
+			b.WriteRune(g.Char)
+		}
+		b.WriteString("\x1b[0m")
+	}
+	return b.String()
+}
+
+// glyphSGR returns the escape sequence that sets a cell's colors and
+// attributes.
+func glyphSGR(g vt10x.Glyph) string {
+	params := []string{"0"}
+	if g.Mode&attrBold != 0 {
+		params = append(params, "1")
+	}
+	if g.Mode&attrItalic != 0 {
+		params = append(params, "3")
+	}
+	if g.Mode&attrUnderline != 0 {
+		params = append(params, "4")
+	}
+	if g.Mode&attrReverse != 0 {
+		params = append(params, "7")
+	}
+	if p := colorSGR(g.FG, 30, 90, 38); p != "" {
+		params = append(params, p)
+	}
+	if p := colorSGR(g.BG, 40, 100, 48); p != "" {
+		params = append(params, p)
+	}
+	return "\x1b[" + strings.Join(params, ";") + "m"
+}
+
+func colorSGR(c vt10x.Color, base, bright, extended int) string {
+	switch {
+	case c == vt10x.DefaultFG || c == vt10x.DefaultBG:
+		return ""
+	case c < 8:
+		return fmt.Sprint(base + int(c))
+	case c < 16:
+		return fmt.Sprint(bright + int(c) - 8)
+	case c < 256:
+		return fmt.Sprintf("%d;5;%d", extended, c)
+	}
+	return ""
+}
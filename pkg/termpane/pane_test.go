@@ -0,0 +1,86 @@
+package termpane
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func startPane(t *testing.T, cols, rows int, args ...string) *Pane {
+	t.Helper()
+	if !Supported {
+		t.Skip("embedded terminals are Linux only")
+	}
+	p, err := Start(exec.Command(args[0], args[1:]...), "test", cols, rows, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = p.Close() })
+	return p
+}
+
+func waitDone(t *testing.T, p *Pane) {
+	t.Helper()
+	select {
+	case <-p.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("the command did not exit")
+	}
+}
+
+func TestPane_ScreenAndScrollback(t *testing.T) {
+	p := startPane(t, 20, 3, "sh", "-c", `printf 'one\ntwo\n\033[31mred\033[0m\nfour'`)
+	waitDone(t, p)
+	require.NoError(t, p.Err())
+
+	screen := p.Render(false)
+	lines := strings.Split(screen, "\n")
+	require.Len(t, lines, 3, "one line per row")
+	assert.Contains(t, lines[0], "two", "the first line scrolled off")
+	assert.Contains(t, lines[1], "\x1b[0;31mred", "colors are kept")
+	assert.Contains(t, lines[2], "four")
+
+	assert.Equal(t, []string{"one", "two", "red", "four"}, p.Scrollback())
+}
+
+func TestPane_InputAndResize(t *testing.T) {
+	p := startPane(t, 40, 5, "sh", "-c", "read line; stty size; echo got $line")
+	require.NoError(t, p.Resize(50, 7))
+	cols, rows := p.Size()
+	assert.Equal(t, 50, cols)
+	assert.Equal(t, 7, rows)
+
+	_, err := p.Write([]byte("hello\r"))
+	require.NoError(t, err)
+	waitDone(t, p)
+
+	assert.Contains(t, p.Scrollback(), "7 50", "the program sees the new size")
+	assert.Contains(t, p.Scrollback(), "got hello")
+	_, err = p.Write([]byte("more"))
+	assert.Error(t, err, "input after exit is refused")
+}
+
+func TestPane_ExitStatus(t *testing.T) {
+	p := startPane(t, 20, 3, "sh", "-c", "exit 3")
+	waitDone(t, p)
+	var exitErr *exec.ExitError
+	require.ErrorAs(t, p.Err(), &exitErr)
+	assert.Equal(t, 3, exitErr.ExitCode())
+}
+
+func TestPane_CloseEndsSession(t *testing.T) {
+	p := startPane(t, 20, 3, "sleep", "30")
+	require.NoError(t, p.Close())
+	waitDone(t, p)
+}
+
+func TestScrollback_CarriageReturnAndLimit(t *testing.T) {
+	s := newScrollback(2)
+	s.Write([]byte("progress 10%\rprogress 100%\r\nfirst\r\n\x1b[1mbold"))
+	assert.Equal(t, []string{"progress 100%", "first", "bold"}, s.Lines())
+
+	s.Write([]byte("\nlast\n"))
+	assert.Equal(t, []string{"bold", "last"}, s.Lines(), "only the newest lines are kept")
+}
@@ -0,0 +1,85 @@
+//go:build linux
+// +build linux
+
+package termpane
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// Supported reports whether embedded terminals work on this platform.
+const Supported = true
+
+// openPTY opens a pseudo-terminal pair and starts c on its terminal side
+// as the leader of a new session, so the shell gets job control and a
+// SIGHUP when the pane closes.
+func openPTY(c *exec.Cmd, cols, rows int, start StartFunc) (*os.File, func() error, error) {
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open pty: %w", err)
+	}
+	var n uint32
+	err = control(master, func(fd int) error {
+		if err := unix.IoctlSetPointerInt(fd, unix.TIOCSPTLCK, 0); err != nil {
+			return fmt.Errorf("unlock pty: %w", err)
+		}
+		var err error
+		if n, err = unix.IoctlGetUint32(fd, unix.TIOCGPTN); err != nil {
+			return fmt.Errorf("pty number: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		_ = master.Close()
+		return nil, nil, err
+	}
+	tty, err := os.OpenFile(fmt.Sprintf("/dev/pts/%d", n), os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		_ = master.Close()
+		return nil, nil, fmt.Errorf("open pty terminal: %w", err)
+	}
+	defer func() { _ = tty.Close() }()
+
+	if err := setSize(master, cols, rows); err != nil {
+		_ = master.Close()
+		return nil, nil, err
+	}
+
+	c.Stdin, c.Stdout, c.Stderr = tty, tty, tty
+	c.SysProcAttr = &syscall.SysProcAttr{Setsid: true, Setctty: true}
+	wait, err := start(c)
+	if err != nil {
+		_ = master.Close()
+		return nil, nil, err
+	}
+	return master, wait, nil
+}
+
+// setSize tells the program in the pane its new size; it gets a SIGWINCH.
+func setSize(pty *os.File, cols, rows int) error {
+	return control(pty, func(fd int) error {
+		if err := unix.IoctlSetWinsize(fd, unix.TIOCSWINSZ, &unix.Winsize{Row: uint16(rows), Col: uint16(cols)}); err != nil {
+			return fmt.Errorf("resize pty: %w", err)
+		}
+		return nil
+	})
+}
+
+// control runs an ioctl on the pty. Unlike File.Fd it leaves the file in
+// the runtime poller, so closing the pane unblocks its reader.
+func control(f *os.File, fn func(fd int) error) error {
+	conn, err := f.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var fnErr error
+	if err := conn.Control(func(fd uintptr) { fnErr = fn(int(fd)) }); err != nil {
+		return err
+	}
+	return fnErr
+}
@@ -0,0 +1,21 @@
+//go:build !linux
+// +build !linux
+
+package termpane
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+)
+
+// Supported reports whether embedded terminals work on this platform.
+const Supported = false
+
+func openPTY(c *exec.Cmd, cols, rows int, start StartFunc) (*os.File, func() error, error) {
+	return nil, nil, errors.New("embedded terminals are only supported on Linux")
+}
+
+func setSize(pty *os.File, cols, rows int) error {
+	return nil
+}
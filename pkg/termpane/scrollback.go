@@ -0,0 +1,64 @@
+package termpane
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// scrollback keeps the last lines a pane printed as plain text. The screen
+// only holds what fits; this is what scrolling back shows.
+type scrollback struct {
+	mu      sync.Mutex
+	max     int
+	lines   []string
+	partial []byte
+}
+
+func newScrollback(max int) *scrollback {
+	return &scrollback{max: max}
+}
+
+// Write adds output. Lines are kept once complete; a carriage return
+// within a line (a progress bar, a redrawn prompt) keeps what was drawn
+// last.
+func (s *scrollback) Write(b []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for {
+		i := bytes.IndexByte(b, '\n')
+		if i < 0 {
+			s.partial = append(s.partial, b...)
+			return
+		}
+		s.partial = append(s.partial, b[:i]...)
+		s.lines = append(s.lines, plainLine(s.partial))
+		if len(s.lines) > s.max {
+			s.lines = append(s.lines[:0], s.lines[len(s.lines)-s.max:]...)
+		}
+		s.partial = s.partial[:0]
+		b = b[i+1:]
+	}
+}
+
+// Lines returns the kept lines and the line being printed.
+func (s *scrollback) Lines() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	lines := append([]string(nil), s.lines...)
+	if len(s.partial) > 0 {
+		lines = append(lines, plainLine(s.partial))
+	}
+	return lines
+}
+
+func plainLine(b []byte) string {
+	line := ansi.Strip(string(b))
+	line = strings.TrimSuffix(line, "\r")
+	if i := strings.LastIndexByte(line, '\r'); i >= 0 && i < len(line)-1 {
+		line = line[i+1:]
+	}
+	return strings.ReplaceAll(line, "\r", "")
+}
@@ -0,0 +1,205 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/PagerDuty/go-pagerduty"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/clcollins/srepd/pkg/pd"
+)
+
+const (
+	alertActionResolve = "resolve"
+	alertActionMove    = "move"
+)
+
+// managedIncidentAlertMsg reports the result of a per-alert action taken
+// from the Alerts tab. newIncident is set when the alert was moved.
+type managedIncidentAlertMsg struct {
+	action      string
+	incidentID  string
+	alertID     string
+	newIncident *pagerduty.Incident
+	err         error
+}
+
+// selectedAlertIndex returns the position of the Alerts tab selection in
+// selectedIncidentAlerts. The selection is kept by alert ID so it survives
+// re-fetches, and falls back to the first alert when the ID is gone.
+func (m model) selectedAlertIndex() int {
+	if i := slices.IndexFunc(m.selectedIncidentAlerts, func(a pagerduty.IncidentAlert) bool {
+		return a.ID == m.selectedAlertID
+	}); i >= 0 {
+		return i
+	}
+	return 0
+}
+
+// selectedAlert returns the alert selected in the Alerts tab.
+func (m model) selectedAlert() (pagerduty.IncidentAlert, bool) {
+	if !m.incidentAlertsLoaded || len(m.selectedIncidentAlerts) == 0 {
+		return pagerduty.IncidentAlert{}, false
+	}
+	return m.selectedIncidentAlerts[m.selectedAlertIndex()], true
+}
+
+// moveAlertSelection moves the Alerts tab selection by delta, wrapping at
+// either end, and asks the next render to scroll to it.
+func (m *model) moveAlertSelection(delta int) {
+	n := len(m.selectedIncidentAlerts)
+	if n == 0 {
+		return
+	}
+	i := (m.selectedAlertIndex() + delta + n) % n
+	m.selectedAlertID = m.selectedIncidentAlerts[i].ID
+	m.scrollToSelectedAlert = true
+	m.setStatus(fmt.Sprintf("selected alert %d/%d", i+1, n))
+}
+
+// alertHeadingLine returns the line of rendered Alerts tab content holding
+// the heading for alert index (0-based) of total, or -1.
+func alertHeadingLine(content string, index, total int) int {
+	heading := fmt.Sprintf("Alert %d/%d", index+1, total)
+	for i, line := range strings.Split(content, "\n") {
+		if strings.Contains(ansi.Strip(line), heading) {
+			return i
+		}
+	}
+	return -1
+}
+
+// openAlertCount counts the alerts that are not resolved.
+func openAlertCount(alerts []pagerduty.IncidentAlert) int {
+	n := 0
+	for _, a := range alerts {
+		if a.Status != "resolved" {
+			n++
+		}
+	}
+	return n
+}
+
+// describeAlert names an alert for confirmation prompts: its ID, plus the
+// cluster it fired for when known.
+func describeAlert(a pagerduty.IncidentAlert) string {
+	if clusters := getUniqueClusters([]pagerduty.IncidentAlert{a}); len(clusters) > 0 {
+		return fmt.Sprintf("%s (cluster %s)", a.ID, clusters[0])
+	}
+	return a.ID
+}
+
+// splitIncidentTitle is the title of the incident an alert is moved into:
+// the alert's own summary, falling back to the source incident's title.
+func splitIncidentTitle(a pagerduty.IncidentAlert, source *pagerduty.Incident) string {
+	if title := strings.TrimSpace(stripControl(a.Summary)); title != "" {
+		return title
+	}
+	return source.Title
+}
+
+// confirmResolveSelectedAlert asks before resolving the selected alert.
+// Resolving the last open alert resolves the incident, so the prompt says so.
+func (m *model) confirmResolveSelectedAlert() {
+	a, ok := m.selectedAlert()
+	if !ok {
+		m.setStatus("no alert selected")
+		return
+	}
+	if a.Status == "resolved" {
+		m.setStatus(fmt.Sprintf("alert %s is already resolved", a.ID))
+		return
+	}
+	incidentID := m.selectedIncident.ID
+	prompt := fmt.Sprintf("Resolve alert %s in %s? [y/n]", describeAlert(a), incidentID)
+	if openAlertCount(m.selectedIncidentAlerts) == 1 {
+		prompt = fmt.Sprintf("Resolve alert %s? It is the last open alert and resolves %s [y/n]", describeAlert(a), incidentID)
+	}
+	m.pendingConfirmation = &confirmActionState{
+		prompt: prompt,
+		action: resolveIncidentAlert(m.config, incidentID, a.ID),
+	}
+}
+
+// confirmMoveSelectedAlert asks before moving the selected alert out of the
+// incident into a new incident on the same service.
+func (m *model) confirmMoveSelectedAlert() {
+	a, ok := m.selectedAlert()
+	if !ok {
+		m.setStatus("no alert selected")
+		return
+	}
+	if len(m.selectedIncidentAlerts) < 2 {
+		m.setStatus("incident has a single alert; nothing to split")
+		return
+	}
+	source := *m.selectedIncident
+	m.pendingConfirmation = &confirmActionState{
+		prompt: fmt.Sprintf("Move alert %s out of %s into a new incident? [y/n]", describeAlert(a), source.ID),
+		action: moveAlertToNewIncident(m.config, source, a),
+	}
+}
+
+func resolveIncidentAlert(p *pd.Config, incidentID, alertID string) tea.Cmd {
+	return func() tea.Msg {
+		_, err := pd.ResolveIncidentAlerts(p.Client, incidentID, []string{alertID}, p.CurrentUser)
+		return managedIncidentAlertMsg{action: alertActionResolve, incidentID: incidentID, alertID: alertID, err: err}
+	}
+}
+
+func moveAlertToNewIncident(p *pd.Config, source pagerduty.Incident, a pagerduty.IncidentAlert) tea.Cmd {
+	return func() tea.Msg {
+		created, err := pd.MoveAlertsToNewIncident(p.Client, &source, []string{a.ID}, splitIncidentTitle(a, &source), p.CurrentUser)
+		return managedIncidentAlertMsg{action: alertActionMove, incidentID: source.ID, alertID: a.ID, newIncident: created, err: err}
+	}
+}
+
+// applyManagedIncidentAlert mirrors a successful per-alert action into the
+// cached alerts, so the Alerts tab, getUniqueClusters and the (+N) cluster
+// count in the table reflect it before the next poll. A resolved alert stays
+// listed with its new status; a moved alert leaves the incident.
+func (m *model) applyManagedIncidentAlert(msg managedIncidentAlertMsg) {
+	update := func(alerts []pagerduty.IncidentAlert) []pagerduty.IncidentAlert {
+		switch msg.action {
+		case alertActionResolve:
+			alerts = slices.Clone(alerts)
+			for i := range alerts {
+				if alerts[i].ID == msg.alertID {
+					alerts[i].Status = "resolved"
+				}
+			}
+		case alertActionMove:
+			alerts = slices.DeleteFunc(slices.Clone(alerts), func(a pagerduty.IncidentAlert) bool {
+				return a.ID == msg.alertID
+			})
+		}
+		return alerts
+	}
+
+	var alerts []pagerduty.IncidentAlert
+	known := false
+	if cached, ok := m.incidentCache[msg.incidentID]; ok && cached != nil && cached.alertsLoaded {
+		cached.alerts = update(cached.alerts)
+		alerts, known = cached.alerts, true
+	}
+	if m.selectedIncident != nil && m.selectedIncident.ID == msg.incidentID && m.incidentAlertsLoaded {
+		m.selectedIncidentAlerts = update(m.selectedIncidentAlerts)
+		alerts, known = m.selectedIncidentAlerts, true
+	}
+	if !known {
+		return
+	}
+
+	if clusterIDs := getUniqueClusters(alerts); len(clusterIDs) > 0 {
+		if m.incidentClusterMap == nil {
+			m.incidentClusterMap = make(map[string][]string)
+		}
+		m.incidentClusterMap[msg.incidentID] = clusterIDs
+	} else {
+		delete(m.incidentClusterMap, msg.incidentID)
+	}
+	log.Debug("applyManagedIncidentAlert", "action", msg.action, "incident_id", msg.incidentID, "alert_id", msg.alertID, "alerts", len(alerts))
+}
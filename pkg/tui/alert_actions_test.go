@@ -0,0 +1,201 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/PagerDuty/go-pagerduty"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/clcollins/srepd/pkg/pd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func idAlert(id, alertName, clusterID string) pagerduty.IncidentAlert {
+	a := hiveAlert(alertName, clusterID, "")
+	a.ID = id
+	a.Summary = alertName + " on " + clusterID
+	a.Status = "triggered"
+	return a
+}
+
+// alertActionTestModel views incident OTHER, which carries alerts for three
+// clusters, on the Alerts tab.
+func alertActionTestModel(t *testing.T) (model, *pd.MockPagerDutyClient) {
+	t.Helper()
+	m := dupTestModel(t)
+	client := &pd.MockPagerDutyClient{}
+	m.config.Client = client
+	m.teamMode = true
+	m.showLowUrgency = true
+
+	m.incidentList[2].Service.ID = "SVC1"
+	m.incidentCache["OTHER"].alerts = []pagerduty.IncidentAlert{
+		idAlert("A1", "Y", "c2"),
+		idAlert("A2", "Y", "c3"),
+		idAlert("A3", "Y", "c4"),
+	}
+	m.incidentClusterMap = map[string][]string{"OTHER": {"c2", "c3", "c4"}}
+
+	incident := m.incidentList[2]
+	m.selectedIncident = &incident
+	m.selectedIncidentAlerts = m.incidentCache["OTHER"].alerts
+	m.incidentAlertsLoaded = true
+	m.incidentDataLoaded = true
+	m.incidentNotesLoaded = true
+	m.viewingIncident = true
+	m.activeTab = tabAlerts
+	return m, client
+}
+
+func pressRune(t *testing.T, m model, r rune) (model, tea.Cmd) {
+	t.Helper()
+	result, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	updated, ok := result.(model)
+	require.True(t, ok)
+	return updated, cmd
+}
+
+func TestAlertSelectionKeys(t *testing.T) {
+	m, _ := alertActionTestModel(t)
+
+	a, ok := m.selectedAlert()
+	require.True(t, ok)
+	assert.Equal(t, "A1", a.ID, "selection defaults to the first alert")
+
+	m, _ = pressRune(t, m, ']')
+	assert.Equal(t, "A2", m.selectedAlertID)
+	assert.True(t, m.scrollToSelectedAlert)
+	assert.Equal(t, "selected alert 2/3", m.status)
+
+	m, _ = pressRune(t, m, '[')
+	m, _ = pressRune(t, m, '[')
+	assert.Equal(t, "A3", m.selectedAlertID, "selection wraps")
+
+	content, _, err := m.renderTabContent()
+	require.NoError(t, err)
+	assert.Contains(t, content, "Alert 3/3 ◀ selected")
+	assert.NotContains(t, content, "Alert 1/3 ◀ selected")
+}
+
+func TestAlertActionKeys_RequireAlertsTab(t *testing.T) {
+	m, _ := alertActionTestModel(t)
+	m.activeTab = tabDetails
+
+	m, _ = pressRune(t, m, 'R')
+	assert.Nil(t, m.pendingConfirmation)
+	assert.Equal(t, "switch to the Alerts tab to select an alert", m.status)
+}
+
+func TestResolveAlertKey_ResolvesSelectedAlert(t *testing.T) {
+	m, client := alertActionTestModel(t)
+	m.selectedAlertID = "A2"
+
+	m, _ = pressRune(t, m, 'R')
+	require.NotNil(t, m.pendingConfirmation)
+	assert.Equal(t, "Resolve alert A2 (cluster c3) in OTHER? [y/n]", m.pendingConfirmation.prompt)
+
+	msg, ok := m.pendingConfirmation.action().(managedIncidentAlertMsg)
+	require.True(t, ok)
+	require.NoError(t, msg.err)
+	require.Len(t, client.LastManageIncidentAlerts.Alerts, 1)
+	assert.Equal(t, "A2", client.LastManageIncidentAlerts.Alerts[0].ID)
+	assert.Equal(t, "resolved", client.LastManageIncidentAlerts.Alerts[0].Status)
+
+	result, cmd := m.Update(msg)
+	m = result.(model)
+	assert.Equal(t, "resolved", m.incidentCache["OTHER"].alerts[1].Status)
+	assert.Equal(t, "resolved", m.selectedIncidentAlerts[1].Status)
+	assert.Equal(t, []string{"c2", "c4"}, m.incidentClusterMap["OTHER"])
+	assert.Equal(t, []string{"c2", "c4"}, getUniqueClusters(m.selectedIncidentAlerts))
+	assert.Equal(t, "Resolved alert A2 in OTHER", m.status)
+
+	var redraw tea.Msg
+	drainCmd(t, cmd, func(msg tea.Msg) {
+		if _, ok := msg.(updatedIncidentListMsg); ok {
+			redraw = msg
+		}
+		_, refetch := msg.(updateIncidentListMsg)
+		assert.False(t, refetch, "other alerts are still open")
+	})
+	require.NotNil(t, redraw, "the table is redrawn without waiting for a poll")
+
+	result, _ = m.Update(redraw)
+	m = result.(model)
+	idx := findRowIndex(m.table.Rows(), "OTHER")
+	require.GreaterOrEqual(t, idx, 0)
+	assert.Contains(t, m.table.Rows()[idx][3], "(+1)")
+}
+
+func TestResolveAlertKey_LastOpenAlert(t *testing.T) {
+	m, _ := alertActionTestModel(t)
+	m.selectedIncidentAlerts[0].Status = "resolved"
+	m.selectedIncidentAlerts[1].Status = "resolved"
+	m.selectedAlertID = "A3"
+
+	m, _ = pressRune(t, m, 'R')
+	require.NotNil(t, m.pendingConfirmation)
+	assert.Contains(t, m.pendingConfirmation.prompt, "last open alert and resolves OTHER")
+
+	m.pendingConfirmation = nil
+	m.selectedAlertID = "A1"
+	m, _ = pressRune(t, m, 'R')
+	assert.Nil(t, m.pendingConfirmation)
+	assert.Equal(t, "alert A1 is already resolved", m.status)
+}
+
+func TestSplitAlertKey_MovesAlertToNewIncident(t *testing.T) {
+	m, client := alertActionTestModel(t)
+	m.selectedAlertID = "A3"
+
+	m, _ = pressRune(t, m, 'S')
+	require.NotNil(t, m.pendingConfirmation)
+	assert.Equal(t, "Move alert A3 (cluster c4) out of OTHER into a new incident? [y/n]", m.pendingConfirmation.prompt)
+
+	msg, ok := m.pendingConfirmation.action().(managedIncidentAlertMsg)
+	require.True(t, ok)
+	require.NoError(t, msg.err)
+	assert.Equal(t, "Y on c4", client.LastCreateIncidentOpts.Title, "the new incident is named after the alert")
+	assert.Equal(t, "SVC1", client.LastCreateIncidentOpts.Service.ID)
+	assert.Equal(t, "PNEWINC", client.LastManageIncidentAlerts.Alerts[0].Incident.ID)
+
+	result, cmd := m.Update(msg)
+	m = result.(model)
+	assert.Len(t, m.incidentCache["OTHER"].alerts, 2)
+	assert.Len(t, m.selectedIncidentAlerts, 2)
+	assert.Equal(t, []string{"c2", "c3"}, m.incidentClusterMap["OTHER"])
+	assert.Equal(t, "Moved alert A3 from OTHER to new incident PNEWINC", m.status)
+
+	refetch := false
+	drainCmd(t, cmd, func(msg tea.Msg) {
+		if _, ok := msg.(updateIncidentListMsg); ok {
+			refetch = true
+		}
+	})
+	assert.True(t, refetch, "the new incident is fetched")
+}
+
+func TestSplitAlertKey_SingleAlert(t *testing.T) {
+	m, _ := alertActionTestModel(t)
+	m.selectedIncidentAlerts = m.selectedIncidentAlerts[:1]
+
+	m, _ = pressRune(t, m, 'S')
+	assert.Nil(t, m.pendingConfirmation)
+	assert.Equal(t, "incident has a single alert; nothing to split", m.status)
+}
+
+func TestManagedIncidentAlertMsg_Error(t *testing.T) {
+	m, _ := alertActionTestModel(t)
+
+	_, cmd := m.Update(managedIncidentAlertMsg{action: alertActionResolve, incidentID: "OTHER", alertID: "A1", err: pd.ErrMockError})
+	require.NotNil(t, cmd)
+	_, isErr := cmd().(errMsg)
+	assert.True(t, isErr)
+	assert.Len(t, getUniqueClusters(m.selectedIncidentAlerts), 3, "nothing changes on failure")
+}
+
+func TestAlertHeadingLine(t *testing.T) {
+	content := "intro\n\x1b[1m### Alert 1/2\x1b[0m\nbody\n\x1b[1m### Alert 2/2 ◀ selected\x1b[0m\n"
+	assert.Equal(t, 1, alertHeadingLine(content, 0, 2))
+	assert.Equal(t, 3, alertHeadingLine(content, 1, 2))
+	assert.Equal(t, -1, alertHeadingLine(content, 2, 3))
+}
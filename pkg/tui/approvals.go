@@ -17,6 +17,7 @@ const (
 	AskDraftNote AskKind = iota
 	AskSuggestedCommand
 	AskEscalationSuggestion
+	AskFlagTag
 )
 
 // Ask represents a pending approval item from the AI watcher.
@@ -148,6 +149,8 @@ func (a *approvalsStrip) RenderExpanded(width int) string {
 			target = "    Copy command to clipboard"
 		case AskEscalationSuggestion:
 			target = fmt.Sprintf("    Re-escalate incident %s", ask.IncidentID)
+		case AskFlagTag:
+			target = fmt.Sprintf("    Retitle incident %s", ask.IncidentID)
 		default:
 			target = fmt.Sprintf("    Action on incident %s", ask.IncidentID)
 		}
@@ -203,6 +206,8 @@ func askKindLabel(k AskKind) string {
 		return "Command"
 	case AskEscalationSuggestion:
 		return "Escalation"
+	case AskFlagTag:
+		return "Tag"
 	default:
 		return "Unknown"
 	}
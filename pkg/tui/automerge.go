@@ -0,0 +1,246 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/PagerDuty/go-pagerduty"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+	pkgconfig "github.com/clcollins/srepd/pkg/config"
+	"github.com/clcollins/srepd/pkg/delta"
+	"github.com/clcollins/srepd/pkg/pd"
+	"github.com/spf13/viper"
+)
+
+// autoMergeConfig is the resolved auto_merge_* configuration.
+type autoMergeConfig struct {
+	rules []pkgconfig.AutoMergeRule
+	// dryRun only logs what would be merged. It is forced on while the
+	// rule set's digest does not match auto_merge_rules_reviewed.
+	dryRun   bool
+	reviewed bool
+}
+
+// resolveAutoMergeConfig reads the auto-merge rules. An invalid rule set
+// disables auto-merge rather than keeping srepd from starting.
+func resolveAutoMergeConfig() autoMergeConfig {
+	rules, err := pkgconfig.ParseAutoMergeRules(viper.Get("auto_merge_rules"))
+	if err != nil {
+		log.Warn("Auto-merge disabled", "error", err)
+		return autoMergeConfig{}
+	}
+	if len(rules) == 0 {
+		return autoMergeConfig{}
+	}
+	cfg := autoMergeConfig{
+		rules:    rules,
+		dryRun:   viper.GetBool("auto_merge_dry_run"),
+		reviewed: pkgconfig.AutoMergeDigest(rules) == viper.GetString("auto_merge_rules_reviewed"),
+	}
+	if !cfg.reviewed {
+		cfg.dryRun = true
+		log.Warn("Auto-merge rules have not been reviewed; running as a dry run (run 'srepd automerge review')", "rules", len(rules))
+	}
+	log.Info("Auto-merge rules loaded", "rules", len(rules), "dry_run", cfg.dryRun)
+	return cfg
+}
+
+// autoMergeMatch is a rule firing: source is merged into target because
+// both carry fingerprint.
+type autoMergeMatch struct {
+	rule        pkgconfig.AutoMergeRule
+	sourceID    string
+	targetID    string
+	fingerprint alertFingerprint
+}
+
+type autoMergedIncidentMsg struct {
+	match   autoMergeMatch
+	err     error
+	noteErr error
+}
+
+// ruleMatchesFingerprint reports whether fp, from an incident on service,
+// is the alert rule describes.
+func ruleMatchesFingerprint(rule pkgconfig.AutoMergeRule, fp alertFingerprint, service string) bool {
+	if fp.AlertName != rule.AlertName {
+		return false
+	}
+	if rule.AlertType != "" && fp.AlertType != rule.AlertType {
+		return false
+	}
+	return rule.Service == "" || rule.Service == service
+}
+
+// findAutoMergeTarget returns the merge a rule calls for on source: the
+// oldest other incident, created before source and within the rule's
+// window, sharing a rule-matching fingerprint. Both incidents' alerts must
+// be loaded.
+func findAutoMergeTarget(rules []pkgconfig.AutoMergeRule, source pagerduty.Incident, incidents []pagerduty.Incident, cache map[string]*cachedIncidentData) (autoMergeMatch, bool) {
+	cached, ok := cache[source.ID]
+	if !ok || cached == nil || !cached.alertsLoaded {
+		return autoMergeMatch{}, false
+	}
+	sourceCreated, err := time.Parse(time.RFC3339, source.CreatedAt)
+	if err != nil {
+		return autoMergeMatch{}, false
+	}
+	sourceFPs := fingerprintAlerts(cached.alerts)
+
+	candidates := slices.Clone(incidents)
+	slices.SortFunc(candidates, incidentCreatedBefore)
+
+	for _, rule := range rules {
+		for _, fp := range sourceFPs {
+			if !ruleMatchesFingerprint(rule, fp, source.Service.Summary) {
+				continue
+			}
+			for _, target := range candidates {
+				if target.ID == source.ID || incidentCreatedBefore(target, source) >= 0 {
+					continue
+				}
+				if rule.Service != "" && target.Service.Summary != rule.Service {
+					continue
+				}
+				targetCreated, err := time.Parse(time.RFC3339, target.CreatedAt)
+				if err != nil || sourceCreated.Sub(targetCreated) > rule.Within {
+					continue
+				}
+				tc, ok := cache[target.ID]
+				if !ok || tc == nil || !tc.alertsLoaded {
+					continue
+				}
+				if slices.Contains(fingerprintAlerts(tc.alerts), fp) {
+					return autoMergeMatch{rule: rule, sourceID: source.ID, targetID: target.ID, fingerprint: fp}, true
+				}
+			}
+		}
+	}
+	return autoMergeMatch{}, false
+}
+
+// autoMergeSettled reports whether findAutoMergeTarget has seen everything
+// it will for source: source's alerts and those of every incident created
+// before it within the widest rule window are loaded. Nothing matching by
+// then means nothing ever will.
+func autoMergeSettled(rules []pkgconfig.AutoMergeRule, source pagerduty.Incident, incidents []pagerduty.Incident, cache map[string]*cachedIncidentData) bool {
+	if c, ok := cache[source.ID]; !ok || c == nil || !c.alertsLoaded {
+		return false
+	}
+	sourceCreated, err := time.Parse(time.RFC3339, source.CreatedAt)
+	if err != nil {
+		return true
+	}
+	var window time.Duration
+	for _, rule := range rules {
+		window = max(window, rule.Within)
+	}
+	for _, target := range incidents {
+		if target.ID == source.ID || incidentCreatedBefore(target, source) >= 0 {
+			continue
+		}
+		targetCreated, err := time.Parse(time.RFC3339, target.CreatedAt)
+		if err != nil || sourceCreated.Sub(targetCreated) > window {
+			continue
+		}
+		if c, ok := cache[target.ID]; !ok || c == nil || !c.alertsLoaded {
+			return false
+		}
+	}
+	return true
+}
+
+// queueAutoMerge records the incidents delta.Diff saw arrive. The first
+// poll only establishes the baseline: incidents already open at startup
+// are not "newly arriving" and are never auto-merged.
+func (m *model) queueAutoMerge(changes []delta.Change, firstPoll bool) {
+	if len(m.autoMerge.rules) == 0 || firstPoll {
+		return
+	}
+	for _, c := range changes {
+		if c.Kind != delta.IncidentNew {
+			continue
+		}
+		if m.autoMergePending == nil {
+			m.autoMergePending = make(map[string]bool)
+		}
+		m.autoMergePending[c.IncidentID] = true
+	}
+}
+
+// evaluateAutoMerge checks pending new incidents against the rules. An
+// incident stays pending until its alerts (and a target's) are loaded, and
+// is dropped once it leaves the list, a rule fires for it, or it is
+// settled without a match.
+func (m *model) evaluateAutoMerge() []tea.Cmd {
+	if len(m.autoMergePending) == 0 {
+		return nil
+	}
+
+	var cmds []tea.Cmd
+	ids := make([]string, 0, len(m.autoMergePending))
+	for id := range m.autoMergePending {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	for _, id := range ids {
+		idx := slices.IndexFunc(m.incidentList, func(i pagerduty.Incident) bool { return i.ID == id })
+		if idx < 0 {
+			delete(m.autoMergePending, id)
+			continue
+		}
+		match, ok := findAutoMergeTarget(m.autoMerge.rules, m.incidentList[idx], m.incidentList, m.incidentCache)
+		if !ok {
+			if autoMergeSettled(m.autoMerge.rules, m.incidentList[idx], m.incidentList, m.incidentCache) {
+				delete(m.autoMergePending, id)
+			}
+			continue
+		}
+		delete(m.autoMergePending, id)
+
+		if m.autoMerge.dryRun {
+			log.Info("auto-merge dry run: would merge incident",
+				"rule", match.rule.Name,
+				"source", match.sourceID,
+				"target", match.targetID,
+				"alert", match.fingerprint.AlertName,
+				"cluster_id", match.fingerprint.ClusterID)
+			cmds = append(cmds, m.flashNotification(fmt.Sprintf("Auto-merge (dry run): would merge %s into %s (%s)", match.sourceID, match.targetID, match.rule.Name)))
+			continue
+		}
+		cmds = append(cmds, autoMergeIncidents(m.config, match))
+	}
+	return cmds
+}
+
+// autoMergeNote explains on the surviving incident why srepd merged into it.
+func autoMergeNote(match autoMergeMatch) string {
+	note := fmt.Sprintf("srepd auto-merged %s into this incident.\n\nRule %q: %s.\nMatched alert: %s on cluster %s",
+		match.sourceID, match.rule.Name, match.rule.Describe(),
+		match.fingerprint.AlertName, match.fingerprint.ClusterID)
+	if match.fingerprint.Namespace != "" {
+		note += fmt.Sprintf(" (namespace %s)", match.fingerprint.Namespace)
+	}
+	return note + "."
+}
+
+// autoMergeIncidents merges match.sourceID into match.targetID and notes the
+// rule on the target. A failed note does not undo the merge.
+func autoMergeIncidents(p *pd.Config, match autoMergeMatch) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		_, err := p.Client.MergeIncidentsWithContext(ctx,
+			p.CurrentUser.Email, match.targetID,
+			[]pagerduty.MergeIncidentsOptions{{ID: match.sourceID, Type: "incident_reference"}})
+		if err != nil {
+			return autoMergedIncidentMsg{match: match, err: err}
+		}
+		_, noteErr := pd.PostNote(p.Client, match.targetID, p.CurrentUser, autoMergeNote(match))
+		return autoMergedIncidentMsg{match: match, noteErr: noteErr}
+	}
+}
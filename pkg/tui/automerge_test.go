@@ -0,0 +1,225 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/PagerDuty/go-pagerduty"
+	tea "github.com/charmbracelet/bubbletea"
+	pkgconfig "github.com/clcollins/srepd/pkg/config"
+	"github.com/clcollins/srepd/pkg/delta"
+	"github.com/clcollins/srepd/pkg/pd"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var coDownRule = pkgconfig.AutoMergeRule{Name: "co-down", AlertName: "ClusterOperatorDown", Within: time.Hour}
+
+func TestFindAutoMergeTarget(t *testing.T) {
+	incidents := []pagerduty.Incident{
+		dupIncident("OLDEST", "2026-05-01T09:00:00Z"),
+		dupIncident("OLD", "2026-05-01T11:30:00Z"),
+		dupIncident("NEW", "2026-05-01T12:00:00Z"),
+		dupIncident("OTHER", "2026-05-01T11:45:00Z"),
+	}
+	cache := alertsCache(map[string][]pagerduty.IncidentAlert{
+		"OLDEST": {hiveAlert("ClusterOperatorDown", "c1", "")},
+		"OLD":    {hiveAlert("ClusterOperatorDown", "c1", "")},
+		"NEW":    {hiveAlert("ClusterOperatorDown", "c1", "")},
+		"OTHER":  {hiveAlert("ClusterOperatorDown", "c2", "")},
+	})
+
+	match, ok := findAutoMergeTarget([]pkgconfig.AutoMergeRule{coDownRule}, incidents[2], incidents, cache)
+	require.True(t, ok)
+	assert.Equal(t, "NEW", match.sourceID)
+	assert.Equal(t, "OLD", match.targetID, "OLDEST is outside the 1h window; OTHER is another cluster")
+	assert.Equal(t, "co-down", match.rule.Name)
+	assert.Equal(t, "c1", match.fingerprint.ClusterID)
+
+	_, ok = findAutoMergeTarget([]pkgconfig.AutoMergeRule{coDownRule}, incidents[0], incidents, cache)
+	assert.False(t, ok, "never merges into a newer incident")
+}
+
+func TestFindAutoMergeTarget_RuleFilters(t *testing.T) {
+	incidents := []pagerduty.Incident{
+		dupIncident("OLD", "2026-05-01T11:30:00Z"),
+		dupIncident("NEW", "2026-05-01T12:00:00Z"),
+	}
+	cache := alertsCache(map[string][]pagerduty.IncidentAlert{
+		"OLD": {hiveAlert("KubeNodeNotReady", "c1", "")},
+		"NEW": {hiveAlert("KubeNodeNotReady", "c1", "")},
+	})
+
+	_, ok := findAutoMergeTarget([]pkgconfig.AutoMergeRule{coDownRule}, incidents[1], incidents, cache)
+	assert.False(t, ok, "alert name must match the rule")
+
+	rule := pkgconfig.AutoMergeRule{Name: "n", AlertName: "KubeNodeNotReady", Service: "other-svc", Within: time.Hour}
+	_, ok = findAutoMergeTarget([]pkgconfig.AutoMergeRule{rule}, incidents[1], incidents, cache)
+	assert.False(t, ok, "service must match when set")
+
+	rule.Service = "svc"
+	_, ok = findAutoMergeTarget([]pkgconfig.AutoMergeRule{rule}, incidents[1], incidents, cache)
+	assert.True(t, ok)
+
+	delete(cache, "OLD")
+	_, ok = findAutoMergeTarget([]pkgconfig.AutoMergeRule{rule}, incidents[1], incidents, cache)
+	assert.False(t, ok, "target alerts must be loaded")
+}
+
+func autoMergeTestModel(t *testing.T, dryRun bool) model {
+	t.Helper()
+	m := dupTestModel(t)
+	m.autoMerge = autoMergeConfig{rules: []pkgconfig.AutoMergeRule{coDownRule}, dryRun: dryRun, reviewed: true}
+	m.incidentList = []pagerduty.Incident{
+		dupIncident("OLD", "2026-05-01T11:30:00Z"),
+		dupIncident("NEW", "2026-05-01T12:00:00Z"),
+	}
+	m.incidentCache = alertsCache(map[string][]pagerduty.IncidentAlert{
+		"OLD": {hiveAlert("ClusterOperatorDown", "c1", "")},
+		"NEW": {hiveAlert("ClusterOperatorDown", "c1", "")},
+	})
+	return m
+}
+
+func TestQueueAutoMerge_SkipsFirstPoll(t *testing.T) {
+	m := autoMergeTestModel(t, false)
+	changes := []delta.Change{{Kind: delta.IncidentNew, IncidentID: "NEW"}, {Kind: delta.StatusChanged, IncidentID: "OLD"}}
+
+	m.queueAutoMerge(changes, true)
+	assert.Empty(t, m.autoMergePending, "incidents open at startup are not new arrivals")
+
+	m.queueAutoMerge(changes, false)
+	assert.Equal(t, map[string]bool{"NEW": true}, m.autoMergePending)
+}
+
+func TestEvaluateAutoMerge_MergesAndNotes(t *testing.T) {
+	m := autoMergeTestModel(t, false)
+	client := &pd.MockPagerDutyClient{}
+	m.config.Client = client
+	m.autoMergePending = map[string]bool{"NEW": true}
+
+	cmds := m.evaluateAutoMerge()
+	require.Len(t, cmds, 1)
+	assert.Empty(t, m.autoMergePending, "a fired rule is not re-evaluated")
+
+	msg, ok := cmds[0]().(autoMergedIncidentMsg)
+	require.True(t, ok)
+	require.NoError(t, msg.err)
+	require.NoError(t, msg.noteErr)
+	assert.Equal(t, "OLD", msg.match.targetID)
+	assert.Equal(t, 1, client.CallCounts["MergeIncidentsWithContext"])
+	assert.Equal(t, 1, client.CallCounts["CreateIncidentNoteWithContext"])
+}
+
+func TestEvaluateAutoMerge_DryRunOnlyLogs(t *testing.T) {
+	m := autoMergeTestModel(t, true)
+	client := &pd.MockPagerDutyClient{}
+	m.config.Client = client
+	m.autoMergePending = map[string]bool{"NEW": true}
+
+	cmds := m.evaluateAutoMerge()
+	assert.Contains(t, m.status, "Auto-merge (dry run): would merge NEW into OLD (co-down)")
+	for _, cmd := range cmds {
+		_, isMerge := cmd().(autoMergedIncidentMsg)
+		assert.False(t, isMerge)
+	}
+	assert.Zero(t, client.CallCounts["MergeIncidentsWithContext"])
+}
+
+func TestEvaluateAutoMerge_WaitsForAlertsAndPrunes(t *testing.T) {
+	m := autoMergeTestModel(t, false)
+	delete(m.incidentCache, "NEW")
+	m.autoMergePending = map[string]bool{"NEW": true, "GONE": true}
+
+	assert.Empty(t, m.evaluateAutoMerge())
+	assert.Equal(t, map[string]bool{"NEW": true}, m.autoMergePending, "NEW waits for its alerts; GONE left the list")
+}
+
+func TestEvaluateAutoMerge_DropsSettledWithoutMatch(t *testing.T) {
+	m := autoMergeTestModel(t, false)
+	m.incidentCache["NEW"].alerts = []pagerduty.IncidentAlert{hiveAlert("ClusterOperatorDown", "c2", "")}
+	delete(m.incidentCache, "OLD")
+	m.autoMergePending = map[string]bool{"NEW": true}
+
+	assert.Empty(t, m.evaluateAutoMerge())
+	assert.Equal(t, map[string]bool{"NEW": true}, m.autoMergePending, "OLD's alerts may still match")
+
+	m.incidentCache["OLD"] = &cachedIncidentData{alerts: []pagerduty.IncidentAlert{hiveAlert("ClusterOperatorDown", "c1", "")}, alertsLoaded: true}
+	assert.Empty(t, m.evaluateAutoMerge())
+	assert.Empty(t, m.autoMergePending, "both sides loaded without a match")
+}
+
+func TestAutoMergeNote(t *testing.T) {
+	note := autoMergeNote(autoMergeMatch{
+		rule:        coDownRule,
+		sourceID:    "NEW",
+		targetID:    "OLD",
+		fingerprint: alertFingerprint{AlertName: "ClusterOperatorDown", ClusterID: "c1", Namespace: "openshift-monitoring"},
+	})
+	assert.Contains(t, note, "srepd auto-merged NEW into this incident")
+	assert.Contains(t, note, `Rule "co-down"`)
+	assert.Contains(t, note, "ClusterOperatorDown on cluster c1 (namespace openshift-monitoring)")
+}
+
+func TestAutoMergedIncidentMsg_RefreshesList(t *testing.T) {
+	m := autoMergeTestModel(t, false)
+	match := autoMergeMatch{rule: coDownRule, sourceID: "NEW", targetID: "OLD"}
+
+	result, cmd := m.Update(autoMergedIncidentMsg{match: match})
+	updated, ok := result.(model)
+	require.True(t, ok)
+	assert.Contains(t, updated.status, "Auto-merged NEW into OLD (co-down)")
+
+	refreshed := false
+	drainCmd(t, cmd, func(msg tea.Msg) {
+		if _, ok := msg.(updateIncidentListMsg); ok {
+			refreshed = true
+		}
+	})
+	assert.True(t, refreshed)
+}
+
+func TestResolveAutoMergeConfig_UnreviewedForcesDryRun(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+	viper.Set("auto_merge_rules", []any{map[string]any{"name": "co-down", "alert_name": "ClusterOperatorDown"}})
+
+	cfg := resolveAutoMergeConfig()
+	require.Len(t, cfg.rules, 1)
+	assert.False(t, cfg.reviewed)
+	assert.True(t, cfg.dryRun)
+
+	viper.Set("auto_merge_rules_reviewed", pkgconfig.AutoMergeDigest(cfg.rules))
+	cfg = resolveAutoMergeConfig()
+	assert.True(t, cfg.reviewed)
+	assert.False(t, cfg.dryRun)
+
+	viper.Set("auto_merge_dry_run", true)
+	assert.True(t, resolveAutoMergeConfig().dryRun, "dry run can be kept on after review")
+
+	viper.Set("auto_merge_rules", "nonsense")
+	assert.Empty(t, resolveAutoMergeConfig().rules, "invalid rules disable auto-merge")
+}
+
+func TestUpdatedIncidentList_AutoMergesNewArrival(t *testing.T) {
+	m := autoMergeTestModel(t, false)
+	m.teamMode = true
+	m.showLowUrgency = true
+	client := &pd.MockPagerDutyClient{}
+	m.config.Client = client
+	all := m.incidentList
+	m.incidentList = all[:1]
+	m.prevSnapshots = toSnapshots(m.incidentList, m.incidentCache)
+
+	_, cmd := m.Update(updatedIncidentListMsg{incidents: all})
+
+	merged := false
+	drainCmd(t, cmd, func(msg tea.Msg) {
+		if am, ok := msg.(autoMergedIncidentMsg); ok {
+			merged = am.match.sourceID == "NEW" && am.match.targetID == "OLD"
+		}
+	})
+	assert.True(t, merged, "NEW arrived after the baseline poll and matches the rule")
+	assert.Equal(t, 1, client.CallCounts["MergeIncidentsWithContext"])
+}
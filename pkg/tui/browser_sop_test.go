@@ -428,3 +428,47 @@ func TestWaitForSelectedIncidentThenDoMsg_TableDriven(t *testing.T) {
 		})
 	}
 }
+
+// ---------------------------------------------------------------------------
+// openPrometheusMsg
+// ---------------------------------------------------------------------------
+
+func TestOpenPrometheusMsg_NoLink(t *testing.T) {
+	m := createTestModelWithSelectedIncident()
+	m.selectedIncidentAlerts = []pagerduty.IncidentAlert{firingAlert("Labels:\n - a = b\n", "1")}
+
+	result, cmd := m.Update(openPrometheusMsg("prometheus"))
+	m = result.(model)
+
+	assert.Nil(t, cmd)
+	assert.Equal(t, "no Prometheus query link found", m.status)
+}
+
+func TestOpenPrometheusMsg_OpensGeneratorURL(t *testing.T) {
+	if defaultBrowserOpenCommand == "" {
+		t.Skip("defaultBrowserOpenCommand is empty on this OS")
+	}
+	m := createTestModelWithSelectedIncident()
+	m.selectedIncidentAlerts = []pagerduty.IncidentAlert{firingAlert("Labels:\n - a = b\nSource: https://prom.example.com/graph?g0.expr=up\n", "1")}
+
+	link, ok := getPrometheusLink(m.selectedIncidentAlerts)
+	assert.True(t, ok)
+	assert.Equal(t, "https://prom.example.com/graph?g0.expr=up", link)
+
+	result, cmd := m.Update(openPrometheusMsg("prometheus"))
+	m = result.(model)
+
+	assert.NotNil(t, cmd)
+	assert.Contains(t, m.status, "Opened Prometheus query")
+}
+
+func TestPrometheusKey_WaitsForAlerts(t *testing.T) {
+	m := createTestModelWithSelectedIncident()
+	m.viewingIncident = true
+	m.incidentAlertsLoaded = false
+
+	result, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+	m = result.(model)
+
+	assert.Equal(t, "Loading incident alerts, please wait...", m.status)
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	pkgconfig "github.com/clcollins/srepd/pkg/config"
 )
 
 // chordAction defines a single chord command triggered by a prefix key + second key.
@@ -26,6 +27,7 @@ var chordRegistry = []struct {
 	{Key: "b", Description: "rosa-boundary login"},
 	{Key: "d", Description: "view debug log"},
 	{Key: "s", Description: "bulk silence", Hidden: true},
+	{Key: "t", Description: "focus terminal pane"},
 }
 
 // getChordActions returns the full chord action list with handlers attached.
@@ -37,6 +39,7 @@ func getChordActions() []chordAction {
 		"b": chordRosaBoundaryLogin,
 		"d": chordViewLog,
 		"s": chordBulkSilence,
+		"t": chordFocusPane,
 	}
 
 	var actions []chordAction
@@ -71,9 +74,11 @@ func chordShowHelp(m model) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-// chordKeymap implements help.KeyMap to display chord bindings in the help section.
+// chordKeymap implements help.KeyMap to display chord bindings in the help
+// section, followed by the custom actions bound to a key.
 type chordKeymap struct {
-	prefix string
+	prefix  string
+	actions []pkgconfig.CustomAction
 }
 
 func (k chordKeymap) ShortHelp() []key.Binding {
@@ -96,6 +101,15 @@ func (k chordKeymap) FullHelp() [][]key.Binding {
 			key.WithHelp(k.prefix+" "+entry.Key, entry.Description),
 		))
 	}
+	for _, a := range k.actions {
+		if a.Key == "" {
+			continue
+		}
+		bindings = append(bindings, key.NewBinding(
+			key.WithKeys(k.prefix+" "+a.Key),
+			key.WithHelp(k.prefix+" "+a.Key, a.Name),
+		))
+	}
 	return [][]key.Binding{bindings}
 }
 
@@ -0,0 +1,351 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/PagerDuty/go-pagerduty"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/clcollins/srepd/pkg/launcher"
+	"github.com/clcollins/srepd/pkg/pd"
+)
+
+const (
+	// commandOutputRefresh is how often the Output tab is redrawn while a
+	// command prints.
+	commandOutputRefresh = 250 * time.Millisecond
+	// commandOutputNoteLimit is how many bytes of output a note carries;
+	// PagerDuty notes are limited to 25000 characters.
+	commandOutputNoteLimit = 16000
+)
+
+// commandRun is a command run with its output captured, shown on the
+// Output tab of the incident it ran for.
+type commandRun struct {
+	name       string
+	incidentID string
+	clusterID  string
+	command    string
+	capture    *launcher.Capture
+	posted     bool
+}
+
+type commandRunStartedMsg struct {
+	run *commandRun
+	err error
+}
+
+type commandRunOutputMsg struct {
+	run *commandRun
+}
+
+type commandRunFinishedMsg struct {
+	run *commandRun
+}
+
+type commandOutputPostedMsg struct {
+	run *commandRun
+	err error
+}
+
+// startCommandRun runs l's command without a terminal and captures its
+// output. The PAGERDUTY_* variables reach it the way they reach a login
+// (see buildDirectCommand). It is killed after timeout.
+func startCommandRun(name string, vars map[string]string, l launcher.ClusterLauncher, timeout time.Duration, incident *pagerduty.Incident, alerts []pagerduty.IncidentAlert, notes []pagerduty.IncidentNote) tea.Cmd {
+	return func() tea.Msg {
+		clusterID := vars["%%CLUSTER_ID%%"]
+		envFlags := buildPagerDutyEnvVars(incident, alerts, notes, clusterID)
+		command, processEnvVars := buildDirectCommand(l, vars, envFlags)
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		c := exec.CommandContext(ctx, command[0], command[1:]...)
+		c.WaitDelay = 5 * time.Second
+		if len(processEnvVars) > 0 {
+			c.Env = append(os.Environ(), processEnvVars...)
+		}
+		log.Debug("tui.startCommandRun()", "name", name, "command", c.String())
+
+		capture, err := launcher.StartCapture(c)
+		if err != nil {
+			cancel()
+			return commandRunStartedMsg{run: &commandRun{name: name, incidentID: incident.ID, clusterID: clusterID}, err: err}
+		}
+		go func() {
+			<-capture.Done()
+			cancel()
+		}()
+		return commandRunStartedMsg{run: &commandRun{
+			name:       name,
+			incidentID: incident.ID,
+			clusterID:  clusterID,
+			command:    strings.Join(command, " "),
+			capture:    capture,
+		}}
+	}
+}
+
+// waitForCommandRun waits for a command to print or exit. Output is
+// reported at most every commandOutputRefresh, so a chatty command does
+// not re-render the incident on every line.
+func waitForCommandRun(run *commandRun, delay time.Duration) tea.Cmd {
+	return func() tea.Msg {
+		time.Sleep(delay)
+		select {
+		case <-run.capture.Changed():
+			return commandRunOutputMsg{run: run}
+		case <-run.capture.Done():
+			return commandRunFinishedMsg{run: run}
+		}
+	}
+}
+
+// applyCommandRunStarted adds a run to its incident's history and, while
+// that incident is selected, shows it on the Output tab.
+func (m *model) applyCommandRunStarted(msg commandRunStartedMsg) tea.Cmd {
+	run := msg.run
+	if msg.err != nil {
+		log.Warn("command run failed to start", "name", run.name, "cluster_id", run.clusterID, "incident_id", run.incidentID, "error", msg.err)
+		return m.flashNotification(fmt.Sprintf("%s failed to start: %s", run.name, msg.err))
+	}
+	if m.commandRuns == nil {
+		m.commandRuns = make(map[string][]*commandRun)
+	}
+	m.commandRuns[run.incidentID] = append(m.commandRuns[run.incidentID], run)
+
+	cmds := []tea.Cmd{waitForCommandRun(run, 0)}
+	if m.selectedIncident != nil && m.selectedIncident.ID == run.incidentID {
+		m.selectedCommandRun = run
+		m.activeTab = tabOutput
+		m.table.Blur()
+		cmds = append(cmds, func() tea.Msg { return renderIncidentMsg("command started") })
+	}
+	return tea.Batch(cmds...)
+}
+
+// applyCommandRunOutput redraws the Output tab showing a run and keeps
+// following it.
+func (m *model) applyCommandRunOutput(msg commandRunOutputMsg) tea.Cmd {
+	return tea.Batch(waitForCommandRun(msg.run, commandOutputRefresh), m.rerenderCommandOutput(msg.run))
+}
+
+// applyCommandRunFinished reports how a run exited.
+func (m *model) applyCommandRunFinished(msg commandRunFinishedMsg) tea.Cmd {
+	run := msg.run
+	code, duration, err := run.capture.Result()
+	var status string
+	if err != nil {
+		output, _ := run.capture.Output()
+		log.Warn("command run failed", "name", run.name, "cluster_id", run.clusterID, "incident_id", run.incidentID, "exit_code", code, "error", err)
+		status = fmt.Sprintf("%s failed: %s", run.name, lastLine(output, err))
+	} else {
+		log.Info("command run finished", "name", run.name, "cluster_id", run.clusterID, "incident_id", run.incidentID, "duration", duration)
+		status = fmt.Sprintf("%s finished", run.name)
+	}
+	return tea.Batch(m.flashNotification(status), m.rerenderCommandOutput(run))
+}
+
+// rerenderCommandOutput redraws the Output tab when it shows run's
+// incident.
+func (m model) rerenderCommandOutput(run *commandRun) tea.Cmd {
+	if !m.viewingIncident || m.activeTab != tabOutput || m.selectedIncident == nil || m.selectedIncident.ID != run.incidentID {
+		return nil
+	}
+	return func() tea.Msg { return renderIncidentMsg("command output") }
+}
+
+// selectedIncidentRun returns the run that the Output tab's actions work
+// on: the one picked with [ and ], or the newest.
+func (m model) selectedIncidentRun() *commandRun {
+	if m.selectedIncident == nil {
+		return nil
+	}
+	runs := m.commandRuns[m.selectedIncident.ID]
+	if len(runs) == 0 {
+		return nil
+	}
+	if slices.Contains(runs, m.selectedCommandRun) {
+		return m.selectedCommandRun
+	}
+	return runs[len(runs)-1]
+}
+
+// moveCommandRunSelection moves the Output tab selection by delta down
+// the list, which shows the newest run first, wrapping at either end.
+func (m *model) moveCommandRunSelection(delta int) {
+	runs := m.commandRuns[m.selectedIncident.ID]
+	if len(runs) == 0 {
+		m.setStatus("no command runs for this incident")
+		return
+	}
+	pos := len(runs) - 1 - slices.Index(runs, m.selectedIncidentRun())
+	pos = ((pos+delta)%len(runs) + len(runs)) % len(runs)
+	m.selectedCommandRun = runs[len(runs)-1-pos]
+	m.setStatus(fmt.Sprintf("selected run %d/%d: %s", pos+1, len(runs), m.selectedCommandRun.name))
+}
+
+// confirmPostCommandOutput asks before posting the selected run's output
+// as a note on the incident.
+func (m *model) confirmPostCommandOutput() tea.Cmd {
+	run := m.selectedIncidentRun()
+	if run == nil {
+		return m.flashNotification("no command output to post")
+	}
+	if run.capture.Running() {
+		return m.flashNotification(fmt.Sprintf("%s is still running — post its output once it finishes", run.name))
+	}
+	note, truncated := commandOutputNote(run)
+	prompt := fmt.Sprintf("Post the output of %s as a note on %s?", run.name, run.incidentID)
+	if truncated {
+		prompt += fmt.Sprintf(" It is truncated to %d bytes.", commandOutputNoteLimit)
+	}
+	if run.posted {
+		prompt += " It was posted already."
+	}
+	m.pendingConfirmation = &confirmActionState{
+		prompt: prompt + " [y/n]",
+		action: postCommandOutput(m.config, run, note),
+	}
+	return nil
+}
+
+func postCommandOutput(p *pd.Config, run *commandRun, note string) tea.Cmd {
+	return func() tea.Msg {
+		if p == nil || p.Client == nil {
+			return commandOutputPostedMsg{run: run, err: fmt.Errorf("PagerDuty not configured")}
+		}
+		_, err := pd.PostNote(p.Client, run.incidentID, p.CurrentUser, note)
+		return commandOutputPostedMsg{run: run, err: err}
+	}
+}
+
+func (m *model) applyCommandOutputPosted(msg commandOutputPostedMsg) tea.Cmd {
+	if msg.err != nil {
+		log.Warn("command output note failed", "name", msg.run.name, "incident_id", msg.run.incidentID, "error", msg.err)
+		return m.flashNotification("output not posted: " + msg.err.Error())
+	}
+	log.Info("command output posted", "name", msg.run.name, "incident_id", msg.run.incidentID)
+	msg.run.posted = true
+	cmds := []tea.Cmd{m.flashNotification(fmt.Sprintf("posted the output of %s to %s", msg.run.name, msg.run.incidentID))}
+	if m.selectedIncident != nil && m.selectedIncident.ID == msg.run.incidentID {
+		incidentID := msg.run.incidentID
+		cmds = append(cmds, func() tea.Msg { return getIncidentMsg(incidentID) })
+	}
+	return tea.Batch(cmds...)
+}
+
+// commandOutputNote is the note recording a run: the command, how it
+// exited and its output in a code block, cut to commandOutputNoteLimit
+// bytes at a line boundary.
+func commandOutputNote(run *commandRun) (note string, truncated bool) {
+	output, dropped := run.capture.Output()
+	output = strings.TrimRight(ansi.Strip(output), "\n")
+	total := strings.Count(output, "\n") + 1
+	if len(output) > commandOutputNoteLimit {
+		cut := commandOutputNoteLimit
+		for cut > 0 && !utf8.RuneStart(output[cut]) {
+			cut--
+		}
+		output = output[:cut]
+		if i := strings.LastIndexByte(output, '\n'); i > 0 {
+			output = output[:i]
+		}
+		truncated = true
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Output of %s", run.name)
+	if run.clusterID != "" {
+		fmt.Fprintf(&b, " on cluster %s", run.clusterID)
+	}
+	code, duration, _ := run.capture.Result()
+	fmt.Fprintf(&b, " (exit code %d, %s):\n\n", code, duration.Round(time.Second))
+	fmt.Fprintf(&b, "Command: %s\n\n", run.command)
+	if output == "" {
+		b.WriteString("No output.")
+		return b.String(), false
+	}
+	fence := codeFence(output)
+	fmt.Fprintf(&b, "%s\n%s\n%s", fence, output, fence)
+	if dropped > 0 {
+		fmt.Fprintf(&b, "\n\nThe first %d bytes of output were not kept.", dropped)
+	}
+	if truncated {
+		fmt.Fprintf(&b, "\n\nTruncated: %d of %d lines shown.", strings.Count(output, "\n")+1, total)
+	}
+	return b.String(), truncated
+}
+
+// codeFence returns a fence longer than any run of backticks in s, so s
+// cannot close its own code block.
+func codeFence(s string) string {
+	longest, current := 0, 0
+	for _, r := range s {
+		if r == '`' {
+			current++
+			longest = max(longest, current)
+		} else {
+			current = 0
+		}
+	}
+	return strings.Repeat("`", max(3, longest+1))
+}
+
+// renderOutputTab shows the selected incident's command runs, newest
+// first.
+func (m model) renderOutputTab() (string, error) {
+	var runs []*commandRun
+	if m.selectedIncident != nil {
+		runs = m.commandRuns[m.selectedIncident.ID]
+	}
+	if len(runs) == 0 {
+		return "\n_No command output — custom actions with `mode: capture` show theirs here_\n", nil
+	}
+
+	selected := m.selectedIncidentRun()
+	var b strings.Builder
+	for i := len(runs) - 1; i >= 0; i-- {
+		r := runs[i]
+		fmt.Fprintf(&b, "### %s", r.name)
+		if r.clusterID != "" {
+			fmt.Fprintf(&b, " on %s", r.clusterID)
+		}
+		if r == selected && len(runs) > 1 {
+			b.WriteString(" ◀ selected")
+		}
+		b.WriteString("\n\n")
+		fmt.Fprintf(&b, "* Command: `%s`\n", r.command)
+		fmt.Fprintf(&b, "* Started: %s\n", r.capture.Started.Format(sessionTimeFormat))
+		if r.capture.Running() {
+			b.WriteString("* Status: running\n")
+		} else {
+			code, duration, _ := r.capture.Result()
+			fmt.Fprintf(&b, "* Status: exited (%d) after %s\n", code, duration.Round(time.Millisecond))
+		}
+		if r.posted {
+			b.WriteString("* Posted as a note\n")
+		}
+		output, dropped := r.capture.Output()
+		output = strings.TrimRight(ansi.Strip(output), "\n")
+		if dropped > 0 {
+			fmt.Fprintf(&b, "\n_The first %d bytes of output were not kept._\n", dropped)
+		}
+		if output != "" {
+			fence := codeFence(output)
+			fmt.Fprintf(&b, "\n%s\n%s\n%s\n", fence, output, fence)
+		} else {
+			b.WriteString("\n_No output._\n")
+		}
+		if i > 0 {
+			b.WriteString("\n---\n")
+		}
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,152 @@
+package tui
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/PagerDuty/go-pagerduty"
+	tea "github.com/charmbracelet/bubbletea"
+	pkgconfig "github.com/clcollins/srepd/pkg/config"
+	"github.com/clcollins/srepd/pkg/launcher"
+	"github.com/clcollins/srepd/pkg/pd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// finishedTestRun runs a shell script for incidentID and waits for it to
+// exit.
+func finishedTestRun(t *testing.T, name, incidentID, script string) *commandRun {
+	t.Helper()
+	capture, err := launcher.StartCapture(exec.Command("sh", "-c", script))
+	require.NoError(t, err)
+	<-capture.Done()
+	return &commandRun{name: name, incidentID: incidentID, clusterID: "cluster-a", command: "sh -c " + script, capture: capture}
+}
+
+func TestCommandRun_StreamsIntoOutputTab(t *testing.T) {
+	m := customActionsTestModel(t, pkgconfig.CustomAction{Name: "health", Command: "echo checking %%CLUSTER_ID%%", Mode: pkgconfig.CustomActionCapture})
+
+	started := m.applyCustomAction(customActionMsg{action: m.customActions[0]})().(commandRunStartedMsg)
+	require.NoError(t, started.err)
+	m.applyCommandRunStarted(started)
+	assert.Equal(t, tabOutput, m.activeTab)
+	require.Len(t, m.commandRuns[m.selectedIncident.ID], 1)
+
+	// The run is followed until it exits.
+	msg := waitForCommandRun(started.run, 0)()
+	for {
+		if _, ok := msg.(commandRunFinishedMsg); ok {
+			break
+		}
+		msg = waitForCommandRun(started.run, 0)()
+	}
+	m.viewingIncident = true
+	assert.NotNil(t, m.applyCommandRunFinished(msg.(commandRunFinishedMsg)), "the showing tab is redrawn")
+	assert.Equal(t, "health finished", m.status)
+
+	content, err := m.renderOutputTab()
+	require.NoError(t, err)
+	assert.Contains(t, content, "### health on cluster-a")
+	assert.Contains(t, content, "* Status: exited (0)")
+	assert.Contains(t, content, "```\nchecking cluster-a\n```")
+	defer func(size tea.WindowSizeMsg) { windowSize = size }(windowSize)
+	windowSize = tea.WindowSizeMsg{Width: 300, Height: 40}
+	assert.Contains(t, m.renderTabBar(), "Output (1)")
+}
+
+func TestCommandRun_KeepsHistoryPerIncident(t *testing.T) {
+	m := customActionsTestModel(t)
+	id := m.selectedIncident.ID
+	first := finishedTestRun(t, "first", id, "echo one")
+	second := finishedTestRun(t, "second", id, "echo two; exit 2")
+	other := finishedTestRun(t, "other", "Q0000000", "echo elsewhere")
+	m.applyCommandRunStarted(commandRunStartedMsg{run: first})
+	m.applyCommandRunStarted(commandRunStartedMsg{run: other})
+	m.applyCommandRunStarted(commandRunStartedMsg{run: second})
+
+	assert.Len(t, m.commandRuns[id], 2)
+	assert.Same(t, second, m.selectedIncidentRun(), "the newest run is selected")
+
+	content, err := m.renderOutputTab()
+	require.NoError(t, err)
+	assert.NotContains(t, content, "elsewhere")
+	assert.Less(t, strings.Index(content, "### second"), strings.Index(content, "### first"), "newest first")
+	assert.Contains(t, content, "* Status: exited (2)")
+	assert.Contains(t, content, "### second on cluster-a ◀ selected")
+
+	// ] moves down the list to the older run, wrapping at the end.
+	m.activeTab = tabOutput
+	m.viewingIncident = true
+	result, cmd := m.keyMsgHandler(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("]")})
+	m = result.(model)
+	require.NotNil(t, cmd)
+	assert.Same(t, first, m.selectedIncidentRun())
+	assert.Equal(t, "selected run 2/2: first", m.status)
+	m.moveCommandRunSelection(1)
+	assert.Same(t, second, m.selectedIncidentRun())
+	m.moveCommandRunSelection(-1)
+	assert.Same(t, first, m.selectedIncidentRun())
+}
+
+func TestCommandOutputNote(t *testing.T) {
+	run := finishedTestRun(t, "logs", "P1234567", "printf '\\033[31mred\\033[0m\\n'; echo '```'; exit 1")
+	note, truncated := commandOutputNote(run)
+	assert.False(t, truncated)
+	assert.Contains(t, note, "Output of logs on cluster cluster-a (exit code 1,")
+	assert.Contains(t, note, "````\nred\n```\n````", "ANSI is stripped and the fence outlasts the output's")
+
+	long := finishedTestRun(t, "long", "P1234567", "i=0; while [ $i -lt 2000 ]; do echo line-$i-padding-padding; i=$((i+1)); done")
+	note, truncated = commandOutputNote(long)
+	assert.True(t, truncated)
+	assert.Less(t, len(note), commandOutputNoteLimit+500)
+	assert.Contains(t, note, "line-0-padding")
+	assert.NotContains(t, note, "line-1999-padding")
+	assert.Contains(t, note, "of 2000 lines shown.")
+
+	oneLine := finishedTestRun(t, "wide", "P1234567", "printf a; yes é | head -n 9000 | tr -d '\\n'")
+	note, truncated = commandOutputNote(oneLine)
+	assert.True(t, truncated)
+	assert.True(t, utf8.ValidString(note), "the cut does not split a character")
+
+	empty := finishedTestRun(t, "quiet", "P1234567", "true")
+	note, _ = commandOutputNote(empty)
+	assert.True(t, strings.HasSuffix(note, "No output."))
+}
+
+func TestCodeFence(t *testing.T) {
+	assert.Equal(t, "```", codeFence("plain"))
+	assert.Equal(t, "````", codeFence("a ``` b"))
+	assert.Equal(t, "``````", codeFence("`````"))
+}
+
+func TestPostCommandOutput(t *testing.T) {
+	m := customActionsTestModel(t)
+	m.config = &pd.Config{Client: &pd.MockPagerDutyClient{}, CurrentUser: &pagerduty.User{}}
+	m.activeTab = tabOutput
+	m.viewingIncident = true
+
+	result, _ := m.keyMsgHandler(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("P")})
+	m = result.(model)
+	assert.Equal(t, "no command output to post", m.status)
+
+	run := finishedTestRun(t, "health", m.selectedIncident.ID, "echo ok")
+	m.applyCommandRunStarted(commandRunStartedMsg{run: run})
+	result, _ = m.keyMsgHandler(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("P")})
+	m = result.(model)
+	require.NotNil(t, m.pendingConfirmation)
+	assert.Contains(t, m.pendingConfirmation.prompt, "Post the output of health as a note on P1234567?")
+
+	posted, ok := m.pendingConfirmation.action().(commandOutputPostedMsg)
+	require.True(t, ok)
+	require.NoError(t, posted.err)
+	m.pendingConfirmation = nil
+	assert.NotNil(t, m.applyCommandOutputPosted(posted))
+	assert.True(t, run.posted)
+	assert.Equal(t, "posted the output of health to P1234567", m.status)
+
+	m.activeTab = tabAlerts
+	result, _ = m.keyMsgHandler(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("P")})
+	assert.Equal(t, "switch to the Output tab to post command output", result.(model).status)
+}
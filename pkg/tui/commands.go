@@ -683,6 +683,7 @@ type browserFinishedMsg struct {
 }
 type openBrowserMsg string
 type openSOPMsg string
+type openPrometheusMsg string
 
 func openBrowserCmd(browser []string, url string) tea.Cmd {
 	// All process work happens inside the returned command so the Update
@@ -905,6 +906,27 @@ func extractEnvVarPairs(envFlags []string) []string {
 	return pairs
 }
 
+// buildDirectCommand returns l's command for running without a terminal
+// window, and the variables to add to its environment. The PAGERDUTY_*
+// variables reach it the way they reach a login: inside the ssh command
+// for a bastion, as ocm-container -e flags, host-exec env arguments in a
+// container, or the process environment.
+func buildDirectCommand(l launcher.ClusterLauncher, vars map[string]string, envFlags []string) (command, processEnvVars []string) {
+	if l.IsRemote() {
+		return l.BuildRemoteDirectCommand(vars, envFlags), nil
+	}
+	command = l.BuildDirectCommand(vars)
+	switch {
+	case l.LoginCommandContainsOCMContainer():
+		command = launcher.InsertEnvFlagsAfterOCMContainer(command, envFlags)
+	case l.HostExec() != nil:
+		command = l.InsertHostEnvArgs(command, l.HostEnvArgs(envFlags))
+	default:
+		processEnvVars = extractEnvVarPairs(envFlags)
+	}
+	return command, processEnvVars
+}
+
 func login(vars map[string]string, l launcher.ClusterLauncher, incident *pagerduty.Incident, alerts []pagerduty.IncidentAlert, notes []pagerduty.IncidentNote) tea.Cmd {
 	return func() tea.Msg {
 		clusterID := vars["%%CLUSTER_ID%%"]
@@ -924,7 +946,10 @@ func login(vars map[string]string, l launcher.ClusterLauncher, incident *pagerdu
 			// This handles both ocm-container and non-ocm-container flows.
 			loginCmd := l.BuildRawLoginCommand(vars)
 			hasOCM := l.LoginCommandContainsOCMContainer()
-			if hasOCM {
+			if l.IsRemote() {
+				// The variables travel inside the ssh command.
+				loginCmd = l.RemoteLoginCommand(vars, envFlags)
+			} else if hasOCM {
 				loginCmd = launcher.InsertEnvFlagsAfterOCMContainer(loginCmd, envFlags)
 			}
 
@@ -937,7 +962,7 @@ func login(vars map[string]string, l launcher.ClusterLauncher, incident *pagerdu
 			// When ocm-container is present, env vars ride as -e flags in
 			// loginCmd — don't also export them in the wrapper script.
 			var envPairsForScript []string
-			if !hasOCM {
+			if !hasOCM && !l.IsRemote() {
 				envPairsForScript = extractEnvVarPairs(envFlags)
 			}
 
@@ -949,16 +974,21 @@ func login(vars map[string]string, l launcher.ClusterLauncher, incident *pagerdu
 
 			finalCommand = l.BuildLoginCommandForScript(vars, scriptPath)
 			log.Debug("tui.login(): AppleScript wrapper flow", "scriptPath", scriptPath, "finalCommand", finalCommand)
+		} else if l.IsRemote() {
+			// SSH bastion: the terminal connects to the bastion, and the
+			// variables travel quoted inside the remote command.
+			finalCommand = l.BuildRemoteLoginCommand(vars, envFlags)
+			log.Debug("tui.login(): ssh bastion flow", "finalCommand", finalCommand)
 		} else if l.LoginCommandContainsOCMContainer() {
 			// Non-AppleScript ocm-container: inject -e flags before
 			// profile wrapping so argv boundaries are preserved.
 			finalCommand = l.BuildLoginCommandWithEnv(vars, envFlags)
 			log.Debug("tui.login(): ocm-container flow", "finalCommand", finalCommand)
-		} else if l.IsToolbox() {
+		} else if l.HostExec() != nil {
 			command := l.BuildLoginCommand(vars)
-			toolboxFlags := l.ToolboxEnvFlags(envFlags)
-			finalCommand = launcher.InsertToolboxEnvFlags(command, toolboxFlags)
-			log.Debug("tui.login(): toolbox non-ocm-container flow", "toolboxFlags", toolboxFlags, "finalCommand", finalCommand)
+			hostArgs := l.HostEnvArgs(envFlags)
+			finalCommand = l.InsertHostEnvArgs(command, hostArgs)
+			log.Debug("tui.login(): container non-ocm-container flow", "hostExec", l.HostExec().Name(), "hostArgs", hostArgs, "finalCommand", finalCommand)
 		} else {
 			finalCommand = l.BuildLoginCommand(vars)
 			processEnvVars = extractEnvVarPairs(envFlags)
@@ -979,14 +1009,18 @@ func login(vars map[string]string, l launcher.ClusterLauncher, incident *pagerdu
 		log.Debug("tui.login(): final command", "finalCommand", finalCommand)
 		log.Debug("tui.login()", "command", c.String())
 
-		startCmdErr := c.Start()
+		var incidentID string
+		if incident != nil {
+			incidentID = incident.ID
+		}
+		wait, startCmdErr := l.Start(c, incidentID, clusterID)
 		if startCmdErr != nil {
 			log.Error("tui.login()", "error", startCmdErr)
 			return loginFinishedMsg{err: startCmdErr}
 		}
 
 		waitCmd := func() tea.Msg {
-			err := c.Wait()
+			err := wait()
 			stderr := strings.TrimSpace(stderrBuf.String())
 			if err != nil {
 				log.Debug("tui.login(): terminal process exited", "error", err, "stderr", stderr)
@@ -1225,16 +1259,34 @@ func getSOPLink(alerts []pagerduty.IncidentAlert) (string, bool) {
 	return "", false
 }
 
+// getPrometheusLink returns the first firing alert's generatorURL, the
+// Prometheus graph for the expression that fired.
+func getPrometheusLink(alerts []pagerduty.IncidentAlert) (string, bool) {
+	for _, a := range alerts {
+		normalized := alert.NormalizeAlert(a.Service.Summary, "", a)
+		for _, f := range normalized.FiringAlerts {
+			if link := prometheusURL(f.GeneratorURL); link != "" {
+				return link, true
+			}
+		}
+	}
+	return "", false
+}
+
 // getUniqueClusters extracts deduplicated cluster_ids from alerts, preserving
-// the order of first appearance. Alerts without a cluster_id are skipped, as are
-// values that are not well-formed cluster IDs (ocm.ValidClusterID). Cluster IDs
-// originate from attacker-influenceable PagerDuty alert data and are later
-// substituted into launched commands, so rejecting malformed values here prevents
+// the order of first appearance. Resolved alerts and alerts without a
+// cluster_id are skipped, as are values that are not well-formed cluster
+// IDs (ocm.ValidClusterID). Cluster IDs originate from
+// attacker-influenceable PagerDuty alert data and are later substituted
+// into launched commands, so rejecting malformed values here prevents
 // argument injection at the launcher boundary.
 func getUniqueClusters(alerts []pagerduty.IncidentAlert) []string {
 	seen := make(map[string]bool)
 	var clusters []string
 	for _, a := range alerts {
+		if a.Status == "resolved" {
+			continue
+		}
 		cluster := getDetailFieldFromAlert("cluster_id", a)
 		if cluster == "" {
 			normalized := alert.NormalizeAlert(a.Service.Summary, "", a)
@@ -1548,6 +1600,9 @@ func prepareConfigWizardCmd(m model) tea.Cmd {
 		existing.Editor = viperConfiguredString("editor")
 		existing.ClusterLoginCommand = viperConfiguredString("cluster_login_command")
 		existing.AgentCLICommand = viper.GetString("agent_cli_command")
+		if actions, err := pkgconfig.ParseCustomActions(viper.Get("custom_actions")); err == nil {
+			existing.CustomActions = actions
+		}
 
 		var presetApplied pkgconfig.PresetApplied
 		if ref := viper.GetString("config_preset"); ref != "" {
@@ -1733,7 +1788,7 @@ func (m model) connectOCMCmdIfNeeded() tea.Cmd {
 			if err != nil {
 				return nil, err
 			}
-			return client, nil
+			return WrapOCMClient(client), nil
 		}
 	}
 	return func() tea.Msg {
@@ -3260,3 +3260,11 @@ func TestTranslateLoginStderr(t *testing.T) {
 		})
 	}
 }
+
+func TestGetUniqueClusters_SkipsResolvedAlerts(t *testing.T) {
+	resolved := hiveAlert("X", "c1", "")
+	resolved.Status = "resolved"
+	alerts := []pagerduty.IncidentAlert{resolved, hiveAlert("X", "c2", "")}
+
+	assert.Equal(t, []string{"c2"}, getUniqueClusters(alerts))
+}
@@ -0,0 +1,282 @@
+package tui
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/PagerDuty/go-pagerduty"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+	"github.com/clcollins/srepd/pkg/alert"
+	pkgconfig "github.com/clcollins/srepd/pkg/config"
+	"github.com/clcollins/srepd/pkg/launcher"
+	"github.com/spf13/viper"
+)
+
+// customActionTimeout bounds a capture action, whose output streams into
+// the Output tab. Background actions may run as long as they need.
+const customActionTimeout = 2 * time.Minute
+
+// customActionMsg runs a custom action on the selected incident. cluster
+// is the cluster given to `:action <name> <cluster>`, if any.
+type customActionMsg struct {
+	action  pkgconfig.CustomAction
+	cluster string
+}
+
+// customActionFinishedMsg reports the exit of a background action.
+type customActionFinishedMsg struct {
+	action     pkgconfig.CustomAction
+	incidentID string
+	clusterID  string
+	output     string
+	err        error
+}
+
+// resolveCustomActions reads the custom actions. An invalid list disables
+// them all; a key taken by a built-in chord is dropped, leaving the action
+// to :action.
+func resolveCustomActions() []pkgconfig.CustomAction {
+	actions, err := pkgconfig.ParseCustomActions(viper.Get("custom_actions"))
+	if err != nil {
+		log.Warn("Custom actions disabled", "error", err)
+		return nil
+	}
+	for i := range actions {
+		if actions[i].Key != "" && resolveChord(actions[i].Key) != nil {
+			log.Warn("Custom action key is a built-in chord; run it with :action", "action", actions[i].Name, "key", actions[i].Key)
+			actions[i].Key = ""
+		}
+	}
+	if len(actions) > 0 {
+		log.Info("Custom actions loaded", "actions", len(actions))
+	}
+	return actions
+}
+
+func isActionCommand(input string) bool {
+	trimmed := strings.TrimSpace(input)
+	return trimmed == ":action" || strings.HasPrefix(trimmed, ":action ")
+}
+
+// dispatchActionCommand lists the custom actions (`:action`) or runs one
+// on the selected incident (`:action <name> [cluster]`).
+func (m *model) dispatchActionCommand(input string) tea.Cmd {
+	parts := strings.Fields(input)
+	if len(parts) == 1 {
+		return m.showCustomActions()
+	}
+	if len(parts) > 3 {
+		return m.flashNotification("usage: :action [<name> [cluster]]")
+	}
+	i := slices.IndexFunc(m.customActions, func(a pkgconfig.CustomAction) bool { return a.Name == parts[1] })
+	if i < 0 {
+		return m.flashNotification(fmt.Sprintf("unknown action %q — :action lists them", parts[1]))
+	}
+	if m.selectedIncident == nil {
+		return m.flashNotification("no incident selected")
+	}
+	msg := customActionMsg{action: m.customActions[i]}
+	if len(parts) == 3 {
+		msg.cluster = parts[2]
+	}
+	return func() tea.Msg { return msg }
+}
+
+// showCustomActions lists the configured actions in the incident viewer.
+func (m *model) showCustomActions() tea.Cmd {
+	if len(m.customActions) == 0 {
+		return m.flashNotification("no custom_actions configured — see docs/custom-actions.md")
+	}
+	content := formatCustomActions(m.customActions, m.chordPrefix)
+	rendered, err := renderIncidentMarkdown(m, content)
+	if err != nil {
+		rendered = content
+	}
+	m.incidentViewer.SetContent(rendered)
+	m.incidentViewer.GotoTop()
+	m.viewingIncident = true
+	m.table.Blur()
+	return nil
+}
+
+func formatCustomActions(actions []pkgconfig.CustomAction, chordPrefix string) string {
+	var b strings.Builder
+	b.WriteString("# Custom Actions\n\n")
+	b.WriteString("Run one on the selected incident with `:action <name> [cluster]`")
+	if chordPrefix != "" {
+		fmt.Fprintf(&b, ", or with `%s` and its key", chordPrefix)
+	}
+	b.WriteString(".\n\n| Name | Mode | Key | Command |\n|------|------|-----|---------|\n")
+	for _, a := range actions {
+		key := "-"
+		if a.Key != "" {
+			key = a.Key
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | `%s` |\n", a.Name, a.Mode, key, a.Command)
+	}
+	return b.String()
+}
+
+// customActionForKey returns the action bound to a chord key.
+func (m model) customActionForKey(key string) (pkgconfig.CustomAction, bool) {
+	i := slices.IndexFunc(m.customActions, func(a pkgconfig.CustomAction) bool { return a.Key == key })
+	if i < 0 {
+		return pkgconfig.CustomAction{}, false
+	}
+	return m.customActions[i], true
+}
+
+// chordCustomAction runs a custom action bound to a chord key on the
+// viewed or highlighted incident.
+func chordCustomAction(m model, a pkgconfig.CustomAction) (tea.Model, tea.Cmd) {
+	msg := customActionMsg{action: a}
+	if m.viewingIncident {
+		if m.selectedIncident == nil {
+			m.setStatus("no incident selected")
+			return m, nil
+		}
+		return m, func() tea.Msg { return msg }
+	}
+	return m, doIfIncidentSelected(&m, func() tea.Msg {
+		return waitForSelectedIncidentThenDoMsg{
+			action: func() tea.Msg { return msg },
+			msg:    "wait",
+		}
+	})
+}
+
+// customActionVars returns the placeholders of an action: the login
+// variables and the fields of the alert firing on the cluster (or the
+// incident's first alert when no cluster is picked).
+func customActionVars(incident *pagerduty.Incident, alerts []pagerduty.IncidentAlert, clusterID string) map[string]string {
+	var n alert.NormalizedAlert
+	for _, a := range alerts {
+		candidate := alert.NormalizeAlert(a.Service.Summary, incident.Title, a)
+		if clusterID == "" || candidate.ClusterID == clusterID || getDetailFieldFromAlert("cluster_id", a) == clusterID {
+			n = candidate
+			break
+		}
+	}
+	// As with login, every value must be set, even if only to "".
+	return map[string]string{
+		"%%CLUSTER_ID%%":   clusterID,
+		"%%INCIDENT_ID%%":  incident.ID,
+		"%%ALERT_NAME%%":   n.AlertName,
+		"%%ALERT_TYPE%%":   n.AlertType,
+		"%%SEVERITY%%":     n.Severity,
+		"%%SERVICE%%":      incident.Service.Summary,
+		"%%CLUSTER_NAME%%": n.ClusterName,
+		"%%REGION%%":       n.Region,
+		"%%NAMESPACE%%":    n.Namespace,
+	}
+}
+
+// applyCustomAction picks the action's cluster and runs it: in a terminal
+// through login(), like a cluster login, or without one.
+func (m *model) applyCustomAction(msg customActionMsg) tea.Cmd {
+	a := msg.action
+	if m.selectedIncident == nil {
+		m.setStatus(fmt.Sprintf("unable to run %s - no selected incident", a.Name))
+		return nil
+	}
+	if a.NeedsCluster() && m.ocmAuthPending {
+		return m.flashNotification("Action blocked — complete OCM browser auth first")
+	}
+	if a.NeedsCluster() && len(m.selectedIncidentAlerts) == 0 {
+		return requeueAfterDelay(msg)
+	}
+
+	clusters := getUniqueClusters(m.selectedIncidentAlerts)
+	cluster := msg.cluster
+	switch {
+	case cluster != "":
+		if !slices.Contains(clusters, cluster) {
+			return m.flashNotification(fmt.Sprintf("cluster %q is not a cluster of incident %s", cluster, m.selectedIncident.ID))
+		}
+	case len(clusters) == 1:
+		cluster = clusters[0]
+	case !a.NeedsCluster():
+	case len(clusters) == 0:
+		return m.flashNotification(fmt.Sprintf("No cluster_id found in alerts — cannot run %s", a.Name))
+	default:
+		return m.flashNotification(fmt.Sprintf("incident has %d clusters — use :action %s <cluster>", len(clusters), a.Name))
+	}
+
+	vars := customActionVars(m.selectedIncident, m.selectedIncidentAlerts, cluster)
+	l := m.launcher.WithCommand(a.Command)
+	log.Info("custom action started",
+		"user_id", m.config.CurrentUser.ID,
+		"action", a.Name,
+		"mode", a.Mode,
+		"cluster_id", cluster,
+		"incident_id", m.selectedIncident.ID)
+
+	if a.Mode == pkgconfig.CustomActionTerminal {
+		m.setStatus(fmt.Sprintf("running %s", a.Name))
+		return m.startLogin(strings.TrimSpace(a.Name+" "+cluster), vars, l, false)
+	}
+	if a.Mode == pkgconfig.CustomActionCapture {
+		m.setStatus(fmt.Sprintf("running %s", a.Name))
+		return startCommandRun(a.Name, vars, l, customActionTimeout, m.selectedIncident, m.selectedIncidentAlerts, m.selectedIncidentNotes)
+	}
+	m.setStatus(fmt.Sprintf("running %s in the background", a.Name))
+	return runCustomAction(vars, l, a, m.selectedIncident, m.selectedIncidentAlerts, m.selectedIncidentNotes)
+}
+
+// runCustomAction runs a background action without a terminal.
+// The PAGERDUTY_* variables reach it the way they reach a login (see
+// buildDirectCommand).
+func runCustomAction(vars map[string]string, l launcher.ClusterLauncher, a pkgconfig.CustomAction, incident *pagerduty.Incident, alerts []pagerduty.IncidentAlert, notes []pagerduty.IncidentNote) tea.Cmd {
+	return func() tea.Msg {
+		clusterID := vars["%%CLUSTER_ID%%"]
+		envFlags := buildPagerDutyEnvVars(incident, alerts, notes, clusterID)
+
+		command, processEnvVars := buildDirectCommand(l, vars, envFlags)
+
+		c := exec.Command(command[0], command[1:]...)
+		var out bytes.Buffer
+		c.Stdout = &out
+		c.Stderr = &out
+		c.WaitDelay = 5 * time.Second
+		if len(processEnvVars) > 0 {
+			c.Env = append(os.Environ(), processEnvVars...)
+		}
+		log.Debug("tui.runCustomAction()", "action", a.Name, "command", c.String())
+
+		err := c.Run()
+		return customActionFinishedMsg{
+			action:     a,
+			incidentID: incident.ID,
+			clusterID:  clusterID,
+			output:     out.String(),
+			err:        err,
+		}
+	}
+}
+
+// applyCustomActionFinished reports a background action's result.
+func (m *model) applyCustomActionFinished(msg customActionFinishedMsg) tea.Cmd {
+	a := msg.action
+	if msg.err != nil {
+		log.Warn("custom action failed", "action", a.Name, "cluster_id", msg.clusterID, "incident_id", msg.incidentID, "error", msg.err)
+		return m.flashNotification(fmt.Sprintf("%s failed: %s", a.Name, lastLine(msg.output, msg.err)))
+	}
+	log.Info("custom action finished", "action", a.Name, "cluster_id", msg.clusterID, "incident_id", msg.incidentID)
+	return m.flashNotification(fmt.Sprintf("%s finished", a.Name))
+}
+
+// lastLine returns the last line a failed command printed, which usually
+// says why, or the error itself.
+func lastLine(output string, err error) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if last := strings.TrimSpace(lines[len(lines)-1]); last != "" {
+		return last
+	}
+	return err.Error()
+}
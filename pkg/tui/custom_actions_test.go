@@ -0,0 +1,192 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/PagerDuty/go-pagerduty"
+	tea "github.com/charmbracelet/bubbletea"
+	pkgconfig "github.com/clcollins/srepd/pkg/config"
+	"github.com/clcollins/srepd/pkg/launcher"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func actionAlert(clusterID, alertName string) pagerduty.IncidentAlert {
+	return pagerduty.IncidentAlert{
+		Service: pagerduty.APIObject{Summary: "osd-prod-hive"},
+		Body: map[string]interface{}{
+			"details": map[string]interface{}{
+				"cluster_id": clusterID,
+				"alert_name": alertName,
+			},
+		},
+	}
+}
+
+// customActionsTestModel returns a model with an incident on cluster-a
+// whose launcher runs commands directly, without a terminal.
+func customActionsTestModel(t *testing.T, actions ...pkgconfig.CustomAction) model {
+	t.Helper()
+	m := createTestModelWithSelectedIncident()
+	l, err := launcher.NewClusterLauncherWithToolbox("true", "ocm-container --cluster-id %%CLUSTER_ID%%", "false", func() bool { return false })
+	require.NoError(t, err)
+	m.launcher = l
+	m.selectedIncidentAlerts = []pagerduty.IncidentAlert{actionAlert("cluster-a", "ClusterOperatorDown")}
+	m.customActions = actions
+	return m
+}
+
+func TestResolveCustomActions(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+
+	assert.Nil(t, resolveCustomActions())
+
+	viper.Set("custom_actions", []any{
+		map[string]any{"name": "context", "command": "osdctl cluster context %%CLUSTER_ID%%", "key": "c"},
+		map[string]any{"name": "logs", "command": "ls", "key": "d"},
+	})
+	actions := resolveCustomActions()
+	require.Len(t, actions, 2)
+	assert.Equal(t, "c", actions[0].Key)
+	assert.Empty(t, actions[1].Key, "d is the built-in debug log chord")
+
+	viper.Set("custom_actions", []any{map[string]any{"name": "broken"}})
+	assert.Nil(t, resolveCustomActions(), "an invalid list disables custom actions")
+}
+
+func TestCustomActionVars(t *testing.T) {
+	incident := &pagerduty.Incident{
+		APIObject: pagerduty.APIObject{ID: "Q1AAA"},
+		Service:   pagerduty.APIObject{Summary: "osd-prod-hive"},
+	}
+	alerts := []pagerduty.IncidentAlert{actionAlert("cluster-a", "KubeAPIDown"), actionAlert("cluster-b", "ClusterOperatorDown")}
+
+	vars := customActionVars(incident, alerts, "cluster-b")
+	assert.Equal(t, "cluster-b", vars["%%CLUSTER_ID%%"])
+	assert.Equal(t, "Q1AAA", vars["%%INCIDENT_ID%%"])
+	assert.Equal(t, "ClusterOperatorDown", vars["%%ALERT_NAME%%"], "the alert firing on the cluster")
+	assert.Equal(t, "osd-prod-hive", vars["%%SERVICE%%"])
+	assert.Contains(t, vars, "%%NAMESPACE%%", "unknown fields are empty, not missing")
+}
+
+func TestActionCommand_ListsActions(t *testing.T) {
+	m := customActionsTestModel(t)
+	m.dispatchActionCommand(":action")
+	assert.Contains(t, m.status, "no custom_actions configured")
+
+	m = customActionsTestModel(t, pkgconfig.CustomAction{Name: "context", Command: "osdctl cluster context %%CLUSTER_ID%%", Mode: pkgconfig.CustomActionCapture, Key: "c"})
+	m.chordPrefix = "ctrl+x"
+	assert.Nil(t, m.dispatchActionCommand(":action"))
+	assert.True(t, m.viewingIncident)
+	assert.Contains(t, formatCustomActions(m.customActions, m.chordPrefix), "| context | capture | c | `osdctl cluster context %%CLUSTER_ID%%` |")
+
+	m.dispatchActionCommand(":action nope")
+	assert.Contains(t, m.status, `unknown action "nope"`)
+	m.dispatchActionCommand(":action context a b")
+	assert.Contains(t, m.status, "usage: :action")
+}
+
+func TestCustomAction_CaptureShowsOutput(t *testing.T) {
+	m := customActionsTestModel(t, pkgconfig.CustomAction{Name: "names", Command: "echo %%CLUSTER_ID%% %%ALERT_NAME%%", Mode: pkgconfig.CustomActionCapture})
+
+	cmd := m.dispatchActionCommand(":action names")
+	require.NotNil(t, cmd)
+	msg := cmd().(customActionMsg)
+	cmd = m.applyCustomAction(msg)
+	require.NotNil(t, cmd)
+	started, ok := cmd().(commandRunStartedMsg)
+	require.True(t, ok)
+	require.NoError(t, started.err)
+	<-started.run.capture.Done()
+
+	m.applyCommandRunStarted(started)
+	assert.Equal(t, tabOutput, m.activeTab)
+	output, _ := started.run.capture.Output()
+	assert.Equal(t, "cluster-a ClusterOperatorDown\n", output)
+}
+
+// Background and capture actions get the PAGERDUTY_* context like a
+// cluster login does.
+func TestCustomAction_PassesPagerDutyEnv(t *testing.T) {
+	m := customActionsTestModel(t, pkgconfig.CustomAction{Name: "env", Command: "printenv PAGERDUTY_CLUSTER_ID PAGERDUTY_INCIDENT_ID", Mode: pkgconfig.CustomActionBackground})
+
+	finished := m.applyCustomAction(customActionMsg{action: m.customActions[0]})().(customActionFinishedMsg)
+	require.NoError(t, finished.err)
+	assert.Equal(t, "cluster-a\nP1234567\n", finished.output)
+}
+
+func TestCustomAction_BackgroundReportsResult(t *testing.T) {
+	m := customActionsTestModel(t, pkgconfig.CustomAction{Name: "fails", Command: "false", Mode: pkgconfig.CustomActionBackground})
+
+	finished := m.applyCustomAction(customActionMsg{action: m.customActions[0]})().(customActionFinishedMsg)
+	require.Error(t, finished.err)
+	m.applyCustomActionFinished(finished)
+	assert.Equal(t, "fails failed: exit status 1", m.status)
+	assert.False(t, m.viewingIncident, "background output is not shown")
+}
+
+func TestCustomAction_TerminalRunsThroughLogin(t *testing.T) {
+	m := customActionsTestModel(t, pkgconfig.CustomAction{Name: "shell", Command: "ocm-container --cluster-id %%CLUSTER_ID%% --no-banner", Mode: pkgconfig.CustomActionTerminal})
+	m.sessions = launcher.NewSessionRegistry()
+	m.launcher.RecordSessions(m.sessions)
+
+	finished, ok := m.applyCustomAction(customActionMsg{action: m.customActions[0]})().(loginFinishedMsg)
+	require.True(t, ok)
+	require.NoError(t, finished.err)
+	got := m.sessions.List()
+	require.Len(t, got, 1)
+	assert.Equal(t, "cluster-a", got[0].ClusterID)
+	assert.Contains(t, got[0].Command, "--no-banner")
+	finished.waitCmd()
+}
+
+func TestCustomAction_PicksCluster(t *testing.T) {
+	a := pkgconfig.CustomAction{Name: "context", Command: "echo %%CLUSTER_ID%%", Mode: pkgconfig.CustomActionBackground}
+	m := customActionsTestModel(t, a)
+	m.selectedIncidentAlerts = append(m.selectedIncidentAlerts, actionAlert("cluster-b", "KubeAPIDown"))
+
+	assert.NotNil(t, m.applyCustomAction(customActionMsg{action: a}))
+	assert.Equal(t, "incident has 2 clusters — use :action context <cluster>", m.status)
+
+	m.applyCustomAction(customActionMsg{action: a, cluster: "cluster-z"})
+	assert.Contains(t, m.status, `cluster "cluster-z" is not a cluster of incident`)
+
+	finished := m.applyCustomAction(customActionMsg{action: a, cluster: "cluster-b"})().(customActionFinishedMsg)
+	assert.Equal(t, "cluster-b\n", finished.output)
+
+	// An action that does not refer to a cluster runs on any incident.
+	noCluster := pkgconfig.CustomAction{Name: "incident", Command: "echo %%INCIDENT_ID%%", Mode: pkgconfig.CustomActionBackground}
+	finished = m.applyCustomAction(customActionMsg{action: noCluster})().(customActionFinishedMsg)
+	assert.Equal(t, "P1234567\n", finished.output)
+}
+
+func TestCustomAction_ChordKey(t *testing.T) {
+	m := customActionsTestModel(t, pkgconfig.CustomAction{Name: "context", Command: "echo %%CLUSTER_ID%%", Mode: pkgconfig.CustomActionCapture, Key: "c"})
+	m.chordPrefix = "ctrl+x"
+	m.chordPending = true
+	m.viewingIncident = true
+
+	_, cmd := m.keyMsgHandler(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	require.NotNil(t, cmd)
+	assert.Equal(t, "context", cmd().(customActionMsg).action.Name)
+
+	help := chordKeymap{prefix: "ctrl+x", actions: m.customActions}.FullHelp()
+	last := help[0][len(help[0])-1].Help()
+	assert.Equal(t, "ctrl+x c", last.Key)
+	assert.Equal(t, "context", last.Desc)
+}
+
+// On a bastion the PAGERDUTY_* variables travel inside the ssh command
+// rather than in srepd's environment.
+func TestBuildDirectCommand_Bastion(t *testing.T) {
+	m := customActionsTestModel(t)
+	l := m.launcher.WithCommand("osdctl cluster context %%CLUSTER_ID%%")
+	l.UseBastion("sre@bastion")
+	vars := customActionVars(m.selectedIncident, m.selectedIncidentAlerts, "cluster-a")
+
+	command, processEnvVars := buildDirectCommand(l, vars, []string{"-e", "PAGERDUTY_CLUSTER_ID=cluster-a"})
+	assert.Nil(t, processEnvVars)
+	assert.Equal(t, []string{"ssh", "-t", "sre@bastion", "exec env PAGERDUTY_CLUSTER_ID='cluster-a' 'osdctl' 'cluster' 'context' 'cluster-a'"}, command)
+}
@@ -0,0 +1,228 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/PagerDuty/go-pagerduty"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/clcollins/srepd/pkg/alert"
+)
+
+const (
+	emojiDuplicateMarker   = "👥 "
+	noEmojiDuplicateMarker = "≡ "
+)
+
+// alertFingerprint identifies "the same alert" across incidents. PagerDuty
+// opens separate incidents for repeat firings of one AlertName on one
+// cluster; those share a fingerprint.
+type alertFingerprint struct {
+	AlertType string
+	AlertName string
+	ClusterID string
+	Namespace string
+}
+
+// fingerprintAlerts returns the distinct fingerprints of an incident's
+// alerts. Alerts without a name or cluster are skipped: "Unknown alert on
+// no cluster" would group unrelated incidents together.
+func fingerprintAlerts(alerts []pagerduty.IncidentAlert) []alertFingerprint {
+	var fps []alertFingerprint
+	for _, a := range alerts {
+		n := alert.NormalizeAlert(a.Service.Summary, "", a)
+		if n.AlertName == "" || n.ClusterID == "" {
+			continue
+		}
+		fp := alertFingerprint{
+			AlertType: n.AlertType,
+			AlertName: n.AlertName,
+			ClusterID: n.ClusterID,
+			Namespace: n.Namespace,
+		}
+		if !slices.Contains(fps, fp) {
+			fps = append(fps, fp)
+		}
+	}
+	return fps
+}
+
+// incidentCreatedBefore orders incidents oldest first, falling back to the
+// ID so the order (and so the merge target) is stable.
+func incidentCreatedBefore(a, b pagerduty.Incident) int {
+	ta, errA := time.Parse(time.RFC3339, a.CreatedAt)
+	tb, errB := time.Parse(time.RFC3339, b.CreatedAt)
+	if errA == nil && errB == nil {
+		if c := ta.Compare(tb); c != 0 {
+			return c
+		}
+	} else if c := strings.Compare(a.CreatedAt, b.CreatedAt); c != 0 {
+		return c
+	}
+	return strings.Compare(a.ID, b.ID)
+}
+
+// findDuplicateGroups groups incidents that share at least one alert
+// fingerprint (transitively). Only incidents with loaded alerts take part.
+// The result maps each grouped incident ID to its group's IDs, oldest
+// first; incidents without duplicates are absent.
+func findDuplicateGroups(incidents []pagerduty.Incident, cache map[string]*cachedIncidentData) map[string][]string {
+	byID := make(map[string]pagerduty.Incident, len(incidents))
+	parent := make(map[string]string)
+	var find func(id string) string
+	find = func(id string) string {
+		if parent[id] != id {
+			parent[id] = find(parent[id])
+		}
+		return parent[id]
+	}
+
+	owner := make(map[alertFingerprint]string)
+	for _, inc := range incidents {
+		cached, ok := cache[inc.ID]
+		if !ok || cached == nil || !cached.alertsLoaded {
+			continue
+		}
+		byID[inc.ID] = inc
+		parent[inc.ID] = inc.ID
+		for _, fp := range fingerprintAlerts(cached.alerts) {
+			if other, seen := owner[fp]; seen {
+				parent[find(inc.ID)] = find(other)
+				continue
+			}
+			owner[fp] = inc.ID
+		}
+	}
+
+	members := make(map[string][]pagerduty.Incident)
+	for id := range parent {
+		root := find(id)
+		members[root] = append(members[root], byID[id])
+	}
+
+	groups := make(map[string][]string)
+	for _, group := range members {
+		if len(group) < 2 {
+			continue
+		}
+		slices.SortFunc(group, incidentCreatedBefore)
+		ids := make([]string, len(group))
+		for i, inc := range group {
+			ids[i] = inc.ID
+		}
+		for _, id := range ids {
+			groups[id] = ids
+		}
+	}
+	return groups
+}
+
+// rebuildDuplicateGroups recomputes duplicateGroups from the incident cache.
+// It reports whether the grouping changed, so callers know to redraw rows.
+func (m *model) rebuildDuplicateGroups() bool {
+	groups := findDuplicateGroups(m.incidentList, m.incidentCache)
+	if len(groups) == 0 {
+		groups = nil
+	}
+	changed := len(groups) != len(m.duplicateGroups)
+	if !changed {
+		for id, ids := range groups {
+			if !slices.Equal(ids, m.duplicateGroups[id]) {
+				changed = true
+				break
+			}
+		}
+	}
+	m.duplicateGroups = groups
+	return changed
+}
+
+// renderDuplicatesSection lists the selected incident's duplicates for the
+// Details tab.
+func (m model) renderDuplicatesSection() string {
+	if m.selectedIncident == nil {
+		return ""
+	}
+	group, ok := m.duplicateGroups[m.selectedIncident.ID]
+	if !ok {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\n## Possible Duplicates\n\n")
+	for i, id := range group {
+		switch {
+		case id == m.selectedIncident.ID && i == 0:
+			fmt.Fprintf(&b, "* %s (this incident, oldest)\n", id)
+		case id == m.selectedIncident.ID:
+			fmt.Fprintf(&b, "* %s (this incident)\n", id)
+		case i == 0:
+			fmt.Fprintf(&b, "* %s (oldest)\n", id)
+		default:
+			fmt.Fprintf(&b, "* %s\n", id)
+		}
+	}
+	b.WriteString("\nPress `M` in the incident list to merge duplicates into the oldest.\n")
+	return b.String()
+}
+
+// duplicateMergePair picks the merge for a highlighted incident in a
+// duplicate group: the oldest incident is always the target, and the source
+// is the highlighted incident — or, when that is the oldest, the newest
+// duplicate. Repeating the action drains the group one incident at a time.
+func duplicateMergePair(group []string, highlightedID string) (sourceID, targetID string) {
+	targetID = group[0]
+	sourceID = highlightedID
+	if sourceID == targetID {
+		sourceID = group[len(group)-1]
+	}
+	return sourceID, targetID
+}
+
+// startDuplicateMerge pre-fills the merge flow for the highlighted
+// incident's duplicate group and asks for confirmation.
+func (m model) startDuplicateMerge() (tea.Model, tea.Cmd) {
+	if m.table.SelectedRow() == nil {
+		m.setStatus("no incident highlighted")
+		return m, nil
+	}
+	m.syncSelectedIncidentToHighlightedRow()
+	if m.selectedIncident == nil {
+		m.setStatus("no incident selected")
+		return m, nil
+	}
+	group, ok := m.duplicateGroups[m.selectedIncident.ID]
+	if !ok {
+		m.setStatus(fmt.Sprintf("no duplicates found for %s", m.selectedIncident.ID))
+		return m, nil
+	}
+
+	sourceID, targetID := duplicateMergePair(group, m.selectedIncident.ID)
+	idx := slices.IndexFunc(m.incidentList, func(i pagerduty.Incident) bool { return i.ID == sourceID })
+	if idx < 0 {
+		m.setStatus(fmt.Sprintf("duplicate %s is no longer in the list", sourceID))
+		return m, nil
+	}
+	source := m.incidentList[idx]
+
+	// Team mode so the target is listed even when it is assigned to
+	// someone else; the cursor lands on it as a visual cue.
+	m.mergeMode = true
+	m.mergeSourceIncident = &source
+	m.mergeTeamMode = true
+	m.mergeTable = newTableWithStyles()
+	m.rebuildMergeTable()
+	if i := findRowIndex(m.mergeTable.Rows(), targetID); i >= 0 {
+		m.mergeTable.SetCursor(i)
+	}
+	m.mergeTargetID = targetID
+	m.pendingConfirmation = &confirmActionState{
+		prompt: fmt.Sprintf("Merge duplicate %s into oldest %s? [y/n]", sourceID, targetID),
+		action: func() tea.Msg {
+			return mergeIncidentMsg{}
+		},
+	}
+	return m, nil
+}
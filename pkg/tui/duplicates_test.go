@@ -0,0 +1,277 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/PagerDuty/go-pagerduty"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/clcollins/srepd/pkg/pd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func hiveAlert(alertName, clusterID, namespace string) pagerduty.IncidentAlert {
+	details := map[string]interface{}{
+		"alert_name": alertName,
+		"cluster_id": clusterID,
+	}
+	if namespace != "" {
+		details["firing"] = "Labels:\n - namespace = " + namespace + "\n"
+	}
+	return pagerduty.IncidentAlert{
+		Service: pagerduty.APIObject{Summary: "osd-test-hive-cluster"},
+		Body:    map[string]interface{}{"details": details},
+	}
+}
+
+func dupIncident(id, createdAt string) pagerduty.Incident {
+	return pagerduty.Incident{
+		APIObject: pagerduty.APIObject{ID: id},
+		Title:     "Title " + id,
+		CreatedAt: createdAt,
+		Service:   pagerduty.APIObject{Summary: "svc"},
+	}
+}
+
+func alertsCache(byID map[string][]pagerduty.IncidentAlert) map[string]*cachedIncidentData {
+	cache := make(map[string]*cachedIncidentData)
+	for id, alerts := range byID {
+		cache[id] = &cachedIncidentData{alerts: alerts, alertsLoaded: true}
+	}
+	return cache
+}
+
+func TestFingerprintAlerts(t *testing.T) {
+	fps := fingerprintAlerts([]pagerduty.IncidentAlert{
+		hiveAlert("KubeNodeNotReady", "c1", "openshift-monitoring"),
+		hiveAlert("KubeNodeNotReady", "c1", "openshift-monitoring"),
+		hiveAlert("KubeNodeNotReady", "c2", ""),
+		hiveAlert("", "c3", ""),
+		hiveAlert("NoCluster", "", ""),
+	})
+
+	require.Len(t, fps, 2, "duplicates collapse; alerts missing a name or cluster are skipped")
+	assert.Equal(t, alertFingerprint{"osd_hive", "KubeNodeNotReady", "c1", "openshift-monitoring"}, fps[0])
+	assert.Equal(t, alertFingerprint{"osd_hive", "KubeNodeNotReady", "c2", ""}, fps[1])
+}
+
+func TestFindDuplicateGroups(t *testing.T) {
+	incidents := []pagerduty.Incident{
+		dupIncident("P3", "2026-05-03T00:00:00Z"),
+		dupIncident("P1", "2026-05-01T00:00:00Z"),
+		dupIncident("P2", "2026-05-02T00:00:00Z"),
+		dupIncident("P4", "2026-05-04T00:00:00Z"),
+		dupIncident("P5", "2026-05-05T00:00:00Z"),
+	}
+	cache := alertsCache(map[string][]pagerduty.IncidentAlert{
+		"P1": {hiveAlert("ClusterOperatorDown", "c1", "")},
+		"P2": {hiveAlert("ClusterOperatorDown", "c1", "")},
+		"P3": {hiveAlert("ClusterOperatorDown", "c1", "")},
+		"P4": {hiveAlert("ClusterOperatorDown", "c2", "")},
+		// P5 has no loaded alerts
+	})
+
+	groups := findDuplicateGroups(incidents, cache)
+
+	assert.Equal(t, []string{"P1", "P2", "P3"}, groups["P1"], "oldest first")
+	assert.Equal(t, groups["P1"], groups["P3"], "every member maps to the same group")
+	assert.NotContains(t, groups, "P4", "different cluster is not a duplicate")
+	assert.NotContains(t, groups, "P5", "incidents without loaded alerts are skipped")
+}
+
+func TestFindDuplicateGroups_Transitive(t *testing.T) {
+	incidents := []pagerduty.Incident{
+		dupIncident("A", "2026-05-01T00:00:00Z"),
+		dupIncident("B", "2026-05-02T00:00:00Z"),
+		dupIncident("C", "2026-05-03T00:00:00Z"),
+	}
+	cache := alertsCache(map[string][]pagerduty.IncidentAlert{
+		"A": {hiveAlert("X", "c1", "")},
+		"B": {hiveAlert("X", "c1", ""), hiveAlert("Y", "c1", "")},
+		"C": {hiveAlert("Y", "c1", "")},
+	})
+
+	groups := findDuplicateGroups(incidents, cache)
+	assert.Equal(t, []string{"A", "B", "C"}, groups["C"])
+}
+
+func TestFindDuplicateGroups_NamespaceDistinguishes(t *testing.T) {
+	incidents := []pagerduty.Incident{
+		dupIncident("A", "2026-05-01T00:00:00Z"),
+		dupIncident("B", "2026-05-02T00:00:00Z"),
+	}
+	cache := alertsCache(map[string][]pagerduty.IncidentAlert{
+		"A": {hiveAlert("PodCrashLooping", "c1", "ns-a")},
+		"B": {hiveAlert("PodCrashLooping", "c1", "ns-b")},
+	})
+
+	assert.Empty(t, findDuplicateGroups(incidents, cache))
+}
+
+func TestIncidentCreatedBefore_TieBreaksOnID(t *testing.T) {
+	a := dupIncident("PA", "2026-05-01T00:00:00Z")
+	b := dupIncident("PB", "2026-05-01T00:00:00Z")
+	assert.Negative(t, incidentCreatedBefore(a, b))
+	assert.Positive(t, incidentCreatedBefore(b, a))
+
+	// Different offsets, same instant
+	c := dupIncident("PC", "2026-05-01T02:00:00+02:00")
+	assert.Negative(t, incidentCreatedBefore(a, c), "equal instants fall back to ID")
+}
+
+func TestDuplicateMergePair(t *testing.T) {
+	group := []string{"OLD", "MID", "NEW"}
+
+	src, tgt := duplicateMergePair(group, "MID")
+	assert.Equal(t, "MID", src)
+	assert.Equal(t, "OLD", tgt)
+
+	src, tgt = duplicateMergePair(group, "OLD")
+	assert.Equal(t, "NEW", src, "highlighting the oldest merges the newest into it")
+	assert.Equal(t, "OLD", tgt)
+}
+
+func TestRebuildDuplicateGroups_ReportsChange(t *testing.T) {
+	m := createTestModel()
+	m.incidentList = []pagerduty.Incident{
+		dupIncident("A", "2026-05-01T00:00:00Z"),
+		dupIncident("B", "2026-05-02T00:00:00Z"),
+	}
+	m.incidentCache = alertsCache(map[string][]pagerduty.IncidentAlert{
+		"A": {hiveAlert("X", "c1", "")},
+	})
+
+	assert.False(t, m.rebuildDuplicateGroups(), "no groups before and after")
+
+	m.incidentCache["B"] = &cachedIncidentData{alerts: []pagerduty.IncidentAlert{hiveAlert("X", "c1", "")}, alertsLoaded: true}
+	assert.True(t, m.rebuildDuplicateGroups(), "new group is a change")
+	assert.False(t, m.rebuildDuplicateGroups(), "same grouping is not a change")
+}
+
+func dupTestModel(t *testing.T) model {
+	t.Helper()
+	m := createTestModel()
+	m.config = &pd.Config{
+		Client: &pd.MockPagerDutyClient{},
+		CurrentUser: &pagerduty.User{
+			APIObject: pagerduty.APIObject{ID: "USER1"},
+			Email:     "test@example.com",
+		},
+	}
+	m.incidentList = []pagerduty.Incident{
+		dupIncident("OLD", "2026-05-01T00:00:00Z"),
+		dupIncident("NEW", "2026-05-02T00:00:00Z"),
+		dupIncident("OTHER", "2026-05-03T00:00:00Z"),
+	}
+	m.incidentCache = alertsCache(map[string][]pagerduty.IncidentAlert{
+		"OLD":   {hiveAlert("X", "c1", "")},
+		"NEW":   {hiveAlert("X", "c1", "")},
+		"OTHER": {hiveAlert("Y", "c2", "")},
+	})
+	cols := []table.Column{
+		{Title: "", Width: 1},
+		{Title: "ID", Width: 16},
+		{Title: "Title", Width: 40},
+		{Title: "Service", Width: 30},
+	}
+	rows := []table.Row{
+		{"•", "OLD", "Title OLD", "svc"},
+		{"•", "NEW", "Title NEW", "svc"},
+		{"•", "OTHER", "Title OTHER", "svc"},
+	}
+	m.table = table.New(table.WithColumns(cols), table.WithRows(rows), table.WithFocused(true))
+	m.rebuildDuplicateGroups()
+	return m
+}
+
+func TestMergeDuplicatesKey_PrefillsMerge(t *testing.T) {
+	m := dupTestModel(t)
+	m.table.SetCursor(1) // NEW
+
+	result, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'M'}})
+	updated, ok := result.(model)
+	require.True(t, ok)
+
+	assert.True(t, updated.mergeMode)
+	require.NotNil(t, updated.mergeSourceIncident)
+	assert.Equal(t, "NEW", updated.mergeSourceIncident.ID)
+	assert.Equal(t, "OLD", updated.mergeTargetID, "oldest incident is the target")
+	require.NotNil(t, updated.pendingConfirmation)
+	assert.Contains(t, updated.pendingConfirmation.prompt, "Merge duplicate NEW into oldest OLD")
+	assert.Equal(t, "OLD", updated.mergeTable.SelectedRow()[1], "cursor lands on the target")
+
+	// Confirming runs the existing merge flow
+	_, cmd := updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	require.NotNil(t, cmd)
+	msg := cmd()
+	_, isMerge := msg.(mergeIncidentMsg)
+	assert.True(t, isMerge, "confirmation dispatches mergeIncidentMsg")
+}
+
+func TestMergeDuplicatesKey_NoDuplicates(t *testing.T) {
+	m := dupTestModel(t)
+	m.table.SetCursor(2) // OTHER
+
+	result, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'M'}})
+	updated, ok := result.(model)
+	require.True(t, ok)
+
+	assert.False(t, updated.mergeMode)
+	assert.Nil(t, updated.pendingConfirmation)
+	assert.Contains(t, updated.status, "no duplicates found for OTHER")
+}
+
+func TestUpdatedIncidentList_MarksDuplicates(t *testing.T) {
+	m := dupTestModel(t)
+	m.teamMode = true
+	m.showLowUrgency = true
+
+	result, _ := m.Update(updatedIncidentListMsg{incidents: m.incidentList})
+	updated, ok := result.(model)
+	require.True(t, ok)
+
+	byID := make(map[string]string)
+	for _, r := range updated.table.Rows() {
+		byID[r[1]] = r[2]
+	}
+	assert.Equal(t, updated.duplicateMarker+"Title OLD", byID["OLD"])
+	assert.Equal(t, updated.duplicateMarker+"Title NEW", byID["NEW"])
+	assert.Equal(t, "Title OTHER", byID["OTHER"])
+}
+
+func TestRenderDuplicatesSection(t *testing.T) {
+	m := dupTestModel(t)
+	m.selectedIncident = &m.incidentList[0]
+
+	out := m.renderDuplicatesSection()
+	assert.Contains(t, out, "Possible Duplicates")
+	assert.Contains(t, out, "OLD (this incident, oldest)")
+	assert.Contains(t, out, "* NEW")
+
+	m.selectedIncident = &m.incidentList[2]
+	assert.Empty(t, m.renderDuplicatesSection())
+}
+
+func TestGotIncidentAlerts_RedrawsOnNewDuplicate(t *testing.T) {
+	m := dupTestModel(t)
+	m.selectedIncident = &m.incidentList[2]
+	m.incidentCache["OTHER"] = &cachedIncidentData{}
+	m.priorAlertCache = make(map[string]*PriorAlertData)
+	m.priorAlertPending = make(map[string]int)
+	m.rebuildDuplicateGroups()
+
+	// OTHER's alerts arrive and match NEW/OLD
+	result, cmd := m.Update(gotIncidentAlertsMsg{incidentID: "OTHER", alerts: []pagerduty.IncidentAlert{hiveAlert("X", "c1", "")}})
+	updated, ok := result.(model)
+	require.True(t, ok)
+	assert.Equal(t, []string{"OLD", "NEW", "OTHER"}, updated.duplicateGroups["OTHER"])
+
+	redraw := false
+	drainCmd(t, cmd, func(msg tea.Msg) {
+		if _, ok := msg.(updatedIncidentListMsg); ok {
+			redraw = true
+		}
+	})
+	assert.True(t, redraw, "group change triggers a table redraw")
+}
@@ -0,0 +1,406 @@
+package tui
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/PagerDuty/go-pagerduty"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+	"github.com/clcollins/srepd/pkg/pd"
+)
+
+// FlagActionKind is something a flag does to the incidents it matches,
+// beyond the marker.
+type FlagActionKind string
+
+const (
+	// FlagActionNotify raises a desktop notification.
+	FlagActionNotify FlagActionKind = "notify"
+	// FlagActionPin keeps matching incidents at the top of the table.
+	FlagActionPin FlagActionKind = "pin"
+	// FlagActionTag prepends tags to the incident title, after approval.
+	FlagActionTag FlagActionKind = "tag"
+	// FlagActionNote posts a templated note, after approval.
+	FlagActionNote FlagActionKind = "note"
+)
+
+// FlagAction is one action of a FlagCondition. Value holds the tags of a
+// tag action ("[VIP]") and the text/template of a note action.
+type FlagAction struct {
+	Kind  FlagActionKind `json:"kind"`
+	Value string         `json:"value,omitempty"`
+}
+
+// flagActionLogRetention is how long a fired action is remembered.
+const flagActionLogRetention = 90 * 24 * time.Hour
+
+// parseFlagAction validates `:flags action <id> <kind> [value]`.
+func parseFlagAction(kind, value string) (FlagAction, error) {
+	switch FlagActionKind(kind) {
+	case FlagActionNotify, FlagActionPin:
+		if value != "" {
+			return FlagAction{}, fmt.Errorf("%s action takes no value", kind)
+		}
+		return FlagAction{Kind: FlagActionKind(kind)}, nil
+	case FlagActionTag:
+		tags := ParseTags(value)
+		if len(tags) == 0 {
+			return FlagAction{}, fmt.Errorf("usage: :flags action <id> tag <tags>")
+		}
+		return FlagAction{Kind: FlagActionTag, Value: FormatTags(tags)}, nil
+	case FlagActionNote:
+		if value == "" {
+			return FlagAction{}, fmt.Errorf("usage: :flags action <id> note <template>")
+		}
+		if _, err := template.New("flag-note").Parse(value); err != nil {
+			return FlagAction{}, fmt.Errorf("invalid note template: %w", err)
+		}
+		return FlagAction{Kind: FlagActionNote, Value: value}, nil
+	default:
+		return FlagAction{}, fmt.Errorf("unknown flag action %q (use: notify, pin, tag, note)", kind)
+	}
+}
+
+func (a FlagAction) String() string {
+	switch a.Kind {
+	case FlagActionTag:
+		return "tag " + a.Value
+	case FlagActionNote:
+		return fmt.Sprintf("note %q", a.Value)
+	default:
+		return string(a.Kind)
+	}
+}
+
+// formatFlagActions lists a condition's actions for `:flags`.
+func formatFlagActions(actions []FlagAction) string {
+	parts := make([]string, len(actions))
+	for i, a := range actions {
+		parts[i] = a.String()
+	}
+	return strings.Join(parts, ", ")
+}
+
+// flagNoteData is what a note action's template can refer to.
+type flagNoteData struct {
+	ID       string
+	Title    string
+	Service  string
+	Flag     string
+	Clusters string
+}
+
+func renderFlagNote(tmpl string, data flagNoteData) (string, error) {
+	t, err := template.New("flag-note").Parse(tmpl)
+	if err != nil {
+		return "", err
+	}
+	var b bytes.Buffer
+	if err := t.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// flagActionLog remembers which actions already ran for which incident, so
+// a restart, a re-poll or a flag that stops and starts matching again does
+// not repeat them. It is kept in flag_actions.json next to flags.json.
+//
+// An action counts as run once its notification is sent or its approval
+// is accepted. offered holds the actions of this session that are waiting
+// in the approvals strip (or were dismissed there); it is not saved, so a
+// restart offers them again.
+type flagActionLog struct {
+	path    string
+	fired   map[string]time.Time
+	offered map[string]bool
+}
+
+func defaultFlagActionLogPath() string {
+	return filepath.Join(filepath.Dir(defaultFlagsPath()), "flag_actions.json")
+}
+
+// loadFlagActionLog reads the log, dropping entries past the retention. A
+// missing or unreadable file starts an empty log.
+func loadFlagActionLog(path string) *flagActionLog {
+	l := &flagActionLog{path: path, fired: make(map[string]time.Time), offered: make(map[string]bool)}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Warn("flag action log unreadable; starting empty", "path", path, "error", err)
+		}
+		return l
+	}
+	if err := json.Unmarshal(data, &l.fired); err != nil {
+		log.Warn("flag action log invalid; starting empty", "path", path, "error", err)
+		l.fired = make(map[string]time.Time)
+		return l
+	}
+	cutoff := time.Now().Add(-flagActionLogRetention)
+	maps.DeleteFunc(l.fired, func(_ string, at time.Time) bool { return at.Before(cutoff) })
+	return l
+}
+
+// flagActionKey identifies one action of one flag on one incident. The
+// flag's creation time tells apart flags that reuse an ID across sessions.
+func flagActionKey(incidentID string, cond FlagCondition, a FlagAction) string {
+	return fmt.Sprintf("%s|%d|%s|%s", incidentID, cond.ID, cond.CreatedAt.UTC().Format(time.RFC3339Nano), a)
+}
+
+// handled reports whether the action ran, or was offered in this session.
+func (l *flagActionLog) handled(key string) bool {
+	_, fired := l.fired[key]
+	return fired || l.offered[key]
+}
+
+// offer marks the action as queued for this session.
+func (l *flagActionLog) offer(key string) {
+	if l.offered == nil {
+		l.offered = make(map[string]bool)
+	}
+	l.offered[key] = true
+}
+
+// record marks the action as run and returns the command saving the log.
+func (l *flagActionLog) record(key string) tea.Cmd {
+	l.fired[key] = time.Now()
+	return l.saveCmd()
+}
+
+// saveCmd writes a snapshot of the log. Without a path (tests) it is a no-op.
+func (l *flagActionLog) saveCmd() tea.Cmd {
+	if l.path == "" {
+		return nil
+	}
+	path, fired := l.path, maps.Clone(l.fired)
+	return func() tea.Msg {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			log.Warn("flag action log not saved", "path", path, "error", err)
+			return nil
+		}
+		data, err := json.MarshalIndent(fired, "", "  ")
+		if err == nil {
+			err = os.WriteFile(path, data, 0644)
+		}
+		if err != nil {
+			log.Warn("flag action log not saved", "path", path, "error", err)
+		}
+		return nil
+	}
+}
+
+// pendingFlagAction is an action due to run for a newly matching incident.
+type pendingFlagAction struct {
+	incident  pagerduty.Incident
+	condition FlagCondition
+	action    FlagAction
+	key       string // flagActionKey
+}
+
+// flagActionResultMsg reports a notify or note action that ran.
+type flagActionResultMsg struct {
+	flagID     int
+	incidentID string
+	kind       FlagActionKind
+	err        error
+}
+
+// queueFlagActions records the actions of matching flags that have not run
+// for the incident yet, nor been offered in this session; runFlagActions
+// carries them out. Pin is a standing action and never queued.
+func (m *model) queueFlagActions() {
+	if m.flagActionLog == nil {
+		return
+	}
+	for _, inc := range m.incidentList {
+		for _, condID := range m.flagMatchCache[inc.ID] {
+			idx := slices.IndexFunc(m.flagConditions, func(c FlagCondition) bool { return c.ID == condID })
+			if idx < 0 {
+				continue
+			}
+			cond := m.flagConditions[idx]
+			for _, a := range cond.Actions {
+				if a.Kind == FlagActionPin {
+					continue
+				}
+				key := flagActionKey(inc.ID, cond, a)
+				if m.flagActionLog.handled(key) {
+					continue
+				}
+				m.flagActionLog.offer(key)
+				m.flagActionPending = append(m.flagActionPending, pendingFlagAction{incident: inc, condition: cond, action: a, key: key})
+			}
+		}
+	}
+}
+
+// runFlagActions carries out the queued actions. Notifications go out
+// directly; tags and notes change the incident in PagerDuty, so they are
+// offered in the approvals strip instead, and count as run once accepted.
+func (m *model) runFlagActions() []tea.Cmd {
+	if len(m.flagActionPending) == 0 {
+		return nil
+	}
+	if m.approvals == nil {
+		m.approvals = newApprovalsStrip()
+	}
+
+	var cmds []tea.Cmd
+	notified := false
+	for _, p := range m.flagActionPending {
+		log.Info("flag action", "flag", p.condition.ID, "incident_id", p.incident.ID, "action", p.action.Kind)
+		switch p.action.Kind {
+		case FlagActionNotify:
+			m.flagActionLog.fired[p.key] = time.Now()
+			notified = true
+			cmds = append(cmds, desktopNotifyCmd(p.condition.ID, p.incident.ID,
+				fmt.Sprintf("srepd: flag #%d", p.condition.ID),
+				fmt.Sprintf("%s %s\n%s", p.incident.ID, stripControl(p.incident.Title), p.condition.Label)))
+		case FlagActionTag:
+			m.approvals.Add(m.flagTagAsk(p))
+		case FlagActionNote:
+			ask, err := m.flagNoteAsk(p)
+			if err != nil {
+				m.setStatus(fmt.Sprintf("flag #%d note: %v", p.condition.ID, err))
+				continue
+			}
+			m.approvals.Add(ask)
+		}
+	}
+	m.flagActionPending = nil
+	if notified {
+		if cmd := m.flagActionLog.saveCmd(); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+	}
+	return cmds
+}
+
+func (m *model) flagTagAsk(p pendingFlagAction) Ask {
+	newTitle := PrependTags(p.action.Value, p.incident.Title)
+	config, incidentID, actionLog := m.config, p.incident.ID, m.flagActionLog
+	return Ask{
+		Kind:          AskFlagTag,
+		Title:         fmt.Sprintf("Flag #%d: tag %s", p.condition.ID, p.action.Value),
+		Body:          stripControl(newTitle),
+		IncidentID:    incidentID,
+		IncidentTitle: stripControl(p.incident.Title),
+		Action: func() tea.Cmd {
+			return tea.Batch(updateIncidentTitle(config, incidentID, newTitle), actionLog.record(p.key))
+		},
+	}
+}
+
+func (m *model) flagNoteAsk(p pendingFlagAction) (Ask, error) {
+	var clusters []string
+	for _, cid := range m.incidentClusterMap[p.incident.ID] {
+		if info, ok := m.clusterCache[cid]; ok && info != nil && info.DisplayName != "" {
+			cid = info.DisplayName
+		}
+		clusters = append(clusters, cid)
+	}
+	content, err := renderFlagNote(p.action.Value, flagNoteData{
+		ID:       p.incident.ID,
+		Title:    p.incident.Title,
+		Service:  p.incident.Service.Summary,
+		Flag:     p.condition.Label,
+		Clusters: strings.Join(clusters, ", "),
+	})
+	if err != nil {
+		return Ask{}, err
+	}
+	config, incidentID, flagID, actionLog := m.config, p.incident.ID, p.condition.ID, m.flagActionLog
+	return Ask{
+		Kind:          AskDraftNote,
+		Title:         fmt.Sprintf("Flag #%d: note", flagID),
+		Body:          stripControl(content),
+		IncidentID:    incidentID,
+		IncidentTitle: stripControl(p.incident.Title),
+		Action: func() tea.Cmd {
+			return tea.Batch(postFlagNote(config, flagID, incidentID, content), actionLog.record(p.key))
+		},
+	}, nil
+}
+
+func postFlagNote(p *pd.Config, flagID int, incidentID, content string) tea.Cmd {
+	return func() tea.Msg {
+		if p == nil || p.Client == nil {
+			return flagActionResultMsg{flagID: flagID, incidentID: incidentID, kind: FlagActionNote, err: fmt.Errorf("PagerDuty not configured")}
+		}
+		_, err := pd.PostNote(p.Client, incidentID, p.CurrentUser, content)
+		return flagActionResultMsg{flagID: flagID, incidentID: incidentID, kind: FlagActionNote, err: err}
+	}
+}
+
+// pinFlaggedIncidents moves incidents matching a flag with a pin action to
+// the front, keeping the order within both groups.
+func (m model) pinFlaggedIncidents(incidents []pagerduty.Incident) []pagerduty.Incident {
+	pinned := make(map[int]bool)
+	for _, c := range m.flagConditions {
+		if slices.ContainsFunc(c.Actions, func(a FlagAction) bool { return a.Kind == FlagActionPin }) {
+			pinned[c.ID] = true
+		}
+	}
+	if len(pinned) == 0 {
+		return incidents
+	}
+	isPinned := func(i pagerduty.Incident) bool {
+		return slices.ContainsFunc(m.flagMatchCache[i.ID], func(id int) bool { return pinned[id] })
+	}
+	sorted := slices.Clone(incidents)
+	slices.SortStableFunc(sorted, func(a, b pagerduty.Incident) int {
+		switch pa, pb := isPinned(a), isPinned(b); {
+		case pa && !pb:
+			return -1
+		case pb && !pa:
+			return 1
+		default:
+			return 0
+		}
+	})
+	return sorted
+}
+
+// desktopNotify runs a desktop notification command; replaced in tests.
+var desktopNotify = func(args []string) error {
+	return exec.Command(args[0], args[1:]...).Run()
+}
+
+// desktopNotifyArgs is the notification command for the OS: notify-send on
+// Linux, osascript on macOS, nothing elsewhere.
+func desktopNotifyArgs(goos, title, body string) []string {
+	switch goos {
+	case "linux":
+		return []string{"notify-send", "--app-name=srepd", title, body}
+	case "darwin":
+		return []string{"osascript", "-e", fmt.Sprintf("display notification %s with title %s", appleScriptString(body), appleScriptString(title))}
+	default:
+		return nil
+	}
+}
+
+func appleScriptString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+func desktopNotifyCmd(flagID int, incidentID, title, body string) tea.Cmd {
+	return func() tea.Msg {
+		args := desktopNotifyArgs(runtime.GOOS, title, body)
+		if args == nil {
+			return flagActionResultMsg{flagID: flagID, incidentID: incidentID, kind: FlagActionNotify, err: fmt.Errorf("desktop notifications are not supported on %s", runtime.GOOS)}
+		}
+		return flagActionResultMsg{flagID: flagID, incidentID: incidentID, kind: FlagActionNotify, err: desktopNotify(args)}
+	}
+}
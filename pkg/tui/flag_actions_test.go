@@ -0,0 +1,283 @@
+package tui
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/PagerDuty/go-pagerduty"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/clcollins/srepd/pkg/ocm"
+	"github.com/clcollins/srepd/pkg/pd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var vipCreated = time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+
+// flagActionTestModel has incidents VIP1 (cluster c1, org Acme) and OTHER
+// (cluster c2, org Initech), and flag #1 on org Acme with the given actions.
+func flagActionTestModel(t *testing.T, actions ...FlagAction) (model, *pd.MockPagerDutyClient) {
+	t.Helper()
+	m := createTestModel()
+	client := &pd.MockPagerDutyClient{}
+	m.config = &pd.Config{Client: client, CurrentUser: &pagerduty.User{APIObject: pagerduty.APIObject{ID: "U123"}}}
+	m.incidentList = []pagerduty.Incident{
+		{APIObject: pagerduty.APIObject{ID: "OTHER"}, Title: "KubeNodeNotReady"},
+		{APIObject: pagerduty.APIObject{ID: "VIP1"}, Title: "ClusterOperatorDown", Service: pagerduty.APIObject{Summary: "osd-hive"}},
+	}
+	m.incidentClusterMap = map[string][]string{"VIP1": {"c1"}, "OTHER": {"c2"}}
+	m.clusterCache = map[string]*ocm.ClusterInfo{
+		"c1": {ID: "c1", DisplayName: "acme-prod", Organization: "Acme"},
+		"c2": {ID: "c2", Organization: "Initech"},
+	}
+	m.flagConditions = []FlagCondition{{
+		ID:        1,
+		Type:      FlagOrgName,
+		Pattern:   "Acme",
+		Label:     `org name matches "Acme"`,
+		CreatedAt: vipCreated,
+		Actions:   actions,
+	}}
+	m.flagActionLog = &flagActionLog{fired: make(map[string]time.Time)}
+	m.approvals = newApprovalsStrip()
+	return m, client
+}
+
+func TestParseFlagAction(t *testing.T) {
+	a, err := parseFlagAction("tag", "VIP, acme")
+	require.NoError(t, err)
+	assert.Equal(t, FlagAction{Kind: FlagActionTag, Value: "[VIP][acme]"}, a)
+	assert.Equal(t, "tag [VIP][acme]", a.String())
+
+	a, err = parseFlagAction("note", "VIP customer — follow VIP SOP for {{.Clusters}}")
+	require.NoError(t, err)
+	assert.Equal(t, FlagActionNote, a.Kind)
+
+	for _, tt := range []struct{ kind, value, want string }{
+		{"notify", "loudly", "takes no value"},
+		{"tag", "", "usage: :flags action <id> tag"},
+		{"note", "", "usage: :flags action <id> note"},
+		{"note", "{{.Nope", "invalid note template"},
+		{"page", "", "unknown flag action"},
+	} {
+		_, err := parseFlagAction(tt.kind, tt.value)
+		require.Error(t, err, tt.kind)
+		assert.Contains(t, err.Error(), tt.want)
+	}
+}
+
+func TestParseFlagCommand_Action(t *testing.T) {
+	cmd, err := parseFlagCommand(":flags action 2 tag VIP")
+	require.NoError(t, err)
+	assert.Equal(t, flagCmdAddAction, cmd.action)
+	assert.Equal(t, 2, cmd.targetID)
+	assert.Equal(t, FlagAction{Kind: FlagActionTag, Value: "[VIP]"}, cmd.flagAction)
+
+	cmd, err = parseFlagCommand(":flags action 2 clear")
+	require.NoError(t, err)
+	assert.Equal(t, flagCmdClearActions, cmd.action)
+
+	_, err = parseFlagCommand(":flags action x notify")
+	assert.ErrorContains(t, err, "invalid flag ID")
+	_, err = parseFlagCommand(":flags action 2")
+	assert.ErrorContains(t, err, "usage: :flags action")
+}
+
+func TestFlagActions_RunOncePerIncident(t *testing.T) {
+	m, _ := flagActionTestModel(t,
+		FlagAction{Kind: FlagActionNotify},
+		FlagAction{Kind: FlagActionTag, Value: "[VIP]"},
+		FlagAction{Kind: FlagActionNote, Value: "VIP customer ({{.Clusters}}) — follow VIP SOP"},
+	)
+
+	m.rebuildFlagMatchCache()
+	require.Len(t, m.flagActionPending, 3)
+	cmds := m.runFlagActions()
+	require.Len(t, cmds, 1, "the notification; writes wait for approval")
+	assert.Empty(t, m.flagActionPending)
+	assert.Len(t, m.flagActionLog.fired, 1, "only the notification has run")
+
+	require.Equal(t, 2, m.approvals.Count())
+	tag, note := m.approvals.asks[0], m.approvals.asks[1]
+	assert.Equal(t, AskFlagTag, tag.Kind)
+	assert.Equal(t, "VIP1", tag.IncidentID)
+	assert.Equal(t, "[VIP] ClusterOperatorDown", tag.Body)
+	assert.Equal(t, AskDraftNote, note.Kind)
+	assert.Equal(t, "VIP customer (acme-prod) — follow VIP SOP", note.Body)
+
+	m.rebuildFlagMatchCache()
+	assert.Empty(t, m.flagActionPending, "already run or offered for VIP1")
+	assert.Empty(t, m.runFlagActions())
+
+	m.approvals.Dismiss(0)
+	m.rebuildFlagMatchCache()
+	assert.Empty(t, m.flagActionPending, "a dismissed ask is not offered again this session")
+	assert.Len(t, m.flagActionLog.fired, 1, "nor does it count as run")
+}
+
+func TestFlagActions_ApprovedWrites(t *testing.T) {
+	m, client := flagActionTestModel(t,
+		FlagAction{Kind: FlagActionTag, Value: "[VIP]"},
+		FlagAction{Kind: FlagActionNote, Value: "VIP customer — follow VIP SOP"},
+	)
+	m.rebuildFlagMatchCache()
+	m.runFlagActions()
+	require.Equal(t, 2, m.approvals.Count())
+
+	assert.Empty(t, m.flagActionLog.fired)
+	title, ok := m.approvals.Accept(0)().(updatedIncidentTitleMsg)
+	require.True(t, ok)
+	assert.Len(t, m.flagActionLog.fired, 1, "accepting runs the action")
+	require.NoError(t, title.err)
+	assert.Equal(t, "[VIP] ClusterOperatorDown", title.newTitle)
+
+	note, ok := m.approvals.Accept(0)().(flagActionResultMsg)
+	require.True(t, ok)
+	require.NoError(t, note.err)
+	assert.Equal(t, "VIP1", note.incidentID)
+	assert.Equal(t, 1, client.CallCounts["CreateIncidentNoteWithContext"])
+
+	result, _ := m.Update(note)
+	assert.Contains(t, result.(model).status, "flag #1: added note to VIP1")
+}
+
+func TestFlagActions_Notify(t *testing.T) {
+	var ran []string
+	restore := desktopNotify
+	desktopNotify = func(args []string) error { ran = args; return nil }
+	defer func() { desktopNotify = restore }()
+
+	m, _ := flagActionTestModel(t, FlagAction{Kind: FlagActionNotify})
+	m.rebuildFlagMatchCache()
+	cmds := m.runFlagActions()
+	require.Len(t, cmds, 1)
+
+	msg, ok := cmds[0]().(flagActionResultMsg)
+	require.True(t, ok)
+	assert.Equal(t, FlagActionNotify, msg.kind)
+	if msg.err == nil {
+		assert.Contains(t, ran[len(ran)-1], "VIP1 ClusterOperatorDown")
+	}
+}
+
+func TestDesktopNotifyArgs(t *testing.T) {
+	assert.Equal(t, []string{"notify-send", "--app-name=srepd", "t", "b"}, desktopNotifyArgs("linux", "t", "b"))
+	assert.Equal(t, []string{"osascript", "-e", `display notification "say \"hi\"" with title "t"`}, desktopNotifyArgs("darwin", "t", `say "hi"`))
+	assert.Nil(t, desktopNotifyArgs("windows", "t", "b"))
+}
+
+func TestFlagActions_KeyedByFlagIdentity(t *testing.T) {
+	m, _ := flagActionTestModel(t, FlagAction{Kind: FlagActionTag, Value: "[VIP]"})
+	m.rebuildFlagMatchCache()
+	m.runFlagActions()
+
+	// A flag with the same ID from another session is a different flag
+	m.flagConditions[0].CreatedAt = vipCreated.Add(time.Hour)
+	m.rebuildFlagMatchCache()
+	assert.Len(t, m.flagActionPending, 1)
+
+	// A new action on the same flag runs for incidents already matching
+	m.flagConditions[0].Actions = append(m.flagConditions[0].Actions, FlagAction{Kind: FlagActionTag, Value: "[acme]"})
+	m.flagActionPending = nil
+	m.rebuildFlagMatchCache()
+	require.Len(t, m.flagActionPending, 1)
+	assert.Equal(t, "[acme]", m.flagActionPending[0].action.Value)
+}
+
+func TestFlagActionLog_PersistsAcrossRestarts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "srepd", "flag_actions.json")
+	restart := func() model {
+		m, _ := flagActionTestModel(t, FlagAction{Kind: FlagActionTag, Value: "[VIP]"})
+		m.flagActionLog = loadFlagActionLog(path)
+		m.rebuildFlagMatchCache()
+		return m
+	}
+
+	m := restart()
+	require.Len(t, m.flagActionPending, 1)
+	assert.Empty(t, m.runFlagActions(), "nothing has run yet, so nothing is saved")
+
+	// Quitting with the ask unanswered offers it again.
+	m = restart()
+	require.Len(t, m.flagActionPending, 1)
+	m.runFlagActions()
+	cmd := m.approvals.Accept(0)
+	require.NotNil(t, cmd)
+	for _, c := range cmd().(tea.BatchMsg) {
+		c()
+	}
+
+	m = restart()
+	assert.Empty(t, m.flagActionPending)
+}
+
+func TestLoadFlagActionLog_PrunesAndTolerates(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "flag_actions.json")
+	data, err := json.Marshal(map[string]time.Time{
+		"recent": time.Now().Add(-time.Hour),
+		"stale":  time.Now().Add(-flagActionLogRetention - time.Hour),
+	})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0644))
+
+	l := loadFlagActionLog(path)
+	assert.Contains(t, l.fired, "recent")
+	assert.NotContains(t, l.fired, "stale")
+
+	require.NoError(t, os.WriteFile(path, []byte("{not json"), 0644))
+	assert.Empty(t, loadFlagActionLog(path).fired)
+	assert.Empty(t, loadFlagActionLog(filepath.Join(dir, "missing.json")).fired)
+}
+
+func TestPinFlaggedIncidents(t *testing.T) {
+	m, _ := flagActionTestModel(t, FlagAction{Kind: FlagActionPin})
+	m.rebuildFlagMatchCache()
+	assert.Empty(t, m.flagActionPending, "pin is a standing action, not a one-off")
+
+	pinned := m.pinFlaggedIncidents(m.incidentList)
+	assert.Equal(t, "VIP1", pinned[0].ID)
+	assert.Equal(t, "OTHER", m.incidentList[0].ID, "the incident list itself is not reordered")
+
+	m.flagConditions[0].Actions = nil
+	assert.Equal(t, "OTHER", m.pinFlaggedIncidents(m.incidentList)[0].ID)
+}
+
+func TestFlagActionsMsg_AddAndClear(t *testing.T) {
+	m, _ := flagActionTestModel(t)
+
+	result, _ := m.Update(flagActionsMsg{id: 1, action: &FlagAction{Kind: FlagActionTag, Value: "[VIP]"}})
+	m = result.(model)
+	assert.Equal(t, []FlagAction{{Kind: FlagActionTag, Value: "[VIP]"}}, m.flagConditions[0].Actions)
+	assert.Contains(t, m.status, "flag #1 will tag [VIP]")
+	assert.Len(t, m.flagActionPending, 1, "VIP1 already matches")
+
+	_, cmd := m.Update(updatedIncidentListMsg{m.incidentList, nil})
+	drainCmd(t, cmd, func(tea.Msg) {})
+	assert.Contains(t, formatFlagsList(m.flagConditions), `org name matches "Acme" → tag [VIP]`)
+
+	result, _ = m.Update(flagActionsMsg{id: 1})
+	m = result.(model)
+	assert.Empty(t, m.flagConditions[0].Actions)
+
+	result, _ = m.Update(flagActionsMsg{id: 9})
+	assert.Contains(t, result.(model).status, "no flag #9")
+}
+
+func TestUpdatedIncidentList_RunsFlagActionsAndPins(t *testing.T) {
+	m, _ := flagActionTestModel(t, FlagAction{Kind: FlagActionPin}, FlagAction{Kind: FlagActionTag, Value: "[VIP]"})
+	m.teamMode = true
+	m.showLowUrgency = true
+	m.table.SetColumns([]table.Column{{Title: "", Width: 1}, {Title: "ID", Width: 16}, {Title: "Title", Width: 40}, {Title: "Service", Width: 30}})
+
+	result, _ := m.Update(updatedIncidentListMsg{m.incidentList, nil})
+	m = result.(model)
+
+	assert.Equal(t, 1, m.approvals.Count())
+	require.NotEmpty(t, m.table.Rows())
+	assert.Equal(t, "VIP1", m.table.Rows()[0][1])
+}
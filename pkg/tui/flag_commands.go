@@ -22,13 +22,18 @@ const (
 	flagCmdList
 	flagCmdSave
 	flagCmdLoad
+	flagCmdAddAction
+	flagCmdClearActions
+	flagCmdExport
+	flagCmdImport
 )
 
 type parsedFlagCommand struct {
-	action    flagCmdAction
-	condition FlagCondition
-	targetID  int
-	path      string
+	action     flagCmdAction
+	condition  FlagCondition
+	targetID   int
+	path       string
+	flagAction FlagAction
 }
 
 type addFlagConditionMsg struct{ condition FlagCondition }
@@ -36,6 +41,13 @@ type removeFlagConditionMsg struct{ id int }
 type clearFlagConditionsMsg struct{}
 type listFlagConditionsMsg struct{}
 type flagsSavedMsg struct{ err error }
+
+// flagActionsMsg adds an action to flag id, or clears its actions when
+// action is nil.
+type flagActionsMsg struct {
+	id     int
+	action *FlagAction
+}
 type flagsLoadedMsg struct {
 	conditions []FlagCondition
 	err        error
@@ -89,47 +101,88 @@ func parseFlagsSubcommand(args []string) (*parsedFlagCommand, error) {
 			cmd.path = strings.Join(args[1:], " ")
 		}
 		return cmd, nil
+	case "action":
+		return parseFlagsAction(args[1:])
+	case "export":
+		if len(args) < 2 {
+			return nil, fmt.Errorf("usage: :flags export <file>")
+		}
+		return &parsedFlagCommand{action: flagCmdExport, path: strings.Join(args[1:], " ")}, nil
+	case "import":
+		if len(args) < 2 {
+			return nil, fmt.Errorf("usage: :flags import <file|https-url>")
+		}
+		return &parsedFlagCommand{action: flagCmdImport, path: strings.Join(args[1:], " ")}, nil
 	default:
-		return nil, fmt.Errorf("unknown :flags subcommand: %s (use: save, load)", args[0])
+		return nil, fmt.Errorf("unknown :flags subcommand: %s (use: save, load, action, export, import)", args[0])
 	}
 }
 
+// parseFlagsAction parses `:flags action <id> <kind> [value]` and
+// `:flags action <id> clear`.
+func parseFlagsAction(args []string) (*parsedFlagCommand, error) {
+	if len(args) < 2 {
+		return nil, fmt.Errorf("usage: :flags action <id> <notify|pin|tag <tags>|note <template>|clear>")
+	}
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid flag ID %q: must be a number", args[0])
+	}
+	if args[1] == "clear" && len(args) == 2 {
+		return &parsedFlagCommand{action: flagCmdClearActions, targetID: id}, nil
+	}
+	a, err := parseFlagAction(args[1], strings.Join(args[2:], " "))
+	if err != nil {
+		return nil, err
+	}
+	return &parsedFlagCommand{action: flagCmdAddAction, targetID: id, flagAction: a}, nil
+}
+
 func parseFlagAdd(args []string) (*parsedFlagCommand, error) {
 	if len(args) == 0 {
-		return nil, fmt.Errorf("usage: :flag <cluster|org> <value>")
+		return nil, fmt.Errorf("usage: :flag <type> <value> (types: %s)", flagKeywords())
 	}
 
-	flagType := args[0]
-	value := strings.Join(args[1:], " ")
+	expr, err := parseFlagExpr(args)
+	if err != nil {
+		return nil, err
+	}
 
-	switch flagType {
-	case "cluster":
-		if value == "" {
-			return nil, fmt.Errorf("usage: :flag cluster <cluster-id>")
-		}
-		return &parsedFlagCommand{
-			action: flagCmdAdd,
-			condition: FlagCondition{
-				Type:    FlagClusterID,
-				Pattern: value,
-				Label:   fmt.Sprintf("cluster matches %q", value),
-			},
-		}, nil
-	case "org":
+	cond := FlagCondition{Type: FlagExpression, Pattern: expr.String(), Label: expr.label()}
+	if expr.op == flagExprTerm {
+		cond = FlagCondition{Type: expr.spec.Type, Pattern: expr.pattern, Label: flagLabel(expr.spec, expr.pattern)}
+	}
+	return &parsedFlagCommand{action: flagCmdAdd, condition: cond}, nil
+}
+
+// parseFlagTerm validates the value of a single `<keyword> <value>`
+// condition and returns its spec and stored pattern.
+func parseFlagTerm(keyword, value string) (flagTypeSpec, string, error) {
+	spec, ok := flagSpecForKeyword(keyword)
+	if !ok {
+		return flagTypeSpec{}, "", fmt.Errorf("unknown flag type %q (use: %s)", keyword, flagKeywords())
+	}
+
+	switch spec.Kind {
+	case flagPatternBool:
+		b, err := parseFlagBool(value)
+		if err != nil {
+			return spec, "", fmt.Errorf("usage: :flag %s %s: %w", spec.Keyword, spec.Arg, err)
+		}
+		value = b
+	case flagPatternRegex:
 		if value == "" {
-			return nil, fmt.Errorf("usage: :flag org <pattern>")
-		}
-		return &parsedFlagCommand{
-			action: flagCmdAdd,
-			condition: FlagCondition{
-				Type:    FlagOrgName,
-				Pattern: value,
-				Label:   fmt.Sprintf("org name matches %q", value),
-			},
-		}, nil
+			return spec, "", fmt.Errorf("usage: :flag %s %s", spec.Keyword, spec.Arg)
+		}
+		if _, err := compileFlagRegex(value); err != nil {
+			return spec, "", fmt.Errorf("invalid %s regex: %w", spec.Keyword, err)
+		}
 	default:
-		return nil, fmt.Errorf("unknown flag type %q (use: cluster, org)", flagType)
+		if value == "" {
+			return spec, "", fmt.Errorf("usage: :flag %s %s", spec.Keyword, spec.Arg)
+		}
 	}
+	return spec, value, nil
 }
 
 func parseUnflag(args []string) (*parsedFlagCommand, error) {
@@ -218,6 +271,19 @@ func (m model) dispatchFlagCommand(input string) tea.Cmd {
 		return saveFlagsCmd(m.flagConditions, parsed.path)
 	case flagCmdLoad:
 		return loadFlagsCmd(parsed.path)
+	case flagCmdAddAction:
+		id, a := parsed.targetID, parsed.flagAction
+		return func() tea.Msg { return flagActionsMsg{id: id, action: &a} }
+	case flagCmdClearActions:
+		id := parsed.targetID
+		return func() tea.Msg { return flagActionsMsg{id: id} }
+	case flagCmdExport:
+		if len(m.flagConditions) == 0 {
+			return m.flashNotification("no flag conditions to export")
+		}
+		return exportFlagsCmd(m.flagConditions, parsed.path)
+	case flagCmdImport:
+		return importFlagsCmd(parsed.path)
 	default:
 		return nil
 	}
@@ -48,6 +48,58 @@ func TestParseFlagCommand_OrgNameMultiWord(t *testing.T) {
 	})
 }
 
+func TestParseFlagCommand_ConditionTypes(t *testing.T) {
+	tests := []struct {
+		input   string
+		typ     FlagConditionType
+		pattern string
+		label   string
+	}{
+		{":flag cloud gcp", FlagCloudProvider, "gcp", `cloud provider matches "gcp"`},
+		{":flag version 4.14*", FlagVersion, "4.14*", `version matches "4.14*"`},
+		{":flag region us-east-*", FlagRegion, "us-east-*", `region matches "us-east-*"`},
+		{":flag hypershift", FlagHypershift, "true", "hypershift is true"},
+		{":flag hypershift false", FlagHypershift, "false", "hypershift is false"},
+		{":flag ccs true", FlagCCS, "true", "CCS is true"},
+		{":flag state limited*", FlagClusterState, "limited*", `cluster state matches "limited*"`},
+		{":flag alert etcd*", FlagAlertName, "etcd*", `alert name matches "etcd*"`},
+		{":flag alert-type rhobs_*", FlagAlertType, "rhobs_*", `alert type matches "rhobs_*"`},
+		{":flag severity critical", FlagSeverity, "critical", `severity matches "critical"`},
+		{":flag namespace openshift-*", FlagNamespace, "openshift-*", `namespace matches "openshift-*"`},
+		{":flag title ^etcd.* CRITICAL", FlagTitle, "^etcd.* CRITICAL", "title matches /^etcd.* CRITICAL/"},
+		{":flag service hive$", FlagService, "hive$", "service matches /hive$/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			cmd, err := parseFlagCommand(tt.input)
+
+			require.NoError(t, err)
+			assert.Equal(t, flagCmdAdd, cmd.action)
+			assert.Equal(t, tt.typ, cmd.condition.Type)
+			assert.Equal(t, tt.pattern, cmd.condition.Pattern)
+			assert.Equal(t, tt.label, cmd.condition.Label)
+		})
+	}
+}
+
+func TestParseFlagCommand_ConditionTypeErrors(t *testing.T) {
+	tests := map[string]string{
+		":flag hypershift maybe": "expected true or false",
+		":flag title (":          "invalid title regex",
+		":flag service":          "usage: :flag service <regex>",
+		":flag version":          "usage: :flag version <pattern>",
+		":flag":                  "types: cluster, org, cloud",
+	}
+	for input, want := range tests {
+		t.Run(input, func(t *testing.T) {
+			_, err := parseFlagCommand(input)
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), want)
+		})
+	}
+}
+
 func TestParseFlagCommand_List(t *testing.T) {
 	t.Run("parses :flags as list command", func(t *testing.T) {
 		cmd, err := parseFlagCommand(":flags")
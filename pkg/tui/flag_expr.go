@@ -0,0 +1,274 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/clcollins/srepd/pkg/ocm"
+)
+
+// Flag expressions combine `<keyword> <value>` terms with AND, OR, NOT and
+// parentheses:
+//
+//	:flag org Acme AND alert KubeAPIDown
+//	:flag hypershift AND NOT region us-east-1
+//	:flag (org Acme OR org Initech) AND severity critical
+//
+// NOT binds tightest, then AND, then OR. Operators are upper case, so values
+// such as "Black and Decker" are left alone. A value runs until the next
+// operator, or until the ")" closing its group; parentheses balanced within
+// the value, as in `title (etcd|kube)`, stay part of it.
+
+type flagExprOp int
+
+const (
+	flagExprTerm flagExprOp = iota
+	flagExprAnd
+	flagExprOr
+	flagExprNot
+)
+
+// flagExpr is a parsed flag expression. Terms carry a spec and pattern;
+// AND/OR carry two or more args and NOT carries one.
+type flagExpr struct {
+	op      flagExprOp
+	args    []*flagExpr
+	spec    flagTypeSpec
+	pattern string
+}
+
+// flagToken is a word or parenthesis of a `:flag` command. glued is set when
+// no space separates it from the previous token.
+type flagToken struct {
+	text  string
+	glued bool
+}
+
+// tokenizeFlagExpr splits leading "(" and trailing ")" off each word.
+func tokenizeFlagExpr(words []string) []flagToken {
+	var tokens []flagToken
+	for _, w := range words {
+		glued := false
+		for strings.HasPrefix(w, "(") {
+			tokens = append(tokens, flagToken{text: "(", glued: glued})
+			w, glued = w[1:], true
+		}
+		closing := len(w) - len(strings.TrimRight(w, ")"))
+		if core := w[:len(w)-closing]; core != "" {
+			tokens = append(tokens, flagToken{text: core, glued: glued})
+			glued = true
+		}
+		for range closing {
+			tokens = append(tokens, flagToken{text: ")", glued: glued})
+			glued = true
+		}
+	}
+	return tokens
+}
+
+type flagExprParser struct {
+	tokens []flagToken
+	pos    int
+	depth  int // open groups
+}
+
+// parseFlagExpr parses the arguments of `:flag`. A plain `<keyword> <value>`
+// yields a single term, exactly as before expressions existed.
+func parseFlagExpr(words []string) (*flagExpr, error) {
+	p := &flagExprParser{tokens: tokenizeFlagExpr(words)}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q in flag expression", p.tokens[p.pos].text)
+	}
+	return expr, nil
+}
+
+func (p *flagExprParser) peek() (flagToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return flagToken{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+// atOperator reports whether the next token is the operator op. Operators
+// are whole words.
+func (p *flagExprParser) atOperator(op string) bool {
+	t, ok := p.peek()
+	return ok && !t.glued && t.text == op
+}
+
+func (p *flagExprParser) parseOr() (*flagExpr, error) {
+	return p.parseBinary("OR", flagExprOr, p.parseAnd)
+}
+
+func (p *flagExprParser) parseAnd() (*flagExpr, error) {
+	return p.parseBinary("AND", flagExprAnd, p.parseUnary)
+}
+
+func (p *flagExprParser) parseBinary(word string, op flagExprOp, operand func() (*flagExpr, error)) (*flagExpr, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	if !p.atOperator(word) {
+		return left, nil
+	}
+	expr := &flagExpr{op: op, args: []*flagExpr{left}}
+	for p.atOperator(word) {
+		p.pos++
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		expr.args = append(expr.args, right)
+	}
+	return expr, nil
+}
+
+func (p *flagExprParser) parseUnary() (*flagExpr, error) {
+	if p.atOperator("NOT") {
+		p.pos++
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &flagExpr{op: flagExprNot, args: []*flagExpr{operand}}, nil
+	}
+	if t, ok := p.peek(); ok && t.text == "(" {
+		p.pos++
+		p.depth++
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if t, ok := p.peek(); !ok || t.text != ")" {
+			return nil, fmt.Errorf("missing ) in flag expression")
+		}
+		p.pos++
+		p.depth--
+		return expr, nil
+	}
+	return p.parseTerm()
+}
+
+func (p *flagExprParser) parseTerm() (*flagExpr, error) {
+	t, ok := p.peek()
+	if !ok {
+		return nil, fmt.Errorf("expected a condition at the end of the flag expression (types: %s)", flagKeywords())
+	}
+	if t.text == ")" || p.atOperator("AND") || p.atOperator("OR") {
+		return nil, fmt.Errorf("expected a condition before %q (types: %s)", t.text, flagKeywords())
+	}
+	keyword := t.text
+	p.pos++
+
+	var value strings.Builder
+	start, open := p.pos, 0
+	for ; p.pos < len(p.tokens); p.pos++ {
+		t := p.tokens[p.pos]
+		if open == 0 && (p.atOperator("AND") || p.atOperator("OR")) {
+			break
+		}
+		// A ")" the value did not open closes the enclosing group. Outside
+		// any group it is just text, as in single-condition flags.
+		if t.text == ")" && open == 0 && p.depth > 0 {
+			break
+		}
+		open = max(0, open+strings.Count(t.text, "(")-strings.Count(t.text, ")"))
+		if p.pos > start && !t.glued {
+			value.WriteByte(' ')
+		}
+		value.WriteString(t.text)
+	}
+
+	spec, pattern, err := parseFlagTerm(keyword, value.String())
+	if err != nil {
+		return nil, err
+	}
+	return &flagExpr{op: flagExprTerm, spec: spec, pattern: pattern}, nil
+}
+
+// flagExprCache holds parsed FlagExpression patterns; flag matching runs on
+// every incident list update.
+var flagExprCache sync.Map
+
+// compileFlagExpr parses a stored FlagExpression pattern.
+func compileFlagExpr(pattern string) (*flagExpr, error) {
+	if expr, ok := flagExprCache.Load(pattern); ok {
+		return expr.(*flagExpr), nil
+	}
+	expr, err := parseFlagExpr(strings.Fields(pattern))
+	if err != nil {
+		return nil, err
+	}
+	flagExprCache.Store(pattern, expr)
+	return expr, nil
+}
+
+// match evaluates the expression. NOT inverts a term's result as is, so
+// `NOT region us-east-1` also holds for clusters OCM has not described.
+func (e *flagExpr) match(subject flagSubject, clusterCache map[string]*ocm.ClusterInfo) bool {
+	switch e.op {
+	case flagExprAnd:
+		for _, a := range e.args {
+			if !a.match(subject, clusterCache) {
+				return false
+			}
+		}
+		return true
+	case flagExprOr:
+		for _, a := range e.args {
+			if a.match(subject, clusterCache) {
+				return true
+			}
+		}
+		return false
+	case flagExprNot:
+		return !e.args[0].match(subject, clusterCache)
+	default:
+		return matchFlagTerm(e.spec.Type, e.pattern, subject, clusterCache)
+	}
+}
+
+// String renders the expression as `:flag` input; it is what a
+// FlagExpression condition stores as its Pattern.
+func (e *flagExpr) String() string {
+	return e.render(func(t *flagExpr) string { return t.spec.Keyword + " " + t.pattern })
+}
+
+// label renders the expression with each term's human-readable label.
+func (e *flagExpr) label() string {
+	return e.render(func(t *flagExpr) string { return flagLabel(t.spec, t.pattern) })
+}
+
+func (e *flagExpr) render(term func(*flagExpr) string) string {
+	switch e.op {
+	case flagExprAnd, flagExprOr:
+		sep := " AND "
+		if e.op == flagExprOr {
+			sep = " OR "
+		}
+		parts := make([]string, len(e.args))
+		for i, a := range e.args {
+			parts[i] = a.render(term)
+			// AND binds tighter than OR, so only an OR inside an AND
+			// needs parentheses.
+			if a.op == flagExprOr && e.op == flagExprAnd {
+				parts[i] = "(" + parts[i] + ")"
+			}
+		}
+		return strings.Join(parts, sep)
+	case flagExprNot:
+		inner := e.args[0].render(term)
+		if e.args[0].op == flagExprAnd || e.args[0].op == flagExprOr {
+			inner = "(" + inner + ")"
+		}
+		return "NOT " + inner
+	default:
+		return term(e)
+	}
+}
@@ -0,0 +1,164 @@
+package tui
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/PagerDuty/go-pagerduty"
+	"github.com/clcollins/srepd/pkg/ocm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFlagExpr_Rendering(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"org Acme AND alert KubeAPIDown", "org Acme AND alert KubeAPIDown"},
+		{"hypershift AND NOT region us-east-1", "hypershift true AND NOT region us-east-1"},
+		{"org A OR org B AND severity critical", "org A OR org B AND severity critical"},
+		{"(org A OR org B) AND severity critical", "(org A OR org B) AND severity critical"},
+		{"NOT (cloud aws OR cloud gcp)", "NOT (cloud aws OR cloud gcp)"},
+		{"((org A))", "org A"},
+		{"NOT (ccs)", "NOT ccs true"},
+		{"title (etcd|kube)Down AND service hive$", "title (etcd|kube)Down AND service hive$"},
+		{"(title (etcd|kube) OR org A)", "title (etcd|kube) OR org A"},
+		{"org Black and Decker OR org Acme", "org Black and Decker OR org Acme"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			expr, err := parseFlagExpr(strings.Fields(tt.input))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, expr.String())
+
+			again, err := parseFlagExpr(strings.Fields(expr.String()))
+			require.NoError(t, err)
+			assert.Equal(t, expr.String(), again.String(), "the stored pattern parses back to the same expression")
+		})
+	}
+}
+
+func TestParseFlagExpr_Precedence(t *testing.T) {
+	expr, err := parseFlagExpr(strings.Fields("org A OR NOT org B AND org C"))
+	require.NoError(t, err)
+
+	require.Equal(t, flagExprOr, expr.op)
+	require.Len(t, expr.args, 2)
+	assert.Equal(t, flagExprTerm, expr.args[0].op)
+	and := expr.args[1]
+	require.Equal(t, flagExprAnd, and.op)
+	assert.Equal(t, flagExprNot, and.args[0].op)
+	assert.Equal(t, "B", and.args[0].args[0].pattern)
+	assert.Equal(t, "C", and.args[1].pattern)
+}
+
+func TestParseFlagExpr_Errors(t *testing.T) {
+	tests := map[string]string{
+		"org A AND":             "expected a condition at the end",
+		"AND org A":             `expected a condition before "AND"`,
+		"(org A":                "missing )",
+		"org A) OR org B":       "",
+		"(org A)) OR org B":     `unexpected ")"`,
+		"NOT":                   "expected a condition at the end",
+		"org A AND bogus x":     `unknown flag type "bogus"`,
+		"org A OR title (":      "invalid title regex",
+		"hypershift maybe OR x": "expected true or false",
+	}
+	for input, want := range tests {
+		t.Run(input, func(t *testing.T) {
+			_, err := parseFlagExpr(strings.Fields(input))
+			if want == "" {
+				assert.NoError(t, err, "an unmatched ) outside any group is part of the value")
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), want)
+		})
+	}
+}
+
+func TestParseFlagCommand_Expression(t *testing.T) {
+	cmd, err := parseFlagCommand(":flag hypershift AND NOT region us-east-1")
+
+	require.NoError(t, err)
+	assert.Equal(t, flagCmdAdd, cmd.action)
+	assert.Equal(t, FlagExpression, cmd.condition.Type)
+	assert.Equal(t, "hypershift true AND NOT region us-east-1", cmd.condition.Pattern)
+	assert.Equal(t, `hypershift is true AND NOT region matches "us-east-1"`, cmd.condition.Label)
+}
+
+func TestParseFlagCommand_SingleTermUnchanged(t *testing.T) {
+	cmd, err := parseFlagCommand(":flag org Acme (EU)")
+
+	require.NoError(t, err)
+	assert.Equal(t, FlagOrgName, cmd.condition.Type, "a single condition is stored as before")
+	assert.Equal(t, "Acme (EU)", cmd.condition.Pattern)
+	assert.Equal(t, `org name matches "Acme (EU)"`, cmd.condition.Label)
+}
+
+func TestEvaluateIncidentFlags_Expressions(t *testing.T) {
+	incidents := []pagerduty.Incident{
+		{APIObject: pagerduty.APIObject{ID: "HCP_EAST"}},
+		{APIObject: pagerduty.APIObject{ID: "HCP_WEST"}},
+		{APIObject: pagerduty.APIObject{ID: "CLASSIC"}},
+	}
+	clusterMap := map[string][]string{"HCP_EAST": {"c1"}, "HCP_WEST": {"c2"}, "CLASSIC": {"c3"}}
+	clusterCache := map[string]*ocm.ClusterInfo{
+		"c1": {ID: "c1", Hypershift: true, Region: "us-east-1", Organization: "Acme"},
+		"c2": {ID: "c2", Hypershift: true, Region: "us-west-2", Organization: "Initech"},
+		"c3": {ID: "c3", Region: "us-east-1", Organization: "Acme"},
+	}
+	cache := alertsCache(map[string][]pagerduty.IncidentAlert{
+		"HCP_EAST": {hiveAlert("KubeAPIDown", "c1", "")},
+		"CLASSIC":  {hiveAlert("KubeAPIDown", "c3", "")},
+	})
+
+	tests := []struct {
+		expr    string
+		matches []string
+	}{
+		{"hypershift AND NOT region us-east-1", []string{"HCP_WEST"}},
+		{"org Acme AND alert KubeAPIDown", []string{"HCP_EAST", "CLASSIC"}},
+		{"org Initech OR NOT hypershift", []string{"HCP_WEST", "CLASSIC"}},
+		{"(org Initech OR org Acme) AND hypershift AND alert KubeAPIDown", []string{"HCP_EAST"}},
+		{"NOT (org Acme OR org Initech)", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			cmd, err := parseFlagCommand(":flag " + tt.expr)
+			require.NoError(t, err)
+			cond := cmd.condition
+			cond.ID = 1
+
+			result := evaluateIncidentFlags(incidents, []FlagCondition{cond}, clusterMap, clusterCache, cache)
+			var got []string
+			for _, inc := range incidents {
+				if len(result[inc.ID]) > 0 {
+					got = append(got, inc.ID)
+				}
+			}
+			assert.Equal(t, tt.matches, got)
+		})
+	}
+}
+
+func TestFlagExpression_JSONCompatibility(t *testing.T) {
+	saved := `[
+		{"id": 1, "type": 1, "pattern": "^Acme*", "label": "org name matches \"^Acme*\""},
+		{"id": 2, "type": 14, "pattern": "org Acme AND NOT ccs true", "label": "org name matches \"Acme\" AND NOT CCS is true"},
+		{"id": 3, "type": 14, "pattern": "org Acme AND", "label": "broken by hand"}
+	]`
+	var conds []FlagCondition
+	require.NoError(t, json.Unmarshal([]byte(saved), &conds))
+
+	clusterCache := map[string]*ocm.ClusterInfo{"c1": {ID: "c1", Organization: "Acme Corp"}}
+	result := evaluateFlags([]string{"INC001"}, conds, map[string][]string{"INC001": {"c1"}}, clusterCache)
+	assert.Equal(t, []int{1, 2}, result["INC001"], "single conditions load unchanged; an unparsable expression never matches")
+}
+
+func TestFormatFlagsList_Expression(t *testing.T) {
+	out := formatFlagsList([]FlagCondition{{ID: 4, Type: FlagExpression, Label: `org name matches "Acme" AND NOT CCS is true`}})
+	assert.Contains(t, out, `**#4** [expression] org name matches "Acme" AND NOT CCS is true`)
+}
@@ -0,0 +1,229 @@
+package tui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+	pkgconfig "github.com/clcollins/srepd/pkg/config"
+)
+
+// flagImportPreviewLimit caps the conditions listed per section of the
+// import confirmation.
+const flagImportPreviewLimit = 8
+
+type flagsExportedMsg struct {
+	path  string
+	count int
+	err   error
+}
+
+// flagsImportFetchedMsg carries the validated conditions of an import,
+// before the user confirms the merge.
+type flagsImportFetchedMsg struct {
+	source     string
+	conditions []FlagCondition
+	err        error
+}
+
+type applyFlagImportMsg struct{ preview flagImportPreview }
+
+// flagImportChange pairs an existing condition with the imported one that
+// updates it: same condition with a new label, or same label with a new
+// condition.
+type flagImportChange struct {
+	existing FlagCondition
+	incoming FlagCondition
+}
+
+// flagImportPreview sorts imported conditions against the current ones.
+type flagImportPreview struct {
+	source     string
+	added      []FlagCondition
+	changed    []flagImportChange
+	duplicates []FlagCondition
+}
+
+// flagConditionSource renders a condition in `:flag` syntax.
+func flagConditionSource(c FlagCondition) string {
+	if c.Type == FlagExpression {
+		return c.Pattern
+	}
+	if spec, ok := flagSpecForType(c.Type); ok {
+		return spec.Keyword + " " + c.Pattern
+	}
+	return ""
+}
+
+// presetFlagsFromConditions is what `:flags export` shares: conditions and
+// labels, never actions.
+func presetFlagsFromConditions(conditions []FlagCondition) []pkgconfig.PresetFlag {
+	flags := make([]pkgconfig.PresetFlag, 0, len(conditions))
+	for _, c := range conditions {
+		if src := flagConditionSource(c); src != "" {
+			flags = append(flags, pkgconfig.PresetFlag{Condition: src, Label: c.Label})
+		}
+	}
+	return flags
+}
+
+func exportFlagsCmd(conditions []FlagCondition, path string) tea.Cmd {
+	flags := presetFlagsFromConditions(conditions)
+	return func() tea.Msg {
+		data, err := pkgconfig.FormatPresetFlags(flags)
+		if err != nil {
+			return flagsExportedMsg{err: fmt.Errorf("marshal flags: %w", err)}
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return flagsExportedMsg{err: fmt.Errorf("create directory: %w", err)}
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return flagsExportedMsg{err: fmt.Errorf("write flags: %w", err)}
+		}
+		log.Info("flags exported", "path", path, "count", len(flags))
+		return flagsExportedMsg{path: path, count: len(flags)}
+	}
+}
+
+// importFlagsCmd reads flags from a file or HTTPS URL, with the preset
+// fetch rules. It accepts a preset (or `:flags export` output) and a
+// `:flags save` JSON file; actions in the latter are dropped.
+func importFlagsCmd(ref string) tea.Cmd {
+	return func() tea.Msg {
+		data, err := pkgconfig.ReadPresetData(ref, nil)
+		if err != nil {
+			return flagsImportFetchedMsg{source: ref, err: err}
+		}
+		conditions, err := parseFlagImport(data, ref)
+		return flagsImportFetchedMsg{source: ref, conditions: conditions, err: err}
+	}
+}
+
+func parseFlagImport(data []byte, source string) ([]FlagCondition, error) {
+	var flags []pkgconfig.PresetFlag
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		var saved []FlagCondition
+		if err := json.Unmarshal(data, &saved); err != nil {
+			return nil, fmt.Errorf("parse flags: %w", err)
+		}
+		flags = presetFlagsFromConditions(saved)
+	} else {
+		p, err := pkgconfig.ParsePreset(data, source)
+		if err != nil {
+			return nil, err
+		}
+		flags = p.Flags
+	}
+	if len(flags) == 0 {
+		return nil, fmt.Errorf("no flag conditions in %s", source)
+	}
+
+	conditions := make([]FlagCondition, 0, len(flags))
+	for i, f := range flags {
+		parsed, err := parseFlagAdd(strings.Fields(f.Condition))
+		if err != nil {
+			return nil, fmt.Errorf("flag %d (%s): %w", i+1, f.Condition, err)
+		}
+		cond := parsed.condition
+		if f.Label != "" {
+			cond.Label = stripControl(f.Label)
+		}
+		conditions = append(conditions, cond)
+	}
+	return conditions, nil
+}
+
+func sameFlagCondition(a, b FlagCondition) bool {
+	return a.Type == b.Type && a.Pattern == b.Pattern
+}
+
+// previewFlagImport classifies each imported condition as a duplicate
+// (same condition and label), a change to an existing condition (same
+// condition, or same label), or new.
+func previewFlagImport(existing, incoming []FlagCondition, source string) flagImportPreview {
+	preview := flagImportPreview{source: source}
+	for _, in := range incoming {
+		if i := slices.IndexFunc(existing, func(c FlagCondition) bool { return sameFlagCondition(c, in) }); i >= 0 {
+			if existing[i].Label == in.Label {
+				preview.duplicates = append(preview.duplicates, in)
+			} else {
+				preview.changed = append(preview.changed, flagImportChange{existing: existing[i], incoming: in})
+			}
+			continue
+		}
+		if i := slices.IndexFunc(existing, func(c FlagCondition) bool { return c.Label == in.Label }); i >= 0 {
+			preview.changed = append(preview.changed, flagImportChange{existing: existing[i], incoming: in})
+			continue
+		}
+		preview.added = append(preview.added, in)
+	}
+	return preview
+}
+
+// prompt is the confirmation shown before merging: what is new, what
+// changes, and what is already there.
+func (p flagImportPreview) prompt() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Import flag conditions from %s?\n", p.source)
+
+	section := func(title string, lines []string) {
+		if len(lines) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "\n%s (%d):\n", title, len(lines))
+		for i, l := range lines {
+			if i == flagImportPreviewLimit {
+				fmt.Fprintf(&b, "  … and %d more\n", len(lines)-i)
+				break
+			}
+			fmt.Fprintf(&b, "  %s\n", l)
+		}
+	}
+
+	var added, changed, dups []string
+	for _, c := range p.added {
+		added = append(added, "+ "+c.Label)
+	}
+	for _, c := range p.changed {
+		if sameFlagCondition(c.existing, c.incoming) {
+			changed = append(changed, fmt.Sprintf("~ #%d label: %s → %s", c.existing.ID, c.existing.Label, c.incoming.Label))
+		} else {
+			changed = append(changed, fmt.Sprintf("~ #%d %s → %s", c.existing.ID, flagConditionSource(c.existing), flagConditionSource(c.incoming)))
+		}
+	}
+	for _, c := range p.duplicates {
+		dups = append(dups, "= "+c.Label)
+	}
+	section("New", added)
+	section("Changed", changed)
+	section("Already present, skipped", dups)
+
+	b.WriteString("\n[y/n]")
+	return b.String()
+}
+
+// applyFlagImport adds the new conditions and updates the changed ones in
+// place, keeping their IDs and actions.
+func (m *model) applyFlagImport(p flagImportPreview) {
+	for _, c := range p.changed {
+		if i := slices.IndexFunc(m.flagConditions, func(fc FlagCondition) bool { return fc.ID == c.existing.ID }); i >= 0 {
+			m.flagConditions[i].Type = c.incoming.Type
+			m.flagConditions[i].Pattern = c.incoming.Pattern
+			m.flagConditions[i].Label = c.incoming.Label
+		}
+	}
+	for _, c := range p.added {
+		m.flagNextID++
+		c.ID = m.flagNextID
+		c.CreatedAt = time.Now()
+		m.flagConditions = append(m.flagConditions, c)
+	}
+	m.rebuildFlagMatchCache()
+}
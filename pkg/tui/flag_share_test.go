@@ -0,0 +1,173 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/table"
+	pkgconfig "github.com/clcollins/srepd/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFlagsCommand_ExportImport(t *testing.T) {
+	cmd, err := parseFlagCommand(":flags export /tmp/team flags.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, flagCmdExport, cmd.action)
+	assert.Equal(t, "/tmp/team flags.yaml", cmd.path)
+
+	cmd, err = parseFlagCommand(":flags import https://example.com/vip.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, flagCmdImport, cmd.action)
+	assert.Equal(t, "https://example.com/vip.yaml", cmd.path)
+
+	_, err = parseFlagCommand(":flags export")
+	assert.ErrorContains(t, err, "usage: :flags export <file>")
+	_, err = parseFlagCommand(":flags import")
+	assert.ErrorContains(t, err, "usage: :flags import")
+}
+
+func TestExportFlags_RoundTripWithoutActions(t *testing.T) {
+	conds := []FlagCondition{
+		{ID: 1, Type: FlagOrgName, Pattern: "Acme", Label: "VIP: Acme", Actions: []FlagAction{{Kind: FlagActionNotify}}},
+		{ID: 2, Type: FlagExpression, Pattern: "hypershift true AND NOT region us-east-1", Label: "HCP outside us-east-1"},
+	}
+	path := filepath.Join(t.TempDir(), "team", "flags.yaml")
+
+	msg := exportFlagsCmd(conds, path)().(flagsExportedMsg)
+	require.NoError(t, msg.err)
+	assert.Equal(t, 2, msg.count)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "notify", "actions are never exported")
+
+	p, err := pkgconfig.ParsePreset(data, path)
+	require.NoError(t, err, "an export is a valid preset")
+	assert.Equal(t, []pkgconfig.PresetFlag{
+		{Condition: "org Acme", Label: "VIP: Acme"},
+		{Condition: "hypershift true AND NOT region us-east-1", Label: "HCP outside us-east-1"},
+	}, p.Flags)
+
+	imported, err := parseFlagImport(data, path)
+	require.NoError(t, err)
+	require.Len(t, imported, 2)
+	for i := range conds {
+		assert.True(t, sameFlagCondition(conds[i], imported[i]))
+		assert.Equal(t, conds[i].Label, imported[i].Label)
+		assert.Empty(t, imported[i].Actions)
+	}
+}
+
+func TestParseFlagImport(t *testing.T) {
+	t.Run("preset without labels", func(t *testing.T) {
+		conds, err := parseFlagImport([]byte("flags:\n  - condition: org Acme\n"), "team.yaml")
+		require.NoError(t, err)
+		require.Len(t, conds, 1)
+		assert.Equal(t, `org name matches "Acme"`, conds[0].Label, "a missing label is generated")
+	})
+
+	t.Run("saved flags.json drops actions", func(t *testing.T) {
+		saved := `[{"id": 7, "type": 1, "pattern": "Acme", "label": "VIP", "actions": [{"kind": "note", "value": "x"}]}]`
+		conds, err := parseFlagImport([]byte(saved), "flags.json")
+		require.NoError(t, err)
+		require.Len(t, conds, 1)
+		assert.Zero(t, conds[0].ID)
+		assert.Empty(t, conds[0].Actions)
+		assert.Equal(t, "VIP", conds[0].Label)
+	})
+
+	for name, tt := range map[string]struct{ data, want string }{
+		"invalid condition": {"flags:\n  - condition: bogus x\n", `flag 1 (bogus x): unknown flag type "bogus"`},
+		"actions in preset": {"flags:\n  - condition: org Acme\n    actions: [notify]\n", "never actions"},
+		"no flags":          {"teams: [T1]\n", "no flag conditions in team.yaml"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseFlagImport([]byte(tt.data), "team.yaml")
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+}
+
+func TestPreviewFlagImport(t *testing.T) {
+	existing := []FlagCondition{
+		{ID: 1, Type: FlagOrgName, Pattern: "Acme", Label: "VIP: Acme"},
+		{ID: 2, Type: FlagOrgName, Pattern: "Initech", Label: "Initech"},
+		{ID: 3, Type: FlagRegion, Pattern: "us-east-1", Label: "East"},
+	}
+	incoming := []FlagCondition{
+		{Type: FlagOrgName, Pattern: "Acme", Label: "VIP: Acme"},
+		{Type: FlagOrgName, Pattern: "Initech", Label: "VIP: Initech"},
+		{Type: FlagRegion, Pattern: "us-east-2", Label: "East"},
+		{Type: FlagCloudProvider, Pattern: "gcp", Label: "GCP"},
+	}
+
+	p := previewFlagImport(existing, incoming, "team.yaml")
+
+	require.Len(t, p.duplicates, 1)
+	assert.Equal(t, "Acme", p.duplicates[0].Pattern)
+	require.Len(t, p.changed, 2)
+	assert.Equal(t, 2, p.changed[0].existing.ID, "same condition, new label")
+	assert.Equal(t, 3, p.changed[1].existing.ID, "same label, new condition")
+	require.Len(t, p.added, 1)
+	assert.Equal(t, "gcp", p.added[0].Pattern)
+
+	prompt := p.prompt()
+	assert.Contains(t, prompt, "Import flag conditions from team.yaml?")
+	assert.Contains(t, prompt, "New (1):\n  + GCP")
+	assert.Contains(t, prompt, "~ #2 label: Initech → VIP: Initech")
+	assert.Contains(t, prompt, "~ #3 region us-east-1 → region us-east-2")
+	assert.Contains(t, prompt, "Already present, skipped (1):\n  = VIP: Acme")
+	assert.True(t, strings.HasSuffix(prompt, "[y/n]"))
+}
+
+func TestFlagImportPreview_PromptCapsSections(t *testing.T) {
+	var incoming []FlagCondition
+	for i := range flagImportPreviewLimit + 3 {
+		incoming = append(incoming, FlagCondition{Type: FlagClusterID, Pattern: strings.Repeat("c", i+1), Label: strings.Repeat("c", i+1)})
+	}
+	prompt := previewFlagImport(nil, incoming, "big.yaml").prompt()
+	assert.Contains(t, prompt, "New (11):")
+	assert.Contains(t, prompt, "… and 3 more")
+}
+
+func TestFlagImport_ConfirmAndApply(t *testing.T) {
+	m, _ := flagActionTestModel(t, FlagAction{Kind: FlagActionPin})
+	m.table.SetColumns([]table.Column{{Title: "ID", Width: 10}, {Title: "Summary", Width: 40}})
+	m.flagNextID = 1
+
+	result, _ := m.Update(flagsImportFetchedMsg{source: "team.yaml", conditions: []FlagCondition{
+		{Type: FlagOrgName, Pattern: "Acme", Label: "VIP: Acme"},
+		{Type: FlagOrgName, Pattern: "Initech", Label: "VIP: Initech"},
+	}})
+	m = result.(model)
+	require.NotNil(t, m.pendingConfirmation)
+	assert.Len(t, m.flagConditions, 1, "nothing changes before the user confirms")
+
+	msg, ok := m.pendingConfirmation.action().(applyFlagImportMsg)
+	require.True(t, ok)
+	result, _ = m.Update(msg)
+	m = result.(model)
+
+	require.Len(t, m.flagConditions, 2)
+	assert.Equal(t, "VIP: Acme", m.flagConditions[0].Label)
+	assert.Equal(t, []FlagAction{{Kind: FlagActionPin}}, m.flagConditions[0].Actions, "a changed condition keeps its actions")
+	assert.Equal(t, 2, m.flagConditions[1].ID)
+	assert.False(t, m.flagConditions[1].CreatedAt.IsZero())
+	assert.Equal(t, []int{1}, m.flagMatchCache["VIP1"])
+	assert.Equal(t, []int{2}, m.flagMatchCache["OTHER"])
+}
+
+func TestFlagImport_NothingNew(t *testing.T) {
+	m, _ := flagActionTestModel(t)
+
+	result, _ := m.Update(flagsImportFetchedMsg{source: "team.yaml", conditions: []FlagCondition{
+		{Type: FlagOrgName, Pattern: "Acme", Label: `org name matches "Acme"`},
+	}})
+	m = result.(model)
+
+	assert.Nil(t, m.pendingConfirmation)
+	assert.Contains(t, m.status, "nothing to import: all 1 flag conditions already present")
+}
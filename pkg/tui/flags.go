@@ -2,20 +2,108 @@ package tui
 
 import (
 	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/PagerDuty/go-pagerduty"
+	"github.com/clcollins/srepd/pkg/alert"
 	"github.com/clcollins/srepd/pkg/ocm"
 )
 
-// FlagConditionType identifies the kind of flag condition.
+// FlagConditionType identifies the kind of flag condition. The values are
+// persisted in flags.json, so new types are only ever appended.
 type FlagConditionType int
 
 const (
 	FlagClusterID FlagConditionType = iota
 	FlagOrgName
+	FlagCloudProvider
+	FlagVersion
+	FlagRegion
+	FlagHypershift
+	FlagCCS
+	FlagClusterState
+	FlagAlertName
+	FlagAlertType
+	FlagSeverity
+	FlagNamespace
+	FlagTitle
+	FlagService
+	// FlagExpression combines the other types with AND, OR and NOT; its
+	// Pattern holds the expression text (see flag_expr.go).
+	FlagExpression
 )
 
+// flagPatternKind is how a condition's Pattern is interpreted.
+type flagPatternKind int
+
+const (
+	flagPatternGlob  flagPatternKind = iota // matchGlob
+	flagPatternRegex                        // case-insensitive regular expression
+	flagPatternBool                         // "true" or "false"
+)
+
+// flagTypeSpec describes one `:flag <keyword>` condition type.
+type flagTypeSpec struct {
+	Type    FlagConditionType
+	Keyword string
+	Kind    flagPatternKind
+	Arg     string // argument placeholder shown in usage
+	Subject string // what the pattern is matched against, for labels
+	Help    string
+}
+
+var flagTypeSpecs = []flagTypeSpec{
+	{FlagClusterID, "cluster", flagPatternGlob, "<id>", "cluster", "flag incidents by cluster ID"},
+	{FlagOrgName, "org", flagPatternGlob, "<pattern>", "org name", "flag incidents by org name"},
+	{FlagCloudProvider, "cloud", flagPatternGlob, "<pattern>", "cloud provider", "flag incidents by cloud provider (aws, gcp)"},
+	{FlagVersion, "version", flagPatternGlob, "<pattern>", "version", "flag incidents by OpenShift version"},
+	{FlagRegion, "region", flagPatternGlob, "<pattern>", "region", "flag incidents by cloud region"},
+	{FlagHypershift, "hypershift", flagPatternBool, "[true|false]", "hypershift", "flag incidents on HCP clusters"},
+	{FlagCCS, "ccs", flagPatternBool, "[true|false]", "CCS", "flag incidents on CCS clusters"},
+	{FlagClusterState, "state", flagPatternGlob, "<pattern>", "cluster state", "flag incidents by cluster state"},
+	{FlagAlertName, "alert", flagPatternGlob, "<pattern>", "alert name", "flag incidents by alert name"},
+	{FlagAlertType, "alert-type", flagPatternGlob, "<pattern>", "alert type", "flag incidents by alert type"},
+	{FlagSeverity, "severity", flagPatternGlob, "<pattern>", "severity", "flag incidents by alert severity"},
+	{FlagNamespace, "namespace", flagPatternGlob, "<pattern>", "namespace", "flag incidents by alert namespace"},
+	{FlagTitle, "title", flagPatternRegex, "<regex>", "title", "flag incidents whose title matches a regex"},
+	{FlagService, "service", flagPatternRegex, "<regex>", "service", "flag incidents whose service matches a regex"},
+}
+
+// flagSpecForType returns the spec of a condition type.
+func flagSpecForType(t FlagConditionType) (flagTypeSpec, bool) {
+	for _, s := range flagTypeSpecs {
+		if s.Type == t {
+			return s, true
+		}
+	}
+	return flagTypeSpec{}, false
+}
+
+// flagSpecForKeyword returns the spec behind `:flag <keyword>`.
+func flagSpecForKeyword(keyword string) (flagTypeSpec, bool) {
+	for _, s := range flagTypeSpecs {
+		if s.Keyword == keyword {
+			return s, true
+		}
+	}
+	return flagTypeSpec{}, false
+}
+
+// flagKeywords lists the `:flag` keywords for usage messages.
+func flagKeywords() string {
+	keywords := make([]string, 0, len(flagTypeSpecs))
+	for _, s := range flagTypeSpecs {
+		keywords = append(keywords, s.Keyword)
+	}
+	return strings.Join(keywords, ", ")
+}
+
 const (
 	emojiFlagMarker   = "🚩 "
 	noEmojiFlagMarker = "|► "
@@ -28,6 +116,7 @@ type FlagCondition struct {
 	Pattern   string            `json:"pattern"`
 	Label     string            `json:"label"`
 	CreatedAt time.Time         `json:"created_at"`
+	Actions   []FlagAction      `json:"actions,omitempty"`
 }
 
 // matchGlob matches a value against a simple glob pattern.
@@ -72,13 +161,65 @@ func matchGlob(pattern, value string) bool {
 	return strings.Contains(lowerValue, lowerCore)
 }
 
+// flagSubject is what flag conditions are matched against for one incident.
+// Alert conditions need the incident's alerts to be loaded; cluster
+// conditions beyond the raw ID need OCM enrichment.
+type flagSubject struct {
+	title      string
+	service    string
+	clusterIDs []string
+	alerts     []alert.NormalizedAlert
+}
+
 // evaluateFlags checks all incidents against all flag conditions and returns
-// a map of incident ID to the IDs of matching conditions.
+// a map of incident ID to the IDs of matching conditions. Only cluster
+// information is known for the incidents; see evaluateIncidentFlags.
 func evaluateFlags(
 	incidents []string,
 	conditions []FlagCondition,
 	incidentClusterMap map[string][]string,
 	clusterCache map[string]*ocm.ClusterInfo,
+) map[string][]int {
+	subjects := make(map[string]flagSubject, len(incidents))
+	for _, id := range incidents {
+		subjects[id] = flagSubject{clusterIDs: incidentClusterMap[id]}
+	}
+	return evaluateFlagSubjects(incidents, subjects, conditions, clusterCache)
+}
+
+// evaluateIncidentFlags is evaluateFlags with the incidents' titles,
+// services and cached alerts available to the conditions.
+func evaluateIncidentFlags(
+	incidents []pagerduty.Incident,
+	conditions []FlagCondition,
+	incidentClusterMap map[string][]string,
+	clusterCache map[string]*ocm.ClusterInfo,
+	incidentCache map[string]*cachedIncidentData,
+) map[string][]int {
+	ids := make([]string, 0, len(incidents))
+	subjects := make(map[string]flagSubject, len(incidents))
+	for _, inc := range incidents {
+		ids = append(ids, inc.ID)
+		subject := flagSubject{
+			title:      inc.Title,
+			service:    inc.Service.Summary,
+			clusterIDs: incidentClusterMap[inc.ID],
+		}
+		if cached, ok := incidentCache[inc.ID]; ok && cached != nil && cached.alertsLoaded {
+			for _, a := range cached.alerts {
+				subject.alerts = append(subject.alerts, alert.NormalizeAlert(a.Service.Summary, inc.Title, a))
+			}
+		}
+		subjects[inc.ID] = subject
+	}
+	return evaluateFlagSubjects(ids, subjects, conditions, clusterCache)
+}
+
+func evaluateFlagSubjects(
+	incidents []string,
+	subjects map[string]flagSubject,
+	conditions []FlagCondition,
+	clusterCache map[string]*ocm.ClusterInfo,
 ) map[string][]int {
 	if len(conditions) == 0 {
 		return nil
@@ -87,9 +228,9 @@ func evaluateFlags(
 	result := make(map[string][]int)
 
 	for _, incidentID := range incidents {
-		clusterIDs := incidentClusterMap[incidentID]
+		subject := subjects[incidentID]
 		for _, cond := range conditions {
-			if matchCondition(cond, clusterIDs, clusterCache) {
+			if matchCondition(cond, subject, clusterCache) {
 				result[incidentID] = append(result[incidentID], cond.ID)
 			}
 		}
@@ -98,17 +239,140 @@ func evaluateFlags(
 	return result
 }
 
-func matchCondition(cond FlagCondition, clusterIDs []string, clusterCache map[string]*ocm.ClusterInfo) bool {
-	switch cond.Type {
+func matchCondition(cond FlagCondition, subject flagSubject, clusterCache map[string]*ocm.ClusterInfo) bool {
+	if cond.Type == FlagExpression {
+		expr, err := compileFlagExpr(cond.Pattern)
+		if err != nil {
+			return false
+		}
+		return expr.match(subject, clusterCache)
+	}
+	return matchFlagTerm(cond.Type, cond.Pattern, subject, clusterCache)
+}
+
+// matchFlagTerm matches a single condition of type t against the subject.
+func matchFlagTerm(t FlagConditionType, pattern string, subject flagSubject, clusterCache map[string]*ocm.ClusterInfo) bool {
+	clusterIDs := subject.clusterIDs
+	switch t {
 	case FlagClusterID:
-		return matchClusterID(cond.Pattern, clusterIDs, clusterCache)
+		return matchClusterID(pattern, clusterIDs, clusterCache)
 	case FlagOrgName:
-		return matchOrgName(cond.Pattern, clusterIDs, clusterCache)
+		return matchOrgName(pattern, clusterIDs, clusterCache)
+	case FlagCloudProvider:
+		return matchClusterField(clusterIDs, clusterCache, func(c *ocm.ClusterInfo) bool {
+			return c.CloudProvider != "" && matchGlob(pattern, c.CloudProvider)
+		})
+	case FlagVersion:
+		return matchClusterField(clusterIDs, clusterCache, func(c *ocm.ClusterInfo) bool {
+			return c.Version != "" && matchGlob(pattern, c.Version)
+		})
+	case FlagRegion:
+		return matchClusterField(clusterIDs, clusterCache, func(c *ocm.ClusterInfo) bool {
+			return c.Region != "" && matchGlob(pattern, c.Region)
+		}) || matchAlertField(subject.alerts, func(a alert.NormalizedAlert) string { return a.Region }, pattern)
+	case FlagHypershift:
+		want := pattern != "false"
+		return matchClusterField(clusterIDs, clusterCache, func(c *ocm.ClusterInfo) bool { return c.Hypershift == want })
+	case FlagCCS:
+		want := pattern != "false"
+		return matchClusterField(clusterIDs, clusterCache, func(c *ocm.ClusterInfo) bool { return c.CCS == want })
+	case FlagClusterState:
+		return matchClusterField(clusterIDs, clusterCache, func(c *ocm.ClusterInfo) bool {
+			return c.State != "" && matchGlob(pattern, c.State)
+		})
+	case FlagAlertName:
+		return matchAlertField(subject.alerts, func(a alert.NormalizedAlert) string { return a.AlertName }, pattern)
+	case FlagAlertType:
+		return matchAlertField(subject.alerts, func(a alert.NormalizedAlert) string { return a.AlertType }, pattern)
+	case FlagSeverity:
+		return matchAlertField(subject.alerts, func(a alert.NormalizedAlert) string { return a.Severity }, pattern)
+	case FlagNamespace:
+		return matchAlertField(subject.alerts, func(a alert.NormalizedAlert) string { return a.Namespace }, pattern)
+	case FlagTitle:
+		return matchRegex(pattern, subject.title)
+	case FlagService:
+		return matchRegex(pattern, subject.service)
 	default:
 		return false
 	}
 }
 
+// matchClusterField reports whether any of the incident's enriched clusters
+// satisfies match. Clusters OCM has not described yet never match.
+func matchClusterField(clusterIDs []string, clusterCache map[string]*ocm.ClusterInfo, match func(*ocm.ClusterInfo) bool) bool {
+	for _, cid := range clusterIDs {
+		if info, ok := clusterCache[cid]; ok && info != nil && match(info) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchAlertField reports whether any alert's field, as picked by field,
+// matches the glob pattern. Empty fields never match.
+func matchAlertField(alerts []alert.NormalizedAlert, field func(alert.NormalizedAlert) string, pattern string) bool {
+	for _, a := range alerts {
+		if v := field(a); v != "" && matchGlob(pattern, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// flagRegexCache holds compiled title/service patterns; flag matching runs
+// on every incident list update.
+var flagRegexCache sync.Map
+
+// compileFlagRegex compiles a title/service pattern, case-insensitively.
+func compileFlagRegex(pattern string) (*regexp.Regexp, error) {
+	if re, ok := flagRegexCache.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		return nil, err
+	}
+	flagRegexCache.Store(pattern, re)
+	return re, nil
+}
+
+// matchRegex matches value against a title/service pattern. A pattern that
+// does not compile (e.g. hand-edited in flags.json) never matches.
+func matchRegex(pattern, value string) bool {
+	if value == "" {
+		return false
+	}
+	re, err := compileFlagRegex(pattern)
+	if err != nil {
+		return false
+	}
+	return re.MatchString(value)
+}
+
+// flagLabel is the human-readable description of a condition.
+func flagLabel(spec flagTypeSpec, pattern string) string {
+	switch spec.Kind {
+	case flagPatternRegex:
+		return fmt.Sprintf("%s matches /%s/", spec.Subject, pattern)
+	case flagPatternBool:
+		return fmt.Sprintf("%s is %s", spec.Subject, pattern)
+	default:
+		return fmt.Sprintf("%s matches %q", spec.Subject, pattern)
+	}
+}
+
+// parseFlagBool reads the optional argument of a boolean condition.
+func parseFlagBool(value string) (string, error) {
+	if value == "" {
+		return "true", nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return "", fmt.Errorf("expected true or false, got %q", value)
+	}
+	return strconv.FormatBool(b), nil
+}
+
 func matchClusterID(pattern string, clusterIDs []string, clusterCache map[string]*ocm.ClusterInfo) bool {
 	for _, cid := range clusterIDs {
 		if matchGlob(pattern, cid) {
@@ -157,7 +421,11 @@ func (m model) renderFlagConditionsSection() string {
 	for _, flagID := range matched {
 		for _, cond := range m.flagConditions {
 			if cond.ID == flagID {
-				fmt.Fprintf(&b, "* %s#%d: %s\n", m.flagMarker, cond.ID, cond.Label)
+				fmt.Fprintf(&b, "* %s#%d: %s", m.flagMarker, cond.ID, cond.Label)
+				if len(cond.Actions) > 0 {
+					fmt.Fprintf(&b, " → %s", formatFlagActions(cond.Actions))
+				}
+				b.WriteString("\n")
 				break
 			}
 		}
@@ -167,38 +435,43 @@ func (m model) renderFlagConditionsSection() string {
 
 func formatFlagsList(conditions []FlagCondition) string {
 	if len(conditions) == 0 {
-		return "No active flag conditions.\n\nUse `:flag cluster <id>` or `:flag org <pattern>` to add one."
+		return "No active flag conditions.\n\nUse `:flag <type> <value>` to add one (types: " + flagKeywords() + ")."
 	}
 
 	var b strings.Builder
 	b.WriteString("# Active Flag Conditions\n\n")
 	for _, c := range conditions {
 		typeName := "unknown"
-		switch c.Type {
-		case FlagClusterID:
-			typeName = "cluster"
-		case FlagOrgName:
-			typeName = "org"
+		if spec, ok := flagSpecForType(c.Type); ok {
+			typeName = spec.Keyword
+		} else if c.Type == FlagExpression {
+			typeName = "expression"
 		}
-		fmt.Fprintf(&b, "* **#%d** [%s] %s\n", c.ID, typeName, c.Label)
+		fmt.Fprintf(&b, "* **#%d** [%s] %s", c.ID, typeName, c.Label)
+		if len(c.Actions) > 0 {
+			fmt.Fprintf(&b, " → %s", formatFlagActions(c.Actions))
+		}
+		b.WriteString("\n")
 	}
 	b.WriteString("\nUse `:unflag <id>` to remove, `:unflag all` to clear.")
 	return b.String()
 }
 
-func (m *model) rebuildFlagMatchCache() {
+// rebuildFlagMatchCache re-evaluates the flag conditions and reports
+// whether any incident's matches changed.
+func (m *model) rebuildFlagMatchCache() bool {
+	previous := m.flagMatchCache
 	if len(m.flagConditions) == 0 {
 		m.flagMatchCache = nil
-		return
-	}
-	var incidentIDs []string
-	for _, inc := range m.incidentList {
-		incidentIDs = append(incidentIDs, inc.ID)
-	}
-	m.flagMatchCache = evaluateFlags(
-		incidentIDs,
-		m.flagConditions,
-		m.incidentClusterMap,
-		m.clusterCache,
-	)
+	} else {
+		m.flagMatchCache = evaluateIncidentFlags(
+			m.incidentList,
+			m.flagConditions,
+			m.incidentClusterMap,
+			m.clusterCache,
+			m.incidentCache,
+		)
+	}
+	m.queueFlagActions()
+	return !maps.EqualFunc(previous, m.flagMatchCache, slices.Equal[[]int])
 }
@@ -604,3 +604,118 @@ func TestFormatFlagsList_Empty(t *testing.T) {
 		assert.Contains(t, content, "No active flag conditions")
 	})
 }
+
+func TestEvaluateIncidentFlags_ClusterInfoConditions(t *testing.T) {
+	incidents := []pagerduty.Incident{
+		{APIObject: pagerduty.APIObject{ID: "HCP"}},
+		{APIObject: pagerduty.APIObject{ID: "CLASSIC"}},
+		{APIObject: pagerduty.APIObject{ID: "UNENRICHED"}},
+	}
+	clusterMap := map[string][]string{
+		"HCP":        {"c-hcp"},
+		"CLASSIC":    {"c-classic"},
+		"UNENRICHED": {"c-unknown"},
+	}
+	clusterCache := map[string]*ocm.ClusterInfo{
+		"c-hcp":     {ID: "c-hcp", CloudProvider: "aws", Version: "4.14.3", Region: "ap-southeast-2", Hypershift: true, State: "ready"},
+		"c-classic": {ID: "c-classic", CloudProvider: "gcp", Version: "4.15.1", Region: "us-east1", CCS: true, State: "installing"},
+	}
+
+	tests := []struct {
+		name    string
+		cond    FlagCondition
+		matches []string
+	}{
+		{"cloud", FlagCondition{Type: FlagCloudProvider, Pattern: "gcp"}, []string{"CLASSIC"}},
+		{"version prefix", FlagCondition{Type: FlagVersion, Pattern: "^4.14."}, []string{"HCP"}},
+		{"region", FlagCondition{Type: FlagRegion, Pattern: "ap-southeast-2"}, []string{"HCP"}},
+		{"hypershift", FlagCondition{Type: FlagHypershift, Pattern: "true"}, []string{"HCP"}},
+		{"not hypershift", FlagCondition{Type: FlagHypershift, Pattern: "false"}, []string{"CLASSIC"}},
+		{"ccs", FlagCondition{Type: FlagCCS, Pattern: "true"}, []string{"CLASSIC"}},
+		{"state", FlagCondition{Type: FlagClusterState, Pattern: "install*"}, []string{"CLASSIC"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cond.ID = 1
+			result := evaluateIncidentFlags(incidents, []FlagCondition{tt.cond}, clusterMap, clusterCache, nil)
+			var got []string
+			for _, inc := range incidents {
+				if len(result[inc.ID]) > 0 {
+					got = append(got, inc.ID)
+				}
+			}
+			assert.Equal(t, tt.matches, got, "clusters OCM has not described never match")
+		})
+	}
+}
+
+func TestEvaluateIncidentFlags_AlertAndIncidentConditions(t *testing.T) {
+	incidents := []pagerduty.Incident{
+		{APIObject: pagerduty.APIObject{ID: "ETCD"}, Title: "etcdMembersDown CRITICAL (1)", Service: pagerduty.APIObject{Summary: "osd-prod-hive"}},
+		{APIObject: pagerduty.APIObject{ID: "NODE"}, Title: "KubeNodeNotReady", Service: pagerduty.APIObject{Summary: "rhobs-hcp"}},
+		{APIObject: pagerduty.APIObject{ID: "NOALERTS"}, Title: "etcd something", Service: pagerduty.APIObject{Summary: "osd-prod-hive"}},
+	}
+	cache := alertsCache(map[string][]pagerduty.IncidentAlert{
+		"ETCD": {hiveAlert("etcdMembersDown", "c1", "openshift-etcd")},
+		"NODE": {hiveAlert("KubeNodeNotReady", "c2", "")},
+	})
+
+	tests := []struct {
+		name    string
+		cond    FlagCondition
+		matches []string
+	}{
+		{"alert name glob", FlagCondition{Type: FlagAlertName, Pattern: "etcd*"}, []string{"ETCD"}},
+		{"alert type", FlagCondition{Type: FlagAlertType, Pattern: "osd_hive"}, []string{"ETCD", "NODE"}},
+		{"namespace", FlagCondition{Type: FlagNamespace, Pattern: "openshift-etcd"}, []string{"ETCD"}},
+		{"title regex", FlagCondition{Type: FlagTitle, Pattern: `^etcd\w+ CRITICAL`}, []string{"ETCD"}},
+		{"title regex is case-insensitive", FlagCondition{Type: FlagTitle, Pattern: "^ETCD"}, []string{"ETCD", "NOALERTS"}},
+		{"service regex", FlagCondition{Type: FlagService, Pattern: "hive$"}, []string{"ETCD", "NOALERTS"}},
+		{"invalid regex never matches", FlagCondition{Type: FlagService, Pattern: "("}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cond.ID = 1
+			result := evaluateIncidentFlags(incidents, []FlagCondition{tt.cond}, nil, nil, cache)
+			var got []string
+			for _, inc := range incidents {
+				if len(result[inc.ID]) > 0 {
+					got = append(got, inc.ID)
+				}
+			}
+			assert.Equal(t, tt.matches, got)
+		})
+	}
+}
+
+func TestEvaluateIncidentFlags_Severity(t *testing.T) {
+	incidents := []pagerduty.Incident{
+		{APIObject: pagerduty.APIObject{ID: "INC001"}, Title: "etcdMembersDown CRITICAL (1)"},
+		{APIObject: pagerduty.APIObject{ID: "INC002"}, Title: "KubeNodeNotReady WARNING (1)"},
+	}
+	cache := alertsCache(map[string][]pagerduty.IncidentAlert{
+		"INC001": {hiveAlert("etcdMembersDown", "c1", "")},
+		"INC002": {hiveAlert("KubeNodeNotReady", "c1", "")},
+	})
+
+	result := evaluateIncidentFlags(incidents, []FlagCondition{{ID: 1, Type: FlagSeverity, Pattern: "critical"}}, nil, nil, cache)
+	assert.Equal(t, []int{1}, result["INC001"])
+	assert.Empty(t, result["INC002"])
+}
+
+func TestRebuildFlagMatchCache_ReportsChanges(t *testing.T) {
+	m := createTestModel()
+	m.incidentList = []pagerduty.Incident{{APIObject: pagerduty.APIObject{ID: "INC001"}}}
+	m.flagConditions = []FlagCondition{{ID: 1, Type: FlagAlertName, Pattern: "etcd*"}}
+
+	assert.False(t, m.rebuildFlagMatchCache(), "no alerts loaded, nothing matches")
+
+	m.incidentCache = alertsCache(map[string][]pagerduty.IncidentAlert{"INC001": {hiveAlert("etcdMembersDown", "c1", "")}})
+	assert.True(t, m.rebuildFlagMatchCache())
+	assert.False(t, m.rebuildFlagMatchCache(), "unchanged matches")
+}
+
+func TestFormatFlagsList_NewTypes(t *testing.T) {
+	out := formatFlagsList([]FlagCondition{{ID: 3, Type: FlagVersion, Label: `version matches "4.14*"`}})
+	assert.Contains(t, out, "**#3** [version]")
+}
@@ -24,6 +24,65 @@ type ToolRunnerFactory interface {
 	NewToolRunner(betaTools []anthropic.BetaTool, params anthropic.BetaToolRunnerParams, opts ...option.RequestOption) *anthropic.BetaToolRunner
 }
 
+// toolLoop runs an investigation's bounded tool-using conversation with
+// the gated tools and returns the text of the model's replies.
+type toolLoop interface {
+	run(ctx context.Context, gatedTools []anthropic.BetaTool, model, systemPrompt, userPrompt string, maxTurns int) (string, error)
+}
+
+// anthropicToolLoop runs the conversation with the SDK's BetaToolRunner,
+// using NextMessage for per-turn control.
+type anthropicToolLoop struct {
+	factory ToolRunnerFactory
+}
+
+func (l anthropicToolLoop) run(ctx context.Context, gatedTools []anthropic.BetaTool, model, systemPrompt, userPrompt string, maxTurns int) (string, error) {
+	if model == "" {
+		return "", fmt.Errorf("investigation: model not configured; set llm_api.model or use a provider with a default")
+	}
+
+	toolRunner := l.factory.NewToolRunner(gatedTools, anthropic.BetaToolRunnerParams{
+		BetaMessageNewParams: anthropic.BetaMessageNewParams{
+			Model:     anthropic.Model(model),
+			MaxTokens: 2048,
+			System: []anthropic.BetaTextBlockParam{
+				{Text: systemPrompt},
+			},
+			Messages: []anthropic.BetaMessageParam{
+				anthropic.NewBetaUserMessage(anthropic.NewBetaTextBlock(userPrompt)),
+			},
+		},
+		MaxIterations: maxTurns,
+	})
+
+	var fullText strings.Builder
+	for {
+		msg, err := toolRunner.NextMessage(ctx)
+		if err != nil {
+			return "", err
+		}
+		if msg == nil {
+			return fullText.String(), nil
+		}
+		for _, block := range msg.Content {
+			if block.Type == "text" {
+				fullText.WriteString(block.AsText().Text)
+			}
+		}
+	}
+}
+
+// callerToolLoop runs the conversation with tools.RunLoop for providers
+// that call tools through ai.ToolCaller (OpenAI-compatible and Ollama).
+// They use their own configured model.
+type callerToolLoop struct {
+	caller ai.ToolCaller
+}
+
+func (l callerToolLoop) run(ctx context.Context, gatedTools []anthropic.BetaTool, _ string, systemPrompt, userPrompt string, maxTurns int) (string, error) {
+	return tools.RunLoop(ctx, l.caller, gatedTools, systemPrompt, userPrompt, maxTurns)
+}
+
 // toolAsk captures a tool call that requires user approval during an investigation.
 type toolAsk struct {
 	toolName string
@@ -55,10 +114,10 @@ func defaultInvestigationConfig() investigationConfig {
 }
 
 // watcherInvestigateCmd runs a bounded tool-using investigation in response
-// to a detector observation. It uses the SDK's BetaToolRunner with
-// NextMessage for per-turn control.
+// to a detector observation. Every tool call goes through the policy gate of
+// GatedBetaTools, whichever provider's loop runs the conversation.
 func watcherInvestigateCmd(
-	runner ToolRunnerFactory,
+	loop toolLoop,
 	registry *tools.Registry,
 	cfg investigationConfig,
 	systemPrompt string,
@@ -69,7 +128,7 @@ func watcherInvestigateCmd(
 	incidentIDs []string,
 ) tea.Cmd {
 	return func() tea.Msg {
-		if runner == nil || registry == nil {
+		if loop == nil || registry == nil {
 			return investigationMsg{
 				observation: observation,
 				err:         fmt.Errorf("tool runner or registry not configured"),
@@ -112,57 +171,20 @@ func watcherInvestigateCmd(
 
 		userPrompt := fmt.Sprintf("Observation: %s\n\nContext:\n%s", observation, contextStr)
 
-		messages := []anthropic.BetaMessageParam{
-			anthropic.NewBetaUserMessage(anthropic.NewBetaTextBlock(userPrompt)),
-		}
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.timeout)
+		defer cancel()
 
-		if model == "" {
+		text, err := loop.run(ctx, gatedTools, model, systemPrompt+verdictInstruction, userPrompt, cfg.maxToolTurns)
+		if err != nil {
+			log.Debug("investigation.runner", "error", err)
+			log.Warn("investigation.runner", "error", ai.ClassifyProviderError(err))
 			return investigationMsg{
 				observation: observation,
-				err:         fmt.Errorf("investigation: model not configured; set llm_api.model or use a provider with a default"),
+				err:         err,
 				incidentIDs: incidentIDs,
 			}
 		}
 
-		toolRunner := runner.NewToolRunner(gatedTools, anthropic.BetaToolRunnerParams{
-			BetaMessageNewParams: anthropic.BetaMessageNewParams{
-				Model:     anthropic.Model(model),
-				MaxTokens: 2048,
-				System: []anthropic.BetaTextBlockParam{
-					{Text: systemPrompt + verdictInstruction},
-				},
-				Messages: messages,
-			},
-			MaxIterations: cfg.maxToolTurns,
-		})
-
-		ctx, cancel := context.WithTimeout(context.Background(), cfg.timeout)
-		defer cancel()
-
-		var fullText strings.Builder
-		for {
-			msg, err := toolRunner.NextMessage(ctx)
-			if err != nil {
-				log.Debug("investigation.runner", "error", err)
-				log.Warn("investigation.runner", "error", ai.ClassifyProviderError(err))
-				return investigationMsg{
-					observation: observation,
-					err:         err,
-					incidentIDs: incidentIDs,
-				}
-			}
-			if msg == nil {
-				break
-			}
-
-			for _, block := range msg.Content {
-				if block.Type == "text" {
-					fullText.WriteString(block.AsText().Text)
-				}
-			}
-		}
-
-		text := fullText.String()
 		verdict, _ := tools.ParseWatcherVerdict([]byte(text))
 
 		mu.Lock()
@@ -190,6 +212,25 @@ func extractToolRunnerFactory(provider interface{}) ToolRunnerFactory {
 	return nil
 }
 
+// extractToolLoop returns the tool loop for a provider: the SDK's tool
+// runner for the Anthropic family, tools.RunLoop for providers that
+// implement ai.ToolCaller, or nil when the provider cannot call tools.
+func extractToolLoop(provider ai.Provider) toolLoop {
+	if provider == nil {
+		return nil
+	}
+	if isAnthropicFamily(provider.Name()) {
+		if factory := extractToolRunnerFactory(provider); factory != nil {
+			return anthropicToolLoop{factory: factory}
+		}
+		return nil
+	}
+	if caller := ai.AsToolCaller(provider); caller != nil {
+		return callerToolLoop{caller: caller}
+	}
+	return nil
+}
+
 // isAnthropicFamily returns true if the provider name is an Anthropic-family
 // provider that supports the Tool Runner.
 func isAnthropicFamily(providerName string) bool {
@@ -87,7 +87,7 @@ func TestWatcherInvestigateCmd_DenyEverything_ProductionPath(t *testing.T) {
 	}
 
 	cmd := watcherInvestigateCmd(
-		factory,
+		anthropicToolLoop{factory: factory},
 		reg,
 		cfg,
 		"You are a test system prompt.",
@@ -168,7 +168,7 @@ func TestWatcherInvestigateCmd_AllowPath_HandlerRuns(t *testing.T) {
 	}
 
 	cmd := watcherInvestigateCmd(
-		&client.Beta.Messages,
+		anthropicToolLoop{factory: &client.Beta.Messages},
 		reg,
 		cfg,
 		"Test prompt",
@@ -231,7 +231,7 @@ func TestWatcherInvestigateCmd_ModelPlumbing_ExplicitModel(t *testing.T) {
 	}
 
 	configuredModel := "us.anthropic.claude-sonnet-4-6"
-	cmd := watcherInvestigateCmd(factory, reg, cfg, "test prompt", "obs", "ctx", configuredModel, nil, nil)
+	cmd := watcherInvestigateCmd(anthropicToolLoop{factory: factory}, reg, cfg, "test prompt", "obs", "ctx", configuredModel, nil, nil)
 	msg := cmd()
 	result, ok := msg.(investigationMsg)
 	require.True(t, ok)
@@ -259,7 +259,7 @@ func TestWatcherInvestigateCmd_ModelPlumbing_EmptyModelReturnsError(t *testing.T
 		option.WithBaseURL(server.URL),
 	)
 
-	cmd := watcherInvestigateCmd(&client.Beta.Messages, reg, cfg, "prompt", "obs", "ctx", "", nil, nil)
+	cmd := watcherInvestigateCmd(anthropicToolLoop{factory: &client.Beta.Messages}, reg, cfg, "prompt", "obs", "ctx", "", nil, nil)
 	msg := cmd()
 	result, ok := msg.(investigationMsg)
 	require.True(t, ok)
@@ -324,7 +324,7 @@ func TestWatcherInvestigateCmd_AskDedup_SameToolAndInput(t *testing.T) {
 	}
 
 	cmd := watcherInvestigateCmd(
-		&client.Beta.Messages, reg, cfg,
+		anthropicToolLoop{factory: &client.Beta.Messages}, reg, cfg,
 		"test prompt", "obs", "ctx", "claude-sonnet-4-6", nil, nil,
 	)
 
@@ -414,3 +414,117 @@ func TestExtractToolRunnerFactory_NonNilService_ReturnsUsableFactory(t *testing.
 	assert.NotNil(t, runner,
 		"factory must be able to construct a BetaToolRunner")
 }
+
+// toolCallingServers returns a fake OpenAI-compatible (vLLM) and a fake
+// Ollama server. Each asks for write_note and get_incident on its first
+// turn and answers with a verdict once it has the results.
+func toolCallingServers(t *testing.T) (openaiURL, ollamaURL string) {
+	t.Helper()
+	const verdict = "Done.\n```json\n{\"tier\": \"noteworthy\", \"summary\": \"investigated\"}\n```"
+	hasToolResult := func(r *http.Request) bool {
+		var req struct {
+			Messages []struct {
+				Role string `json:"role"`
+			} `json:"messages"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		return req.Messages[len(req.Messages)-1].Role == "tool"
+	}
+
+	openai := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hasToolResult(r) {
+			_ = json.NewEncoder(w).Encode(map[string]any{"choices": []any{map[string]any{"message": map[string]any{"role": "assistant", "content": verdict}}}})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"choices": []any{map[string]any{"message": map[string]any{
+			"role": "assistant",
+			"tool_calls": []any{
+				map[string]any{"id": "c1", "type": "function", "function": map[string]any{"name": "write_note", "arguments": `{"id":"INC-001","content":"note"}`}},
+				map[string]any{"id": "c2", "type": "function", "function": map[string]any{"name": "get_incident", "arguments": `{"id":"INC-001"}`}},
+			},
+		}}}})
+	}))
+	t.Cleanup(openai.Close)
+
+	ollama := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hasToolResult(r) {
+			_ = json.NewEncoder(w).Encode(map[string]any{"message": map[string]any{"role": "assistant", "content": verdict}, "done": true})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"message": map[string]any{
+			"role": "assistant",
+			"tool_calls": []any{
+				map[string]any{"function": map[string]any{"name": "write_note", "arguments": map[string]any{"id": "INC-001", "content": "note"}}},
+				map[string]any{"function": map[string]any{"name": "get_incident", "arguments": map[string]any{"id": "INC-001"}}},
+			},
+		}, "done": true})
+	}))
+	t.Cleanup(ollama.Close)
+
+	return openai.URL, ollama.URL
+}
+
+// TestWatcherInvestigateCmd_ToolCallingProviders runs the same investigation
+// through vLLM (OpenAI-compatible) and Ollama and expects what the Anthropic
+// runner gives: the read runs, the write is held for approval, and the
+// verdict is parsed.
+func TestWatcherInvestigateCmd_ToolCallingProviders(t *testing.T) {
+	openaiURL, ollamaURL := toolCallingServers(t)
+	for _, cfg := range []ai.Config{
+		{Provider: "openai", Endpoint: openaiURL, Model: "served-model"},
+		{Provider: "ollama", Endpoint: ollamaURL},
+	} {
+		t.Run(cfg.Provider, func(t *testing.T) {
+			provider, err := ai.NewProvider(cfg)
+			require.NoError(t, err)
+			loop := extractToolLoop(provider)
+			require.NotNil(t, loop)
+
+			var reads, writes atomic.Int64
+			reg := tools.NewRegistry()
+			require.NoError(t, reg.Register(tools.Tool{
+				Name:   "get_incident",
+				Class:  policy.ClassRead,
+				Schema: []byte(`{"type":"object","properties":{"id":{"type":"string"}},"required":["id"]}`),
+				Handler: func(_ context.Context, _ json.RawMessage) (string, error) {
+					reads.Add(1)
+					return `{"id":"INC-001"}`, nil
+				},
+			}))
+			require.NoError(t, reg.Register(tools.Tool{
+				Name:   "write_note",
+				Class:  policy.ClassWriteLocal,
+				Schema: []byte(`{"type":"object","properties":{"id":{"type":"string"},"content":{"type":"string"}}}`),
+				Handler: func(_ context.Context, _ json.RawMessage) (string, error) {
+					writes.Add(1)
+					return `{"ok":true}`, nil
+				},
+			}))
+
+			investigation := investigationConfig{maxToolTurns: 6, timeout: 5 * time.Second, policyConfig: policy.Config{Mode: policy.ModeInteractive}}
+			cmd := watcherInvestigateCmd(loop, reg, investigation, "prompt", "obs", "ctx", ai.ResolvedModel(provider), nil, []string{"INC-001"})
+			result, ok := cmd().(investigationMsg)
+			require.True(t, ok)
+			require.NoError(t, result.err)
+
+			assert.Equal(t, int64(1), reads.Load())
+			assert.Zero(t, writes.Load(), "the write waits for approval")
+			require.Len(t, result.toolAsks, 1)
+			assert.Equal(t, "write_note", result.toolAsks[0].toolName)
+			assert.Equal(t, tools.TierNoteworthy, result.verdict.Tier)
+			assert.Equal(t, "investigated", result.verdict.Summary)
+		})
+	}
+}
+
+func TestExtractToolLoop(t *testing.T) {
+	assert.Nil(t, extractToolLoop(nil))
+	assert.Nil(t, extractToolLoop(&ai.MockProvider{ProviderName: "mock"}), "no tool calling")
+
+	svc := anthropic.NewBetaMessageService()
+	assert.IsType(t, anthropicToolLoop{}, extractToolLoop(&realBetaMessagesProvider{MockProvider: ai.MockProvider{ProviderName: "anthropic"}, svc: &svc}))
+
+	ollama, err := ai.NewProvider(ai.Config{Provider: "ollama"})
+	require.NoError(t, err)
+	assert.IsType(t, callerToolLoop{}, extractToolLoop(ollama))
+}
@@ -17,11 +17,11 @@ func (k keymap) FullHelp() [][]key.Binding {
 		// Column 1: Help at top, navigation
 		{k.Help, k.ViewDocs, k.Up, k.Down, k.Top, k.Bottom, k.Enter, k.Back},
 		// Column 2: Primary incident actions
-		{k.Ack, k.Note, k.Login, k.Open, k.SOP, k.UnAck, k.Silence, k.Merge, k.Tag, k.Input},
+		{k.Ack, k.Note, k.Login, k.Open, k.SOP, k.UnAck, k.Silence, k.Merge, k.MergeDups, k.Tag, k.Input},
 		// Column 3: Settings & toggles, Quit at bottom
 		{k.Team, k.Refresh, k.AutoRefresh, k.AutoAck, k.Urgency, k.Watcher, k.ViewLog, k.Quit},
 		// Column 4: Tab navigation (incident viewer)
-		{k.TabNext, k.TabPrev},
+		{k.TabNext, k.TabPrev, k.Prometheus, k.AlertNext, k.AlertPrev, k.ResolveAlert, k.SplitAlert, k.PostOutput, k.LSChange},
 	}
 
 	// Column 4: Chord commands (generated from chordActions registry)
@@ -34,35 +34,43 @@ func (k keymap) FullHelp() [][]key.Binding {
 }
 
 type keymap struct {
-	Up          key.Binding
-	Down        key.Binding
-	Top         key.Binding
-	Bottom      key.Binding
-	Back        key.Binding
-	Enter       key.Binding
-	Quit        key.Binding
-	Help        key.Binding
-	Team        key.Binding
-	Refresh     key.Binding
-	AutoRefresh key.Binding
-	Note        key.Binding
-	Silence     key.Binding
-	Ack         key.Binding
-	UnAck       key.Binding
-	AutoAck     key.Binding
-	Urgency     key.Binding
-	Input       key.Binding
-	Login       key.Binding
-	Open        key.Binding
-	SOP         key.Binding
-	ViewLog     key.Binding
-	Merge       key.Binding
-	Watcher     key.Binding
-	Tag         key.Binding
-	TabNext     key.Binding
-	TabPrev     key.Binding
-	ViewDocs    key.Binding
-	Approvals   key.Binding
+	Up           key.Binding
+	Down         key.Binding
+	Top          key.Binding
+	Bottom       key.Binding
+	Back         key.Binding
+	Enter        key.Binding
+	Quit         key.Binding
+	Help         key.Binding
+	Team         key.Binding
+	Refresh      key.Binding
+	AutoRefresh  key.Binding
+	Note         key.Binding
+	Silence      key.Binding
+	Ack          key.Binding
+	UnAck        key.Binding
+	AutoAck      key.Binding
+	Urgency      key.Binding
+	Input        key.Binding
+	Login        key.Binding
+	Open         key.Binding
+	SOP          key.Binding
+	Prometheus   key.Binding
+	AlertNext    key.Binding
+	AlertPrev    key.Binding
+	ResolveAlert key.Binding
+	SplitAlert   key.Binding
+	PostOutput   key.Binding
+	LSChange     key.Binding
+	ViewLog      key.Binding
+	Merge        key.Binding
+	MergeDups    key.Binding
+	Watcher      key.Binding
+	Tag          key.Binding
+	TabNext      key.Binding
+	TabPrev      key.Binding
+	ViewDocs     key.Binding
+	Approvals    key.Binding
 }
 
 type inputKeymap struct {
@@ -228,6 +236,34 @@ var defaultKeyMap = keymap{
 		key.WithKeys("s"),
 		key.WithHelp("s", "open SOP"),
 	),
+	Prometheus: key.NewBinding(
+		key.WithKeys("p"),
+		key.WithHelp("p", "open Prometheus query"),
+	),
+	AlertNext: key.NewBinding(
+		key.WithKeys("]"),
+		key.WithHelp("]", "next alert / run / LS entry"),
+	),
+	AlertPrev: key.NewBinding(
+		key.WithKeys("["),
+		key.WithHelp("[", "prev alert / run / LS entry"),
+	),
+	ResolveAlert: key.NewBinding(
+		key.WithKeys("R"),
+		key.WithHelp("R", "resolve alert"),
+	),
+	SplitAlert: key.NewBinding(
+		key.WithKeys("S"),
+		key.WithHelp("S", "move alert to new incident"),
+	),
+	PostOutput: key.NewBinding(
+		key.WithKeys("P"),
+		key.WithHelp("P", "post command output as note"),
+	),
+	LSChange: key.NewBinding(
+		key.WithKeys("L"),
+		key.WithHelp("L", "add/remove selected LS entry"),
+	),
 	ViewLog: key.NewBinding(
 		key.WithKeys("ctrl+l"),
 		key.WithHelp("ctrl+l", "view debug log"),
@@ -236,6 +272,10 @@ var defaultKeyMap = keymap{
 		key.WithKeys("m"),
 		key.WithHelp("m", "merge incident"),
 	),
+	MergeDups: key.NewBinding(
+		key.WithKeys("M"),
+		key.WithHelp("M", "merge duplicates"),
+	),
 	Watcher: key.NewBinding(
 		key.WithKeys("w"),
 		key.WithHelp("w", "toggle watcher"),
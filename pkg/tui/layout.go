@@ -6,6 +6,7 @@ import (
 
 	"github.com/charmbracelet/bubbles/help"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
 )
 
 const (
@@ -40,6 +41,11 @@ const (
 
 	layoutMaxClusterIDWidth = 40
 	layoutMaxServiceWidth   = 50
+
+	layoutPaneTabBarLines = 1
+	layoutPaneBorderLines = 2
+	layoutPaneOverhead    = layoutPaneTabBarLines + layoutPaneBorderLines
+	layoutMinPaneRows     = 5
 )
 
 type Layout struct {
@@ -66,6 +72,9 @@ type Layout struct {
 
 	ClusterSelectClusterIDWidth int
 	ClusterSelectServiceWidth   int
+
+	PaneWidth  int
+	PaneHeight int
 }
 
 func computeLayout(ws tea.WindowSizeMsg, styles Styles, helpView string, watcherExpanded bool) Layout {
@@ -185,6 +194,20 @@ func computeLayout(ws tea.WindowSizeMsg, styles Styles, helpView string, watcher
 	}
 }
 
+// withTerminalPane makes room for the embedded terminal below the main
+// view: it takes half the table's rows, and as many from the incident
+// viewer, so a pane keeps its size from one view to the next.
+func withTerminalPane(l Layout, styles Styles) Layout {
+	paneHOverhead := styles.TableContainer.GetHorizontalFrameSize()
+	l.PaneWidth = max(l.ContentWidth-paneHOverhead, 1)
+	l.PaneHeight = max(l.TableHeight/2-layoutPaneOverhead, layoutMinPaneRows)
+
+	paneRows := l.PaneHeight + layoutPaneOverhead
+	l.TableHeight = max(l.TableHeight-paneRows, layoutMinTableHeight)
+	l.IncidentViewerHeight = max(l.IncidentViewerHeight-paneRows, layoutMinTableHeight)
+	return l
+}
+
 func (m *model) recomputeLayout() {
 	mainHOverhead := m.styles.Main.GetHorizontalMargins() +
 		m.styles.Main.GetHorizontalPadding() +
@@ -195,7 +218,7 @@ func (m *model) recomputeLayout() {
 
 	var helpKeyMap help.KeyMap
 	if m.chordHelpActive {
-		helpKeyMap = chordKeymap{prefix: m.chordPrefix}
+		helpKeyMap = chordKeymap{prefix: m.chordPrefix, actions: m.customActions}
 	} else if m.input.Focused() {
 		helpKeyMap = inputModeKeyMap
 	} else {
@@ -205,6 +228,16 @@ func (m *model) recomputeLayout() {
 	helpView := m.help.View(helpKeyMap)
 
 	m.layout = computeLayout(windowSize, m.styles, helpView, m.watcherExpanded)
+	if len(m.panes) > 0 {
+		m.layout = withTerminalPane(m.layout, m.styles)
+		for _, p := range m.panes {
+			if err := p.Resize(m.layout.PaneWidth, m.layout.PaneHeight); err != nil {
+				log.Debug("tui.recomputeLayout(): resizing pane", "pane", p.Title, "error", err)
+			}
+		}
+		m.paneScrollViewport.Width = m.layout.PaneWidth
+		m.paneScrollViewport.Height = m.layout.PaneHeight
+	}
 	m.table.SetHeight(m.layout.TableHeight)
 
 	m.incidentViewer.Width = m.layout.IncidentViewerWidth
@@ -0,0 +1,363 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+	"github.com/clcollins/srepd/pkg/ocm"
+	"github.com/clcollins/srepd/pkg/pd"
+	"github.com/clcollins/srepd/pkg/servicelog"
+)
+
+const (
+	limitedSupportAdd    = "add"
+	limitedSupportRemove = "remove"
+)
+
+type listLimitedSupportTemplatesMsg struct{}
+
+// limitedSupportFinalConfirmMsg asks the second confirmation of a limited
+// support change, after the user has seen the customer-visible text.
+type limitedSupportFinalConfirmMsg struct {
+	prompt string
+	action tea.Cmd
+}
+
+// limitedSupportChangedMsg reports an add or remove. noteErr is set when
+// the change went through but the PD note recording it did not.
+type limitedSupportChangedMsg struct {
+	action      string
+	incidentID  string
+	clusterID   string // PD cluster ID, the OCM cache key
+	clusterName string
+	reason      ocm.LimitedSupportReason
+	noteErr     error
+	err         error
+}
+
+type limitedSupportCommand struct {
+	action   string // "" lists the templates
+	template string
+	cluster  string
+	reasonID string
+}
+
+func isLimitedSupportCommand(input string) bool {
+	trimmed := strings.TrimSpace(input)
+	return trimmed == ":ls" || strings.HasPrefix(trimmed, ":ls ")
+}
+
+// parseLimitedSupportCommand parses `:ls`, `:ls add <template> [cluster]`
+// and `:ls remove <reason-id>`.
+func parseLimitedSupportCommand(input string) (limitedSupportCommand, error) {
+	parts := strings.Fields(input)
+	if len(parts) == 1 {
+		return limitedSupportCommand{}, nil
+	}
+	switch parts[1] {
+	case limitedSupportAdd:
+		switch len(parts) {
+		case 3:
+			return limitedSupportCommand{action: limitedSupportAdd, template: parts[2]}, nil
+		case 4:
+			return limitedSupportCommand{action: limitedSupportAdd, template: parts[2], cluster: parts[3]}, nil
+		}
+		return limitedSupportCommand{}, fmt.Errorf("usage: :ls add <template> [cluster]")
+	case limitedSupportRemove:
+		if len(parts) != 3 {
+			return limitedSupportCommand{}, fmt.Errorf("usage: :ls remove <reason-id>")
+		}
+		return limitedSupportCommand{action: limitedSupportRemove, reasonID: parts[2]}, nil
+	}
+	return limitedSupportCommand{}, fmt.Errorf("usage: :ls [add <template> [cluster] | remove <reason-id>]")
+}
+
+// dispatchLimitedSupportCommand lists the reason templates, or previews an
+// add or remove for the selected incident's cluster.
+func (m *model) dispatchLimitedSupportCommand(input string) tea.Cmd {
+	cmd, err := parseLimitedSupportCommand(input)
+	if err != nil {
+		return m.flashNotification(err.Error())
+	}
+	if cmd.action == "" {
+		return func() tea.Msg { return listLimitedSupportTemplatesMsg{} }
+	}
+	if m.ocmClient == nil {
+		return m.flashNotification("OCM not connected — cannot change limited support")
+	}
+	if m.selectedIncident == nil {
+		return m.flashNotification("no incident selected")
+	}
+	if cmd.action == limitedSupportRemove {
+		return m.confirmRemoveLimitedSupport(cmd.reasonID)
+	}
+	return m.confirmAddLimitedSupport(cmd.template, cmd.cluster)
+}
+
+func (m *model) confirmAddLimitedSupport(name, clusterRef string) tea.Cmd {
+	tmpl, ok := servicelog.LookupReason(name)
+	if !ok {
+		return m.flashNotification(fmt.Sprintf("unknown limited support template %q — :ls lists them", name))
+	}
+	clusterID, info, err := m.incidentCluster(clusterRef, ":ls add <template> <cluster>")
+	if err != nil {
+		return m.flashNotification(err.Error())
+	}
+	reason, err := tmpl.Render(m.templateData(clusterID, info))
+	if err != nil {
+		return m.flashNotification(fmt.Sprintf("limited support template %s: %v", name, err))
+	}
+
+	clusterName := clusterDisplayName(clusterID, info)
+	change := changeLimitedSupport(m.ocmClient, m.config, limitedSupportChangedMsg{
+		action:      limitedSupportAdd,
+		incidentID:  m.selectedIncident.ID,
+		clusterID:   clusterID,
+		clusterName: clusterName,
+		reason:      reason,
+	}, info.ID)
+	m.pendingConfirmation = &confirmActionState{
+		prompt: limitedSupportPreview(limitedSupportAdd, reason, clusterName, info.ID, m.selectedIncident.ID),
+		action: finalLimitedSupportConfirm(
+			fmt.Sprintf("Place %s into limited support now? OCM emails the cluster owner, and SRE support is reduced until the reason is removed. [y/n]", clusterName),
+			change),
+	}
+	return nil
+}
+
+// confirmRemoveLimitedSupport finds the reason among the selected
+// incident's clusters, as shown on the LS History tab.
+func (m *model) confirmRemoveLimitedSupport(reasonID string) tea.Cmd {
+	for _, clusterID := range m.incidentClusterMap[m.selectedIncident.ID] {
+		info := m.clusterCache[clusterID]
+		if info == nil {
+			continue
+		}
+		for _, r := range m.limitedSupportCache[clusterID] {
+			if r.ID != reasonID {
+				continue
+			}
+			name := clusterDisplayName(clusterID, info)
+			change := changeLimitedSupport(m.ocmClient, m.config, limitedSupportChangedMsg{
+				action:      limitedSupportRemove,
+				incidentID:  m.selectedIncident.ID,
+				clusterID:   clusterID,
+				clusterName: name,
+				reason:      r,
+			}, info.ID)
+			m.pendingConfirmation = &confirmActionState{
+				prompt: limitedSupportPreview(limitedSupportRemove, r, name, info.ID, m.selectedIncident.ID),
+				action: finalLimitedSupportConfirm(
+					fmt.Sprintf("Remove reason %s from %s now? The cluster returns to full support once no reasons remain. [y/n]", r.ID, name),
+					change),
+			}
+			return nil
+		}
+	}
+	return m.flashNotification(fmt.Sprintf("no limited support reason %s on incident %s's clusters — see the LS History tab", reasonID, m.selectedIncident.ID))
+}
+
+// lsHistoryEntry is a row of the LS History tab that the LSChange key acts
+// on: a reason to remove, or a template to add to a cluster.
+type lsHistoryEntry struct {
+	clusterID string
+	reason    *ocm.LimitedSupportReason // nil for a template
+	template  string
+}
+
+// key identifies the entry across re-fetches of the history.
+func (e lsHistoryEntry) key() string {
+	if e.reason != nil {
+		return e.reason.ID
+	}
+	return e.clusterID + "/" + e.template
+}
+
+// lsHistoryEntries lists the LS History tab's rows in the order they are
+// rendered: the reasons on the incident's clusters, newest first, then the
+// templates for each cluster.
+func (m model) lsHistoryEntries() []lsHistoryEntry {
+	var entries []lsHistoryEntry
+	clusterIDs := slices.DeleteFunc(m.sortedClusterIDs(), func(id string) bool {
+		return m.clusterCache[id] == nil
+	})
+	for _, id := range clusterIDs {
+		for _, r := range m.limitedSupportCache[id] {
+			entries = append(entries, lsHistoryEntry{clusterID: id, reason: &r})
+		}
+	}
+	slices.SortStableFunc(entries, func(a, b lsHistoryEntry) int {
+		return strings.Compare(b.reason.CreatedAt, a.reason.CreatedAt)
+	})
+	for _, id := range clusterIDs {
+		for _, t := range servicelog.Reasons() {
+			entries = append(entries, lsHistoryEntry{clusterID: id, template: t.Name})
+		}
+	}
+	return entries
+}
+
+// selectedLSIndex returns the position of the LS History tab selection in
+// entries, falling back to the first entry when it is gone.
+func (m model) selectedLSIndex(entries []lsHistoryEntry) int {
+	if i := slices.IndexFunc(entries, func(e lsHistoryEntry) bool {
+		return e.key() == m.selectedLSEntry
+	}); i >= 0 {
+		return i
+	}
+	return 0
+}
+
+// moveLSSelection moves the LS History tab selection by delta, wrapping at
+// either end.
+func (m *model) moveLSSelection(delta int) {
+	entries := m.lsHistoryEntries()
+	if len(entries) == 0 {
+		m.setStatus("no limited support history for this incident")
+		return
+	}
+	i := (m.selectedLSIndex(entries) + delta + len(entries)) % len(entries)
+	m.selectedLSEntry = entries[i].key()
+	m.setStatus(fmt.Sprintf("selected %d/%d: %s", i+1, len(entries), m.lsEntryLabel(entries[i])))
+}
+
+// lsEntryLabel describes an entry for the status line and the tab.
+func (m model) lsEntryLabel(e lsHistoryEntry) string {
+	name := clusterDisplayName(e.clusterID, m.clusterCache[e.clusterID])
+	if e.reason != nil {
+		return fmt.Sprintf("remove %s from %s", e.reason.ID, name)
+	}
+	return fmt.Sprintf("add %s to %s", e.template, name)
+}
+
+// confirmSelectedLSEntry starts the same two-step confirmation as `:ls add`
+// or `:ls remove` for the entry selected on the LS History tab.
+func (m *model) confirmSelectedLSEntry() tea.Cmd {
+	if m.ocmClient == nil {
+		return m.flashNotification("OCM not connected — cannot change limited support")
+	}
+	entries := m.lsHistoryEntries()
+	if len(entries) == 0 {
+		return m.flashNotification("no limited support history for this incident")
+	}
+	e := entries[m.selectedLSIndex(entries)]
+	if e.reason != nil {
+		return m.confirmRemoveLimitedSupport(e.reason.ID)
+	}
+	return m.confirmAddLimitedSupport(e.template, e.clusterID)
+}
+
+// limitedSupportPreview is the first confirmation: the reason text exactly
+// as the cluster owner sees it.
+func limitedSupportPreview(action string, r ocm.LimitedSupportReason, clusterName, ocmID, incidentID string) string {
+	var b strings.Builder
+	if action == limitedSupportAdd {
+		fmt.Fprintf(&b, "Place %s (%s) into limited support?\n\n", clusterName, ocmID)
+		b.WriteString("CUSTOMER-VISIBLE — the cluster owner is notified with this text:\n\n")
+	} else {
+		fmt.Fprintf(&b, "Remove limited support reason %s from %s (%s)?\n\n", r.ID, clusterName, ocmID)
+	}
+	fmt.Fprintf(&b, "Summary: %s\n", r.Summary)
+	if r.Details != "" {
+		fmt.Fprintf(&b, "\n%s\n", r.Details)
+	}
+	fmt.Fprintf(&b, "\nA note recording the change is added to %s.\n", incidentID)
+	b.WriteString("\n[y/n]")
+	return b.String()
+}
+
+func finalLimitedSupportConfirm(prompt string, action tea.Cmd) tea.Cmd {
+	return func() tea.Msg { return limitedSupportFinalConfirmMsg{prompt: prompt, action: action} }
+}
+
+// changeLimitedSupport adds or removes the reason in msg on cluster ocmID,
+// then notes the change on the incident. A failed note does not undo the
+// change.
+func changeLimitedSupport(client ocm.OCMClient, p *pd.Config, msg limitedSupportChangedMsg, ocmID string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), ocmAPITimeout)
+		defer cancel()
+		if msg.action == limitedSupportAdd {
+			added, err := client.AddLimitedSupportReason(ctx, ocmID, msg.reason)
+			if err != nil {
+				msg.err = err
+				return msg
+			}
+			msg.reason = *added
+		} else if msg.err = client.RemoveLimitedSupportReason(ctx, ocmID, msg.reason.ID); msg.err != nil {
+			return msg
+		}
+
+		if p == nil || p.Client == nil {
+			msg.noteErr = fmt.Errorf("PagerDuty not configured")
+			return msg
+		}
+		_, msg.noteErr = pd.PostNote(p.Client, msg.incidentID, p.CurrentUser, limitedSupportNote(msg, ocmID))
+		return msg
+	}
+}
+
+// limitedSupportNote records a change on the incident.
+func limitedSupportNote(msg limitedSupportChangedMsg, ocmID string) string {
+	verb := "Placed cluster %s (%s) into limited support"
+	if msg.action == limitedSupportRemove {
+		verb = "Removed a limited support reason from cluster %s (%s)"
+	}
+	note := fmt.Sprintf(verb, msg.clusterName, ocmID)
+	if msg.reason.ID != "" {
+		note += fmt.Sprintf(". Reason %s", msg.reason.ID)
+	}
+	note += fmt.Sprintf(":\n\n%s", msg.reason.Summary)
+	if msg.reason.Details != "" {
+		note += "\n\n" + msg.reason.Details
+	}
+	return note
+}
+
+// applyLimitedSupportChanged refreshes the cluster's LS History and the
+// incident's notes.
+func (m *model) applyLimitedSupportChanged(msg limitedSupportChangedMsg) tea.Cmd {
+	if msg.err != nil {
+		log.Warn("limited support change failed", "action", msg.action, "cluster_id", msg.clusterID, "error", msg.err)
+		return m.flashNotification("limited support not changed: " + msg.err.Error())
+	}
+	log.Info("limited support changed", "action", msg.action, "cluster_id", msg.clusterID, "reason_id", msg.reason.ID, "incident_id", msg.incidentID)
+
+	status := fmt.Sprintf("%s placed into limited support", msg.clusterName)
+	if msg.action == limitedSupportRemove {
+		status = fmt.Sprintf("limited support reason %s removed from %s", msg.reason.ID, msg.clusterName)
+	}
+	if msg.noteErr != nil {
+		log.Warn("limited support note failed", "incident_id", msg.incidentID, "error", msg.noteErr)
+		status += " — note not added: " + msg.noteErr.Error()
+	}
+	cmds := []tea.Cmd{m.flashNotification(status)}
+
+	delete(m.limitedSupportCache, msg.clusterID)
+	delete(m.limitedSupportErrors, msg.clusterID)
+	if info := m.clusterCache[msg.clusterID]; info != nil && m.ocmClient != nil {
+		cmds = append(cmds, getLimitedSupportHistory(m.ocmClient, info.ID, msg.clusterID))
+	}
+	if msg.noteErr == nil && m.selectedIncident != nil && m.selectedIncident.ID == msg.incidentID {
+		cmds = append(cmds, func() tea.Msg { return getIncidentMsg(msg.incidentID) })
+	}
+	return tea.Batch(cmds...)
+}
+
+// formatLimitedSupportTemplates renders the reason library for `:ls`.
+func formatLimitedSupportTemplates(templates []servicelog.ReasonTemplate) string {
+	var b strings.Builder
+	b.WriteString("# Limited Support Templates\n\n")
+	b.WriteString("Place the selected incident's cluster into limited support with `:ls add <template> [cluster]`, ")
+	b.WriteString("and remove a reason listed on the LS History tab with `:ls remove <reason-id>`; ")
+	b.WriteString("on that tab `[`/`]` select a reason or template and `L` removes or adds it. ")
+	b.WriteString("Limited support reasons are **customer-visible**.\n\n")
+	for _, t := range templates {
+		fmt.Fprintf(&b, "* **%s** — %s\n", t.Name, t.Summary)
+	}
+	return b.String()
+}
@@ -0,0 +1,219 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/clcollins/srepd/pkg/ocm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLimitedSupportCommand(t *testing.T) {
+	tests := map[string]limitedSupportCommand{
+		":ls":                          {},
+		":ls add cloud-credentials":    {action: limitedSupportAdd, template: "cloud-credentials"},
+		":ls add cloud-credentials c1": {action: limitedSupportAdd, template: "cloud-credentials", cluster: "c1"},
+		"  :ls  remove  ls-001 ":       {action: limitedSupportRemove, reasonID: "ls-001"},
+	}
+	for input, want := range tests {
+		cmd, err := parseLimitedSupportCommand(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, cmd, input)
+	}
+
+	for input, want := range map[string]string{
+		":ls add":       "usage: :ls add <template> [cluster]",
+		":ls add a b c": "usage: :ls add <template> [cluster]",
+		":ls remove":    "usage: :ls remove <reason-id>",
+		":ls bogus":     "usage: :ls [add",
+	} {
+		_, err := parseLimitedSupportCommand(input)
+		assert.ErrorContains(t, err, want, input)
+	}
+
+	assert.True(t, isLimitedSupportCommand(":ls add x"))
+	assert.False(t, isLimitedSupportCommand(":lsx"))
+}
+
+// confirmTwice runs the first confirmation, checks it leads to the second,
+// and runs that.
+func confirmTwice(t *testing.T, m model) (model, limitedSupportChangedMsg) {
+	t.Helper()
+	require.NotNil(t, m.pendingConfirmation)
+	final, ok := m.pendingConfirmation.action().(limitedSupportFinalConfirmMsg)
+	require.True(t, ok, "the first confirmation only leads to the second")
+
+	result, _ := m.Update(final)
+	m = result.(model)
+	require.NotNil(t, m.pendingConfirmation)
+	assert.True(t, strings.HasSuffix(m.pendingConfirmation.prompt, "[y/n]"))
+
+	msg, ok := m.pendingConfirmation.action().(limitedSupportChangedMsg)
+	require.True(t, ok)
+	return m, msg
+}
+
+func TestAddLimitedSupport_TwoStepConfirmAndNote(t *testing.T) {
+	m, mock, client := serviceLogTestModel(t)
+
+	m.dispatchLimitedSupportCommand(":ls add cloud-credentials")
+	require.NotNil(t, m.pendingConfirmation)
+	prompt := m.pendingConfirmation.prompt
+	assert.Contains(t, prompt, "Place acme-prod (c1) into limited support?")
+	assert.Contains(t, prompt, "CUSTOMER-VISIBLE")
+	assert.Contains(t, prompt, "Red Hat SRE cannot manage cluster acme-prod")
+	assert.Contains(t, prompt, "A note recording the change is added to INC1.")
+
+	m, msg := confirmTwice(t, m)
+	assert.Contains(t, m.pendingConfirmation.prompt, "Place acme-prod into limited support now?")
+	require.NoError(t, msg.err)
+	require.NoError(t, msg.noteErr)
+
+	require.Len(t, mock.LimitedSupport["c1"], 1)
+	assert.Equal(t, "manual", mock.LimitedSupport["c1"][0].DetectionType)
+	assert.Equal(t, mock.LimitedSupport["c1"][0].ID, msg.reason.ID)
+	assert.Equal(t, 1, client.CallCounts["CreateIncidentNoteWithContext"])
+
+	m.limitedSupportCache = map[string][]ocm.LimitedSupportReason{"c1": {}}
+	cmd := m.applyLimitedSupportChanged(msg)
+	assert.Contains(t, m.status, "acme-prod placed into limited support")
+	assert.NotContains(t, m.limitedSupportCache, "c1")
+
+	var refreshed *limitedSupportMsg
+	for _, got := range collectCmdMsgs(t, cmd, 500*time.Millisecond) {
+		if ls, ok := got.(limitedSupportMsg); ok {
+			refreshed = &ls
+		}
+	}
+	require.NotNil(t, refreshed)
+	assert.Len(t, refreshed.reasons, 1)
+}
+
+func TestRemoveLimitedSupport(t *testing.T) {
+	m, mock, client := serviceLogTestModel(t)
+	reason := ocm.LimitedSupportReason{ID: "ls-001", Summary: "Customer modified ingress", Details: "details"}
+	mock.LimitedSupport["c1"] = []ocm.LimitedSupportReason{reason}
+	m.limitedSupportCache = map[string][]ocm.LimitedSupportReason{"c1": {reason}}
+
+	m.dispatchLimitedSupportCommand(":ls remove ls-001")
+	require.NotNil(t, m.pendingConfirmation)
+	assert.Contains(t, m.pendingConfirmation.prompt, "Remove limited support reason ls-001 from acme-prod (c1)?")
+	assert.NotContains(t, m.pendingConfirmation.prompt, "CUSTOMER-VISIBLE")
+
+	_, msg := confirmTwice(t, m)
+	require.NoError(t, msg.err)
+	assert.Empty(t, mock.LimitedSupport["c1"])
+	assert.Equal(t, 1, client.CallCounts["CreateIncidentNoteWithContext"])
+	assert.Contains(t, limitedSupportNote(msg, "c1"), "Removed a limited support reason from cluster acme-prod (c1). Reason ls-001:\n\nCustomer modified ingress")
+}
+
+func TestLimitedSupportTab_SelectAndChange(t *testing.T) {
+	m, mock, _ := serviceLogTestModel(t)
+	older := ocm.LimitedSupportReason{ID: "ls-001", Summary: "Customer modified ingress", CreatedAt: "2026-01-01T00:00:00Z"}
+	newer := ocm.LimitedSupportReason{ID: "ls-002", Summary: "Cloud credentials removed", CreatedAt: "2026-02-01T00:00:00Z"}
+	mock.LimitedSupport["c1"] = []ocm.LimitedSupportReason{older, newer}
+	m.limitedSupportCache = map[string][]ocm.LimitedSupportReason{"c1": {older, newer}}
+	m.activeTab = tabLimitedSupport
+	m.viewingIncident = true
+
+	content, _, err := m.renderLimitedSupportTab()
+	require.NoError(t, err)
+	assert.Contains(t, content, "### Limited Support 1/2 ◀ selected\n\n* Cluster: acme-prod\n* Reason ID: ls-002")
+	assert.Contains(t, content, "* add cloud-credentials to acme-prod")
+
+	// L on the newest reason removes it, through both confirmations.
+	result, _ := m.keyMsgHandler(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("L")})
+	m = result.(model)
+	require.NotNil(t, m.pendingConfirmation)
+	assert.Contains(t, m.pendingConfirmation.prompt, "Remove limited support reason ls-002 from acme-prod (c1)?")
+	m, msg := confirmTwice(t, m)
+	require.NoError(t, msg.err)
+	assert.Equal(t, []ocm.LimitedSupportReason{older}, mock.LimitedSupport["c1"])
+	m.pendingConfirmation = nil
+
+	// [ wraps to the last template, ] comes back round to the first reason.
+	result, cmd := m.keyMsgHandler(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("[")})
+	m = result.(model)
+	require.NotNil(t, cmd)
+	entries := m.lsHistoryEntries()
+	last := entries[len(entries)-1]
+	assert.Equal(t, last.key(), m.selectedLSEntry)
+	assert.Equal(t, fmt.Sprintf("selected %d/%d: add %s to acme-prod", len(entries), len(entries), last.template), m.status)
+	m.moveLSSelection(1)
+	assert.Equal(t, "ls-002", m.selectedLSEntry)
+
+	// L on a template adds it to the template's cluster.
+	m.selectedLSEntry = "c1/cloud-credentials"
+	result, _ = m.keyMsgHandler(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("L")})
+	m = result.(model)
+	require.NotNil(t, m.pendingConfirmation)
+	assert.Contains(t, m.pendingConfirmation.prompt, "Place acme-prod (c1) into limited support?")
+	_, msg = confirmTwice(t, m)
+	require.NoError(t, msg.err)
+	assert.Len(t, mock.LimitedSupport["c1"], 2)
+
+	m.pendingConfirmation = nil
+	m.activeTab = tabAlerts
+	result, _ = m.keyMsgHandler(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("L")})
+	assert.Equal(t, "switch to the LS History tab to change limited support", result.(model).status)
+}
+
+func TestLimitedSupportCommand_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(m *model)
+		input string
+		want  string
+	}{
+		{"unknown template", nil, ":ls add nope", `unknown limited support template "nope"`},
+		{"no OCM", func(m *model) { m.ocmClient = nil }, ":ls add cloud-credentials", "OCM not connected"},
+		{"unknown reason", nil, ":ls remove ls-404", "no limited support reason ls-404 on incident INC1's clusters"},
+		{"several clusters", func(m *model) {
+			m.incidentClusterMap["INC1"] = []string{"c1", "c2"}
+			m.clusterCache["c2"] = &ocm.ClusterInfo{ID: "c2", Name: "initech"}
+		}, ":ls add cloud-credentials", "use :ls add <template> <cluster>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, _, _ := serviceLogTestModel(t)
+			if tt.setup != nil {
+				tt.setup(&m)
+			}
+			m.dispatchLimitedSupportCommand(tt.input)
+			assert.Nil(t, m.pendingConfirmation)
+			assert.Contains(t, m.status, tt.want)
+		})
+	}
+}
+
+func TestApplyLimitedSupportChanged_Failures(t *testing.T) {
+	t.Run("change failed", func(t *testing.T) {
+		m, mock, client := serviceLogTestModel(t)
+		mock.LimitedSupportErr = assert.AnError
+
+		m.dispatchLimitedSupportCommand(":ls add cloud-credentials")
+		_, msg := confirmTwice(t, m)
+		require.Error(t, msg.err)
+		assert.Zero(t, client.CallCounts["CreateIncidentNoteWithContext"], "nothing is noted when nothing changed")
+
+		m.applyLimitedSupportChanged(msg)
+		assert.Contains(t, m.status, "limited support not changed")
+	})
+
+	t.Run("note failed", func(t *testing.T) {
+		m, _, _ := serviceLogTestModel(t)
+		m.config = nil
+
+		m.dispatchLimitedSupportCommand(":ls add cloud-credentials")
+		_, msg := confirmTwice(t, m)
+		require.NoError(t, msg.err)
+		require.Error(t, msg.noteErr)
+
+		m.applyLimitedSupportChanged(msg)
+		assert.Contains(t, m.status, "note not added: PagerDuty not configured")
+	})
+}
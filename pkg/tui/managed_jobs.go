@@ -0,0 +1,457 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+	"github.com/clcollins/srepd/pkg/backplane"
+	"github.com/clcollins/srepd/pkg/pd"
+)
+
+const (
+	jobList = "list"
+	jobRun  = "run"
+
+	// jobPollInterval is how often a running job's status and logs are
+	// fetched for the Jobs tab.
+	jobPollInterval = 3 * time.Second
+	// jobMaxPollFailures stops polling a job the backplane API keeps
+	// failing to report.
+	jobMaxPollFailures = 3
+)
+
+type jobCommand struct {
+	action  string
+	script  string
+	cluster string
+	params  map[string]string
+}
+
+// managedJobRun is a managed job started from srepd, shown on the Jobs tab
+// of the incident it was started for.
+type managedJobRun struct {
+	incidentID   string
+	clusterID    string // PD cluster ID
+	ocmID        string
+	clusterName  string
+	params       map[string]string
+	job          backplane.ManagedJob
+	logs         string
+	err          error
+	pollFailures int
+}
+
+// setJob records the job as the API reports it, keeping the script name
+// when the response leaves it out.
+func (r *managedJobRun) setJob(job backplane.ManagedJob) {
+	if job.ScriptRef.CanonicalName == "" {
+		job.ScriptRef = r.job.ScriptRef
+	}
+	r.job = job
+}
+
+// managedScriptsMsg carries a cluster's script list. run is set when the
+// list was fetched to validate a `:job run`.
+type managedScriptsMsg struct {
+	clusterID   string
+	clusterName string
+	scripts     []backplane.ManagedScript
+	run         *jobCommand
+	err         error
+}
+
+// managedJobCreatedMsg reports a started job. noteErr is set when the job
+// started but the PD note recording it was not added.
+type managedJobCreatedMsg struct {
+	run     managedJobRun
+	noteErr error
+	err     error
+}
+
+type managedJobPolledMsg struct {
+	incidentID string
+	jobID      string
+	job        *backplane.ManagedJob
+	logs       string
+	err        error
+}
+
+func isJobCommand(input string) bool {
+	trimmed := strings.TrimSpace(input)
+	return trimmed == ":job" || strings.HasPrefix(trimmed, ":job ")
+}
+
+// parseJobCommand parses `:job [list [cluster]]` and
+// `:job run <script> [cluster] [KEY=VALUE ...]`.
+func parseJobCommand(input string) (jobCommand, error) {
+	parts := strings.Fields(input)
+	if len(parts) == 1 {
+		return jobCommand{action: jobList}, nil
+	}
+	switch parts[1] {
+	case jobList:
+		switch len(parts) {
+		case 2:
+			return jobCommand{action: jobList}, nil
+		case 3:
+			return jobCommand{action: jobList, cluster: parts[2]}, nil
+		}
+		return jobCommand{}, fmt.Errorf("usage: :job list [cluster]")
+	case jobRun:
+		if len(parts) < 3 {
+			return jobCommand{}, fmt.Errorf("usage: :job run <script> [cluster] [KEY=VALUE ...]")
+		}
+		cmd := jobCommand{action: jobRun, script: parts[2], params: make(map[string]string)}
+		for _, arg := range parts[3:] {
+			key, value, isParam := strings.Cut(arg, "=")
+			switch {
+			case isParam && key == "":
+				return jobCommand{}, fmt.Errorf("parameter %q has no name", arg)
+			case isParam:
+				cmd.params[key] = value
+			case cmd.cluster != "":
+				return jobCommand{}, fmt.Errorf("usage: :job run <script> [cluster] [KEY=VALUE ...]")
+			default:
+				cmd.cluster = arg
+			}
+		}
+		return cmd, nil
+	}
+	return jobCommand{}, fmt.Errorf("usage: :job [list [cluster] | run <script> [cluster] [KEY=VALUE ...]]")
+}
+
+// dispatchJobCommand lists the managed scripts of the selected incident's
+// cluster, or previews running one. Scripts are listed before a run so the
+// preview can show, and check, what the script takes.
+func (m *model) dispatchJobCommand(input string) tea.Cmd {
+	cmd, err := parseJobCommand(input)
+	if err != nil {
+		return m.flashNotification(err.Error())
+	}
+	if m.backplaneClient == nil {
+		return m.flashNotification("backplane not available — cannot run managed jobs")
+	}
+	if m.selectedIncident == nil {
+		return m.flashNotification("no incident selected")
+	}
+	usage := ":job list <cluster>"
+	if cmd.action == jobRun {
+		usage = ":job run <script> <cluster>"
+	}
+	clusterID, info, err := m.incidentCluster(cmd.cluster, usage)
+	if err != nil {
+		return m.flashNotification(err.Error())
+	}
+	name := clusterDisplayName(clusterID, info)
+
+	if cmd.action == jobRun {
+		if scripts, ok := m.managedScriptCache[clusterID]; ok {
+			return m.confirmManagedJob(cmd, clusterID, scripts)
+		}
+		run := cmd
+		return listManagedScripts(m.backplaneClient, info.ID, clusterID, name, &run)
+	}
+	return listManagedScripts(m.backplaneClient, info.ID, clusterID, name, nil)
+}
+
+func listManagedScripts(client backplane.BackplaneClient, ocmID, clusterID, clusterName string, run *jobCommand) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), ocmAPITimeout)
+		defer cancel()
+		scripts, err := client.ListManagedScripts(ctx, ocmID)
+		return managedScriptsMsg{clusterID: clusterID, clusterName: clusterName, scripts: scripts, run: run, err: err}
+	}
+}
+
+// applyManagedScripts caches a script list, then shows it or continues the
+// `:job run` it was fetched for.
+func (m *model) applyManagedScripts(msg managedScriptsMsg) tea.Cmd {
+	if msg.err != nil {
+		log.Warn("backplane.ListManagedScripts failed", "cluster_id", msg.clusterID, "error", msg.err)
+		return m.flashNotification("managed scripts not listed: " + msg.err.Error())
+	}
+	if m.managedScriptCache == nil {
+		m.managedScriptCache = make(map[string][]backplane.ManagedScript)
+	}
+	m.managedScriptCache[msg.clusterID] = msg.scripts
+	if msg.run != nil {
+		return m.confirmManagedJob(*msg.run, msg.clusterID, msg.scripts)
+	}
+
+	content := formatManagedScripts(msg.clusterName, msg.scripts)
+	rendered, err := renderIncidentMarkdown(m, content)
+	if err != nil {
+		rendered = content
+	}
+	m.incidentViewer.SetContent(rendered)
+	m.incidentViewer.GotoTop()
+	m.viewingIncident = true
+	m.table.Blur()
+	return nil
+}
+
+// confirmManagedJob checks the parameters against the script and asks for
+// confirmation with everything that will run.
+func (m *model) confirmManagedJob(cmd jobCommand, clusterID string, scripts []backplane.ManagedScript) tea.Cmd {
+	i := slices.IndexFunc(scripts, func(s backplane.ManagedScript) bool { return s.CanonicalName == cmd.script })
+	if i < 0 {
+		return m.flashNotification(fmt.Sprintf("unknown managed script %q — :job lists them", cmd.script))
+	}
+	script := scripts[i]
+	if err := validateJobParams(script, cmd.params); err != nil {
+		return m.flashNotification(err.Error())
+	}
+	// The incident may have changed while the scripts were listed.
+	if m.selectedIncident == nil || !slices.Contains(m.incidentClusterMap[m.selectedIncident.ID], clusterID) || m.clusterCache[clusterID] == nil {
+		return m.flashNotification("incident changed — run :job run again")
+	}
+	info := m.clusterCache[clusterID]
+
+	run := managedJobRun{
+		incidentID:  m.selectedIncident.ID,
+		clusterID:   clusterID,
+		ocmID:       info.ID,
+		clusterName: clusterDisplayName(clusterID, info),
+		params:      cmd.params,
+		job:         backplane.ManagedJob{ScriptRef: backplane.ScriptRef{CanonicalName: script.CanonicalName}},
+	}
+	m.pendingConfirmation = &confirmActionState{
+		prompt: managedJobPreview(script, run),
+		action: createManagedJob(m.backplaneClient, m.config, run),
+	}
+	return nil
+}
+
+// validateJobParams requires every non-optional parameter and rejects
+// parameters the script does not take.
+func validateJobParams(script backplane.ManagedScript, params map[string]string) error {
+	for _, env := range script.Envs {
+		if _, ok := params[env.Key]; !ok && !env.Optional {
+			return fmt.Errorf("%s requires %s=<value> (%s)", script.CanonicalName, env.Key, env.Description)
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(params)) {
+		if !slices.ContainsFunc(script.Envs, func(e backplane.ScriptEnv) bool { return e.Key == key }) {
+			return fmt.Errorf("%s does not take parameter %s", script.CanonicalName, key)
+		}
+	}
+	return nil
+}
+
+func formatJobParams(params map[string]string) string {
+	var pairs []string
+	for _, key := range slices.Sorted(maps.Keys(params)) {
+		pairs = append(pairs, key+"="+params[key])
+	}
+	return strings.Join(pairs, " ")
+}
+
+// managedJobPreview is the confirmation prompt: the script, where it runs
+// and with what.
+func managedJobPreview(script backplane.ManagedScript, run managedJobRun) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Run managed script %s on %s (%s)?\n\n", script.CanonicalName, run.clusterName, run.ocmID)
+	if script.Description != "" {
+		fmt.Fprintf(&b, "%s\n\n", script.Description)
+	}
+	if len(run.params) > 0 {
+		fmt.Fprintf(&b, "Parameters: %s\n\n", formatJobParams(run.params))
+	}
+	b.WriteString("The job runs on the cluster with the script's permissions; follow it on the Jobs tab.\n")
+	fmt.Fprintf(&b, "A note recording the run is added to %s.\n", run.incidentID)
+	b.WriteString("\n[y/n]")
+	return b.String()
+}
+
+// createManagedJob starts the job, then notes it on the incident. A failed
+// note does not stop the job.
+func createManagedJob(client backplane.BackplaneClient, p *pd.Config, run managedJobRun) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), ocmAPITimeout)
+		defer cancel()
+		job, err := client.CreateManagedJob(ctx, run.ocmID, run.job.ScriptRef.CanonicalName, run.params)
+		if err != nil {
+			return managedJobCreatedMsg{run: run, err: err}
+		}
+		run.setJob(*job)
+
+		msg := managedJobCreatedMsg{run: run}
+		if p == nil || p.Client == nil {
+			msg.noteErr = fmt.Errorf("PagerDuty not configured")
+			return msg
+		}
+		_, msg.noteErr = pd.PostNote(p.Client, run.incidentID, p.CurrentUser, managedJobNote(run))
+		return msg
+	}
+}
+
+// managedJobNote records a run on the incident.
+func managedJobNote(run managedJobRun) string {
+	note := fmt.Sprintf("Ran backplane managed script %s on cluster %s (%s). Job %s.",
+		run.job.ScriptRef.CanonicalName, run.clusterName, run.ocmID, run.job.JobID)
+	if len(run.params) > 0 {
+		note += "\n\nParameters: " + formatJobParams(run.params)
+	}
+	return note
+}
+
+// applyManagedJobCreated adds the job to the Jobs tab and starts polling
+// it.
+func (m *model) applyManagedJobCreated(msg managedJobCreatedMsg) tea.Cmd {
+	script := msg.run.job.ScriptRef.CanonicalName
+	if msg.err != nil {
+		log.Warn("managed job not created", "script", script, "cluster_id", msg.run.clusterID, "error", msg.err)
+		return m.flashNotification("managed job not started: " + msg.err.Error())
+	}
+	log.Info("managed job created", "script", script, "cluster_id", msg.run.clusterID, "job_id", msg.run.job.JobID, "incident_id", msg.run.incidentID)
+
+	if m.managedJobs == nil {
+		m.managedJobs = make(map[string][]*managedJobRun)
+	}
+	run := msg.run
+	m.managedJobs[run.incidentID] = append(m.managedJobs[run.incidentID], &run)
+
+	status := fmt.Sprintf("managed job %s started on %s", run.job.JobID, run.clusterName)
+	if msg.noteErr != nil {
+		log.Warn("managed job note failed", "incident_id", run.incidentID, "error", msg.noteErr)
+		status += " — note not added: " + msg.noteErr.Error()
+	}
+	cmds := []tea.Cmd{m.flashNotification(status), pollManagedJob(m.backplaneClient, run.incidentID, run.ocmID, run.job.JobID, 0)}
+
+	if m.selectedIncident != nil && m.selectedIncident.ID == run.incidentID {
+		if msg.noteErr == nil {
+			cmds = append(cmds, func() tea.Msg { return getIncidentMsg(run.incidentID) })
+		}
+		if m.viewingIncident {
+			m.activeTab = tabJobs
+			cmds = append(cmds, func() tea.Msg { return renderIncidentMsg("managed job started") })
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// pollManagedJob fetches a job's status and logs after delay.
+func pollManagedJob(client backplane.BackplaneClient, incidentID, ocmID, jobID string, delay time.Duration) tea.Cmd {
+	poll := func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), ocmAPITimeout)
+		defer cancel()
+		msg := managedJobPolledMsg{incidentID: incidentID, jobID: jobID}
+		if msg.job, msg.err = client.GetManagedJob(ctx, ocmID, jobID); msg.err != nil {
+			return msg
+		}
+		msg.logs, msg.err = client.GetManagedJobLogs(ctx, ocmID, jobID)
+		return msg
+	}
+	if delay == 0 {
+		return poll
+	}
+	return tea.Tick(delay, func(time.Time) tea.Msg { return poll() })
+}
+
+// applyManagedJobPolled updates the job on the Jobs tab and polls again
+// until it finishes.
+func (m *model) applyManagedJobPolled(msg managedJobPolledMsg) tea.Cmd {
+	i := slices.IndexFunc(m.managedJobs[msg.incidentID], func(r *managedJobRun) bool { return r.job.JobID == msg.jobID })
+	if i < 0 {
+		return nil
+	}
+	run := m.managedJobs[msg.incidentID][i]
+
+	if msg.err != nil {
+		run.pollFailures++
+		run.err = msg.err
+		log.Debug("managed job poll failed", "job_id", msg.jobID, "failures", run.pollFailures, "error", msg.err)
+	} else {
+		run.pollFailures = 0
+		run.err = nil
+		run.setJob(*msg.job)
+		run.logs = msg.logs
+	}
+
+	var cmds []tea.Cmd
+	switch {
+	case run.job.Finished():
+		log.Info("managed job finished", "job_id", msg.jobID, "status", run.job.JobStatus.Status)
+		cmds = append(cmds, m.flashNotification(fmt.Sprintf("managed job %s %s", msg.jobID, strings.ToLower(run.job.JobStatus.Status))))
+	case run.pollFailures >= jobMaxPollFailures:
+		cmds = append(cmds, m.flashNotification(fmt.Sprintf("stopped following managed job %s: %v", msg.jobID, msg.err)))
+	default:
+		cmds = append(cmds, pollManagedJob(m.backplaneClient, run.incidentID, run.ocmID, run.job.JobID, jobPollInterval))
+	}
+	if m.viewingIncident && m.activeTab == tabJobs && m.selectedIncident != nil && m.selectedIncident.ID == msg.incidentID {
+		cmds = append(cmds, func() tea.Msg { return renderIncidentMsg("managed job updated") })
+	}
+	return tea.Batch(cmds...)
+}
+
+// renderJobsTab shows the selected incident's managed jobs, newest first.
+func (m model) renderJobsTab() (string, error) {
+	var runs []*managedJobRun
+	if m.selectedIncident != nil {
+		runs = m.managedJobs[m.selectedIncident.ID]
+	}
+	if len(runs) == 0 {
+		return "\n_No managed jobs — `:job` lists the cluster's managed scripts, `:job run <script>` runs one_\n", nil
+	}
+
+	var b strings.Builder
+	for i := len(runs) - 1; i >= 0; i-- {
+		r := runs[i]
+		fmt.Fprintf(&b, "### %s on %s\n\n", r.job.ScriptRef.CanonicalName, r.clusterName)
+		fmt.Fprintf(&b, "* Job ID: %s\n", r.job.JobID)
+		status := r.job.JobStatus.Status
+		if status == "" {
+			status = backplane.JobPending
+		}
+		fmt.Fprintf(&b, "* Status: %s\n", status)
+		if r.job.JobStatus.Start != "" {
+			fmt.Fprintf(&b, "* Started: %s\n", r.job.JobStatus.Start)
+		}
+		if r.job.JobStatus.End != "" {
+			fmt.Fprintf(&b, "* Finished: %s\n", r.job.JobStatus.End)
+		}
+		if len(r.params) > 0 {
+			fmt.Fprintf(&b, "* Parameters: `%s`\n", formatJobParams(r.params))
+		}
+		if r.err != nil {
+			fmt.Fprintf(&b, "* Error: %v\n", r.err)
+		}
+		if r.logs != "" {
+			logs := strings.TrimRight(r.logs, "\n")
+			fence := codeFence(logs)
+			fmt.Fprintf(&b, "\n%s\n%s\n%s\n", fence, logs, fence)
+		}
+		if i > 0 {
+			b.WriteString("\n---\n")
+		}
+	}
+	return b.String(), nil
+}
+
+// formatManagedScripts renders a cluster's scripts for `:job`.
+func formatManagedScripts(clusterName string, scripts []backplane.ManagedScript) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Managed Scripts for %s\n\n", clusterName)
+	b.WriteString("Run one with `:job run <script> [cluster] [KEY=VALUE ...]`; the job is followed on the Jobs tab.\n\n")
+	if len(scripts) == 0 {
+		b.WriteString("_No managed scripts available_\n")
+		return b.String()
+	}
+	for _, s := range scripts {
+		fmt.Fprintf(&b, "* **%s** — %s\n", s.CanonicalName, s.Description)
+		for _, env := range s.Envs {
+			req := "required"
+			if env.Optional {
+				req = "optional"
+			}
+			fmt.Fprintf(&b, "  * `%s` (%s): %s\n", env.Key, req, env.Description)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,229 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/clcollins/srepd/pkg/backplane"
+	"github.com/clcollins/srepd/pkg/pd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func managedJobTestModel(t *testing.T) (model, *backplane.MockClient, *pd.MockPagerDutyClient) {
+	t.Helper()
+	m, _, client := serviceLogTestModel(t)
+	bp := backplane.NewMockClient()
+	bp.Scripts = []backplane.ManagedScript{
+		{CanonicalName: "SREP/check-etcd-health", Description: "Report etcd health", Envs: []backplane.ScriptEnv{{Key: "MEMBER", Optional: true}}},
+		{CanonicalName: "SREP/drain-node", Description: "Drain one node", Envs: []backplane.ScriptEnv{{Key: "NODE", Description: "Node to drain"}}},
+	}
+	m.backplaneClient = bp
+
+	// Wide enough for the whole tab bar, Jobs included.
+	size := windowSize
+	windowSize = tea.WindowSizeMsg{Width: 200, Height: 40}
+	t.Cleanup(func() { windowSize = size })
+	return m, bp, client
+}
+
+func TestParseJobCommand(t *testing.T) {
+	tests := map[string]jobCommand{
+		":job":                                {action: jobList},
+		":job list":                           {action: jobList},
+		":job list c1":                        {action: jobList, cluster: "c1"},
+		":job run SREP/drain-node NODE=w1":    {action: jobRun, script: "SREP/drain-node", params: map[string]string{"NODE": "w1"}},
+		":job run SREP/drain-node c1 NODE=w1": {action: jobRun, script: "SREP/drain-node", cluster: "c1", params: map[string]string{"NODE": "w1"}},
+		":job run SREP/check":                 {action: jobRun, script: "SREP/check", params: map[string]string{}},
+	}
+	for input, want := range tests {
+		cmd, err := parseJobCommand(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, cmd, input)
+	}
+
+	for input, want := range map[string]string{
+		":job run":               "usage: :job run <script>",
+		":job run SREP/x c1 c2":  "usage: :job run <script>",
+		":job run SREP/x =value": "has no name",
+		":job list a b":          "usage: :job list [cluster]",
+		":job bogus":             "usage: :job [list",
+	} {
+		_, err := parseJobCommand(input)
+		assert.ErrorContains(t, err, want, input)
+	}
+
+	assert.True(t, isJobCommand(":job run x"))
+	assert.False(t, isJobCommand(":jobs"))
+}
+
+func TestValidateJobParams(t *testing.T) {
+	script := backplane.ManagedScript{
+		CanonicalName: "SREP/drain-node",
+		Envs:          []backplane.ScriptEnv{{Key: "NODE", Description: "Node to drain"}, {Key: "FORCE", Optional: true}},
+	}
+	assert.NoError(t, validateJobParams(script, map[string]string{"NODE": "w1"}))
+	assert.ErrorContains(t, validateJobParams(script, map[string]string{}), "requires NODE=<value> (Node to drain)")
+	assert.ErrorContains(t, validateJobParams(script, map[string]string{"NODE": "w1", "GRACE": "1"}), "does not take parameter GRACE")
+}
+
+func TestJobCommand_ListsScripts(t *testing.T) {
+	m, _, _ := managedJobTestModel(t)
+
+	msgs := collectCmdMsgs(t, m.dispatchJobCommand(":job"), 500*time.Millisecond)
+	require.Len(t, msgs, 1)
+	msg, ok := msgs[0].(managedScriptsMsg)
+	require.True(t, ok)
+	require.NoError(t, msg.err)
+	assert.Nil(t, msg.run)
+
+	m.applyManagedScripts(msg)
+	assert.True(t, m.viewingIncident)
+	assert.Len(t, m.managedScriptCache["c1"], 2)
+
+	content := formatManagedScripts(msg.clusterName, msg.scripts)
+	assert.Contains(t, content, "# Managed Scripts for acme-prod")
+	assert.Contains(t, content, "**SREP/drain-node** — Drain one node")
+	assert.Contains(t, content, "`NODE` (required): Node to drain")
+}
+
+func TestJobCommand_RequiresBackplane(t *testing.T) {
+	m, _, _ := managedJobTestModel(t)
+	m.backplaneClient = nil
+
+	m.dispatchJobCommand(":job")
+	assert.Contains(t, m.status, "backplane not available")
+}
+
+func TestJobRun_ListsScriptsBeforeConfirming(t *testing.T) {
+	m, _, _ := managedJobTestModel(t)
+
+	msgs := collectCmdMsgs(t, m.dispatchJobCommand(":job run SREP/drain-node NODE=worker-1"), 500*time.Millisecond)
+	require.Len(t, msgs, 1)
+	msg := msgs[0].(managedScriptsMsg)
+	require.NotNil(t, msg.run)
+	assert.Nil(t, m.pendingConfirmation)
+
+	m.applyManagedScripts(msg)
+	require.NotNil(t, m.pendingConfirmation)
+	prompt := m.pendingConfirmation.prompt
+	assert.Contains(t, prompt, "Run managed script SREP/drain-node on acme-prod (c1)?")
+	assert.Contains(t, prompt, "Drain one node")
+	assert.Contains(t, prompt, "Parameters: NODE=worker-1")
+	assert.Contains(t, prompt, "A note recording the run is added to INC1.")
+	assert.Contains(t, prompt, "[y/n]")
+}
+
+func TestJobRun_Validation(t *testing.T) {
+	m, _, _ := managedJobTestModel(t)
+	m.managedScriptCache = map[string][]backplane.ManagedScript{"c1": m.backplaneClient.(*backplane.MockClient).Scripts}
+
+	m.dispatchJobCommand(":job run SREP/drain-node")
+	assert.Nil(t, m.pendingConfirmation)
+	assert.Contains(t, m.status, "requires NODE=<value>")
+
+	m.dispatchJobCommand(":job run SREP/unknown")
+	assert.Contains(t, m.status, `unknown managed script "SREP/unknown"`)
+
+	m.dispatchJobCommand(":job run SREP/check-etcd-health other-cluster")
+	assert.Contains(t, m.status, `cluster "other-cluster" is not an enriched cluster`)
+}
+
+func TestJobRun_CreatesNotesAndFollowsJob(t *testing.T) {
+	m, bp, client := managedJobTestModel(t)
+	m.managedScriptCache = map[string][]backplane.ManagedScript{"c1": bp.Scripts}
+	m.viewingIncident = true
+
+	m.dispatchJobCommand(":job run SREP/drain-node NODE=worker-1")
+	require.NotNil(t, m.pendingConfirmation)
+
+	created, ok := m.pendingConfirmation.action().(managedJobCreatedMsg)
+	require.True(t, ok)
+	require.NoError(t, created.err)
+	require.NoError(t, created.noteErr)
+	assert.Equal(t, "job-mock-1", created.run.job.JobID)
+	assert.Equal(t, map[string]string{"NODE": "worker-1"}, bp.JobParams("job-mock-1"))
+	assert.Equal(t, 1, client.CallCounts["CreateIncidentNoteWithContext"])
+	assert.Contains(t, managedJobNote(created.run), "Ran backplane managed script SREP/drain-node on cluster acme-prod (c1). Job job-mock-1.")
+
+	cmd := m.applyManagedJobCreated(created)
+	assert.Equal(t, tabJobs, m.activeTab)
+	assert.Contains(t, m.status, "managed job job-mock-1 started on acme-prod")
+	require.Len(t, m.managedJobs["INC1"], 1)
+
+	// Follow the job until the mock reports it finished.
+	var polled *managedJobPolledMsg
+	for _, msg := range collectCmdMsgs(t, cmd, 500*time.Millisecond) {
+		if p, ok := msg.(managedJobPolledMsg); ok {
+			polled = &p
+		}
+	}
+	require.NotNil(t, polled, "the first poll is immediate")
+	m.applyManagedJobPolled(*polled)
+	run := m.managedJobs["INC1"][0]
+	assert.Equal(t, backplane.JobRunning, run.job.JobStatus.Status)
+	assert.Equal(t, "SREP/drain-node", run.job.ScriptRef.CanonicalName)
+
+	msg := pollManagedJob(bp, "INC1", "c1", "job-mock-1", 0)().(managedJobPolledMsg)
+	m.applyManagedJobPolled(msg)
+	assert.True(t, run.job.Finished())
+	assert.Contains(t, m.status, "managed job job-mock-1 succeeded")
+
+	content, err := m.renderJobsTab()
+	require.NoError(t, err)
+	assert.Contains(t, content, "### SREP/drain-node on acme-prod")
+	assert.Contains(t, content, "* Status: Succeeded")
+	assert.Contains(t, content, "* Parameters: `NODE=worker-1`")
+	assert.Contains(t, content, "done")
+	assert.Contains(t, m.renderTabBar(), "Jobs (1)")
+}
+
+func TestJobRun_CreateFailure(t *testing.T) {
+	m, bp, client := managedJobTestModel(t)
+	bp.JobErr = assert.AnError
+	m.managedScriptCache = map[string][]backplane.ManagedScript{"c1": bp.Scripts}
+
+	m.dispatchJobCommand(":job run SREP/check-etcd-health")
+	created := m.pendingConfirmation.action().(managedJobCreatedMsg)
+	require.ErrorIs(t, created.err, assert.AnError)
+	assert.Zero(t, client.CallCounts["CreateIncidentNoteWithContext"], "no note for a job that did not start")
+
+	m.applyManagedJobCreated(created)
+	assert.Contains(t, m.status, "managed job not started")
+	assert.Empty(t, m.managedJobs["INC1"])
+}
+
+func TestManagedJobPoll_StopsAfterRepeatedFailures(t *testing.T) {
+	m, _, _ := managedJobTestModel(t)
+	run := &managedJobRun{incidentID: "INC1", ocmID: "c1", job: backplane.ManagedJob{JobID: "job-1", JobStatus: backplane.JobStatus{Status: backplane.JobRunning}}}
+	m.managedJobs = map[string][]*managedJobRun{"INC1": {run}}
+
+	for range jobMaxPollFailures {
+		m.applyManagedJobPolled(managedJobPolledMsg{incidentID: "INC1", jobID: "job-1", err: assert.AnError})
+	}
+	assert.Contains(t, m.status, "stopped following managed job job-1")
+	assert.NotContains(t, m.renderTabBar(), "Jobs (0)")
+
+	content, err := m.renderJobsTab()
+	require.NoError(t, err)
+	assert.Contains(t, content, "* Error: "+assert.AnError.Error())
+}
+
+func TestRenderJobsTab_LogsWithFence(t *testing.T) {
+	m, _, _ := managedJobTestModel(t)
+	run := &managedJobRun{incidentID: "INC1", ocmID: "c1", logs: "before\n```\nafter\n",
+		job: backplane.ManagedJob{JobID: "job-1", JobStatus: backplane.JobStatus{Status: backplane.JobSucceeded}}}
+	m.managedJobs = map[string][]*managedJobRun{"INC1": {run}}
+
+	content, err := m.renderJobsTab()
+	require.NoError(t, err)
+	assert.Contains(t, content, "\n````\nbefore\n```\nafter\n````\n", "the fence outlasts the logs' own")
+}
+
+func TestRenderJobsTab_Empty(t *testing.T) {
+	m, _, _ := managedJobTestModel(t)
+	content, err := m.renderJobsTab()
+	require.NoError(t, err)
+	assert.Contains(t, content, "No managed jobs")
+}
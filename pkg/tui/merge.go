@@ -64,7 +64,7 @@ func switchMergeFocusMode(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		switch {
 		case key.Matches(msg, defaultKeyMap.Quit):
-			return m, tea.Quit
+			return m.quit()
 
 		case key.Matches(msg, defaultKeyMap.Back):
 			m.mergeMode = false
@@ -50,6 +50,7 @@ type cachedIncidentData struct {
 type confirmActionState struct {
 	prompt string  // e.g., "Acknowledge P1234567? [y/n]"
 	action tea.Cmd // Command to execute on 'y'
+	quit   bool    // the quit warning; quitting again confirms it
 }
 
 // defaultScheduledJobs returns a fresh set of scheduled jobs. Each model gets
@@ -105,6 +106,15 @@ type model struct {
 	selectedIncidentNotes  []pagerduty.IncidentNote
 	selectedIncidentAlerts []pagerduty.IncidentAlert
 
+	// Alerts tab selection for per-alert actions, kept by alert ID.
+	// scrollToSelectedAlert asks the next render to scroll to it
+	selectedAlertID       string
+	scrollToSelectedAlert bool
+
+	// LS History tab selection for the LSChange key, kept by
+	// lsHistoryEntry.key
+	selectedLSEntry string
+
 	// Loading state tracking - enables progressive rendering and action guards
 	incidentDataLoaded   bool
 	incidentNotesLoaded  bool
@@ -182,12 +192,12 @@ type model struct {
 
 	// Tool investigation state (Phase 3 AI rearchitecture)
 	toolRegistry       *tools.Registry
-	toolRunnerFactory  ToolRunnerFactory
+	toolLoop           toolLoop
 	investigationCfg   investigationConfig
 	approvals          *approvalsStrip
 	approvalsExpanded  bool
 	watcherWasExpanded bool
-	toolsLoggedOnce    bool // whether non-Anthropic degradation was logged
+	toolsLoggedOnce    bool // whether the fallback to synthesis was logged
 
 	// Live streaming state. When streamResponses is true and the provider supports
 	// streaming, watcher responses are appended token-by-token as they arrive
@@ -232,6 +242,17 @@ type model struct {
 	mergeTable          table.Model
 	mergeTeamMode       bool
 
+	// Cluster sessions — both launchers record their logins in sessions.
+	// The tmux controller is nil unless logins open tmux windows and srepd
+	// runs inside tmux; tmuxWindows is its last listing. The sessions list
+	// is :sessions
+	sessions      *launcher.SessionRegistry
+	tmux          *launcher.Tmux
+	tmuxWindows   []launcher.TmuxWindow
+	sessionsMode  bool
+	sessionRows   []sessionRow
+	sessionsTable table.Model
+
 	// Bulk silence state — triggered via chord ctrl+x s
 	bulkSilenceMode bool
 	bulkSilenceForm *huh.Form
@@ -276,6 +297,9 @@ type model struct {
 	limitedSupportCache   map[string][]ocm.LimitedSupportReason
 	serviceLogErrors      map[string]error
 	limitedSupportErrors  map[string]error
+	// Cluster tab details, fetched lazily; see fetchClusterDetails
+	clusterDetailsInFlight map[string]bool
+	clusterDetailsErrors   map[string]error
 
 	// Backplane enrichment state
 	backplaneClient     backplane.BackplaneClient
@@ -284,6 +308,16 @@ type model struct {
 	clusterReportCache  map[string][]backplane.Report
 	clusterReportErrors map[string]error
 
+	// Managed jobs started with :job, by incident ID, and the managed
+	// scripts listed per cluster
+	managedJobs        map[string][]*managedJobRun
+	managedScriptCache map[string][]backplane.ManagedScript
+
+	// Command runs captured for the Output tab, by incident ID, and the
+	// run picked with [ and ]
+	commandRuns        map[string][]*commandRun
+	selectedCommandRun *commandRun
+
 	// Prior alerts state
 	priorAlertCache   map[string]*PriorAlertData
 	priorAlertPending map[string]int
@@ -294,6 +328,32 @@ type model struct {
 	flagMarker     string
 	flagMatchCache map[string][]int // incident ID → matching condition IDs
 
+	// Flag actions already run, and those queued by the last flag rebuild
+	flagActionLog     *flagActionLog
+	flagActionPending []pendingFlagAction
+
+	// Incidents sharing an alert fingerprint: incident ID → group IDs, oldest first
+	duplicateGroups map[string][]string
+	duplicateMarker string
+
+	// Auto-merge rules and the new incidents awaiting evaluation
+	autoMerge        autoMergeConfig
+	autoMergePending map[string]bool
+
+	// User-defined per-incident commands (custom_actions)
+	customActions []pkgconfig.CustomAction
+
+	// Embedded terminal (embedded_terminal) — logins open as tabs of a
+	// pane below the main view. While paneFocused, keys go to the active
+	// pane; paneScrolling shows its scrollback instead of its screen.
+	embeddedTerminal   bool
+	panes              []*terminalPane
+	activePane         int
+	paneFocused        bool
+	paneChordPending   bool
+	paneScrolling      bool
+	paneScrollViewport viewport.Model
+
 	// Dependency injection for testability
 	pdClientFactory func(string) pd.PagerDutyClient
 	configFS        pkgconfig.ConfigFS
@@ -452,6 +512,7 @@ func InitialModel(
 		editor:                editor,
 		launcher:              launcher,
 		rosaBoundaryLauncher:  rosaBoundaryLauncher,
+		tmux:                  newLoginTmux(launcher),
 		debug:                 debug,
 		help:                  newHelp(),
 		table:                 t,
@@ -497,13 +558,20 @@ func InitialModel(
 		docsTabsPerPage:       defaultDocsTabsPerPage,
 	}
 
+	m.sessions = shareSessions(&m.launcher, &m.rosaBoundaryLauncher)
+
 	mk := resolveMarkers(viper.GetBool("emoji"))
 	m.flagMarker = mk.flag
+	m.duplicateMarker = mk.duplicate
 	m.watcherMarker = mk.watcher
 	m.agentMarker = mk.agent
 	m.watcherDedup = newWatcherDedup(5 * time.Minute)
 	m.approvals = newApprovalsStrip()
 	m.investigationCfg = resolveInvestigationConfig()
+	m.autoMerge = resolveAutoMergeConfig()
+	m.customActions = resolveCustomActions()
+	m.embeddedTerminal = resolveEmbeddedTerminal()
+	m.flagActionLog = loadFlagActionLog(defaultFlagActionLogPath())
 	m.agentSystemPrompt = viper.GetString("agent_system_prompt")
 	m.watcherSystemPrompt = viper.GetString("watcher_system_prompt")
 	m.reescalateLevel = resolveReescalateLevel()
@@ -597,6 +665,7 @@ func InitialModelWithConfig(
 		editor:                editor,
 		launcher:              launcher,
 		rosaBoundaryLauncher:  rosaBoundaryLauncher,
+		tmux:                  newLoginTmux(launcher),
 		debug:                 debug,
 		help:                  newHelp(),
 		table:                 t,
@@ -639,13 +708,20 @@ func InitialModelWithConfig(
 		docsTabsPerPage:       defaultDocsTabsPerPage,
 	}
 
+	m.sessions = shareSessions(&m.launcher, &m.rosaBoundaryLauncher)
+
 	mk2 := resolveMarkers(viper.GetBool("emoji"))
 	m.flagMarker = mk2.flag
+	m.duplicateMarker = mk2.duplicate
 	m.watcherMarker = mk2.watcher
 	m.agentMarker = mk2.agent
 	m.watcherDedup = newWatcherDedup(5 * time.Minute)
 	m.approvals = newApprovalsStrip()
 	m.investigationCfg = resolveInvestigationConfig()
+	m.autoMerge = resolveAutoMergeConfig()
+	m.customActions = resolveCustomActions()
+	m.embeddedTerminal = resolveEmbeddedTerminal()
+	m.flagActionLog = loadFlagActionLog(defaultFlagActionLogPath())
 	m.agentSystemPrompt = viper.GetString("agent_system_prompt")
 	m.watcherSystemPrompt = viper.GetString("watcher_system_prompt")
 	m.reescalateLevel = resolveReescalateLevel()
@@ -703,6 +779,8 @@ func (m *model) clearSelectedIncident(reason interface{}) {
 	m.selectedIncident = nil
 	m.selectedIncidentNotes = nil
 	m.selectedIncidentAlerts = nil
+	m.selectedAlertID = ""
+	m.selectedLSEntry = ""
 	m.viewingIncident = false
 	// Clear loading flags
 	m.incidentDataLoaded = false
@@ -745,6 +823,8 @@ func (m *model) clearOCMCacheForIncident(incidentID string) {
 			delete(m.priorAlertCache, cid)
 			delete(m.priorAlertPending, cid)
 			delete(m.clusterEnrichInFlight, cid)
+			delete(m.clusterDetailsInFlight, cid)
+			delete(m.clusterDetailsErrors, cid)
 		}
 	}
 	log.Debug("clearOCMCacheForIncident", "incident_id", incidentID)
@@ -1054,19 +1134,15 @@ func initToolRegistryForModel(m *model) {
 	if m.aiProvider == nil {
 		return
 	}
-	if !isAnthropicFamily(m.aiProvider.Name()) {
+	loop := extractToolLoop(m.aiProvider)
+	if loop == nil {
 		if !m.toolsLoggedOnce {
-			log.Info("ai.tools", "msg", "tool investigation requires an Anthropic-family provider; falling back to synthesis", "provider", m.aiProvider.Name())
+			log.Info("ai.tools", "msg", "provider cannot call tools; falling back to synthesis", "provider", m.aiProvider.Name())
 			m.toolsLoggedOnce = true
 		}
 		return
 	}
-
-	factory := extractToolRunnerFactory(m.aiProvider)
-	if factory == nil {
-		return
-	}
-	m.toolRunnerFactory = factory
+	m.toolLoop = loop
 
 	reg := tools.NewRegistry()
 	if m.config != nil && m.config.Client != nil {
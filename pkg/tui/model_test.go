@@ -1253,16 +1253,28 @@ func TestTabSwitch_TabKey(t *testing.T) {
 			expectedTab: tabPDHistory,
 		},
 		{
-			name:        "Tab wraps from PD History to Details",
+			name:        "Tab from PD History goes to Jobs",
 			initialTab:  tabPDHistory,
 			keyMsg:      tea.KeyMsg{Type: tea.KeyTab},
+			expectedTab: tabJobs,
+		},
+		{
+			name:        "Tab from Jobs goes to Output",
+			initialTab:  tabJobs,
+			keyMsg:      tea.KeyMsg{Type: tea.KeyTab},
+			expectedTab: tabOutput,
+		},
+		{
+			name:        "Tab wraps from Output to Details",
+			initialTab:  tabOutput,
+			keyMsg:      tea.KeyMsg{Type: tea.KeyTab},
 			expectedTab: tabDetails,
 		},
 		{
-			name:        "Shift+Tab from Details goes to PD History",
+			name:        "Shift+Tab from Details goes to Output",
 			initialTab:  tabDetails,
 			keyMsg:      tea.KeyMsg{Type: tea.KeyShiftTab},
-			expectedTab: tabPDHistory,
+			expectedTab: tabOutput,
 		},
 		{
 			name:        "Shift+Tab from Alerts goes to Details",
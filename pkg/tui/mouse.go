@@ -41,7 +41,7 @@ func (m model) handleMouseMsg(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 		m.logViewer, _ = m.logViewer.Update(msg)
 		return m, nil
 
-	case m.configMode, m.bulkSilenceMode, m.teamSelectMode, m.clusterSelectMode, m.mergeMode:
+	case m.configMode, m.bulkSilenceMode, m.teamSelectMode, m.clusterSelectMode, m.mergeMode, m.sessionsMode:
 		return m, nil
 
 	default:
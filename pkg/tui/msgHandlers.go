@@ -80,6 +80,10 @@ func (m model) windowSizeMsgHandler(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.mergeTable.SetHeight(m.layout.TableHeight)
 	}
 
+	if m.sessionsMode {
+		m.sessionsTable.SetHeight(m.layout.TableHeight)
+	}
+
 	m.docsViewer.Width = m.layout.IncidentViewerWidth
 	m.docsViewer.Height = m.layout.IncidentViewerHeight
 
@@ -108,6 +112,11 @@ func (m model) keyMsgHandler(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.handleConfirmationInput(msg.(tea.KeyMsg))
 	}
 
+	// A focused terminal pane takes every key but its own chords.
+	if m.paneFocused && len(m.panes) > 0 {
+		return switchPaneFocusMode(m, msg.(tea.KeyMsg))
+	}
+
 	// Chat mode: route all keys to the chat focus handler before chord/global
 	// bindings. Without this, single-character bindings (u, w, :, /) match
 	// global key.Matches checks and never reach the textinput.
@@ -140,6 +149,9 @@ func (m model) keyMsgHandler(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if action != nil {
 				return action.Handler(m)
 			}
+			if a, ok := m.customActionForKey(keyStr); ok {
+				return chordCustomAction(m, a)
+			}
 			m.setStatus(fmt.Sprintf("unknown chord: %s %s", m.chordPrefix, keyStr))
 			return m, nil
 		}
@@ -154,13 +166,13 @@ func (m model) keyMsgHandler(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	if m.input.Focused() {
 		if key.Matches(msg.(tea.KeyMsg), defaultKeyMap.Quit) {
-			return m, tea.Quit
+			return m.quit()
 		}
 		return switchInputFocusMode(m, msg)
 	}
 
 	if key.Matches(msg.(tea.KeyMsg), defaultKeyMap.Quit) {
-		return m, tea.Quit
+		return m.quit()
 	}
 
 	if key.Matches(msg.(tea.KeyMsg), defaultKeyMap.AutoRefresh) {
@@ -213,6 +225,9 @@ func (m model) keyMsgHandler(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case m.mergeMode:
 		return switchMergeFocusMode(m, msg)
 
+	case m.sessionsMode:
+		return switchSessionsFocusMode(m, msg)
+
 	case m.viewingLog:
 		return switchLogFocusMode(m, msg)
 
@@ -452,7 +467,7 @@ func switchClusterSelectFocusMode(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		switch {
 		case key.Matches(msg, defaultKeyMap.Quit):
-			return m, tea.Quit
+			return m.quit()
 
 		case key.Matches(msg, defaultKeyMap.Back):
 			m.clusterSelectMode = false
@@ -492,7 +507,7 @@ func switchClusterSelectFocusMode(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
 // Only 'y' (execute), 'n' (cancel), Escape (cancel), and quit keys are accepted.
 func (m model) handleConfirmationInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	if key.Matches(msg, defaultKeyMap.Quit) {
-		return m, tea.Quit
+		return m.quit()
 	}
 
 	keyStr := msg.String()
@@ -715,6 +730,9 @@ func switchTableFocusMode(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.rebuildMergeTable()
 			return m, nil
 
+		case key.Matches(msg, defaultKeyMap.MergeDups):
+			return m.startDuplicateMerge()
+
 		case key.Matches(msg, defaultKeyMap.Login):
 			if m.ocmAuthPending {
 				return m, m.flashNotification("Login blocked — complete OCM browser auth first")
@@ -792,7 +810,7 @@ func switchInputFocusMode(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
 		switch {
 		case key.Matches(msg, defaultKeyMap.Quit):
 			// Ctrl+q/Ctrl+c quits the application
-			return m, tea.Quit
+			return m.quit()
 
 		case key.Matches(msg, defaultKeyMap.Back):
 			// Esc exits input mode
@@ -869,12 +887,47 @@ func switchInputFocusMode(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, m.dispatchFlagCommand(prompt)
 			}
 
+			if isServiceLogCommand(prompt) {
+				cmd := m.dispatchServiceLogCommand(prompt)
+				return m, cmd
+			}
+
+			if isLimitedSupportCommand(prompt) {
+				cmd := m.dispatchLimitedSupportCommand(prompt)
+				return m, cmd
+			}
+
+			if isJobCommand(prompt) {
+				cmd := m.dispatchJobCommand(prompt)
+				return m, cmd
+			}
+
+			if isActionCommand(prompt) {
+				cmd := m.dispatchActionCommand(prompt)
+				return m, cmd
+			}
+
+			if isSessionsCommand(prompt) {
+				cmd := m.openSessions()
+				return m, cmd
+			}
+
+			if isCacheCommand(prompt) {
+				cmd := m.dispatchCacheCommand(prompt)
+				return m, cmd
+			}
+
+			if isUpdateCommand(prompt) {
+				cmd := m.dispatchUpdateCommand(prompt)
+				return m, cmd
+			}
+
 			if isTourCommand(prompt) {
 				return m.startTour()
 			}
 
 			log.Debug("switchInputFocusMode", "msg", "unknown command", "prompt", prompt)
-			m.setStatus("unknown command — try :agent, :watcher, :flag, or :tour")
+			m.setStatus("unknown command — try :agent, :watcher, :flag, :sl, :ls, :job, :sessions, :cache, :update, or :tour")
 			return m, nil
 
 		default:
@@ -1024,6 +1077,90 @@ func switchIncidentFocusMode(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, func() tea.Msg { return openSOPMsg("sop") }
 
+		case key.Matches(msg, defaultKeyMap.Prometheus):
+			if m.selectedIncident == nil {
+				m.setStatus("no incident selected")
+				return m, nil
+			}
+			// generatorURL comes from the alerts' firing text
+			if !m.incidentAlertsLoaded {
+				m.setStatus("Loading incident alerts, please wait...")
+				return m, nil
+			}
+			return m, func() tea.Msg { return openPrometheusMsg("prometheus") }
+
+		// The Output tab's [ and ] pick a command run; P posts its output
+		case m.activeTab == tabOutput && m.selectedIncident != nil &&
+			key.Matches(msg, defaultKeyMap.AlertNext, defaultKeyMap.AlertPrev):
+			if key.Matches(msg, defaultKeyMap.AlertNext) {
+				m.moveCommandRunSelection(1)
+			} else {
+				m.moveCommandRunSelection(-1)
+			}
+			return m, func() tea.Msg { return renderIncidentMsg("command run selection") }
+
+		// The LS History tab's [ and ] pick a reason or template; L removes
+		// or adds it
+		case m.activeTab == tabLimitedSupport && m.selectedIncident != nil &&
+			key.Matches(msg, defaultKeyMap.AlertNext, defaultKeyMap.AlertPrev):
+			if key.Matches(msg, defaultKeyMap.AlertNext) {
+				m.moveLSSelection(1)
+			} else {
+				m.moveLSSelection(-1)
+			}
+			return m, func() tea.Msg { return renderIncidentMsg("limited support selection") }
+
+		case key.Matches(msg, defaultKeyMap.LSChange):
+			if m.selectedIncident == nil {
+				m.setStatus("no incident selected")
+				return m, nil
+			}
+			if m.activeTab != tabLimitedSupport {
+				m.setStatus("switch to the LS History tab to change limited support")
+				return m, nil
+			}
+			return m, m.confirmSelectedLSEntry()
+
+		case key.Matches(msg, defaultKeyMap.PostOutput):
+			if m.selectedIncident == nil {
+				m.setStatus("no incident selected")
+				return m, nil
+			}
+			if m.activeTab != tabOutput {
+				m.setStatus("switch to the Output tab to post command output")
+				return m, nil
+			}
+			return m, m.confirmPostCommandOutput()
+
+		// Per-alert actions act on the alert selected in the Alerts tab
+		case key.Matches(msg, defaultKeyMap.AlertNext, defaultKeyMap.AlertPrev,
+			defaultKeyMap.ResolveAlert, defaultKeyMap.SplitAlert):
+			if m.selectedIncident == nil {
+				m.setStatus("no incident selected")
+				return m, nil
+			}
+			if m.activeTab != tabAlerts {
+				m.setStatus("switch to the Alerts tab to select an alert")
+				return m, nil
+			}
+			if !m.incidentAlertsLoaded {
+				m.setStatus("Loading incident alerts, please wait...")
+				return m, nil
+			}
+			switch {
+			case key.Matches(msg, defaultKeyMap.AlertNext):
+				m.moveAlertSelection(1)
+			case key.Matches(msg, defaultKeyMap.AlertPrev):
+				m.moveAlertSelection(-1)
+			case key.Matches(msg, defaultKeyMap.ResolveAlert):
+				m.confirmResolveSelectedAlert()
+				return m, nil
+			case key.Matches(msg, defaultKeyMap.SplitAlert):
+				m.confirmMoveSelectedAlert()
+				return m, nil
+			}
+			return m, func() tea.Msg { return renderIncidentMsg("alert selection") }
+
 		case key.Matches(msg, defaultKeyMap.ViewDocs):
 			m.viewingIncident = false
 			m.docsReturnToIncident = true
@@ -1120,7 +1257,7 @@ func switchApprovalsFocusMode(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		switch {
 		case key.Matches(msg, defaultKeyMap.Quit):
-			return m, tea.Quit
+			return m.quit()
 
 		case key.Matches(msg, defaultKeyMap.Back):
 			m.closeApprovals()
@@ -1160,7 +1297,7 @@ func switchChatFocusMode(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		switch {
 		case key.Matches(msg, chatModeKeyMap.Quit):
-			return m, tea.Quit
+			return m.quit()
 
 		case key.Matches(msg, chatModeKeyMap.Back):
 			m.chatMode = false
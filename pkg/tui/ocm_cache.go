@@ -0,0 +1,73 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+	"github.com/clcollins/srepd/pkg/ocm"
+	"github.com/spf13/viper"
+)
+
+// WrapOCMClient adds the configured OCM environments and the disk cache to
+// a production client.
+func WrapOCMClient(primary *ocm.Client) ocm.OCMClient {
+	return withOCMCache(ConnectOCMEnvironments(primary))
+}
+
+// withOCMCache wraps client in the disk cache unless ocm_cache is false. A
+// bad ocm_cache_ttl falls back to the default TTLs.
+func withOCMCache(client ocm.OCMClient) ocm.OCMClient {
+	if !viper.GetBool("ocm_cache") {
+		return client
+	}
+	path, err := ocm.DefaultCachePath()
+	if err != nil {
+		log.Warn("OCM cache disabled", "error", err)
+		return client
+	}
+	ttls, err := ocm.ParseCacheTTLs(viper.GetStringMapString("ocm_cache_ttl"))
+	if err != nil {
+		log.Warn("OCM cache TTLs not loaded, using defaults", "error", err)
+	}
+	return ocm.NewCachingClient(client, path, ttls)
+}
+
+func isCacheCommand(input string) bool {
+	trimmed := strings.TrimSpace(input)
+	return trimmed == ":cache" || strings.HasPrefix(trimmed, ":cache ")
+}
+
+// dispatchCacheCommand shows the OCM cache hit rate for `:cache`, and for
+// `:cache clear` empties it and re-enriches every known cluster.
+func (m *model) dispatchCacheCommand(input string) tea.Cmd {
+	parts := strings.Fields(input)
+	if len(parts) > 2 || (len(parts) == 2 && parts[1] != "clear") {
+		return m.flashNotification("usage: :cache [clear]")
+	}
+	cache, ok := m.ocmClient.(*ocm.CachingClient)
+	if !ok {
+		return m.flashNotification("OCM cache not enabled")
+	}
+	if len(parts) == 1 {
+		s := cache.Stats()
+		return m.flashNotification(fmt.Sprintf("OCM cache: %d entries, %.0f%% hit rate (%d fresh, %d stale, %d misses)",
+			cache.Len(), 100*s.HitRate(), s.Hits, s.Stale, s.Misses))
+	}
+
+	if err := cache.Clear(); err != nil {
+		log.Warn("OCM cache not cleared", "error", err)
+		return m.flashNotification(err.Error())
+	}
+	clear(m.clusterCache)
+	clear(m.serviceLogCache)
+	clear(m.limitedSupportCache)
+	clear(m.serviceLogErrors)
+	clear(m.limitedSupportErrors)
+	clear(m.clusterDetailsErrors)
+	clear(m.clusterEnrichFailed)
+	cmds := m.enrichKnownClusters()
+	cmds = append(cmds, m.flashNotification("OCM cache cleared — refetching cluster data"))
+	return tea.Batch(cmds...)
+}
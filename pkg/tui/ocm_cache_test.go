@@ -0,0 +1,73 @@
+package tui
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/clcollins/srepd/pkg/ocm"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithOCMCache_Disabled(t *testing.T) {
+	viper.Set("ocm_cache", false)
+	t.Cleanup(func() { viper.Set("ocm_cache", nil) })
+
+	mock := ocm.NewMockClient()
+	assert.Same(t, mock, withOCMCache(mock))
+}
+
+func TestCacheCommand(t *testing.T) {
+	assert.True(t, isCacheCommand(":cache"))
+	assert.True(t, isCacheCommand(" :cache clear "))
+	assert.False(t, isCacheCommand(":cached"))
+
+	t.Run("not enabled", func(t *testing.T) {
+		m, _, _ := serviceLogTestModel(t)
+		m.dispatchCacheCommand(":cache")
+		assert.Contains(t, m.status, "OCM cache not enabled")
+	})
+
+	t.Run("usage", func(t *testing.T) {
+		m, _, _ := serviceLogTestModel(t)
+		m.dispatchCacheCommand(":cache flush")
+		assert.Contains(t, m.status, "usage: :cache [clear]")
+	})
+
+	t.Run("shows the hit rate", func(t *testing.T) {
+		m, mock, _ := serviceLogTestModel(t)
+		cache := ocm.NewCachingClient(mock, filepath.Join(t.TempDir(), "ocm-cache.json"), ocm.DefaultCacheTTLs)
+		t.Cleanup(cache.Close)
+		m.ocmClient = cache
+
+		m.dispatchCacheCommand(":cache")
+		assert.Contains(t, m.status, "OCM cache: 0 entries, 0% hit rate")
+	})
+
+	t.Run("clear refetches cluster data", func(t *testing.T) {
+		m, mock, _ := serviceLogTestModel(t)
+		mock.Clusters["c1"] = &ocm.ClusterInfo{ID: "c1", ExternalID: "uuid-1", DisplayName: "acme-prod"}
+		cache := ocm.NewCachingClient(mock, filepath.Join(t.TempDir(), "ocm-cache.json"), ocm.DefaultCacheTTLs)
+		t.Cleanup(cache.Close)
+		m.ocmClient = cache
+		m.serviceLogCache = map[string][]ocm.ServiceLog{"c1": {}}
+		m.clusterEnrichFailed = map[string]int{"c1": 3}
+
+		cmd := m.dispatchCacheCommand(":cache clear")
+		assert.Contains(t, m.status, "OCM cache cleared")
+		assert.Empty(t, m.clusterCache)
+		assert.Empty(t, m.serviceLogCache)
+		assert.True(t, m.clusterEnrichInFlight["c1"], "a cluster given up on is retried")
+
+		var found bool
+		for _, msg := range collectCmdMsgs(t, cmd, 500*time.Millisecond) {
+			if info, ok := msg.(clusterInfoMsg); ok && info.clusterID == "c1" {
+				require.NoError(t, info.err)
+				found = true
+			}
+		}
+		assert.True(t, found, "the incident's cluster is looked up again")
+	})
+}
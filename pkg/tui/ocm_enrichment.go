@@ -30,6 +30,12 @@ type limitedSupportMsg struct {
 	err       error
 }
 
+type clusterDetailsMsg struct {
+	clusterID string
+	details   *ocm.ClusterDetails
+	err       error
+}
+
 type clusterReportsMsg struct {
 	clusterID string
 	reports   []backplane.Report
@@ -39,14 +45,16 @@ type clusterReportsMsg struct {
 // enrichClusters dispatches phase 1 (GetCluster) for each cluster ID.
 // Phase 2 (service logs, reports, limited support) is dispatched from
 // the clusterInfoMsg handler after the internal OCM ID is resolved.
-func enrichClusters(client ocm.OCMClient, clusterIDs []string, devMode bool) []tea.Cmd {
+// service is the incident's PagerDuty service, which picks the OCM
+// environment tried first when several are configured.
+func enrichClusters(client ocm.OCMClient, clusterIDs []string, service string, devMode bool) []tea.Cmd {
 	if client == nil || len(clusterIDs) == 0 {
 		return nil
 	}
 	var cmds []tea.Cmd
 	for _, id := range clusterIDs {
 		cid := id
-		cmd := getClusterInfo(client, cid)
+		cmd := getClusterInfo(client, cid, service)
 		// In dev mode, delay enrichment by 1s to simulate the real-world
 		// async flow where alerts arrive first and OCM data follows.
 		// Without this, fixture data enriches instantly and the SRE
@@ -63,10 +71,35 @@ func enrichClusters(client ocm.OCMClient, clusterIDs []string, devMode bool) []t
 	return cmds
 }
 
-func getClusterInfo(client ocm.OCMClient, clusterID string) tea.Cmd {
+// enrichKnownClusters enriches every cluster of a known incident that is
+// not cached, in flight or given up on.
+func (m *model) enrichKnownClusters() []tea.Cmd {
+	if m.clusterEnrichInFlight == nil {
+		m.clusterEnrichInFlight = make(map[string]bool)
+	}
+	var cmds []tea.Cmd
+	for incidentID, clusterIDs := range m.incidentClusterMap {
+		var uncached []string
+		for _, id := range clusterIDs {
+			if _, cached := m.clusterCache[id]; cached {
+				continue
+			}
+			if m.clusterEnrichInFlight[id] || m.clusterEnrichFailed[id] >= 3 {
+				continue
+			}
+			uncached = append(uncached, id)
+			m.clusterEnrichInFlight[id] = true
+		}
+		cmds = append(cmds, enrichClusters(m.ocmClient, uncached, m.incidentService(incidentID), m.devMode)...)
+	}
+	return cmds
+}
+
+func getClusterInfo(client ocm.OCMClient, clusterID, service string) tea.Cmd {
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), ocmAPITimeout)
 		defer cancel()
+		ctx = ocm.WithService(ctx, service)
 		log.Debug("ocm.GetCluster", "cluster_id", clusterID)
 		info, err := client.GetCluster(ctx, clusterID)
 		return clusterInfoMsg{clusterID: clusterID, info: info, err: err}
@@ -95,6 +128,40 @@ func getLimitedSupportHistory(client ocm.OCMClient, clusterID, cacheKey string)
 	}
 }
 
+func getClusterDetails(client ocm.OCMClient, info *ocm.ClusterInfo, cacheKey string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), ocmAPITimeout)
+		defer cancel()
+		details, err := client.GetClusterDetails(ctx, info)
+		return clusterDetailsMsg{clusterID: cacheKey, details: details, err: err}
+	}
+}
+
+// fetchClusterDetails loads machine pools, add-ons, upgrade policies and
+// support level for the selected incident's clusters. It only runs while
+// the Cluster tab is shown, so the incident table never waits on it.
+func (m *model) fetchClusterDetails() tea.Cmd {
+	if m.ocmClient == nil || m.activeTab != tabCluster || m.selectedIncident == nil {
+		return nil
+	}
+	var cmds []tea.Cmd
+	for _, id := range m.incidentClusterMap[m.selectedIncident.ID] {
+		info, ok := m.clusterCache[id]
+		if !ok || info.Details != nil || m.clusterDetailsInFlight[id] {
+			continue
+		}
+		if _, failed := m.clusterDetailsErrors[id]; failed {
+			continue
+		}
+		if m.clusterDetailsInFlight == nil {
+			m.clusterDetailsInFlight = make(map[string]bool)
+		}
+		m.clusterDetailsInFlight[id] = true
+		cmds = append(cmds, getClusterDetails(m.ocmClient, info, id))
+	}
+	return tea.Batch(cmds...)
+}
+
 func getClusterReports(client backplane.BackplaneClient, clusterID, cacheKey string) tea.Cmd {
 	return func() tea.Msg {
 		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
@@ -46,7 +46,7 @@ func TestGetClusterInfo_Success(t *testing.T) {
 	t.Run("returns clusterInfoMsg with cluster data", func(t *testing.T) {
 		mock := createMockOCMClient()
 
-		cmd := getClusterInfo(mock, "1q2w3e4rfakeidtest9o0p1a2s3d4f5g")
+		cmd := getClusterInfo(mock, "1q2w3e4rfakeidtest9o0p1a2s3d4f5g", "")
 		msg := cmd()
 
 		infoMsg, ok := msg.(clusterInfoMsg)
@@ -61,7 +61,7 @@ func TestGetClusterInfo_NotFound(t *testing.T) {
 	t.Run("returns error for unknown cluster", func(t *testing.T) {
 		mock := ocm.NewMockClient()
 
-		cmd := getClusterInfo(mock, "nonexistent")
+		cmd := getClusterInfo(mock, "nonexistent", "")
 		msg := cmd()
 
 		infoMsg, ok := msg.(clusterInfoMsg)
@@ -271,7 +271,7 @@ func TestEnrichClusters_DispatchesCommands(t *testing.T) {
 		mock := createMockOCMClient()
 
 		clusterIDs := []string{"1q2w3e4rfakeidtest9o0p1a2s3d4f5g", "2a3b4c5dfakeidtest0i1j2k3l4m5n6o"}
-		cmds := enrichClusters(mock, clusterIDs, false)
+		cmds := enrichClusters(mock, clusterIDs, "", false)
 
 		assert.Len(t, cmds, 2, "should return 1 command per cluster (phase 1 GetCluster only)")
 	})
@@ -279,12 +279,12 @@ func TestEnrichClusters_DispatchesCommands(t *testing.T) {
 	t.Run("enrichClusters returns nil for empty cluster list", func(t *testing.T) {
 		mock := createMockOCMClient()
 
-		cmds := enrichClusters(mock, []string{}, false)
+		cmds := enrichClusters(mock, []string{}, "", false)
 		assert.Empty(t, cmds)
 	})
 
 	t.Run("enrichClusters returns nil when client is nil", func(t *testing.T) {
-		cmds := enrichClusters(nil, []string{"1q2w3e4rfakeidtest9o0p1a2s3d4f5g"}, false)
+		cmds := enrichClusters(nil, []string{"1q2w3e4rfakeidtest9o0p1a2s3d4f5g"}, "", false)
 		assert.Empty(t, cmds)
 	})
 }
@@ -365,6 +365,19 @@ func TestRenderClusterTab_WithData(t *testing.T) {
 		assert.Contains(t, content, "fake-osd-webapp.7x9k.p1.example.org")
 		assert.Contains(t, content, "us-east-1")
 		assert.Contains(t, content, "1/1")
+		assert.NotContains(t, content, "OCM Environment")
+	})
+
+	t.Run("shows a non-production OCM environment", func(t *testing.T) {
+		m := createTestModel()
+		setupModelWithCluster(&m)
+		m.clusterCache = map[string]*ocm.ClusterInfo{
+			testClusterID: {ID: testClusterID, Name: "fake-osd-webapp", Environment: "staging"},
+		}
+
+		content, err := m.renderClusterTab()
+		assert.NoError(t, err)
+		assert.Contains(t, content, "* OCM Environment: staging")
 	})
 }
 
@@ -899,3 +912,140 @@ func TestOCMClientReadyMsg_EnrichesClusterViewedPreAuth(t *testing.T) {
 			"OCMClientReadyMsg must dispatch phase-1 enrichment (clusterInfoMsg) for the cluster viewed pre-auth")
 	})
 }
+
+func clusterDetailsFixture() *ocm.ClusterDetails {
+	return &ocm.ClusterDetails{
+		SupportLevel: "Premium",
+		MachinePools: []ocm.MachinePool{
+			{ID: "worker", InstanceType: "m5.xlarge", Replicas: 3, AvailabilityZones: []string{"us-east-1a", "us-east-1b"}},
+			{ID: "gpu", InstanceType: "g4dn.xlarge", Replicas: 1, MinReplicas: 1, MaxReplicas: 4},
+		},
+		AddOns: []ocm.AddOn{{ID: "managed-odh", Name: "OpenShift Data Science", Version: "2.8.1", State: "failed"}},
+		UpgradePolicies: []ocm.UpgradePolicy{
+			{Version: "4.16.12", ScheduleType: "automatic", Schedule: "0 2 * * 6", NextRun: "2026-06-13T02:00:00Z", State: "scheduled"},
+		},
+	}
+}
+
+func TestFetchClusterDetails_OnlyOnClusterTab(t *testing.T) {
+	m := createTestModel()
+	setupModelWithCluster(&m)
+	mock := createMockOCMClient()
+	mock.Details[testClusterID] = clusterDetailsFixture()
+	m.ocmClient = mock
+	m.clusterCache = map[string]*ocm.ClusterInfo{testClusterID: mock.Clusters[testClusterID]}
+
+	m.activeTab = tabDetails
+	assert.Nil(t, m.fetchClusterDetails(), "details are not fetched for other tabs")
+	assert.Empty(t, m.clusterDetailsInFlight)
+
+	m.activeTab = tabCluster
+	cmd := m.fetchClusterDetails()
+	assert.NotNil(t, cmd)
+	assert.True(t, m.clusterDetailsInFlight[testClusterID])
+	assert.Nil(t, m.fetchClusterDetails(), "a fetch in flight is not repeated")
+
+	msgs := collectCmdMsgs(t, cmd, 500*time.Millisecond)
+	assert.Len(t, msgs, 1)
+	detailsMsg, ok := msgs[0].(clusterDetailsMsg)
+	assert.True(t, ok)
+	assert.NoError(t, detailsMsg.err)
+	assert.Equal(t, "Premium", detailsMsg.details.SupportLevel)
+}
+
+func TestClusterDetailsMsg_StoresDetails(t *testing.T) {
+	t.Run("stores details on a copy of the cached cluster", func(t *testing.T) {
+		m := createTestModel()
+		setupModelWithCluster(&m)
+		original := &ocm.ClusterInfo{ID: testClusterID, Name: "test-cluster"}
+		m.clusterCache = map[string]*ocm.ClusterInfo{testClusterID: original}
+		m.clusterDetailsInFlight = map[string]bool{testClusterID: true}
+
+		result, _ := m.Update(clusterDetailsMsg{clusterID: testClusterID, details: clusterDetailsFixture()})
+		updated := result.(model)
+
+		assert.False(t, updated.clusterDetailsInFlight[testClusterID])
+		assert.Nil(t, original.Details, "the info a running command may hold is not mutated")
+		assert.Equal(t, "Premium", updated.clusterCache[testClusterID].Details.SupportLevel)
+		assert.Equal(t, "test-cluster", updated.clusterCache[testClusterID].Name)
+		assert.Nil(t, updated.fetchClusterDetails())
+	})
+
+	t.Run("keeps partial details and the error", func(t *testing.T) {
+		m := createTestModel()
+		setupModelWithCluster(&m)
+		m.clusterCache = map[string]*ocm.ClusterInfo{testClusterID: {ID: testClusterID}}
+
+		result, _ := m.Update(clusterDetailsMsg{
+			clusterID: testClusterID,
+			details:   &ocm.ClusterDetails{SupportLevel: "Standard"},
+			err:       fmt.Errorf("add-ons: 403 forbidden"),
+		})
+		updated := result.(model)
+
+		content, err := updated.renderClusterTab()
+		assert.NoError(t, err)
+		assert.Contains(t, content, "Support Level: Standard")
+		assert.Contains(t, content, "Failed to load some cluster details: add-ons: 403 forbidden")
+	})
+}
+
+func TestRenderClusterTab_Details(t *testing.T) {
+	t.Run("renders support level, pools, add-ons and upgrades", func(t *testing.T) {
+		m := createTestModel()
+		setupModelWithCluster(&m)
+		m.clusterCache = map[string]*ocm.ClusterInfo{
+			testClusterID: {ID: testClusterID, Details: clusterDetailsFixture()},
+		}
+
+		content, err := m.renderClusterTab()
+		assert.NoError(t, err)
+		assert.Contains(t, content, "* Support Level: Premium")
+		assert.Contains(t, content, "#### Machine Pools (2)")
+		assert.Contains(t, content, "| worker | m5.xlarge | 3 | us-east-1a, us-east-1b |")
+		assert.Contains(t, content, "| gpu | g4dn.xlarge | 1 (autoscale 1–4) |")
+		assert.Contains(t, content, "#### Add-ons (1)")
+		assert.Contains(t, content, "| OpenShift Data Science | 2.8.1 | failed |")
+		assert.Contains(t, content, "| 4.16.12 | automatic (`0 2 * * 6`) | 2026-06-13T02:00:00Z | scheduled |")
+	})
+
+	t.Run("escapes OCM values in table cells", func(t *testing.T) {
+		m := createTestModel()
+		setupModelWithCluster(&m)
+		m.clusterCache = map[string]*ocm.ClusterInfo{
+			testClusterID: {ID: testClusterID, Details: &ocm.ClusterDetails{
+				AddOns: []ocm.AddOn{{Name: "evil | add-on\x1b[2J", Version: "1\n2", State: "ready"}},
+			}},
+		}
+
+		content, err := m.renderClusterTab()
+		assert.NoError(t, err)
+		assert.Contains(t, content, "| evil \\| add-on | 1 2 | ready |")
+		assert.NotContains(t, content, "\x1b")
+	})
+
+	t.Run("labels Hypershift pools as node pools", func(t *testing.T) {
+		m := createTestModel()
+		setupModelWithCluster(&m)
+		m.clusterCache = map[string]*ocm.ClusterInfo{
+			testClusterID: {ID: testClusterID, Hypershift: true, Details: &ocm.ClusterDetails{}},
+		}
+
+		content, err := m.renderClusterTab()
+		assert.NoError(t, err)
+		assert.Contains(t, content, "#### Node Pools (0)")
+		assert.Contains(t, content, "* Support Level: unknown")
+	})
+
+	t.Run("shows loading while details are fetched", func(t *testing.T) {
+		m := createTestModel()
+		setupModelWithCluster(&m)
+		m.clusterCache = map[string]*ocm.ClusterInfo{testClusterID: {ID: testClusterID}}
+		m.clusterDetailsInFlight = map[string]bool{testClusterID: true}
+
+		content, err := m.renderClusterTab()
+		assert.NoError(t, err)
+		assert.Contains(t, content, "Loading machine pools, add-ons and upgrades")
+		assert.NotContains(t, content, "Support Level")
+	})
+}
@@ -0,0 +1,42 @@
+package tui
+
+import (
+	"github.com/charmbracelet/log"
+	"github.com/clcollins/srepd/pkg/backplane"
+	"github.com/clcollins/srepd/pkg/ocm"
+	"github.com/spf13/viper"
+)
+
+// ConnectOCMEnvironments adds the environments in ocm_environments to a
+// production client. A bad config only costs the extra environments.
+func ConnectOCMEnvironments(primary *ocm.Client) ocm.OCMClient {
+	envs, err := ocm.ParseEnvironments(viper.Get("ocm_environments"))
+	if err != nil {
+		log.Warn("OCM environments not loaded, using production only", "error", err)
+		return primary
+	}
+	return ocm.WithEnvironments(primary, envs, Version)
+}
+
+// NewBackplaneClient builds the backplane client for an OCM connection.
+// cfg.URL must already be resolved for the primary environment; clusters
+// found in another OCM environment go to that environment's backplane.
+func NewBackplaneClient(cfg *backplane.Config, client ocm.OCMClient) backplane.BackplaneClient {
+	primary := backplane.NewClient(cfg, func() (string, error) { return client.GetAccessToken("") })
+	if cache, ok := client.(*ocm.CachingClient); ok {
+		client = cache.Unwrap()
+	}
+	multi, ok := client.(*ocm.MultiClient)
+	if !ok {
+		return primary
+	}
+	return backplane.NewEnvironmentClient(primary, ocm.ProductionEnvironment, multi.EnvironmentOf, func(env string) (backplane.BackplaneClient, error) {
+		url, err := multi.GetBackplaneURL(env)
+		if err != nil {
+			return nil, err
+		}
+		envCfg := *cfg
+		envCfg.URL = url
+		return backplane.NewClient(&envCfg, func() (string, error) { return multi.GetAccessToken(env) }), nil
+	})
+}
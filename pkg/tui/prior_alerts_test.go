@@ -403,5 +403,7 @@ func TestRenderPDHistoryTab_FetchDoneNoData(t *testing.T) {
 
 func TestTabConstants(t *testing.T) {
 	assert.Equal(t, 7, tabPDHistory, "PD History tab should be index 7")
-	assert.Equal(t, 8, tabCount, "tabCount should be 8 with PD History tab")
+	assert.Equal(t, 8, tabJobs, "Jobs tab should be index 8")
+	assert.Equal(t, 9, tabOutput, "Output tab should be index 9")
+	assert.Equal(t, 10, tabCount, "tabCount should be 10 with the Output tab")
 }
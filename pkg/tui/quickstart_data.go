@@ -47,8 +47,15 @@ func KeyBindingEntries() []KeyBindingEntry {
 		{km.Login.Help().Key, km.Login.Help().Desc},
 		{km.Open.Help().Key, km.Open.Help().Desc},
 		{km.SOP.Help().Key, km.SOP.Help().Desc},
+		{km.Prometheus.Help().Key, km.Prometheus.Help().Desc},
+		{km.AlertNext.Help().Key, km.AlertNext.Help().Desc},
+		{km.AlertPrev.Help().Key, km.AlertPrev.Help().Desc},
+		{km.ResolveAlert.Help().Key, km.ResolveAlert.Help().Desc},
+		{km.SplitAlert.Help().Key, km.SplitAlert.Help().Desc},
+		{km.LSChange.Help().Key, km.LSChange.Help().Desc},
 		{km.ViewLog.Help().Key, km.ViewLog.Help().Desc},
 		{km.Merge.Help().Key, km.Merge.Help().Desc},
+		{km.MergeDups.Help().Key, km.MergeDups.Help().Desc},
 		{km.Watcher.Help().Key, km.Watcher.Help().Desc},
 		{km.Tag.Help().Key, km.Tag.Help().Desc},
 		{km.TabNext.Help().Key, km.TabNext.Help().Desc},
@@ -87,18 +94,41 @@ func ChatModeEntries() []KeyBindingEntry {
 }
 
 func InputCommandEntries() []InputCommandEntry {
-	return []InputCommandEntry{
+	entries := []InputCommandEntry{
 		{Command: ":agent", Description: "open chat mode"},
 		{Command: ":agent <query>", Description: "ask Claude AI (fire-and-return)"},
 		{Command: ":watcher <query>", Description: "query AI watcher"},
-		{Command: ":flag cluster <id>", Description: "flag incidents by cluster ID"},
-		{Command: ":flag org <pattern>", Description: "flag incidents by org name"},
+	}
+	for _, s := range flagTypeSpecs {
+		entries = append(entries, InputCommandEntry{Command: fmt.Sprintf(":flag %s %s", s.Keyword, s.Arg), Description: s.Help})
+	}
+	return append(entries, []InputCommandEntry{
+		{Command: ":flag <cond> AND|OR <cond>", Description: "combine flag conditions (also NOT, parentheses)"},
 		{Command: ":unflag <id>", Description: "remove a flag condition by ID"},
 		{Command: ":unflag all", Description: "clear all flag conditions"},
 		{Command: ":flags", Description: "list all flag conditions"},
 		{Command: ":flags save [path]", Description: "save flags to file"},
 		{Command: ":flags load [path]", Description: "load flags from file"},
-	}
+		{Command: ":flags action <id> notify|pin", Description: "desktop-notify on, or pin, a flag's matches"},
+		{Command: ":flags action <id> tag|note <value>", Description: "offer tags or a templated note for a flag's matches"},
+		{Command: ":flags action <id> clear", Description: "remove a flag's actions"},
+		{Command: ":flags export <file>", Description: "export flags (no actions) as a team preset"},
+		{Command: ":flags import <file|url>", Description: "preview and merge shared flags"},
+		{Command: ":sl", Description: "list service log templates"},
+		{Command: ":sl <template> [cluster]", Description: "preview and post an OCM service log"},
+		{Command: ":ls", Description: "list limited support templates"},
+		{Command: ":ls add <template> [cluster]", Description: "place a cluster into limited support"},
+		{Command: ":ls remove <reason-id>", Description: "remove a limited support reason"},
+		{Command: ":job", Description: "list the cluster's backplane managed scripts"},
+		{Command: ":job run <script> [cluster] [KEY=VALUE ...]", Description: "run a managed script and follow it on the Jobs tab"},
+		{Command: ":action", Description: "list your custom actions"},
+		{Command: ":action <name> [cluster]", Description: "run a custom action on the selected incident"},
+		{Command: ":sessions", Description: "list cluster sessions launched this run and their state (Enter=jump, x=close tmux window)"},
+		{Command: ":cache", Description: "show the OCM cache hit rate"},
+		{Command: ":cache clear", Description: "empty the OCM cache and refetch cluster data"},
+		{Command: ":update", Description: "show the available update on your update_channel"},
+		{Command: ":update skip", Description: "hide the update banner until a newer release"},
+	}...)
 }
 
 func GenerateQuickstartMarkdown(keys []KeyBindingEntry, chords []ChordEntry, inputs []InputCommandEntry, chatMode []KeyBindingEntry) string {
@@ -124,7 +154,8 @@ func GenerateQuickstartMarkdown(keys []KeyBindingEntry, chords []ChordEntry, inp
 	b.WriteString("| Command | Action |\n")
 	b.WriteString("|---------|--------|\n")
 	for _, e := range inputs {
-		fmt.Fprintf(&b, "| %s | %s |\n", e.Command, e.Description)
+		// Commands such as "[true|false]" would otherwise split the row.
+		fmt.Fprintf(&b, "| %s | %s |\n", strings.ReplaceAll(e.Command, "|", `\|`), e.Description)
 	}
 
 	b.WriteString("\n## Chat Mode (`:agent`)\n\n")
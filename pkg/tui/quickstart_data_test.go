@@ -127,3 +127,11 @@ func TestGenerateQuickstartMarkdown(t *testing.T) {
 	assert.Contains(t, result, "back to queue")
 	assert.Contains(t, result, "send message")
 }
+
+func TestGenerateQuickstartMarkdown_EscapesPipes(t *testing.T) {
+	inputs := []InputCommandEntry{{Command: ":flag ccs [true|false]", Description: "flag CCS"}}
+
+	result := GenerateQuickstartMarkdown(nil, nil, inputs, nil)
+
+	assert.Contains(t, result, `| :flag ccs [true\|false] | flag CCS |`)
+}
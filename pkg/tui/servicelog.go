@@ -0,0 +1,259 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+	"github.com/clcollins/srepd/pkg/alert"
+	"github.com/clcollins/srepd/pkg/ocm"
+	"github.com/clcollins/srepd/pkg/servicelog"
+	"github.com/spf13/viper"
+)
+
+// serviceLogSentTag is prepended to the incident title after a service log
+// is posted, unless service_log_sent_tag is false.
+const serviceLogSentTag = "[SL Sent]"
+
+type listServiceLogTemplatesMsg struct{}
+
+type serviceLogPostedMsg struct {
+	incidentID  string
+	clusterID   string // PD cluster ID, the OCM cache key
+	clusterName string
+	tag         bool
+	err         error
+}
+
+func isServiceLogCommand(input string) bool {
+	trimmed := strings.TrimSpace(input)
+	return trimmed == ":sl" || strings.HasPrefix(trimmed, ":sl ")
+}
+
+// parseServiceLogCommand splits `:sl [template [cluster]]`. An empty name
+// lists the templates.
+func parseServiceLogCommand(input string) (name, cluster string, err error) {
+	parts := strings.Fields(input)
+	switch len(parts) {
+	case 1:
+		return "", "", nil
+	case 2:
+		return parts[1], "", nil
+	case 3:
+		return parts[1], parts[2], nil
+	default:
+		return "", "", fmt.Errorf("usage: :sl [template [cluster]]")
+	}
+}
+
+// dispatchServiceLogCommand lists the templates, or renders one for the
+// selected incident and asks for confirmation before posting it.
+func (m *model) dispatchServiceLogCommand(input string) tea.Cmd {
+	name, clusterRef, err := parseServiceLogCommand(input)
+	if err != nil {
+		return m.flashNotification(err.Error())
+	}
+	if name == "" {
+		return func() tea.Msg { return listServiceLogTemplatesMsg{} }
+	}
+	if m.ocmClient == nil {
+		return m.flashNotification("OCM not connected — cannot post service logs")
+	}
+	if m.selectedIncident == nil {
+		return m.flashNotification("no incident selected")
+	}
+	tmpl, ok := servicelog.Lookup(name)
+	if !ok {
+		return m.flashNotification(fmt.Sprintf("unknown service log template %q — :sl lists them", name))
+	}
+	clusterID, info, err := m.incidentCluster(clusterRef, ":sl <template> <cluster>")
+	if err != nil {
+		return m.flashNotification(err.Error())
+	}
+	entry, err := tmpl.Render(m.templateData(clusterID, info))
+	if err != nil {
+		return m.flashNotification(fmt.Sprintf("service log template %s: %v", name, err))
+	}
+
+	tag := viper.GetBool("service_log_sent_tag")
+	incidentID := m.selectedIncident.ID
+	m.pendingConfirmation = &confirmActionState{
+		prompt: serviceLogPreview(entry, clusterDisplayName(clusterID, info), incidentID, tag),
+		action: postServiceLog(m.ocmClient, entry, incidentID, clusterID, clusterDisplayName(clusterID, info), tag),
+	}
+	return nil
+}
+
+// incidentCluster picks the cluster a command acts on: the one ref names
+// (by PD cluster ID, OCM ID, external ID or name), or the incident's only
+// enriched cluster. Only the selected incident's clusters are candidates;
+// usage is the command form that names one.
+func (m model) incidentCluster(ref, usage string) (string, *ocm.ClusterInfo, error) {
+	ids := m.incidentClusterMap[m.selectedIncident.ID]
+	var enriched []string
+	for _, id := range ids {
+		if m.clusterCache[id] != nil {
+			enriched = append(enriched, id)
+		}
+	}
+
+	if ref == "" {
+		switch {
+		case len(ids) == 0:
+			return "", nil, fmt.Errorf("no clusters found in incident %s", m.selectedIncident.ID)
+		case len(enriched) == 0:
+			return "", nil, fmt.Errorf("cluster data not loaded from OCM yet")
+		case len(enriched) > 1:
+			return "", nil, fmt.Errorf("incident has %d clusters — use %s", len(enriched), usage)
+		}
+		return enriched[0], m.clusterCache[enriched[0]], nil
+	}
+
+	for _, id := range enriched {
+		info := m.clusterCache[id]
+		if ref == id || ref == info.ID || ref == info.ExternalID || ref == info.Name || ref == info.DisplayName {
+			return id, info, nil
+		}
+	}
+	return "", nil, fmt.Errorf("cluster %q is not an enriched cluster of incident %s", ref, m.selectedIncident.ID)
+}
+
+// templateData fills service log and limited support template data from
+// the selected incident, the alert firing on the cluster and OCM.
+func (m model) templateData(clusterID string, info *ocm.ClusterInfo) servicelog.Data {
+	inc := m.selectedIncident
+	d := servicelog.Data{
+		IncidentID:    inc.ID,
+		IncidentTitle: inc.Title,
+		IncidentURL:   inc.HTMLURL,
+		Service:       inc.Service.Summary,
+		ClusterID:     info.ID,
+		ExternalID:    info.ExternalID,
+		ClusterName:   clusterDisplayName(clusterID, info),
+		Organization:  info.Organization,
+		Version:       info.Version,
+		Region:        info.Region,
+	}
+	for _, a := range m.selectedIncidentAlerts {
+		n := alert.NormalizeAlert(a.Service.Summary, "", a)
+		if n.ClusterID == clusterID || getDetailFieldFromAlert("cluster_id", a) == clusterID {
+			d.AlertName = n.AlertName
+			break
+		}
+	}
+	if d.AlertName == "" {
+		d.AlertName = inc.Title
+	}
+	return d
+}
+
+func clusterDisplayName(clusterID string, info *ocm.ClusterInfo) string {
+	switch {
+	case info.DisplayName != "":
+		return info.DisplayName
+	case info.Name != "":
+		return info.Name
+	}
+	return clusterID
+}
+
+// serviceLogPreview is the confirmation prompt: the rendered log exactly
+// as it will be posted.
+func serviceLogPreview(entry ocm.ServiceLog, clusterName, incidentID string, tag bool) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Post service log to %s (%s)?\n\n", clusterName, entry.ClusterUUID)
+	visibility := "internal only"
+	if !entry.InternalOnly {
+		visibility = "CUSTOMER-VISIBLE — the cluster owner is notified"
+	}
+	fmt.Fprintf(&b, "Severity: %s · %s\n", entry.Severity, visibility)
+	fmt.Fprintf(&b, "Service:  %s\n", entry.ServiceName)
+	fmt.Fprintf(&b, "Summary:  %s\n", entry.Summary)
+	if entry.Description != "" {
+		fmt.Fprintf(&b, "\n%s\n", entry.Description)
+	}
+	if tag {
+		fmt.Fprintf(&b, "\nThen tag %s %s.\n", incidentID, serviceLogSentTag)
+	}
+	b.WriteString("\n[y/n]")
+	return b.String()
+}
+
+func postServiceLog(client ocm.OCMClient, entry ocm.ServiceLog, incidentID, clusterID, clusterName string, tag bool) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), ocmAPITimeout)
+		defer cancel()
+		err := client.PostServiceLog(ctx, entry)
+		return serviceLogPostedMsg{incidentID: incidentID, clusterID: clusterID, clusterName: clusterName, tag: tag, err: err}
+	}
+}
+
+// applyServiceLogPosted refreshes the cluster's service logs and tags the
+// incident. The tag is only offered once the log is known to be sent.
+func (m *model) applyServiceLogPosted(msg serviceLogPostedMsg) tea.Cmd {
+	if msg.err != nil {
+		log.Warn("ocm.PostServiceLog failed", "cluster_id", msg.clusterID, "error", msg.err)
+		return m.flashNotification("service log not sent: " + msg.err.Error())
+	}
+	log.Info("service log posted", "cluster_id", msg.clusterID, "incident_id", msg.incidentID)
+	cmds := []tea.Cmd{m.flashNotification("service log sent to " + msg.clusterName)}
+
+	delete(m.serviceLogCache, msg.clusterID)
+	delete(m.serviceLogErrors, msg.clusterID)
+	if info := m.clusterCache[msg.clusterID]; info != nil && m.ocmClient != nil {
+		cmds = append(cmds, getOCMServiceLogs(m.ocmClient, info.ID, info.ExternalID, msg.clusterID))
+	}
+
+	if msg.tag && m.config != nil {
+		if title, ok := m.incidentTitle(msg.incidentID); ok {
+			if newTitle := PrependTags(serviceLogSentTag, title); newTitle != title {
+				cmds = append(cmds, updateIncidentTitle(m.config, msg.incidentID, newTitle))
+			}
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// incidentTitle returns the current title of a listed or selected incident.
+func (m model) incidentTitle(id string) (string, bool) {
+	for _, inc := range m.incidentList {
+		if inc.ID == id {
+			return inc.Title, true
+		}
+	}
+	if m.selectedIncident != nil && m.selectedIncident.ID == id {
+		return m.selectedIncident.Title, true
+	}
+	return "", false
+}
+
+// incidentService returns the PagerDuty service of a listed or selected
+// incident.
+func (m model) incidentService(id string) string {
+	for _, inc := range m.incidentList {
+		if inc.ID == id {
+			return inc.Service.Summary
+		}
+	}
+	if m.selectedIncident != nil && m.selectedIncident.ID == id {
+		return m.selectedIncident.Service.Summary
+	}
+	return ""
+}
+
+// formatServiceLogTemplates renders the template library for `:sl`.
+func formatServiceLogTemplates(templates []servicelog.Template) string {
+	var b strings.Builder
+	b.WriteString("# Service Log Templates\n\n")
+	b.WriteString("Post one for the selected incident with `:sl <template> [cluster]`.\n\n")
+	for _, t := range templates {
+		visibility := "internal only"
+		if !t.InternalOnly {
+			visibility = "**customer-visible**"
+		}
+		fmt.Fprintf(&b, "* **%s** — %s, %s: %s\n", t.Name, t.Severity, visibility, t.Summary)
+	}
+	return b.String()
+}
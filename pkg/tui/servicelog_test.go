@@ -0,0 +1,193 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PagerDuty/go-pagerduty"
+	"github.com/clcollins/srepd/pkg/ocm"
+	"github.com/clcollins/srepd/pkg/pd"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serviceLogTestModel(t *testing.T) (model, *ocm.MockClient, *pd.MockPagerDutyClient) {
+	t.Helper()
+	m := createTestModel()
+	client := &pd.MockPagerDutyClient{}
+	m.config = &pd.Config{Client: client, CurrentUser: &pagerduty.User{APIObject: pagerduty.APIObject{ID: "U123"}}}
+	inc := pagerduty.Incident{
+		APIObject: pagerduty.APIObject{ID: "INC1", HTMLURL: "https://pd.example.com/incidents/INC1"},
+		Title:     "ClusterOperatorDown",
+		Service:   pagerduty.APIObject{Summary: "osd-hive"},
+	}
+	m.incidentList = []pagerduty.Incident{inc}
+	m.selectedIncident = &inc
+	m.incidentClusterMap = map[string][]string{"INC1": {"c1"}}
+	m.clusterCache = map[string]*ocm.ClusterInfo{
+		"c1": {ID: "c1", ExternalID: "uuid-1", DisplayName: "acme-prod", Version: "4.16.5", Region: "us-east-1"},
+	}
+	mock := ocm.NewMockClient()
+	m.ocmClient = mock
+
+	viper.Set("service_log_sent_tag", true)
+	t.Cleanup(func() { viper.Set("service_log_sent_tag", nil) })
+	return m, mock, client
+}
+
+func TestParseServiceLogCommand(t *testing.T) {
+	for input, want := range map[string][2]string{
+		":sl":                    {"", ""},
+		":sl investigating":      {"investigating", ""},
+		":sl resolved acme-prod": {"resolved", "acme-prod"},
+		"  :sl  handover  c1  ":  {"handover", "c1"},
+	} {
+		name, cluster, err := parseServiceLogCommand(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, [2]string{name, cluster}, input)
+	}
+	_, _, err := parseServiceLogCommand(":sl a b c")
+	assert.ErrorContains(t, err, "usage: :sl [template [cluster]]")
+
+	assert.True(t, isServiceLogCommand(":sl"))
+	assert.True(t, isServiceLogCommand(":sl resolved"))
+	assert.False(t, isServiceLogCommand(":slack"))
+}
+
+func TestDispatchServiceLogCommand_PreviewThenPost(t *testing.T) {
+	m, mock, _ := serviceLogTestModel(t)
+
+	cmd := m.dispatchServiceLogCommand(":sl investigating")
+	assert.Nil(t, cmd)
+	require.NotNil(t, m.pendingConfirmation)
+
+	prompt := m.pendingConfirmation.prompt
+	assert.Contains(t, prompt, "Post service log to acme-prod (uuid-1)?")
+	assert.Contains(t, prompt, "Severity: Info · internal only")
+	assert.Contains(t, prompt, "Summary:  SRE investigating ClusterOperatorDown")
+	assert.Contains(t, prompt, "https://pd.example.com/incidents/INC1")
+	assert.Contains(t, prompt, "Then tag INC1 [SL Sent].")
+	assert.True(t, strings.HasSuffix(prompt, "[y/n]"))
+	assert.Empty(t, mock.Posted, "nothing is posted before the user confirms")
+
+	msg, ok := m.pendingConfirmation.action().(serviceLogPostedMsg)
+	require.True(t, ok)
+	require.NoError(t, msg.err)
+	assert.True(t, msg.tag)
+	assert.Equal(t, "c1", msg.clusterID)
+	require.Len(t, mock.Posted, 1)
+	assert.Equal(t, "uuid-1", mock.Posted[0].ClusterUUID)
+	assert.True(t, mock.Posted[0].InternalOnly)
+}
+
+func TestServiceLogPreview_CustomerVisible(t *testing.T) {
+	prompt := serviceLogPreview(ocm.ServiceLog{Severity: "Warning", ServiceName: "SREManualAction", Summary: "Action required", ClusterUUID: "uuid-1"}, "acme-prod", "INC1", false)
+	assert.Contains(t, prompt, "Severity: Warning · CUSTOMER-VISIBLE — the cluster owner is notified")
+	assert.NotContains(t, prompt, "Then tag")
+}
+
+func TestDispatchServiceLogCommand_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(m *model)
+		input string
+		want  string
+	}{
+		{"unknown template", nil, ":sl nope", `unknown service log template "nope"`},
+		{"no OCM", func(m *model) { m.ocmClient = nil }, ":sl resolved", "OCM not connected"},
+		{"no incident", func(m *model) { m.selectedIncident = nil }, ":sl resolved", "no incident selected"},
+		{"not enriched", func(m *model) { m.clusterCache = nil }, ":sl resolved", "cluster data not loaded from OCM yet"},
+		{"several clusters", func(m *model) {
+			m.incidentClusterMap["INC1"] = []string{"c1", "c2"}
+			m.clusterCache["c2"] = &ocm.ClusterInfo{ID: "c2", ExternalID: "uuid-2", Name: "initech"}
+		}, ":sl resolved", "incident has 2 clusters — use :sl <template> <cluster>"},
+		{"foreign cluster", nil, ":sl resolved c9", `cluster "c9" is not an enriched cluster of incident INC1`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, _, _ := serviceLogTestModel(t)
+			if tt.setup != nil {
+				tt.setup(&m)
+			}
+			m.dispatchServiceLogCommand(tt.input)
+			assert.Nil(t, m.pendingConfirmation)
+			assert.Contains(t, m.status, tt.want)
+		})
+	}
+}
+
+func TestDispatchServiceLogCommand_ClusterRef(t *testing.T) {
+	m, _, _ := serviceLogTestModel(t)
+	m.incidentClusterMap["INC1"] = []string{"c1", "c2"}
+	m.clusterCache["c2"] = &ocm.ClusterInfo{ID: "c2", ExternalID: "uuid-2", Name: "initech"}
+
+	m.dispatchServiceLogCommand(":sl resolved initech")
+	require.NotNil(t, m.pendingConfirmation)
+	assert.Contains(t, m.pendingConfirmation.prompt, "Post service log to initech (uuid-2)?")
+}
+
+func TestApplyServiceLogPosted(t *testing.T) {
+	t.Run("tags the incident and refetches service logs", func(t *testing.T) {
+		m, _, client := serviceLogTestModel(t)
+		m.serviceLogCache = map[string][]ocm.ServiceLog{"c1": {{Summary: "old"}}}
+
+		cmd := m.applyServiceLogPosted(serviceLogPostedMsg{incidentID: "INC1", clusterID: "c1", clusterName: "acme-prod", tag: true})
+		assert.Contains(t, m.status, "service log sent to acme-prod")
+		assert.NotContains(t, m.serviceLogCache, "c1")
+
+		var titled *updatedIncidentTitleMsg
+		var logs *ocmServiceLogsMsg
+		for _, msg := range collectCmdMsgs(t, cmd, 500*time.Millisecond) {
+			switch msg := msg.(type) {
+			case updatedIncidentTitleMsg:
+				titled = &msg
+			case ocmServiceLogsMsg:
+				logs = &msg
+			}
+		}
+		require.NotNil(t, titled)
+		assert.Equal(t, "[SL Sent] ClusterOperatorDown", titled.newTitle)
+		assert.Equal(t, 1, client.CallCounts["ManageIncidentsWithContext"])
+		require.NotNil(t, logs)
+		assert.Equal(t, "c1", logs.clusterID)
+	})
+
+	t.Run("already tagged", func(t *testing.T) {
+		m, _, client := serviceLogTestModel(t)
+		m.incidentList[0].Title = "[SL Sent] ClusterOperatorDown"
+
+		cmd := m.applyServiceLogPosted(serviceLogPostedMsg{incidentID: "INC1", clusterID: "c1", clusterName: "acme-prod", tag: true})
+		collectCmdMsgs(t, cmd, 500*time.Millisecond)
+		assert.Zero(t, client.CallCounts["ManageIncidentsWithContext"])
+	})
+
+	t.Run("tagging disabled", func(t *testing.T) {
+		m, _, client := serviceLogTestModel(t)
+		viper.Set("service_log_sent_tag", false)
+
+		m.dispatchServiceLogCommand(":sl resolved")
+		require.NotNil(t, m.pendingConfirmation)
+		assert.NotContains(t, m.pendingConfirmation.prompt, "Then tag")
+		msg := m.pendingConfirmation.action().(serviceLogPostedMsg)
+		assert.False(t, msg.tag)
+
+		collectCmdMsgs(t, m.applyServiceLogPosted(msg), 500*time.Millisecond)
+		assert.Zero(t, client.CallCounts["ManageIncidentsWithContext"])
+	})
+
+	t.Run("post failed", func(t *testing.T) {
+		m, mock, client := serviceLogTestModel(t)
+		mock.PostErr = assert.AnError
+
+		m.dispatchServiceLogCommand(":sl resolved")
+		msg := m.pendingConfirmation.action().(serviceLogPostedMsg)
+		require.Error(t, msg.err)
+
+		cmd := m.applyServiceLogPosted(msg)
+		assert.Contains(t, m.status, "service log not sent: "+assert.AnError.Error())
+		collectCmdMsgs(t, cmd, 500*time.Millisecond)
+		assert.Zero(t, client.CallCounts["ManageIncidentsWithContext"])
+	})
+}
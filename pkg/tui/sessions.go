@@ -0,0 +1,373 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/PagerDuty/go-pagerduty"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+	"github.com/clcollins/srepd/pkg/launcher"
+	"github.com/clcollins/srepd/pkg/pd"
+)
+
+// sessionTimeFormat is how session start and exit times are shown.
+const sessionTimeFormat = "15:04:05"
+
+// sessionRow is a line of the sessions list: a session launched in this
+// run of srepd, the tmux window of a login, or both.
+type sessionRow struct {
+	session *launcher.Session    // nil for a window left by an earlier run
+	window  *launcher.TmuxWindow // nil unless the login has a tmux window open
+}
+
+func (r sessionRow) incidentID() string {
+	if r.session != nil {
+		return r.session.IncidentID
+	}
+	return r.window.IncidentID
+}
+
+func (r sessionRow) clusterID() string {
+	if r.session != nil {
+		return r.session.ClusterID
+	}
+	return r.window.ClusterID
+}
+
+// shareSessions makes both launchers record their logins in one registry,
+// so the sessions list shows cluster and rosa-boundary logins together.
+func shareSessions(l, rosaBoundary *launcher.ClusterLauncher) *launcher.SessionRegistry {
+	sessions := launcher.NewSessionRegistry()
+	l.RecordSessions(sessions)
+	rosaBoundary.RecordSessions(sessions)
+	return sessions
+}
+
+func isSessionsCommand(input string) bool {
+	return strings.TrimSpace(input) == ":sessions"
+}
+
+// findTmuxWindow returns the listed window with the given name.
+func findTmuxWindow(windows []launcher.TmuxWindow, name string) (launcher.TmuxWindow, bool) {
+	i := slices.IndexFunc(windows, func(w launcher.TmuxWindow) bool { return w.Name == name })
+	if i < 0 {
+		return launcher.TmuxWindow{}, false
+	}
+	return windows[i], true
+}
+
+// sessionOpen reports whether a session is still open. A tmux login is
+// open while its window is, as of the last listing; any other login
+// while the launched process runs.
+func (m model) sessionOpen(s launcher.Session) bool {
+	if s.TmuxWindow != "" && m.tmux != nil {
+		_, ok := findTmuxWindow(m.tmuxWindows, s.TmuxWindow)
+		return ok
+	}
+	return s.Running()
+}
+
+// sessionState describes a session for the sessions list and the Details
+// tab: "running", "closed" for a tmux window that is gone, or the exit
+// code.
+func (m model) sessionState(s launcher.Session) string {
+	switch {
+	case m.sessionOpen(s):
+		return "running"
+	case s.TmuxWindow != "" && m.tmux != nil:
+		return "closed"
+	default:
+		return fmt.Sprintf("exited (%d)", s.ExitCode)
+	}
+}
+
+// buildSessionRows lists the launched sessions in launch order, each with
+// its tmux window, followed by the login windows no session accounts for.
+// A window belongs to the latest session that opened it.
+func (m model) buildSessionRows() []sessionRow {
+	sessions := m.sessions.List()
+	latest := make(map[string]int)
+	for i, s := range sessions {
+		if s.TmuxWindow != "" {
+			latest[s.TmuxWindow] = i
+		}
+	}
+
+	var rows []sessionRow
+	claimed := make(map[string]bool)
+	for i := range sessions {
+		row := sessionRow{session: &sessions[i]}
+		if name := sessions[i].TmuxWindow; name != "" && latest[name] == i {
+			if w, ok := findTmuxWindow(m.tmuxWindows, name); ok {
+				row.window = &w
+				claimed[name] = true
+			}
+		}
+		rows = append(rows, row)
+	}
+	if m.tmux != nil {
+		for i := range m.tmuxWindows {
+			if !claimed[m.tmuxWindows[i].Name] {
+				rows = append(rows, sessionRow{window: &m.tmuxWindows[i]})
+			}
+		}
+	}
+	return rows
+}
+
+// openSessions opens the sessions list. With tmux the login windows are
+// listed first, so the list shows which tmux sessions are still open.
+func (m *model) openSessions() tea.Cmd {
+	if m.tmux != nil {
+		return listTmuxWindows(m.tmux, true)
+	}
+	return m.showSessions()
+}
+
+// showSessions fills the sessions list, keeping the cursor when the list
+// is already open.
+func (m *model) showSessions() tea.Cmd {
+	rows := m.buildSessionRows()
+	if len(rows) == 0 {
+		m.sessionsMode = false
+		m.sessionRows = nil
+		m.table.Focus()
+		return m.flashNotification("no open cluster sessions")
+	}
+
+	cursor := m.sessionsTable.Cursor()
+	m.sessionRows = rows
+	tableRows := make([]table.Row, 0, len(rows))
+	for _, r := range rows {
+		state, started, pid, window := "running", "-", "-", ""
+		if r.session != nil {
+			state = m.sessionState(*r.session)
+			started = r.session.Started.Format(sessionTimeFormat)
+			if r.session.PID > 0 {
+				pid = strconv.Itoa(r.session.PID)
+			}
+		}
+		if r.window != nil {
+			window = r.window.ID
+		} else if r.session != nil && r.session.Embedded {
+			window = "pane"
+		}
+		tableRows = append(tableRows, table.Row{r.incidentID(), r.clusterID(), state, started, pid, window})
+	}
+	if !m.sessionsMode {
+		m.sessionsTable = newTableWithStyles()
+		cursor = 0
+	}
+	m.sessionsTable.SetStyles(m.styles.Table)
+	m.sessionsTable.SetColumns([]table.Column{
+		{Title: "Incident", Width: m.layout.ClusterSelectServiceWidth},
+		{Title: "Cluster ID", Width: m.layout.ClusterSelectClusterIDWidth},
+		{Title: "State", Width: 12},
+		{Title: "Started", Width: 9},
+		{Title: "PID", Width: 8},
+		{Title: "Window", Width: 8},
+	})
+	m.sessionsTable.SetRows(tableRows)
+	m.sessionsTable.SetHeight(m.layout.TableHeight)
+	m.sessionsTable.SetCursor(min(cursor, len(tableRows)-1))
+	m.sessionsTable.Focus()
+	m.sessionsMode = true
+	return nil
+}
+
+// sessionsChanged refreshes what shows sessions after one starts or
+// exits: the open sessions list and the Details tab.
+func (m *model) sessionsChanged() tea.Cmd {
+	var cmds []tea.Cmd
+	if m.tmux != nil {
+		cmds = append(cmds, listTmuxWindows(m.tmux, false))
+	} else if m.sessionsMode {
+		cmds = append(cmds, m.showSessions())
+	}
+	if m.viewingIncident {
+		cmds = append(cmds, func() tea.Msg { return renderIncidentMsg("sessions changed") })
+	}
+	return tea.Batch(cmds...)
+}
+
+// renderSessionsSection lists the sessions of the selected incident for
+// the Details tab.
+func (m model) renderSessionsSection() string {
+	if m.selectedIncident == nil {
+		return ""
+	}
+	sessions := m.sessions.ForIncident(m.selectedIncident.ID)
+	if len(sessions) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\n## Cluster Sessions\n\n")
+	for _, s := range sessions {
+		fmt.Fprintf(&b, "* %s: %s, started %s", s.ClusterID, m.sessionState(s), s.Started.Format(sessionTimeFormat))
+		if !s.Running() && s.TmuxWindow == "" {
+			fmt.Fprintf(&b, ", exited %s", s.Exited.Format(sessionTimeFormat))
+		}
+		if s.PID > 0 {
+			fmt.Fprintf(&b, " (pid %d)", s.PID)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\nRun `:sessions` to list every session.\n")
+	return b.String()
+}
+
+// openSessionsOffList returns the open sessions of incidents that are not
+// in the incident list. They may be resolved, or only filtered out.
+func (m model) openSessionsOffList() []launcher.Session {
+	var open []launcher.Session
+	for _, s := range m.sessions.List() {
+		if s.IncidentID == "" || !m.sessionOpen(s) {
+			continue
+		}
+		if !slices.ContainsFunc(m.incidentList, func(i pagerduty.Incident) bool { return i.ID == s.IncidentID }) {
+			open = append(open, s)
+		}
+	}
+	return open
+}
+
+type quitSessionsCheckedMsg struct {
+	// open are the open sessions of incidents PagerDuty reports resolved.
+	open []launcher.Session
+}
+
+// checkResolvedSessions asks PagerDuty for the status of the incidents of
+// sessions and keeps the sessions of resolved ones. An incident that
+// cannot be fetched does not hold up quitting.
+func checkResolvedSessions(p *pd.Config, sessions []launcher.Session) tea.Cmd {
+	return func() tea.Msg {
+		resolved := make(map[string]bool)
+		var open []launcher.Session
+		for _, s := range sessions {
+			isResolved, checked := resolved[s.IncidentID]
+			if !checked {
+				i, err := pd.GetIncident(p.Client, s.IncidentID)
+				if err != nil {
+					log.Warn("incident status not checked before quitting", "incident_id", s.IncidentID, "error", err)
+				}
+				isResolved = err == nil && i != nil && i.Status == "resolved"
+				resolved[s.IncidentID] = isResolved
+			}
+			if isResolved {
+				open = append(open, s)
+			}
+		}
+		return quitSessionsCheckedMsg{open: open}
+	}
+}
+
+// quit quits srepd, asking first while sessions are still open for
+// resolved incidents. Incidents that left the incident list are looked up
+// first: a filter change also takes them off it.
+func (m model) quit() (tea.Model, tea.Cmd) {
+	if m.pendingConfirmation != nil && m.pendingConfirmation.quit {
+		return m, tea.Quit
+	}
+	candidates := m.openSessionsOffList()
+	if len(candidates) == 0 || m.config == nil || m.config.Client == nil {
+		return m, tea.Quit
+	}
+	m.setStatus("checking the incidents of open sessions before quitting...")
+	return m, checkResolvedSessions(m.config, candidates)
+}
+
+// applyQuitSessionsChecked quits, or asks first when sessions are open
+// for resolved incidents.
+func (m *model) applyQuitSessionsChecked(msg quitSessionsCheckedMsg) tea.Cmd {
+	if len(msg.open) == 0 {
+		return tea.Quit
+	}
+	names := make([]string, 0, len(msg.open))
+	for _, s := range msg.open {
+		names = append(names, fmt.Sprintf("%s (%s)", s.ClusterID, s.IncidentID))
+	}
+	m.pendingConfirmation = &confirmActionState{
+		prompt: fmt.Sprintf("Sessions are still open for resolved incidents: %s. Quit anyway? [y/n]", strings.Join(names, ", ")),
+		action: tea.Quit,
+		quit:   true,
+	}
+	return nil
+}
+
+// selectedSessionRow returns the row under the sessions list cursor.
+func (m model) selectedSessionRow() (sessionRow, bool) {
+	i := m.sessionsTable.Cursor()
+	if i < 0 || i >= len(m.sessionRows) {
+		return sessionRow{}, false
+	}
+	return m.sessionRows[i], true
+}
+
+func (m *model) closeSessionsList() {
+	m.sessionsMode = false
+	m.sessionRows = nil
+	m.table.Focus()
+}
+
+func switchSessionsFocusMode(m model, msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, defaultKeyMap.Quit):
+			return m.quit()
+
+		case key.Matches(msg, defaultKeyMap.Back):
+			m.closeSessionsList()
+			return m, nil
+
+		case key.Matches(msg, defaultKeyMap.Enter):
+			row, ok := m.selectedSessionRow()
+			if !ok {
+				m.setStatus("no session selected")
+				return m, nil
+			}
+			if row.session != nil && row.session.Embedded && row.session.Running() {
+				if m.focusPaneForSession(*row.session) {
+					m.closeSessionsList()
+					return m, nil
+				}
+			}
+			if row.window == nil {
+				m.setStatus("only open tmux sessions and terminal panes can be switched to")
+				return m, nil
+			}
+			m.closeSessionsList()
+			tmux, w := m.tmux, *row.window
+			return m, func() tea.Msg { return tmuxWindowSelectedMsg{window: w, err: tmux.Select(w)} }
+
+		case msg.String() == "x":
+			row, ok := m.selectedSessionRow()
+			if !ok {
+				m.setStatus("no session selected")
+				return m, nil
+			}
+			if row.window == nil {
+				m.setStatus("only open tmux sessions can be closed from srepd")
+				return m, nil
+			}
+			w := *row.window
+			m.pendingConfirmation = &confirmActionState{
+				prompt: fmt.Sprintf("Close the session for %s (%s)? Anything running in it is killed. [y/n]", w.ClusterID, w.IncidentID),
+				action: killTmuxWindow(m.tmux, w),
+			}
+			return m, nil
+
+		default:
+			var cmd tea.Cmd
+			m.sessionsTable, cmd = m.sessionsTable.Update(msg)
+			return m, cmd
+		}
+	}
+	return m, nil
+}
@@ -0,0 +1,217 @@
+package tui
+
+import (
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/PagerDuty/go-pagerduty"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/clcollins/srepd/pkg/launcher"
+	"github.com/clcollins/srepd/pkg/pd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var sessionTestClock = time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC)
+
+// sessionsTestModel returns a model whose launcher records into a
+// registry with a fixed clock.
+func sessionsTestModel() model {
+	m := createTestModel()
+	m.sessions = launcher.NewSessionRegistryWithClock(func() time.Time { return sessionTestClock })
+	m.launcher.RecordSessions(m.sessions)
+	return m
+}
+
+// startSession launches cmd as a session of the incident's cluster; a
+// session whose command exits is waited for, a running one is killed at
+// the end of the test.
+func startSession(t *testing.T, m model, cmd *exec.Cmd, incidentID, clusterID string) {
+	t.Helper()
+	wait, err := m.launcher.Start(cmd, incidentID, clusterID)
+	require.NoError(t, err)
+	if cmd.Args[0] == "sleep" {
+		t.Cleanup(func() {
+			_ = cmd.Process.Kill()
+			_ = wait()
+		})
+		return
+	}
+	_ = wait()
+}
+
+func TestLogin_RecordsSession(t *testing.T) {
+	l, err := launcher.NewClusterLauncherWithToolbox("true", "ocm-container --cluster-id %%CLUSTER_ID%%", "false", func() bool { return false })
+	require.NoError(t, err)
+	sessions := launcher.NewSessionRegistry()
+	l.RecordSessions(sessions)
+
+	incident := &pagerduty.Incident{APIObject: pagerduty.APIObject{ID: "Q1AAA"}}
+	vars := map[string]string{"%%CLUSTER_ID%%": "cluster-a", "%%INCIDENT_ID%%": "Q1AAA"}
+	finished, ok := login(vars, l, incident, nil, nil)().(loginFinishedMsg)
+	require.True(t, ok)
+	require.NoError(t, finished.err)
+
+	got := sessions.List()
+	require.Len(t, got, 1)
+	assert.Equal(t, "Q1AAA", got[0].IncidentID)
+	assert.Equal(t, "cluster-a", got[0].ClusterID)
+	assert.Equal(t, "true", got[0].Command[0])
+
+	exited := finished.waitCmd().(loginProcessExitedMsg)
+	require.NoError(t, exited.exitErr)
+	assert.False(t, sessions.List()[0].Running())
+}
+
+func TestSessionsList_RunningAndExited(t *testing.T) {
+	m := sessionsTestModel()
+	startSession(t, m, exec.Command("sleep", "10"), "Q1AAA", "cluster-a")
+	startSession(t, m, exec.Command("sh", "-c", "exit 2"), "Q2BBB", "cluster-b")
+
+	assert.Nil(t, m.openSessions(), "without tmux the list opens at once")
+	require.True(t, m.sessionsMode)
+	rows := m.sessionsTable.Rows()
+	require.Len(t, rows, 2)
+	assert.Equal(t, []string{"Q1AAA", "cluster-a", "running", "09:30:00"}, []string(rows[0][:4]))
+	assert.NotEqual(t, "-", rows[0][4], "the PID is shown")
+	assert.Equal(t, []string{"Q2BBB", "cluster-b", "exited (2)"}, []string(rows[1][:3]))
+
+	// Only tmux windows can be jumped to or closed.
+	result, _ := switchSessionsFocusMode(m, tea.KeyMsg{Type: tea.KeyEnter})
+	m = result.(model)
+	assert.True(t, m.sessionsMode)
+	assert.Contains(t, m.status, "only open tmux sessions and terminal panes can be switched to")
+
+	result, _ = switchSessionsFocusMode(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	m = result.(model)
+	assert.Nil(t, m.pendingConfirmation)
+	assert.Contains(t, m.status, "only open tmux sessions can be closed")
+}
+
+func TestSessionsList_TmuxWindows(t *testing.T) {
+	m, fake := tmuxTestModel()
+	m.sessions = launcher.NewSessionRegistryWithClock(func() time.Time { return sessionTestClock })
+
+	// A tmux login is recorded under its window name; the tmux command
+	// itself exits as soon as the window is open.
+	tmuxLauncher, err := launcher.NewClusterLauncherWithToolbox("tmux new-window", "ocm-container --cluster-id %%CLUSTER_ID%%", "false", func() bool { return false })
+	require.NoError(t, err)
+	tmuxLauncher.RecordSessions(m.sessions)
+	for _, ids := range [][2]string{{"Q1AAA", "cluster-a"}, {"Q3CCC", "cluster-c"}} {
+		wait, err := tmuxLauncher.Start(exec.Command("true"), ids[0], ids[1])
+		require.NoError(t, err)
+		require.NoError(t, wait())
+	}
+
+	m.applyTmuxWindows(m.openSessions()().(tmuxWindowsMsg))
+	require.True(t, m.sessionsMode)
+	rows := m.sessionsTable.Rows()
+	require.Len(t, rows, 3)
+	assert.Equal(t, []string{"Q1AAA", "cluster-a", "running"}, []string(rows[0][:3]))
+	assert.Equal(t, "@2", rows[0][5])
+	assert.Equal(t, []string{"Q3CCC", "cluster-c", "closed"}, []string(rows[1][:3]), "its window is gone")
+	assert.Equal(t, []string{"Q2BBB", "cluster-b", "running", "-", "-", "@5"}, []string(rows[2]), "a window of an earlier run")
+
+	result, cmd := switchSessionsFocusMode(m, tea.KeyMsg{Type: tea.KeyEnter})
+	assert.False(t, result.(model).sessionsMode)
+	assert.Equal(t, "@2", cmd().(tmuxWindowSelectedMsg).window.ID)
+	assert.Equal(t, []string{"switch-client -t work", "select-window -t @2"}, fake.calls)
+}
+
+func TestSessionsChanged_RefreshesOpenList(t *testing.T) {
+	m := sessionsTestModel()
+	startSession(t, m, exec.Command("true"), "Q1AAA", "cluster-a")
+	m.openSessions()
+	require.Len(t, m.sessionsTable.Rows(), 1)
+
+	startSession(t, m, exec.Command("true"), "Q2BBB", "cluster-b")
+	m.sessionsChanged()
+	assert.Len(t, m.sessionsTable.Rows(), 2)
+}
+
+func TestDetailsTab_ListsIncidentSessions(t *testing.T) {
+	m := sessionsTestModel()
+	m.selectedIncident = &pagerduty.Incident{APIObject: pagerduty.APIObject{ID: "Q1AAA"}}
+	assert.Empty(t, m.renderSessionsSection())
+
+	startSession(t, m, exec.Command("sleep", "10"), "Q1AAA", "cluster-a")
+	startSession(t, m, exec.Command("sh", "-c", "exit 1"), "Q1AAA", "cluster-b")
+	startSession(t, m, exec.Command("true"), "Q2BBB", "cluster-c")
+
+	section := m.renderSessionsSection()
+	assert.Contains(t, section, "## Cluster Sessions")
+	assert.Contains(t, section, "* cluster-a: running, started 09:30:00 (pid ")
+	assert.Contains(t, section, "* cluster-b: exited (1), started 09:30:00, exited 09:30:00")
+	assert.NotContains(t, section, "cluster-c", "sessions of other incidents are not listed")
+
+	content, err := m.renderDetailsTab(incidentSummary{ID: "Q1AAA"})
+	require.NoError(t, err)
+	assert.Contains(t, content, "## Cluster Sessions")
+}
+
+func TestQuit_WarnsAboutSessionsOfResolvedIncidents(t *testing.T) {
+	m := sessionsTestModel()
+	client := &pd.MockPagerDutyClient{IncidentResponses: map[string]*pagerduty.Incident{
+		"Q3CCC": {APIObject: pagerduty.APIObject{ID: "Q3CCC"}, Status: "resolved"},
+		"Q4DDD": {APIObject: pagerduty.APIObject{ID: "Q4DDD"}, Status: "acknowledged"},
+	}}
+	m.config = &pd.Config{Client: client}
+	m.incidentList = []pagerduty.Incident{{APIObject: pagerduty.APIObject{ID: "Q1AAA"}}}
+	startSession(t, m, exec.Command("sleep", "10"), "Q1AAA", "cluster-a")
+	startSession(t, m, exec.Command("true"), "Q2BBB", "cluster-b")
+
+	// Open sessions of incidents still in the list, and exited ones, do
+	// not hold up quitting.
+	_, cmd := m.quit()
+	require.NotNil(t, cmd)
+	assert.IsType(t, tea.QuitMsg{}, cmd())
+	assert.Zero(t, client.CallCounts["GetIncidentWithContext"])
+
+	// An incident filtered out of the list is not resolved.
+	startSession(t, m, exec.Command("sleep", "10"), "Q4DDD", "cluster-d")
+	_, cmd = m.quit()
+	require.NotNil(t, cmd)
+	assert.IsType(t, tea.QuitMsg{}, m.applyQuitSessionsChecked(cmd().(quitSessionsCheckedMsg))())
+
+	startSession(t, m, exec.Command("sleep", "10"), "Q3CCC", "cluster-c")
+	result, cmd := m.keyMsgHandler(tea.KeyMsg{Type: tea.KeyCtrlQ})
+	m = result.(model)
+	require.NotNil(t, cmd)
+	result, cmd = m.Update(cmd())
+	m = result.(model)
+	assert.Nil(t, cmd)
+	require.NotNil(t, m.pendingConfirmation)
+	assert.Contains(t, m.pendingConfirmation.prompt, "still open for resolved incidents: cluster-c (Q3CCC).")
+
+	result, cmd = m.keyMsgHandler(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	assert.Nil(t, result.(model).pendingConfirmation)
+	require.NotNil(t, cmd)
+	assert.IsType(t, tea.QuitMsg{}, cmd())
+
+	// Quitting again at the warning confirms it.
+	_, cmd = m.keyMsgHandler(tea.KeyMsg{Type: tea.KeyCtrlQ})
+	require.NotNil(t, cmd)
+	assert.IsType(t, tea.QuitMsg{}, cmd())
+}
+
+func TestQuit_FromEveryMode(t *testing.T) {
+	m := sessionsTestModel()
+	m.config = &pd.Config{Client: &pd.MockPagerDutyClient{IncidentResponses: map[string]*pagerduty.Incident{
+		"Q3CCC": {APIObject: pagerduty.APIObject{ID: "Q3CCC"}, Status: "resolved"},
+	}}}
+	startSession(t, m, exec.Command("sleep", "10"), "Q3CCC", "cluster-c")
+	quitKey := tea.KeyMsg{Type: tea.KeyCtrlQ}
+
+	for name, handler := range map[string]func(model, tea.Msg) (tea.Model, tea.Cmd){
+		"cluster select": switchClusterSelectFocusMode,
+		"approvals":      switchApprovalsFocusMode,
+		"chat":           switchChatFocusMode,
+		"merge":          switchMergeFocusMode,
+	} {
+		_, cmd := handler(m, quitKey)
+		require.NotNil(t, cmd, name)
+		_, ok := cmd().(quitSessionsCheckedMsg)
+		assert.True(t, ok, "%s checks sessions before quitting", name)
+	}
+}
@@ -0,0 +1,394 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/PagerDuty/go-pagerduty"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/log"
+	"github.com/clcollins/srepd/pkg/launcher"
+	"github.com/clcollins/srepd/pkg/termpane"
+	"github.com/spf13/viper"
+)
+
+// paneTerm is the terminal type a pane's programs are told they run on;
+// it is what the pane's emulator understands.
+const paneTerm = "xterm-256color"
+
+// terminalPane is a login running in the embedded terminal.
+type terminalPane struct {
+	*termpane.Pane
+	incidentID string
+	clusterID  string
+}
+
+// paneOpenedMsg reports a login started in a new pane.
+type paneOpenedMsg struct {
+	pane *terminalPane
+	err  error
+}
+
+// paneOutputMsg reports that a pane printed something and needs drawing.
+type paneOutputMsg struct {
+	pane *terminalPane
+}
+
+// paneExitedMsg reports that the command of a pane exited.
+type paneExitedMsg struct {
+	pane *terminalPane
+}
+
+// closePaneMsg closes a pane, ending its session if it still runs.
+type closePaneMsg struct {
+	pane *terminalPane
+}
+
+// resolveEmbeddedTerminal reads embedded_terminal. Panes need a
+// pseudo-terminal srepd can drive itself, which only the Linux build has.
+func resolveEmbeddedTerminal() bool {
+	if !viper.GetBool("embedded_terminal") {
+		return false
+	}
+	if !termpane.Supported {
+		log.Warn("embedded_terminal is only supported on Linux; logins open in a terminal window")
+		return false
+	}
+	log.Info("Embedded terminal enabled")
+	return true
+}
+
+// startLogin logs into a cluster: in a pane of the embedded terminal when
+// one is configured, or in a terminal window. With reuse, a login to a
+// cluster that already has an open pane or tmux window switches to it.
+func (m *model) startLogin(title string, vars map[string]string, l launcher.ClusterLauncher, reuse bool) tea.Cmd {
+	if !m.embeddedTerminal {
+		if reuse {
+			return loginOrSwitch(m.tmux, vars, l, m.selectedIncident, m.selectedIncidentAlerts, m.selectedIncidentNotes)
+		}
+		return login(vars, l, m.selectedIncident, m.selectedIncidentAlerts, m.selectedIncidentNotes)
+	}
+
+	clusterID := vars["%%CLUSTER_ID%%"]
+	if reuse {
+		i := slices.IndexFunc(m.panes, func(p *terminalPane) bool { return p.clusterID == clusterID && !p.Exited() })
+		if i >= 0 {
+			m.focusPane(i)
+			return nil
+		}
+	}
+	cols, rows := m.newPaneSize()
+	return openPane(title, vars, l, m.selectedIncident, m.selectedIncidentAlerts, m.selectedIncidentNotes, cols, rows)
+}
+
+// newPaneSize returns the size of the pane area once a pane is open.
+func (m model) newPaneSize() (cols, rows int) {
+	l := withTerminalPane(m.layout, m.styles)
+	return l.PaneWidth, l.PaneHeight
+}
+
+// openPane starts a login on a new pane. The login runs without a
+// terminal window, like a custom action, with the PAGERDUTY_* variables
+// passed the same way.
+func openPane(title string, vars map[string]string, l launcher.ClusterLauncher, incident *pagerduty.Incident, alerts []pagerduty.IncidentAlert, notes []pagerduty.IncidentNote, cols, rows int) tea.Cmd {
+	return func() tea.Msg {
+		clusterID := vars["%%CLUSTER_ID%%"]
+		envFlags := buildPagerDutyEnvVars(incident, alerts, notes, clusterID)
+		command, processEnvVars := buildDirectCommand(l, vars, envFlags)
+
+		c := exec.Command(command[0], command[1:]...)
+		c.Env = append(os.Environ(), "TERM="+paneTerm)
+		c.Env = append(c.Env, processEnvVars...)
+		log.Debug("tui.openPane()", "command", c.String())
+
+		var incidentID string
+		if incident != nil {
+			incidentID = incident.ID
+		}
+		start := func(c *exec.Cmd) (func() error, error) {
+			return l.StartEmbedded(c, incidentID, clusterID)
+		}
+		p, err := termpane.Start(c, title, cols, rows, start)
+		if err != nil {
+			log.Error("tui.openPane()", "error", err)
+			return paneOpenedMsg{err: err}
+		}
+		return paneOpenedMsg{pane: &terminalPane{Pane: p, incidentID: incidentID, clusterID: clusterID}}
+	}
+}
+
+// applyPaneOutput keeps waiting on a pane that is still open; the output
+// itself is drawn with the next view.
+func (m *model) applyPaneOutput(msg paneOutputMsg) tea.Cmd {
+	if !slices.Contains(m.panes, msg.pane) {
+		return nil
+	}
+	return waitForPane(msg.pane)
+}
+
+// waitForPane waits for a pane to print or exit.
+func waitForPane(p *terminalPane) tea.Cmd {
+	return func() tea.Msg {
+		select {
+		case <-p.Changed():
+			return paneOutputMsg{pane: p}
+		case <-p.Done():
+			return paneExitedMsg{pane: p}
+		}
+	}
+}
+
+// applyPaneOpened adds a new pane as the active tab and gives it the
+// keyboard.
+func (m *model) applyPaneOpened(msg paneOpenedMsg) tea.Cmd {
+	if msg.err != nil {
+		m.status = fmt.Sprintf("failed to login: %s", msg.err)
+		return func() tea.Msg { return errMsg{msg.err} }
+	}
+	log.Info("login completed", "incident_id", msg.pane.incidentID, "cluster_id", msg.pane.clusterID, "embedded", true)
+	m.panes = append(m.panes, msg.pane)
+	m.focusPane(len(m.panes) - 1)
+	m.recomputeLayout()
+	return tea.Batch(waitForPane(msg.pane), m.sessionsChanged())
+}
+
+// applyPaneExited reports how a pane's session ended and returns the
+// keyboard to srepd. The pane stays open so its last screen can be read.
+func (m *model) applyPaneExited(msg paneExitedMsg) tea.Cmd {
+	i := slices.Index(m.panes, msg.pane)
+	if i < 0 {
+		// Closed from srepd, which already said so.
+		return m.sessionsChanged()
+	}
+	if i == m.activePane {
+		m.paneFocused = false
+		m.paneChordPending = false
+	}
+	status := fmt.Sprintf("session for %s exited", msg.pane.Title)
+	if err := msg.pane.Err(); err != nil {
+		status = fmt.Sprintf("session for %s exited: %s", msg.pane.Title, err)
+	}
+	return tea.Batch(m.flashNotification(status), m.sessionsChanged())
+}
+
+// closePane removes a pane, ending its session.
+func (m *model) closePane(p *terminalPane) tea.Cmd {
+	i := slices.Index(m.panes, p)
+	if i < 0 {
+		return nil
+	}
+	if err := p.Close(); err != nil {
+		log.Debug("tui.closePane()", "error", err)
+	}
+	m.panes = slices.Delete(m.panes, i, i+1)
+	if m.activePane >= len(m.panes) {
+		m.activePane = max(len(m.panes)-1, 0)
+	}
+	m.paneScrolling = false
+	if len(m.panes) == 0 {
+		m.paneFocused = false
+	}
+	m.recomputeLayout()
+	return m.flashNotification(fmt.Sprintf("closed the session for %s", p.Title))
+}
+
+// confirmClosePane closes a pane, asking first while its session runs.
+func (m *model) confirmClosePane(p *terminalPane) tea.Cmd {
+	if p.Exited() {
+		return m.closePane(p)
+	}
+	m.pendingConfirmation = &confirmActionState{
+		prompt: fmt.Sprintf("Close the session for %s? Anything running in it is killed. [y/n]", p.Title),
+		action: func() tea.Msg { return closePaneMsg{pane: p} },
+	}
+	return nil
+}
+
+func (m *model) focusPane(i int) {
+	m.activePane = i
+	m.paneFocused = true
+	m.paneChordPending = false
+	m.paneScrolling = false
+}
+
+// focusPaneForSession gives the keyboard to the pane running a session.
+func (m *model) focusPaneForSession(s launcher.Session) bool {
+	i := slices.IndexFunc(m.panes, func(p *terminalPane) bool { return p.PID() == s.PID })
+	if i < 0 {
+		return false
+	}
+	m.focusPane(i)
+	return true
+}
+
+// chordFocusPane gives the keyboard to the embedded terminal.
+func chordFocusPane(m model) (tea.Model, tea.Cmd) {
+	if len(m.panes) == 0 {
+		m.setStatus("no terminal pane open")
+		return m, nil
+	}
+	m.focusPane(m.activePane)
+	m.setStatus("")
+	return m, nil
+}
+
+// switchPaneFocusMode handles keys while a pane has the keyboard. They go
+// to the pane's program, except after the chord prefix, which picks a
+// pane command; the prefix twice sends the prefix itself.
+func switchPaneFocusMode(m model, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p := m.panes[m.activePane]
+	keyStr := msg.String()
+
+	if m.paneScrolling {
+		switch keyStr {
+		case "esc", "q":
+			m.paneScrolling = false
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.paneScrollViewport, cmd = m.paneScrollViewport.Update(msg)
+		return m, cmd
+	}
+
+	if m.paneChordPending {
+		m.paneChordPending = false
+		m.setStatus("")
+		switch keyStr {
+		case "t", "esc":
+			m.paneFocused = false
+		case "n":
+			m.focusPane((m.activePane + 1) % len(m.panes))
+		case "p":
+			m.focusPane((m.activePane + len(m.panes) - 1) % len(m.panes))
+		case "[":
+			m.openPaneScrollback(p)
+		case "x":
+			return m, m.confirmClosePane(p)
+		case m.chordPrefix:
+			m.writeToPane(p, msg)
+		default:
+			m.setStatus(fmt.Sprintf("unknown terminal chord: %s %s", m.chordPrefix, keyStr))
+		}
+		return m, nil
+	}
+
+	if keyStr == m.chordPrefix {
+		m.paneChordPending = true
+		m.setStatus(fmt.Sprintf("%s ... (t: back to srepd, n/p: next/previous tab, [: scroll back, x: close, %s: send %s)", m.chordPrefix, m.chordPrefix, m.chordPrefix))
+		return m, nil
+	}
+	m.writeToPane(p, msg)
+	return m, nil
+}
+
+func (m *model) writeToPane(p *terminalPane, msg tea.KeyMsg) {
+	b := paneKeyBytes(msg)
+	if len(b) == 0 {
+		return
+	}
+	if _, err := p.Write(b); err != nil {
+		m.setStatus(fmt.Sprintf("%s: %s", p.Title, err))
+	}
+}
+
+// openPaneScrollback shows what a pane has printed, scrolled to the end.
+func (m *model) openPaneScrollback(p *terminalPane) {
+	m.paneScrollViewport = viewport.New(m.layout.PaneWidth, m.layout.PaneHeight)
+	m.paneScrollViewport.SetContent(strings.Join(p.Scrollback(), "\n"))
+	m.paneScrollViewport.GotoBottom()
+	m.paneScrolling = true
+}
+
+// paneKeyBytes translates a key into what a terminal sends for it.
+func paneKeyBytes(k tea.KeyMsg) []byte {
+	var b []byte
+	switch k.Type {
+	case tea.KeyRunes:
+		b = []byte(string(k.Runes))
+	case tea.KeySpace:
+		b = []byte{' '}
+	case tea.KeyShiftTab:
+		b = []byte("\x1b[Z")
+	case tea.KeyUp:
+		b = []byte("\x1b[A")
+	case tea.KeyDown:
+		b = []byte("\x1b[B")
+	case tea.KeyRight:
+		b = []byte("\x1b[C")
+	case tea.KeyLeft:
+		b = []byte("\x1b[D")
+	case tea.KeyHome:
+		b = []byte("\x1b[H")
+	case tea.KeyEnd:
+		b = []byte("\x1b[F")
+	case tea.KeyPgUp:
+		b = []byte("\x1b[5~")
+	case tea.KeyPgDown:
+		b = []byte("\x1b[6~")
+	case tea.KeyInsert:
+		b = []byte("\x1b[2~")
+	case tea.KeyDelete:
+		b = []byte("\x1b[3~")
+	default:
+		// Control keys, enter, tab, backspace and escape are the control
+		// characters themselves.
+		if (k.Type >= tea.KeyCtrlAt && k.Type <= tea.KeyCtrlUnderscore) || k.Type == tea.KeyBackspace {
+			b = []byte{byte(k.Type)}
+		}
+	}
+	if k.Alt && len(b) > 0 {
+		b = append([]byte{0x1b}, b...)
+	}
+	return b
+}
+
+// showsTerminalPane reports whether the current view leaves room for the
+// embedded terminal below it. Forms, the tour and chat take the screen.
+func (m model) showsTerminalPane() bool {
+	if len(m.panes) == 0 || m.err != nil {
+		return false
+	}
+	return !m.configMode && !m.configModeRequested && !m.bulkSilenceMode &&
+		!m.teamSelectMode && !m.tourMode && !m.chatMode
+}
+
+// renderTerminalPanes draws the embedded terminal: a line of tabs and the
+// active pane's screen, or its scrollback.
+func (m model) renderTerminalPanes() string {
+	var tabs []string
+	for i, p := range m.panes {
+		label := fmt.Sprintf("%d %s", i+1, p.Title)
+		if p.Exited() {
+			label += " (exited)"
+		}
+		style := m.styles.Muted
+		if i == m.activePane {
+			style = lipgloss.NewStyle().Foreground(m.theme.Highlight).Bold(true)
+		}
+		tabs = append(tabs, style.Render("["+label+"]"))
+	}
+	hint := fmt.Sprintf("%s t: focus terminal", m.chordPrefix)
+	switch {
+	case m.paneScrolling:
+		hint = "scrollback — esc: back to the terminal"
+	case m.paneFocused:
+		hint = fmt.Sprintf("%s t: back to srepd", m.chordPrefix)
+	}
+	bar := " " + strings.Join(tabs, " ") + "  " + m.styles.Muted.Render(hint)
+
+	p := m.panes[m.activePane]
+	content := p.Render(m.paneFocused)
+	if m.paneScrolling {
+		content = m.paneScrollViewport.View()
+	}
+	border := m.styles.TableContainer
+	if m.paneFocused {
+		border = border.BorderForeground(m.theme.Tab)
+	}
+	return clampLineWidth(bar, m.layout.ContentWidth) + "\n" + border.Render(content)
+}
@@ -0,0 +1,183 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/clcollins/srepd/pkg/launcher"
+	"github.com/clcollins/srepd/pkg/termpane"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaneKeyBytes(t *testing.T) {
+	tests := []struct {
+		key  tea.KeyMsg
+		want string
+	}{
+		{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("ls")}, "ls"},
+		{tea.KeyMsg{Type: tea.KeySpace}, " "},
+		{tea.KeyMsg{Type: tea.KeyEnter}, "\r"},
+		{tea.KeyMsg{Type: tea.KeyBackspace}, "\x7f"},
+		{tea.KeyMsg{Type: tea.KeyTab}, "\t"},
+		{tea.KeyMsg{Type: tea.KeyCtrlC}, "\x03"},
+		{tea.KeyMsg{Type: tea.KeyEsc}, "\x1b"},
+		{tea.KeyMsg{Type: tea.KeyUp}, "\x1b[A"},
+		{tea.KeyMsg{Type: tea.KeyPgDown}, "\x1b[6~"},
+		{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b"), Alt: true}, "\x1bb"},
+		{tea.KeyMsg{Type: tea.KeyF5}, ""},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, string(paneKeyBytes(tt.key)), tt.key.String())
+	}
+}
+
+func TestWithTerminalPane(t *testing.T) {
+	styles := BuildStyles(DefaultTheme())
+	l := computeLayout(tea.WindowSizeMsg{Width: 120, Height: 50}, styles, "help", false)
+	withPane := withTerminalPane(l, styles)
+
+	assert.Less(t, withPane.TableHeight, l.TableHeight)
+	assert.Less(t, withPane.IncidentViewerHeight, l.IncidentViewerHeight)
+	assert.Equal(t, l.TableHeight-withPane.TableHeight, withPane.PaneHeight+layoutPaneOverhead)
+	assert.Equal(t, 118, withPane.PaneWidth)
+
+	small := withTerminalPane(computeLayout(tea.WindowSizeMsg{Width: 80, Height: 10}, styles, "help", false), styles)
+	assert.Equal(t, layoutMinPaneRows, small.PaneHeight)
+	assert.Equal(t, layoutMinTableHeight, small.TableHeight)
+}
+
+// paneTestModel returns a model that logs into clusters in the embedded
+// terminal by running loginCommand.
+func paneTestModel(t *testing.T, loginCommand string) model {
+	t.Helper()
+	if !termpane.Supported {
+		t.Skip("embedded terminal is not supported on this platform")
+	}
+	m := customActionsTestModel(t)
+	l, err := launcher.NewClusterLauncherWithToolbox("true", loginCommand, "false", func() bool { return false })
+	require.NoError(t, err)
+	m.launcher = l
+	m.sessions = launcher.NewSessionRegistry()
+	m.launcher.RecordSessions(m.sessions)
+	m.embeddedTerminal = true
+	windowSize = tea.WindowSizeMsg{Width: 100, Height: 40}
+	m.recomputeLayout()
+	return m
+}
+
+// openTestPane logs into a cluster in a new pane.
+func openTestPane(t *testing.T, m *model, cluster string) *terminalPane {
+	t.Helper()
+	vars := map[string]string{"%%CLUSTER_ID%%": cluster, "%%INCIDENT_ID%%": m.selectedIncident.ID}
+	cmd := m.startLogin(cluster, vars, m.launcher, true)
+	require.NotNil(t, cmd)
+	opened, ok := cmd().(paneOpenedMsg)
+	require.True(t, ok)
+	require.NoError(t, opened.err)
+	m.applyPaneOpened(opened)
+	t.Cleanup(func() { _ = opened.pane.Close() })
+	return opened.pane
+}
+
+func waitForPaneText(t *testing.T, p *terminalPane, text string) {
+	t.Helper()
+	deadline := time.After(5 * time.Second)
+	for !strings.Contains(p.Render(false), text) {
+		select {
+		case <-p.Changed():
+		case <-deadline:
+			t.Fatalf("pane never showed %q:\n%s", text, p.Render(false))
+		}
+	}
+}
+
+func TestStartLogin_OpensPane(t *testing.T) {
+	m := paneTestModel(t, "env CLUSTER=%%CLUSTER_ID%% sh")
+	before := m.layout.TableHeight
+
+	p := openTestPane(t, &m, "cluster-a")
+	require.Len(t, m.panes, 1)
+	assert.True(t, m.paneFocused)
+	assert.Less(t, m.layout.TableHeight, before, "the pane takes rows from the table")
+	cols, rows := p.Size()
+	assert.Equal(t, m.layout.PaneWidth, cols)
+	assert.Equal(t, m.layout.PaneHeight, rows)
+
+	sessions := m.sessions.List()
+	require.Len(t, sessions, 1)
+	assert.True(t, sessions[0].Embedded)
+	assert.Equal(t, p.PID(), sessions[0].PID)
+
+	// Keys go to the program; the prefix chord returns them to srepd.
+	m.chordPrefix = "ctrl+x"
+	result, _ := m.keyMsgHandler(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("echo cluster=$PAGERDUTY_CLUSTER_ID term=$TERM")})
+	m = result.(model)
+	result, _ = m.keyMsgHandler(tea.KeyMsg{Type: tea.KeyEnter})
+	m = result.(model)
+	waitForPaneText(t, p, "cluster=cluster-a term=xterm-256color")
+
+	result, _ = m.keyMsgHandler(tea.KeyMsg{Type: tea.KeyCtrlX})
+	m = result.(model)
+	result, _ = m.keyMsgHandler(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	m = result.(model)
+	assert.False(t, m.paneFocused)
+	assert.Contains(t, m.View(), "[1 cluster-a]")
+
+	// Logging into the same cluster again switches to its pane.
+	vars := map[string]string{"%%CLUSTER_ID%%": "cluster-a", "%%INCIDENT_ID%%": m.selectedIncident.ID}
+	assert.Nil(t, m.startLogin("cluster-a", vars, m.launcher, true))
+	assert.True(t, m.paneFocused)
+	assert.Len(t, m.panes, 1)
+}
+
+func TestPane_CloseAsksWhileRunning(t *testing.T) {
+	m := paneTestModel(t, "env CLUSTER=%%CLUSTER_ID%% cat")
+	m.chordPrefix = "ctrl+x"
+	p := openTestPane(t, &m, "cluster-a")
+
+	result, _ := m.keyMsgHandler(tea.KeyMsg{Type: tea.KeyCtrlX})
+	m = result.(model)
+	result, _ = m.keyMsgHandler(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	m = result.(model)
+	require.NotNil(t, m.pendingConfirmation)
+	closeMsg, ok := m.pendingConfirmation.action().(closePaneMsg)
+	require.True(t, ok)
+
+	m.closePane(closeMsg.pane)
+	assert.Empty(t, m.panes)
+	assert.False(t, m.paneFocused)
+
+	select {
+	case <-p.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("closing the pane did not end its session")
+	}
+	assert.Nil(t, m.applyPaneOutput(paneOutputMsg{pane: p}), "a closed pane is not waited on")
+}
+
+func TestPane_ExitReturnsKeyboard(t *testing.T) {
+	m := paneTestModel(t, "false %%CLUSTER_ID%%")
+	p := openTestPane(t, &m, "cluster-a")
+
+	msg := waitForPane(p)()
+	for {
+		if _, ok := msg.(paneExitedMsg); ok {
+			break
+		}
+		msg = waitForPane(p)()
+	}
+	m.applyPaneExited(msg.(paneExitedMsg))
+	assert.False(t, m.paneFocused)
+	assert.Equal(t, "session for cluster-a exited: exit status 1", m.status)
+	require.Len(t, m.panes, 1, "an exited pane stays until closed")
+	assert.Contains(t, m.renderTerminalPanes(), "(exited)")
+}
+
+func TestChordFocusPane(t *testing.T) {
+	m := createTestModel()
+	result, _ := chordFocusPane(m)
+	assert.Equal(t, "no terminal pane open", result.(model).status)
+}
@@ -0,0 +1,115 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/PagerDuty/go-pagerduty"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+	"github.com/clcollins/srepd/pkg/launcher"
+)
+
+// newLoginTmux returns the tmux controller for a launcher that opens tmux
+// windows, or nil when logins do not go to tmux or srepd is not running
+// inside it.
+func newLoginTmux(l launcher.ClusterLauncher) *launcher.Tmux {
+	if !l.IsTmux() {
+		return nil
+	}
+	if h := l.HostExec(); h != nil {
+		return launcher.NewTmux(os.Getenv, h.Prefix()...)
+	}
+	return launcher.NewTmux(os.Getenv)
+}
+
+// tmuxWindowSelectedMsg reports that a login switched to the window
+// already open for the cluster instead of opening another.
+type tmuxWindowSelectedMsg struct {
+	window launcher.TmuxWindow
+	err    error
+}
+
+// tmuxWindowsMsg carries the login windows, and whether to open the
+// sessions list with them.
+type tmuxWindowsMsg struct {
+	windows []launcher.TmuxWindow
+	open    bool
+	err     error
+}
+
+// tmuxWindowKilledMsg reports a window closed from the sessions list.
+type tmuxWindowKilledMsg struct {
+	window launcher.TmuxWindow
+	err    error
+}
+
+// loginOrSwitch logs into a cluster, or with tmux switches to the window
+// already open for it. A failure to list windows falls back to a new
+// login.
+func loginOrSwitch(tmux *launcher.Tmux, vars map[string]string, l launcher.ClusterLauncher, incident *pagerduty.Incident, alerts []pagerduty.IncidentAlert, notes []pagerduty.IncidentNote) tea.Cmd {
+	if tmux == nil {
+		return login(vars, l, incident, alerts, notes)
+	}
+	return func() tea.Msg {
+		w, found, err := tmux.FindCluster(vars["%%CLUSTER_ID%%"])
+		if err != nil {
+			log.Warn("tui.loginOrSwitch(): listing tmux windows", "error", err)
+		}
+		if !found {
+			return login(vars, l, incident, alerts, notes)()
+		}
+		return tmuxWindowSelectedMsg{window: w, err: tmux.Select(w)}
+	}
+}
+
+func listTmuxWindows(tmux *launcher.Tmux, open bool) tea.Cmd {
+	return func() tea.Msg {
+		windows, err := tmux.Windows()
+		return tmuxWindowsMsg{windows: windows, open: open, err: err}
+	}
+}
+
+func killTmuxWindow(tmux *launcher.Tmux, w launcher.TmuxWindow) tea.Cmd {
+	return func() tea.Msg {
+		return tmuxWindowKilledMsg{window: w, err: tmux.Kill(w)}
+	}
+}
+
+// applyTmuxWindows keeps the listed windows, which tell whether tmux
+// sessions are still open, and shows them in the sessions list.
+func (m *model) applyTmuxWindows(msg tmuxWindowsMsg) tea.Cmd {
+	if msg.err != nil {
+		log.Error("tui.applyTmuxWindows()", "error", msg.err)
+		if !msg.open {
+			return nil
+		}
+		return m.flashNotification(fmt.Sprintf("listing tmux windows failed: %v", msg.err))
+	}
+	m.tmuxWindows = msg.windows
+	if !msg.open && !m.sessionsMode {
+		return nil
+	}
+	return m.showSessions()
+}
+
+func (m *model) applyTmuxWindowKilled(msg tmuxWindowKilledMsg) tea.Cmd {
+	if msg.err != nil {
+		log.Error("tui.applyTmuxWindowKilled()", "window", msg.window.ID, "error", msg.err)
+		return m.flashNotification(fmt.Sprintf("closing %s failed: %v", msg.window.Name, msg.err))
+	}
+	log.Info("closed tmux login window", "incident_id", msg.window.IncidentID, "cluster_id", msg.window.ClusterID)
+	return tea.Batch(
+		m.flashNotification(fmt.Sprintf("closed session for %s", msg.window.ClusterID)),
+		listTmuxWindows(m.tmux, false),
+	)
+}
+
+func (m *model) applyTmuxWindowSelected(msg tmuxWindowSelectedMsg) tea.Cmd {
+	if msg.err != nil {
+		log.Error("tui.applyTmuxWindowSelected()", "window", msg.window.ID, "error", msg.err)
+		return m.flashNotification(fmt.Sprintf("switching to %s failed: %v", msg.window.Name, msg.err))
+	}
+	log.Info("switched to open login window", "cluster_id", msg.window.ClusterID, "window", msg.window.ID)
+	return m.flashNotification(fmt.Sprintf("switched to the open session for %s", msg.window.ClusterID))
+}
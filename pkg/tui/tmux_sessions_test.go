@@ -0,0 +1,120 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/clcollins/srepd/pkg/launcher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTmuxServer answers list-windows with its windows and records every
+// other tmux command.
+type fakeTmuxServer struct {
+	windows string
+	calls   []string
+}
+
+func (f *fakeTmuxServer) run(args ...string) ([]byte, error) {
+	if args[0] == "list-windows" {
+		return []byte(f.windows), nil
+	}
+	f.calls = append(f.calls, strings.Join(args, " "))
+	return nil, nil
+}
+
+func tmuxTestModel() (model, *fakeTmuxServer) {
+	m := createTestModel()
+	fake := &fakeTmuxServer{windows: "@2\twork\tsrepd/Q1AAA/cluster-a\t0\n@5\twork\tsrepd/Q2BBB/cluster-b\t0\n@6\twork\tbash\t1\n"}
+	m.tmux = launcher.NewTmuxWithRunner(fake.run)
+	return m, fake
+}
+
+func TestLoginOrSwitch_SwitchesToOpenWindow(t *testing.T) {
+	m, fake := tmuxTestModel()
+	vars := map[string]string{"%%CLUSTER_ID%%": "cluster-b", "%%INCIDENT_ID%%": "Q3CCC"}
+
+	msg := loginOrSwitch(m.tmux, vars, m.launcher, nil, nil, nil)()
+	selected, ok := msg.(tmuxWindowSelectedMsg)
+	require.True(t, ok, "no second login is opened")
+	require.NoError(t, selected.err)
+	assert.Equal(t, "@5", selected.window.ID)
+	assert.Equal(t, []string{"switch-client -t work", "select-window -t @5"}, fake.calls)
+
+	m.applyTmuxWindowSelected(selected)
+	assert.Contains(t, m.status, "switched to the open session for cluster-b")
+}
+
+func TestSessionsCommand_NothingLaunched(t *testing.T) {
+	m := createTestModel()
+	assert.True(t, isSessionsCommand(" :sessions "))
+	assert.False(t, isSessionsCommand(":session"))
+
+	m.openSessions()
+	assert.False(t, m.sessionsMode)
+	assert.Contains(t, m.status, "no open cluster sessions")
+}
+
+func TestSessionsList_JumpAndKill(t *testing.T) {
+	m, fake := tmuxTestModel()
+
+	msg := m.openSessions()().(tmuxWindowsMsg)
+	m.applyTmuxWindows(msg)
+	require.True(t, m.sessionsMode)
+	require.Len(t, m.sessionsTable.Rows(), 2)
+	assert.Equal(t, []string{"Q1AAA", "cluster-a", "running", "-", "-", "@2"}, []string(m.sessionsTable.Rows()[0]))
+
+	// x asks before closing the window under the cursor.
+	m.sessionsTable.SetCursor(1)
+	result, _ := switchSessionsFocusMode(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	m = result.(model)
+	require.NotNil(t, m.pendingConfirmation)
+	assert.Contains(t, m.pendingConfirmation.prompt, "Close the session for cluster-b (Q2BBB)?")
+	assert.Empty(t, fake.calls)
+
+	killed := m.pendingConfirmation.action().(tmuxWindowKilledMsg)
+	require.NoError(t, killed.err)
+	assert.Equal(t, []string{"kill-window -t @5"}, fake.calls)
+
+	// The list is refetched after a close.
+	fake.windows = "@2\twork\tsrepd/Q1AAA/cluster-a\t0\n"
+	m.pendingConfirmation = nil
+	var refreshed tmuxWindowsMsg
+	for _, msg := range collectCmdMsgs(t, m.applyTmuxWindowKilled(killed), 500*time.Millisecond) {
+		if w, ok := msg.(tmuxWindowsMsg); ok {
+			refreshed = w
+		}
+	}
+	m.applyTmuxWindows(refreshed)
+	assert.Len(t, m.sessionsTable.Rows(), 1)
+	assert.Equal(t, 0, m.sessionsTable.Cursor(), "the cursor stays on the list")
+
+	// Enter jumps to the window and closes the list.
+	result, cmd := switchSessionsFocusMode(m, tea.KeyMsg{Type: tea.KeyEnter})
+	m = result.(model)
+	assert.False(t, m.sessionsMode)
+	require.NotNil(t, cmd)
+	selected := cmd().(tmuxWindowSelectedMsg)
+	assert.Equal(t, "@2", selected.window.ID)
+	assert.Equal(t, []string{"kill-window -t @5", "switch-client -t work", "select-window -t @2"}, fake.calls)
+}
+
+func TestSessionsList_Empty(t *testing.T) {
+	m, fake := tmuxTestModel()
+	fake.windows = "@6\twork\tbash\t1\n"
+
+	m.applyTmuxWindows(m.openSessions()().(tmuxWindowsMsg))
+	assert.False(t, m.sessionsMode)
+	assert.Contains(t, m.status, "no open cluster sessions")
+}
+
+func TestSessionsList_EscCloses(t *testing.T) {
+	m, _ := tmuxTestModel()
+	m.applyTmuxWindows(m.openSessions()().(tmuxWindowsMsg))
+
+	result, _ := switchSessionsFocusMode(m, tea.KeyMsg{Type: tea.KeyEsc})
+	assert.False(t, result.(model).sessionsMode)
+}
@@ -40,7 +40,7 @@ func tourSteps() []tourStep {
 		{
 			Title: "The incident viewer tabs",
 			Body: "An open incident has tabs — Details, Alerts, Notes, Cluster,\n" +
-				"Service Logs, LS History, Reports, and PD History — switch with\n" +
+				"Service Logs, LS History, Reports, PD History, and Jobs — switch with\n" +
 				"tab/shift+tab or number keys. Cluster data is enriched from OCM.",
 		},
 		{
@@ -29,6 +29,7 @@ import (
 	"github.com/clcollins/srepd/pkg/launcher"
 	"github.com/clcollins/srepd/pkg/ocm"
 	"github.com/clcollins/srepd/pkg/pd"
+	"github.com/clcollins/srepd/pkg/servicelog"
 	"github.com/spf13/viper"
 )
 
@@ -411,7 +412,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		if m.backplaneClient == nil && m.backplaneConfig != nil {
 			if m.backplaneConfig.URL == "" {
-				resolvedURL, urlErr := msg.Client.GetBackplaneURL()
+				resolvedURL, urlErr := msg.Client.GetBackplaneURL("")
 				if urlErr != nil {
 					log.Warn("Backplane URL resolution from OCM failed (deferred)", "error", urlErr)
 					m.backplaneInitErr = fmt.Errorf("URL resolution from OCM failed: %w", urlErr)
@@ -421,7 +422,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 			if m.backplaneConfig.URL != "" {
-				m.backplaneClient = backplane.NewClient(m.backplaneConfig, msg.Client.GetAccessToken)
+				m.backplaneClient = NewBackplaneClient(m.backplaneConfig, msg.Client)
 				m.backplaneInitErr = nil
 				log.Info("Backplane client initialized (deferred)")
 			} else if m.backplaneInitErr == nil {
@@ -430,22 +431,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
-		var enrichCmds []tea.Cmd
-		for _, clusterIDs := range m.incidentClusterMap {
-			var uncached []string
-			for _, id := range clusterIDs {
-				if _, cached := m.clusterCache[id]; cached {
-					continue
-				}
-				if m.clusterEnrichInFlight[id] || m.clusterEnrichFailed[id] >= 3 {
-					continue
-				}
-				uncached = append(uncached, id)
-				m.clusterEnrichInFlight[id] = true
-			}
-			enrichCmds = append(enrichCmds, enrichClusters(m.ocmClient, uncached, m.devMode)...)
-		}
-
+		enrichCmds := m.enrichKnownClusters()
 		enrichCmds = append(enrichCmds, m.flashNotification("OCM connected — enriching cluster data"))
 		return m, tea.Batch(enrichCmds...)
 
@@ -478,6 +464,67 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			func() tea.Msg { return updatedIncidentListMsg{m.incidentList, nil} },
 		)
 
+	case flagActionsMsg:
+		idx := slices.IndexFunc(m.flagConditions, func(c FlagCondition) bool { return c.ID == msg.id })
+		if idx < 0 {
+			return m, m.flashNotification(fmt.Sprintf("no flag #%d", msg.id))
+		}
+		cond := &m.flagConditions[idx]
+		if msg.action == nil {
+			cond.Actions = nil
+			return m, tea.Batch(
+				m.flashNotification(fmt.Sprintf("flag #%d actions cleared", msg.id)),
+				func() tea.Msg { return updatedIncidentListMsg{m.incidentList, nil} },
+			)
+		}
+		if !slices.Contains(cond.Actions, *msg.action) {
+			cond.Actions = append(cond.Actions, *msg.action)
+		}
+		// Incidents already matching get the new action too
+		m.rebuildFlagMatchCache()
+		return m, tea.Batch(
+			m.flashNotification(fmt.Sprintf("flag #%d will %s", msg.id, msg.action)),
+			func() tea.Msg { return updatedIncidentListMsg{m.incidentList, nil} },
+		)
+
+	case flagsExportedMsg:
+		if msg.err != nil {
+			return m, m.flashNotification("flags export failed: " + msg.err.Error())
+		}
+		return m, m.flashNotification(fmt.Sprintf("exported %d flag conditions to %s", msg.count, msg.path))
+
+	case flagsImportFetchedMsg:
+		if msg.err != nil {
+			return m, m.flashNotification("flags import failed: " + msg.err.Error())
+		}
+		preview := previewFlagImport(m.flagConditions, msg.conditions, msg.source)
+		if len(preview.added) == 0 && len(preview.changed) == 0 {
+			return m, m.flashNotification(fmt.Sprintf("nothing to import: all %d flag conditions already present", len(preview.duplicates)))
+		}
+		m.pendingConfirmation = &confirmActionState{
+			prompt: preview.prompt(),
+			action: func() tea.Msg { return applyFlagImportMsg{preview: preview} },
+		}
+		return m, nil
+
+	case applyFlagImportMsg:
+		m.applyFlagImport(msg.preview)
+		return m, tea.Batch(
+			m.flashNotification(fmt.Sprintf("imported %d new and %d changed flag conditions", len(msg.preview.added), len(msg.preview.changed))),
+			func() tea.Msg { return updatedIncidentListMsg{m.incidentList, nil} },
+		)
+
+	case flagActionResultMsg:
+		if msg.err != nil {
+			log.Warn("flag action failed", "flag", msg.flagID, "incident_id", msg.incidentID, "action", msg.kind, "error", msg.err)
+			m.setStatus(fmt.Sprintf("flag #%d %s for %s failed: %v", msg.flagID, msg.kind, msg.incidentID, msg.err))
+			return m, nil
+		}
+		if msg.kind == FlagActionNote {
+			return m, m.flashNotification(fmt.Sprintf("flag #%d: added note to %s", msg.flagID, msg.incidentID))
+		}
+		return m, nil
+
 	case listFlagConditionsMsg:
 		content := formatFlagsList(m.flagConditions)
 		rendered, renderErr := renderIncidentMarkdown(&m, content)
@@ -490,6 +537,76 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.table.Blur()
 		return m, nil
 
+	case listServiceLogTemplatesMsg:
+		content := formatServiceLogTemplates(servicelog.Templates())
+		rendered, renderErr := renderIncidentMarkdown(&m, content)
+		if renderErr != nil {
+			rendered = content
+		}
+		m.incidentViewer.SetContent(rendered)
+		m.incidentViewer.GotoTop()
+		m.viewingIncident = true
+		m.table.Blur()
+		return m, nil
+
+	case serviceLogPostedMsg:
+		return m, m.applyServiceLogPosted(msg)
+
+	case listLimitedSupportTemplatesMsg:
+		content := formatLimitedSupportTemplates(servicelog.Reasons())
+		rendered, renderErr := renderIncidentMarkdown(&m, content)
+		if renderErr != nil {
+			rendered = content
+		}
+		m.incidentViewer.SetContent(rendered)
+		m.incidentViewer.GotoTop()
+		m.viewingIncident = true
+		m.table.Blur()
+		return m, nil
+
+	case limitedSupportFinalConfirmMsg:
+		m.pendingConfirmation = &confirmActionState{prompt: msg.prompt, action: msg.action}
+		return m, nil
+
+	case limitedSupportChangedMsg:
+		return m, m.applyLimitedSupportChanged(msg)
+
+	case managedScriptsMsg:
+		return m, m.applyManagedScripts(msg)
+
+	case customActionMsg:
+		return m, m.applyCustomAction(msg)
+
+	case customActionFinishedMsg:
+		return m, m.applyCustomActionFinished(msg)
+
+	case commandRunStartedMsg:
+		return m, m.applyCommandRunStarted(msg)
+
+	case commandRunOutputMsg:
+		return m, m.applyCommandRunOutput(msg)
+
+	case commandRunFinishedMsg:
+		return m, m.applyCommandRunFinished(msg)
+
+	case commandOutputPostedMsg:
+		return m, m.applyCommandOutputPosted(msg)
+
+	case managedJobCreatedMsg:
+		return m, m.applyManagedJobCreated(msg)
+
+	case managedJobPolledMsg:
+		return m, m.applyManagedJobPolled(msg)
+
+	case tmuxWindowSelectedMsg:
+		return m, m.applyTmuxWindowSelected(msg)
+
+	case tmuxWindowsMsg:
+		return m, m.applyTmuxWindows(msg)
+
+	case tmuxWindowKilledMsg:
+		return m, m.applyTmuxWindowKilled(msg)
+
 	case flagsSavedMsg:
 		if msg.err != nil {
 			return m, m.flashNotification("flags save failed: " + msg.err.Error())
@@ -909,6 +1026,16 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+		// New alerts may create or break a duplicate group, or match an
+		// alert flag condition; redraw the table markers only when either
+		// actually changed
+		dupsChanged := m.rebuildDuplicateGroups()
+		if flagsChanged := m.rebuildFlagMatchCache(); dupsChanged || flagsChanged {
+			cmds = append(cmds, func() tea.Msg { return updatedIncidentListMsg{m.incidentList, nil} })
+		}
+		cmds = append(cmds, m.evaluateAutoMerge()...)
+		cmds = append(cmds, m.runFlagActions()...)
+
 		// Only update selected incident alerts if no incident is selected or this matches the selected one
 		// Skip if we're viewing a different incident (don't let background pre-fetch overwrite it)
 		if m.selectedIncident == nil || msg.incidentID == m.selectedIncident.ID {
@@ -963,7 +1090,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.clusterEnrichInFlight[id] = true
 				}
 			}
-			enrichCmds := enrichClusters(m.ocmClient, uncachedClusters, m.devMode)
+			enrichCmds := enrichClusters(m.ocmClient, uncachedClusters, m.incidentService(msg.incidentID), m.devMode)
 			if len(enrichCmds) > 0 {
 				cmds = append(cmds, enrichCmds...)
 			}
@@ -1158,6 +1285,8 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		filteredIncidents := filterByUrgency(m.incidentList, m.showLowUrgency)
 
 		m.rebuildFlagMatchCache()
+		m.rebuildDuplicateGroups()
+		filteredIncidents = m.pinFlaggedIncidents(filteredIncidents)
 
 		var rows []table.Row
 
@@ -1203,6 +1332,9 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 				}
 				title := stripControl(i.Title)
+				if _, ok := m.duplicateGroups[i.ID]; ok {
+					title = m.duplicateMarker + title
+				}
 				if matchedFlags, ok := m.flagMatchCache[i.ID]; ok && len(matchedFlags) > 0 {
 					title = m.flagMarker + title
 				}
@@ -1253,8 +1385,12 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			cmds = append(cmds, cmd)
 		}
 
+		firstPoll := m.prevSnapshots == nil
 		changes := m.computeAndStoreDeltas()
 		cmds = append(cmds, m.runDetectors(changes)...)
+		m.queueAutoMerge(changes, firstPoll)
+		cmds = append(cmds, m.evaluateAutoMerge()...)
+		cmds = append(cmds, m.runFlagActions()...)
 
 	case parseTemplateForNoteMsg:
 		if m.selectedIncident == nil {
@@ -1356,7 +1492,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			"cluster_id", cluster,
 			"reason", m.selectedIncident.HTMLURL,
 			"alert", alert.ExtractAlertName(m.selectedIncident.Title))
-		cmds = append(cmds, login(vars, m.launcher, m.selectedIncident, m.selectedIncidentAlerts, m.selectedIncidentNotes))
+		cmds = append(cmds, m.startLogin(cluster, vars, m.launcher, true))
 
 	case clusterSelectedMsg:
 		if m.selectedIncident == nil {
@@ -1377,7 +1513,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			"cluster_id", cluster,
 			"reason", m.selectedIncident.HTMLURL,
 			"alert", alert.ExtractAlertName(m.selectedIncident.Title))
-		cmds = append(cmds, login(vars, m.launcher, m.selectedIncident, m.selectedIncidentAlerts, m.selectedIncidentNotes))
+		cmds = append(cmds, m.startLogin(cluster, vars, m.launcher, true))
 
 	case rosaBoundaryLoginMsg:
 		if m.ocmAuthPending {
@@ -1432,10 +1568,11 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			"reason", m.selectedIncident.HTMLURL,
 			"alert", alert.ExtractAlertName(m.selectedIncident.Title))
 		// rosa-boundary launches an interactive session into a protected
-		// cluster exactly like ocm-container, so it shares login()'s path:
-		// a new terminal window, PAGERDUTY_* context in the environment, and
+		// cluster exactly like ocm-container, so it shares startLogin()'s
+		// path: a new terminal window or embedded pane, PAGERDUTY_* context
+		// in the environment, and
 		// srepd keeps running (multiple concurrent sessions supported).
-		cmds = append(cmds, login(vars, m.rosaBoundaryLauncher, m.selectedIncident, m.selectedIncidentAlerts, m.selectedIncidentNotes))
+		cmds = append(cmds, m.startLogin("rosa-boundary "+cluster, vars, m.rosaBoundaryLauncher, false))
 
 	case rosaBoundaryClusterSelectedMsg:
 		if m.selectedIncident == nil {
@@ -1457,10 +1594,11 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			"reason", m.selectedIncident.HTMLURL,
 			"alert", alert.ExtractAlertName(m.selectedIncident.Title))
 		// rosa-boundary launches an interactive session into a protected
-		// cluster exactly like ocm-container, so it shares login()'s path:
-		// a new terminal window, PAGERDUTY_* context in the environment, and
+		// cluster exactly like ocm-container, so it shares startLogin()'s
+		// path: a new terminal window or embedded pane, PAGERDUTY_* context
+		// in the environment, and
 		// srepd keeps running (multiple concurrent sessions supported).
-		cmds = append(cmds, login(vars, m.rosaBoundaryLauncher, m.selectedIncident, m.selectedIncidentAlerts, m.selectedIncidentNotes))
+		cmds = append(cmds, m.startLogin("rosa-boundary "+cluster, vars, m.rosaBoundaryLauncher, false))
 
 	case loginFinishedMsg:
 		if msg.err != nil {
@@ -1473,10 +1611,23 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			log.Info("login completed")
 		}
 		if msg.waitCmd != nil {
-			return m, msg.waitCmd
+			return m, tea.Batch(msg.waitCmd, m.sessionsChanged())
 		}
 
+	case paneOpenedMsg:
+		return m, m.applyPaneOpened(msg)
+
+	case paneOutputMsg:
+		return m, m.applyPaneOutput(msg)
+
+	case paneExitedMsg:
+		return m, m.applyPaneExited(msg)
+
+	case closePaneMsg:
+		return m, m.closePane(msg.pane)
+
 	case loginProcessExitedMsg:
+		cmds = append(cmds, m.sessionsChanged())
 		if msg.exitErr != nil {
 			detail := msg.exitErr.Error()
 			if msg.stderr != "" {
@@ -1523,6 +1674,25 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		c := []string{defaultBrowserOpenCommand}
 		return m, tea.Batch(m.flashNotification(fmt.Sprintf("Opened SOP for %s", m.selectedIncident.ID)), openBrowserCmd(c, link))
 
+	case openPrometheusMsg:
+		if m.selectedIncident == nil {
+			m.setStatus("no incident selected")
+			return m, nil
+		}
+		link, ok := getPrometheusLink(m.selectedIncidentAlerts)
+		if !ok {
+			m.setStatus("no Prometheus query link found")
+			return m, nil
+		}
+		if defaultBrowserOpenCommand == "" {
+			return m, func() tea.Msg { return errMsg{fmt.Errorf("unsupported OS: no browser open command available")} }
+		}
+
+		log.Info("opened Prometheus query in browser", "incident_id", m.selectedIncident.ID, "link", link)
+
+		c := []string{defaultBrowserOpenCommand}
+		return m, tea.Batch(m.flashNotification(fmt.Sprintf("Opened Prometheus query for %s", m.selectedIncident.ID)), openBrowserCmd(c, link))
+
 	case browserFinishedMsg:
 		if msg.err != nil {
 			m.setStatus(fmt.Sprintf("failed to open browser: %s", msg.err))
@@ -1592,7 +1762,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
-		cmds = append(cmds, renderIncident(&m))
+		cmds = append(cmds, renderIncident(&m), m.fetchClusterDetails())
 
 	case renderedIncidentMsg:
 		if msg.err != nil {
@@ -1610,6 +1780,12 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if !wasViewingBefore {
 				m.incidentViewer.GotoTop()
 			}
+			if m.scrollToSelectedAlert && m.activeTab == tabAlerts {
+				if line := alertHeadingLine(msg.content, m.selectedAlertIndex(), len(m.selectedIncidentAlerts)); line >= 0 {
+					m.incidentViewer.SetYOffset(line)
+				}
+			}
+			m.scrollToSelectedAlert = false
 			m.viewingIncident = true
 		} else {
 			log.Debug("renderedIncidentMsg", "action", "discarding render - incident was closed")
@@ -1955,6 +2131,54 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			func() tea.Msg { return updateIncidentListMsg("sender: mergedIncidentMsg") },
 		)
 
+	case autoMergedIncidentMsg:
+		if msg.err != nil {
+			return m, func() tea.Msg { return errMsg{fmt.Errorf("auto-merge rule %q: %w", msg.match.rule.Name, msg.err)} }
+		}
+		log.Info("auto-merged incident",
+			"rule", msg.match.rule.Name,
+			"source", msg.match.sourceID,
+			"target", msg.match.targetID)
+		if msg.noteErr != nil {
+			log.Warn("auto-merge note failed", "target", msg.match.targetID, "error", msg.noteErr)
+		}
+		return m, tea.Batch(
+			m.flashNotification(fmt.Sprintf("Auto-merged %s into %s (%s)", msg.match.sourceID, msg.match.targetID, msg.match.rule.Name)),
+			func() tea.Msg { return updateIncidentListMsg("sender: autoMergedIncidentMsg") },
+		)
+
+	case managedIncidentAlertMsg:
+		if msg.err != nil {
+			return m, func() tea.Msg { return errMsg{msg.err} }
+		}
+		m.applyManagedIncidentAlert(msg)
+		log.Info("managed incident alert", "action", msg.action, "incident_id", msg.incidentID, "alert_id", msg.alertID)
+
+		// Redraw the table so the (+N) cluster count reflects the change now
+		cmds = append(cmds, func() tea.Msg { return updatedIncidentListMsg{m.incidentList, nil} })
+		if m.viewingIncident && m.selectedIncident != nil && m.selectedIncident.ID == msg.incidentID {
+			cmds = append(cmds, func() tea.Msg { return renderIncidentMsg("managed alert") })
+		}
+
+		switch msg.action {
+		case alertActionMove:
+			newID := ""
+			if msg.newIncident != nil {
+				newID = msg.newIncident.ID
+			}
+			cmds = append(cmds,
+				m.flashNotification(fmt.Sprintf("Moved alert %s from %s to new incident %s", msg.alertID, msg.incidentID, newID)),
+				func() tea.Msg { return updateIncidentListMsg("sender: managedIncidentAlertMsg") },
+			)
+		default:
+			cmds = append(cmds, m.flashNotification(fmt.Sprintf("Resolved alert %s in %s", msg.alertID, msg.incidentID)))
+			// Resolving the last open alert resolves the incident
+			if cached, ok := m.incidentCache[msg.incidentID]; ok && cached != nil && openAlertCount(cached.alerts) == 0 {
+				cmds = append(cmds, func() tea.Msg { return updateIncidentListMsg("sender: managedIncidentAlertMsg") })
+			}
+		}
+		return m, tea.Batch(cmds...)
+
 	case clusterInfoMsg:
 		delete(m.clusterEnrichInFlight, msg.clusterID)
 		if msg.err != nil {
@@ -1969,6 +2193,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.clusterCache = make(map[string]*ocm.ClusterInfo)
 		}
 		m.clusterCache[msg.clusterID] = msg.info
+		delete(m.clusterDetailsErrors, msg.clusterID)
 		log.Info("OCM enriched cluster", "cluster_id", msg.clusterID, "name", msg.info.DisplayName, "region", msg.info.Region)
 
 		m.rebuildFlagMatchCache()
@@ -2060,6 +2285,26 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case clusterDetailsMsg:
+		delete(m.clusterDetailsInFlight, msg.clusterID)
+		if msg.err != nil {
+			log.Warn("ocm.GetClusterDetails failed", "cluster_id", msg.clusterID, "error", msg.err)
+			if m.clusterDetailsErrors == nil {
+				m.clusterDetailsErrors = make(map[string]error)
+			}
+			m.clusterDetailsErrors[msg.clusterID] = msg.err
+		}
+		// Copy rather than mutate: in-flight commands may hold the old info
+		if info, ok := m.clusterCache[msg.clusterID]; ok && msg.details != nil {
+			enriched := *info
+			enriched.Details = msg.details
+			m.clusterCache[msg.clusterID] = &enriched
+		}
+		if m.viewingIncident && m.activeTab == tabCluster {
+			return m, func() tea.Msg { return renderIncidentMsg("cluster details arrived") }
+		}
+		return m, nil
+
 	case clusterReportsMsg:
 		if msg.err != nil {
 			log.Warn("backplane.ListReports failed", "cluster_id", msg.clusterID, "error", msg.err)
@@ -2142,6 +2387,12 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.updateVersion = msg.latest
 		m.updateReleaseURL = msg.releaseURL
 		return m, nil
+
+	case quitSessionsCheckedMsg:
+		return m, m.applyQuitSessionsChecked(msg)
+
+	case updateSkippedMsg:
+		return m, m.applyUpdateSkipped(msg)
 	}
 
 	if m.configMode && m.configForm != nil {
@@ -2716,6 +2967,12 @@ func (m *model) buildConfigForm(msg configWizardReadyMsg, tokenDesc, keepTeamsDe
 					if m.configPresetApplied.ClusterLogin {
 						fmt.Fprintf(&b, "  cluster_login_command: %s\n", m.configExisting.ClusterLoginCommand)
 					}
+					if m.configPresetApplied.CustomActions {
+						b.WriteString("  custom_actions:\n")
+						for _, a := range m.configExisting.CustomActions {
+							fmt.Fprintf(&b, "    %s\n", a.Describe())
+						}
+					}
 					b.WriteString("\nAnswering No discards all changes.")
 					return b.String()
 				}, &m.configState).
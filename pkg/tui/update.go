@@ -2,22 +2,30 @@ package tui
 
 import (
 	"archive/tar"
+	"bytes"
+	"cmp"
 	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"runtime"
+	"strconv"
 	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/log"
+	"github.com/spf13/viper"
 )
 
 const (
-	githubReleasesURL  = "https://api.github.com/repos/openshift-online/srepd/releases/latest"
+	githubReleasesURL  = "https://api.github.com/repos/openshift-online/srepd/releases"
 	updateCheckTimeout = 10 * time.Second
 )
 
@@ -33,9 +41,12 @@ type releaseAsset struct {
 }
 
 type githubRelease struct {
-	TagName string         `json:"tag_name"`
-	HTMLURL string         `json:"html_url"`
-	Assets  []releaseAsset `json:"assets"`
+	TagName    string         `json:"tag_name"`
+	HTMLURL    string         `json:"html_url"`
+	Body       string         `json:"body"`
+	Draft      bool           `json:"draft"`
+	Prerelease bool           `json:"prerelease"`
+	Assets     []releaseAsset `json:"assets"`
 }
 
 func versionString() string {
@@ -61,30 +72,20 @@ func checkForUpdate(devMode bool, apiURL string) tea.Cmd {
 			url = githubReleasesURL
 		}
 
-		client := &http.Client{Timeout: updateCheckTimeout}
-		resp, err := client.Get(url)
+		releases, err := fetchReleases(url)
 		if err != nil {
 			log.Debug("checkForUpdate", "error", err)
 			return nil
 		}
-		defer resp.Body.Close() //nolint:errcheck
 
-		if resp.StatusCode != http.StatusOK {
-			log.Debug("checkForUpdate", "status", resp.StatusCode)
+		channel := resolveUpdateChannel(viper.GetString("update_channel"))
+		newer := releasesNewerThan(releases, Version, channel)
+		if len(newer) == 0 {
 			return nil
 		}
-
-		var release githubRelease
-		if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
-			log.Debug("checkForUpdate", "error", err)
-			return nil
-		}
-
-		if release.TagName == "" {
-			return nil
-		}
-
-		if !isNewerVersion(Version, release.TagName) {
+		release := newer[0]
+		if release.TagName == viper.GetString("update_skip_version") {
+			log.Debug("checkForUpdate", "skipped", release.TagName)
 			return nil
 		}
 
@@ -96,13 +97,16 @@ func checkForUpdate(devMode bool, apiURL string) tea.Cmd {
 	}
 }
 
+// isNewerVersion reports whether latest is a newer release than current.
+// A pre-release (v1.3.0-rc.1) is older than its release (v1.3.0); two
+// pre-releases of one version compare by their suffix.
 func isNewerVersion(current, latest string) bool {
 	if current == "dev" || !strings.Contains(current, ".") {
 		return true
 	}
 
-	current = strings.TrimPrefix(current, "v")
-	latest = strings.TrimPrefix(latest, "v")
+	current, currentPre := splitVersion(current)
+	latest, latestPre := splitVersion(latest)
 
 	currentParts := strings.Split(current, ".")
 	latestParts := strings.Split(latest, ".")
@@ -119,7 +123,47 @@ func isNewerVersion(current, latest string) bool {
 		}
 	}
 
-	return false
+	switch {
+	case currentPre == "":
+		return false
+	case latestPre == "":
+		return true
+	default:
+		return comparePrerelease(latestPre, currentPre) > 0
+	}
+}
+
+// comparePrerelease orders pre-release suffixes as semver does: identifier
+// by identifier, numeric ones as numbers and below alphanumeric ones, and
+// a longer suffix above its own prefix. So rc.10 is newer than rc.9.
+func comparePrerelease(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.ParseUint(as[i], 10, 64)
+		bn, bErr := strconv.ParseUint(bs[i], 10, 64)
+		var c int
+		switch {
+		case aErr == nil && bErr == nil:
+			c = cmp.Compare(an, bn)
+		case aErr == nil:
+			c = -1
+		case bErr == nil:
+			c = 1
+		default:
+			c = strings.Compare(as[i], bs[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(as), len(bs))
+}
+
+// splitVersion splits "v1.3.0-rc.1+abc" into "1.3.0" and "rc.1".
+func splitVersion(v string) (version, pre string) {
+	v, _, _ = strings.Cut(strings.TrimPrefix(v, "v"), "+")
+	version, pre, _ = strings.Cut(v, "-")
+	return version, pre
 }
 
 func releaseAssetName(goos, goarch string) string {
@@ -140,19 +184,130 @@ func findAssetURL(assets []releaseAsset, targetName string) (string, bool) {
 	return "", false
 }
 
-func selfUpdate(assetURL string, binaryPath string) error {
-	client := &http.Client{Timeout: 60 * time.Second}
-	resp, err := client.Get(assetURL)
+// checksumsAssetName is the checksums file goreleaser publishes with each
+// release, and checksumsSignatureName its detached ed25519 signature.
+const (
+	checksumsAssetName     = "checksums.txt"
+	checksumsSignatureName = "checksums.txt.sig"
+	maxUpdateDownload      = 256 << 20
+)
+
+// backupPath is where selfUpdate keeps the binary it replaced.
+func backupPath(binaryPath string) string {
+	return binaryPath + ".bak"
+}
+
+func download(client *http.Client, url string) ([]byte, error) {
+	resp, err := client.Get(url)
 	if err != nil {
-		return fmt.Errorf("download failed: %w", err)
+		return nil, fmt.Errorf("download failed: %w", err)
 	}
 	defer resp.Body.Close() //nolint:errcheck
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("download returned status %d", resp.StatusCode)
+		return nil, fmt.Errorf("download returned status %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxUpdateDownload+1))
+	if err != nil {
+		return nil, fmt.Errorf("download failed: %w", err)
+	}
+	if len(data) > maxUpdateDownload {
+		return nil, fmt.Errorf("download is larger than %d bytes", maxUpdateDownload)
+	}
+	return data, nil
+}
+
+// releaseChecksum returns the SHA-256 a checksums file lists for
+// assetName ("<hex>  <name>" lines, as sha256sum writes them).
+func releaseChecksum(checksums []byte, assetName string) (string, bool) {
+	for _, line := range strings.Split(string(checksums), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == assetName {
+			return strings.ToLower(fields[0]), true
+		}
+	}
+	return "", false
+}
+
+// verifyChecksumsSignature checks an ed25519 signature over the checksums
+// file. The signature may be raw or base64.
+func verifyChecksumsSignature(publicKey string, checksums, signature []byte) error {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(publicKey))
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid update public key")
+	}
+	if len(signature) != ed25519.SignatureSize {
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+		if err != nil {
+			return fmt.Errorf("unreadable checksums signature: %w", err)
+		}
+		signature = decoded
+	}
+	if !ed25519.Verify(ed25519.PublicKey(key), checksums, signature) {
+		return fmt.Errorf("checksums signature does not match the update public key")
+	}
+	return nil
+}
+
+// selfUpdate replaces binaryPath with the release's assetName. The asset's
+// SHA-256 must match the release checksums file, whose signature is
+// checked too when publicKey is set. The replaced binary is kept at
+// backupPath for RunRollback.
+func selfUpdate(release githubRelease, assetName, binaryPath, publicKey string) error {
+	assetURL, ok := findAssetURL(release.Assets, assetName)
+	if !ok {
+		return fmt.Errorf("no release asset found for %s/%s (expected %s)", runtime.GOOS, runtime.GOARCH, assetName)
+	}
+	checksumsURL, ok := findAssetURL(release.Assets, checksumsAssetName)
+	if !ok {
+		return fmt.Errorf("release %s has no %s; refusing to install an unverified binary", release.TagName, checksumsAssetName)
+	}
+
+	client := &http.Client{Timeout: 60 * time.Second}
+	checksums, err := download(client, checksumsURL)
+	if err != nil {
+		return fmt.Errorf("checksums: %w", err)
+	}
+	if publicKey != "" {
+		signatureURL, ok := findAssetURL(release.Assets, checksumsSignatureName)
+		if !ok {
+			return fmt.Errorf("release %s has no %s; refusing to install an unverified binary", release.TagName, checksumsSignatureName)
+		}
+		signature, err := download(client, signatureURL)
+		if err != nil {
+			return fmt.Errorf("checksums signature: %w", err)
+		}
+		if err := verifyChecksumsSignature(publicKey, checksums, signature); err != nil {
+			return err
+		}
+	} else {
+		log.Debug("selfUpdate: no update public key; skipping the checksums signature check")
 	}
 
-	gr, err := gzip.NewReader(resp.Body)
+	want, ok := releaseChecksum(checksums, assetName)
+	if !ok {
+		return fmt.Errorf("%s lists no checksum for %s", checksumsAssetName, assetName)
+	}
+	archive, err := download(client, assetURL)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(archive)
+	if got := hex.EncodeToString(sum[:]); got != want {
+		return fmt.Errorf("checksum mismatch for %s: got %s, want %s", assetName, got, want)
+	}
+
+	tmpPath := binaryPath + ".tmp"
+	if err := extractBinary(archive, tmpPath); err != nil {
+		os.Remove(tmpPath) //nolint:errcheck
+		return err
+	}
+	return replaceBinary(binaryPath, tmpPath)
+}
+
+// extractBinary writes the srepd binary in a tar.gz archive to path.
+func extractBinary(archive []byte, path string) error {
+	gr, err := gzip.NewReader(bytes.NewReader(archive))
 	if err != nil {
 		return fmt.Errorf("gzip open failed: %w", err)
 	}
@@ -167,73 +322,195 @@ func selfUpdate(assetURL string, binaryPath string) error {
 		if err != nil {
 			return fmt.Errorf("tar read failed: %w", err)
 		}
-		if hdr.Name == "srepd" {
-			tmpPath := binaryPath + ".tmp"
-			f, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0755)
-			if err != nil {
-				return fmt.Errorf("create temp file failed: %w", err)
-			}
-			if _, err := io.Copy(f, tr); err != nil {
-				f.Close()          //nolint:errcheck
-				os.Remove(tmpPath) //nolint:errcheck
-				return fmt.Errorf("write failed: %w", err)
-			}
-			if err := f.Close(); err != nil {
-				os.Remove(tmpPath) //nolint:errcheck
-				return fmt.Errorf("close failed: %w", err)
-			}
-			if err := os.Rename(tmpPath, binaryPath); err != nil {
-				os.Remove(tmpPath) //nolint:errcheck
-				return fmt.Errorf("replace binary failed: %w", err)
-			}
-			return nil
+		if hdr.Name != "srepd" {
+			continue
 		}
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0755)
+		if err != nil {
+			return fmt.Errorf("create temp file failed: %w", err)
+		}
+		if _, err := io.Copy(f, tr); err != nil {
+			f.Close() //nolint:errcheck
+			return fmt.Errorf("write failed: %w", err)
+		}
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("close failed: %w", err)
+		}
+		return nil
 	}
 }
 
-// RunSelfUpdate checks for the latest release and updates the binary in place.
-// This is called from the --update CLI flag before the TUI starts.
-func RunSelfUpdate() error {
-	log.Info("Checking for updates...")
+// replaceBinary moves newPath over binaryPath, keeping the old binary at
+// backupPath. On failure binaryPath is left as it was.
+func replaceBinary(binaryPath, newPath string) error {
+	backup := backupPath(binaryPath)
+	if err := os.Rename(binaryPath, backup); err != nil {
+		os.Remove(newPath) //nolint:errcheck
+		return fmt.Errorf("back up binary failed: %w", err)
+	}
+	if err := os.Rename(newPath, binaryPath); err != nil {
+		os.Remove(newPath)                //nolint:errcheck
+		_ = os.Rename(backup, binaryPath) //nolint:errcheck
+		return fmt.Errorf("replace binary failed: %w", err)
+	}
+	return nil
+}
+
+// rollback swaps binaryPath with the backup of the binary the last update
+// replaced, so a second rollback returns to the update.
+func rollback(binaryPath string) error {
+	backup := backupPath(binaryPath)
+	if _, err := os.Stat(backup); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("no previous binary to roll back to (%s does not exist)", backup)
+		}
+		return fmt.Errorf("previous binary: %w", err)
+	}
+	// Unlike replaceBinary, no failure here removes a file: the backup may
+	// be the only copy of the previous binary.
+	tmpPath := binaryPath + ".tmp"
+	if err := os.Rename(backup, tmpPath); err != nil {
+		return fmt.Errorf("roll back failed: %w", err)
+	}
+	if err := os.Rename(binaryPath, backup); err != nil {
+		_ = os.Rename(tmpPath, backup) //nolint:errcheck
+		return fmt.Errorf("roll back failed: %w", err)
+	}
+	if err := os.Rename(tmpPath, binaryPath); err != nil {
+		_ = os.Rename(backup, binaryPath) //nolint:errcheck
+		_ = os.Rename(tmpPath, backup)    //nolint:errcheck
+		return fmt.Errorf("roll back failed: %w", err)
+	}
+	return nil
+}
 
+// fetchReleases reads the release list from the GitHub API, newest first.
+func fetchReleases(url string) ([]githubRelease, error) {
+	var releases []githubRelease
+	return releases, fetchJSON(url, &releases)
+}
+
+// fetchRelease reads the release tagged tag.
+func fetchRelease(releasesURL, tag string) (githubRelease, error) {
+	var release githubRelease
+	if err := fetchJSON(releasesURL+"/tags/"+tag, &release); err != nil {
+		return githubRelease{}, fmt.Errorf("release %s: %w", tag, err)
+	}
+	return release, nil
+}
+
+func fetchJSON(url string, v any) error {
 	client := &http.Client{Timeout: updateCheckTimeout}
-	resp, err := client.Get(githubReleasesURL)
+	resp, err := client.Get(url)
 	if err != nil {
 		return fmt.Errorf("failed to check for updates: %w", err)
 	}
 	defer resp.Body.Close() //nolint:errcheck
 
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("not found")
+	}
 	if resp.StatusCode != http.StatusOK {
 		return fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
 	}
 
-	var release githubRelease
-	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
 		return fmt.Errorf("failed to parse release info: %w", err)
 	}
+	return nil
+}
 
-	if !isNewerVersion(Version, release.TagName) {
-		log.Info("Already up to date", "version", Version)
-		return nil
+// UpdateOptions picks the release `srepd update` installs.
+type UpdateOptions struct {
+	// Version pins a release tag (e.g. v1.4.2), which may be older than
+	// the running one. Empty installs the newest release on Channel.
+	Version string
+	// Channel is the update_channel setting: stable or prerelease.
+	Channel string
+	// Yes installs without asking.
+	Yes bool
+	In  io.Reader
+	Out io.Writer
+}
+
+// RunSelfUpdate lists the releases newer than the running one with their
+// notes, asks, and updates the binary in place. This is called from the
+// `srepd update` command before the TUI starts.
+func RunSelfUpdate(opts UpdateOptions) error {
+	binaryPath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to determine binary path: %w", err)
+	}
+	return runSelfUpdate(opts, githubReleasesURL, binaryPath)
+}
+
+func runSelfUpdate(opts UpdateOptions, releasesURL, binaryPath string) error {
+	log.Info("Checking for updates...")
+
+	var candidates []githubRelease
+	if opts.Version != "" {
+		tag := "v" + strings.TrimPrefix(opts.Version, "v")
+		if tag == Version {
+			fmt.Fprintf(opts.Out, "Already running %s.\n", Version)
+			return nil
+		}
+		release, err := fetchRelease(releasesURL, tag)
+		if err != nil {
+			return err
+		}
+		candidates = []githubRelease{release}
+	} else {
+		releases, err := fetchReleases(releasesURL)
+		if err != nil {
+			return err
+		}
+		channel := resolveUpdateChannel(opts.Channel)
+		candidates = releasesNewerThan(releases, Version, channel)
+		if len(candidates) == 0 {
+			fmt.Fprintf(opts.Out, "srepd %s is up to date on the %s channel.\n", Version, channel)
+			return nil
+		}
+	}
+
+	release := candidates[0]
+	fmt.Fprintln(opts.Out, renderReleaseNotes(candidates, Version))
+	if !opts.Yes {
+		ok, err := confirm(opts.In, opts.Out, fmt.Sprintf("Install %s over %s?", release.TagName, Version))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Fprintln(opts.Out, "Update cancelled.")
+			return nil
+		}
 	}
 
 	assetName := releaseAssetName(runtime.GOOS, runtime.GOARCH)
-	assetURL, ok := findAssetURL(release.Assets, assetName)
-	if !ok {
-		return fmt.Errorf("no release asset found for %s/%s (expected %s)", runtime.GOOS, runtime.GOARCH, assetName)
+	log.Info("Downloading update", "version", release.TagName, "asset", assetName)
+	if err := selfUpdate(release, assetName, binaryPath, UpdatePublicKey); err != nil {
+		return err
 	}
 
+	log.Info("Updated successfully", "version", release.TagName, "backup", backupPath(binaryPath))
+	fmt.Fprintf(opts.Out, "Updated srepd to %s. The previous binary is kept at %s; `srepd update --rollback` restores it.\n", release.TagName, backupPath(binaryPath))
+	return nil
+}
+
+// RunRollback restores the binary the last `srepd update` replaced and
+// reports it on out.
+func RunRollback(out io.Writer) error {
 	binaryPath, err := os.Executable()
 	if err != nil {
 		return fmt.Errorf("failed to determine binary path: %w", err)
 	}
+	return runRollback(out, binaryPath)
+}
 
-	log.Info("Downloading update", "version", release.TagName, "asset", assetName)
-	if err := selfUpdate(assetURL, binaryPath); err != nil {
+func runRollback(out io.Writer, binaryPath string) error {
+	if err := rollback(binaryPath); err != nil {
 		return err
 	}
-
-	log.Info("Updated successfully", "version", release.TagName)
-	fmt.Printf("Updated srepd to %s\n", release.TagName)
+	log.Info("Rolled back", "binary", binaryPath)
+	fmt.Fprintln(out, "Restored the previous srepd binary; run `srepd update --rollback` again to undo.")
 	return nil
 }
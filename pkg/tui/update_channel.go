@@ -0,0 +1,188 @@
+package tui
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"charm.land/glamour/v2"
+	glamourstyles "charm.land/glamour/v2/styles"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/log"
+	pkgconfig "github.com/clcollins/srepd/pkg/config"
+	"github.com/muesli/termenv"
+	"github.com/spf13/viper"
+)
+
+const (
+	updateChannelStable     = "stable"
+	updateChannelPrerelease = "prerelease"
+
+	// maxListedReleases caps the release notes `srepd update` prints; a dev
+	// build is behind every release.
+	maxListedReleases = 5
+)
+
+// resolveUpdateChannel returns the update_channel setting, "stable" unless
+// it is "prerelease".
+func resolveUpdateChannel(channel string) string {
+	switch strings.ToLower(strings.TrimSpace(channel)) {
+	case "", updateChannelStable:
+		return updateChannelStable
+	case updateChannelPrerelease:
+		return updateChannelPrerelease
+	default:
+		log.Warn("Unknown update_channel value, using stable", "value", channel)
+		return updateChannelStable
+	}
+}
+
+// releasesNewerThan returns the releases on channel newer than current,
+// newest first. Drafts are never offered, and pre-releases only on the
+// prerelease channel.
+func releasesNewerThan(releases []githubRelease, current, channel string) []githubRelease {
+	var newer []githubRelease
+	for _, r := range releases {
+		if r.Draft || r.TagName == "" || (r.Prerelease && channel != updateChannelPrerelease) {
+			continue
+		}
+		if isNewerVersion(current, r.TagName) {
+			newer = append(newer, r)
+		}
+	}
+	sort.SliceStable(newer, func(i, j int) bool {
+		return isNewerVersion(newer[j].TagName, newer[i].TagName)
+	})
+	return newer
+}
+
+// releaseNotesMarkdown lists releases with their notes, newest first.
+func releaseNotesMarkdown(releases []githubRelease, current string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# srepd %s → %s\n\n", current, releases[0].TagName)
+	for i, r := range releases {
+		if i == maxListedReleases {
+			fmt.Fprintf(&b, "_…and %d older releases._\n", len(releases)-i)
+			break
+		}
+		title := r.TagName
+		if r.Prerelease {
+			title += " (pre-release)"
+		}
+		fmt.Fprintf(&b, "## %s\n\n", title)
+		notes := strings.TrimSpace(r.Body)
+		if notes == "" {
+			notes = "_No release notes._"
+		}
+		b.WriteString(notes + "\n\n")
+	}
+	return b.String()
+}
+
+// renderReleaseNotes renders releaseNotesMarkdown for the terminal, falling
+// back to the markdown itself.
+func renderReleaseNotes(releases []githubRelease, current string) string {
+	content := releaseNotesMarkdown(releases, current)
+	style := glamourstyles.DarkStyle
+	if lipgloss.ColorProfile() == termenv.Ascii {
+		style = glamourstyles.NoTTYStyle
+	}
+	renderer, err := glamour.NewTermRenderer(
+		glamour.WithStandardStyle(style),
+		glamour.WithWordWrap(80),
+	)
+	if err != nil {
+		return content
+	}
+	rendered, err := renderer.Render(content)
+	if err != nil {
+		return content
+	}
+	return strings.TrimRight(rendered, "\n")
+}
+
+// confirm asks a yes/no question on out and reads the answer from in.
+// Anything but y or yes is no.
+func confirm(in io.Reader, out io.Writer, prompt string) (bool, error) {
+	fmt.Fprintf(out, "%s [y/N] ", prompt)
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && answer == "" {
+		return false, fmt.Errorf("no answer (use --yes to update without asking)")
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	}
+	return false, nil
+}
+
+type updateSkippedMsg struct {
+	version string
+	err     error
+}
+
+func isUpdateCommand(input string) bool {
+	trimmed := strings.TrimSpace(input)
+	return trimmed == ":update" || strings.HasPrefix(trimmed, ":update ")
+}
+
+// dispatchUpdateCommand shows the available update for `:update`, and for
+// `:update skip` hides the banner until a newer release than it comes out.
+func (m *model) dispatchUpdateCommand(input string) tea.Cmd {
+	parts := strings.Fields(input)
+	channel := resolveUpdateChannel(viper.GetString("update_channel"))
+	switch {
+	case len(parts) == 1:
+		if !m.updateAvailable {
+			return m.flashNotification(fmt.Sprintf("srepd %s is up to date on the %s channel", Version, channel))
+		}
+		return m.flashNotification(fmt.Sprintf("srepd %s is out on the %s channel — quit and run `srepd update`, or :update skip", m.updateVersion, channel))
+	case len(parts) == 2 && parts[1] == "skip":
+		if !m.updateAvailable {
+			return m.flashNotification("no update to skip")
+		}
+		m.updateAvailable = false
+		return skipUpdateVersionCmd(m.updateVersion)
+	default:
+		return m.flashNotification("usage: :update [skip]")
+	}
+}
+
+// skipUpdateVersionCmd saves version as update_skip_version, so the
+// banner stays hidden for it across restarts.
+func skipUpdateVersionCmd(version string) tea.Cmd {
+	return func() tea.Msg {
+		viper.Set("update_skip_version", version)
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return updateSkippedMsg{version: version, err: err}
+		}
+		return updateSkippedMsg{version: version, err: writeUpdateSkipVersion(home, version)}
+	}
+}
+
+func writeUpdateSkipVersion(home, version string) error {
+	configFile := filepath.Join(home, pkgconfig.CfgFileDir, pkgconfig.CfgFileName)
+	data, err := os.ReadFile(configFile)
+	if err != nil {
+		return err
+	}
+	updated, err := pkgconfig.UpsertScalarInConfig(data, "update_skip_version", version)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(configFile, updated, 0600)
+}
+
+func (m *model) applyUpdateSkipped(msg updateSkippedMsg) tea.Cmd {
+	if msg.err != nil {
+		log.Warn("update_skip_version not saved", "version", msg.version, "error", msg.err)
+		return m.flashNotification(fmt.Sprintf("skipped %s until restart — saving it failed: %v", msg.version, msg.err))
+	}
+	return m.flashNotification(fmt.Sprintf("skipped %s — the banner returns for the next release", msg.version))
+}
@@ -0,0 +1,235 @@
+package tui
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	pkgconfig "github.com/clcollins/srepd/pkg/config"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveUpdateChannel(t *testing.T) {
+	assert.Equal(t, "stable", resolveUpdateChannel(""))
+	assert.Equal(t, "stable", resolveUpdateChannel("Stable"))
+	assert.Equal(t, "prerelease", resolveUpdateChannel(" prerelease "))
+	assert.Equal(t, "stable", resolveUpdateChannel("nightly"))
+}
+
+func TestReleasesNewerThan(t *testing.T) {
+	releases := []githubRelease{
+		{TagName: "v1.2.0"},
+		{TagName: "v1.4.0-rc.9", Prerelease: true},
+		{TagName: "v1.4.0-rc.10", Prerelease: true},
+		{TagName: "v1.5.0", Draft: true},
+		{TagName: "v1.3.1"},
+		{TagName: "v1.3.0"},
+		{TagName: "v1.1.0"},
+	}
+	tags := func(rs []githubRelease) []string {
+		var out []string
+		for _, r := range rs {
+			out = append(out, r.TagName)
+		}
+		return out
+	}
+
+	assert.Equal(t, []string{"v1.3.1", "v1.3.0"}, tags(releasesNewerThan(releases, "v1.2.0", "stable")))
+	assert.Equal(t, []string{"v1.4.0-rc.10", "v1.4.0-rc.9", "v1.3.1", "v1.3.0"}, tags(releasesNewerThan(releases, "v1.2.0", "prerelease")))
+	assert.Equal(t, []string{"v1.4.0-rc.10"}, tags(releasesNewerThan(releases, "v1.4.0-rc.9", "prerelease")))
+	assert.Empty(t, releasesNewerThan(releases, "v1.3.1", "stable"))
+}
+
+func TestReleaseNotesMarkdown(t *testing.T) {
+	releases := []githubRelease{
+		{TagName: "v1.4.0-rc.1", Prerelease: true, Body: "* Adds channels\r\n"},
+		{TagName: "v1.3.0"},
+	}
+	md := releaseNotesMarkdown(releases, "v1.2.0")
+	assert.Contains(t, md, "# srepd v1.2.0 → v1.4.0-rc.1")
+	assert.Contains(t, md, "## v1.4.0-rc.1 (pre-release)\n\n* Adds channels")
+	assert.Contains(t, md, "## v1.3.0\n\n_No release notes._")
+
+	many := make([]githubRelease, maxListedReleases+3)
+	for i := range many {
+		many[i] = githubRelease{TagName: "v1.0." + string(rune('9'-i))}
+	}
+	assert.Contains(t, releaseNotesMarkdown(many, "dev"), "_…and 3 older releases._")
+}
+
+func TestConfirm(t *testing.T) {
+	for answer, want := range map[string]bool{"y\n": true, "YES\n": true, "n\n": false, "\n": false, "y": true} {
+		var out bytes.Buffer
+		ok, err := confirm(strings.NewReader(answer), &out, "Install?")
+		require.NoError(t, err)
+		assert.Equal(t, want, ok, "answer %q", answer)
+		assert.Equal(t, "Install? [y/N] ", out.String())
+	}
+	_, err := confirm(strings.NewReader(""), &bytes.Buffer{}, "Install?")
+	assert.ErrorContains(t, err, "--yes")
+}
+
+// updateTestServer serves a release list and the assets and checksums of
+// every release in it.
+func updateTestServer(t *testing.T, tags map[string]bool) string {
+	t.Helper()
+	asset := releaseAssetName(runtime.GOOS, runtime.GOARCH)
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	release := func(tag string) githubRelease {
+		return githubRelease{
+			TagName:    tag,
+			Body:       "Notes for " + tag,
+			Prerelease: tags[tag],
+			Assets: []releaseAsset{
+				{Name: asset, DownloadURL: server.URL + "/download/" + tag + "/" + asset},
+				{Name: checksumsAssetName, DownloadURL: server.URL + "/download/" + tag + "/" + checksumsAssetName},
+			},
+		}
+	}
+	archive := func(tag string) []byte { return createTestTarGz(t, "srepd", []byte("srepd "+tag)) }
+
+	mux.HandleFunc("/releases", func(w http.ResponseWriter, r *http.Request) {
+		var list []githubRelease
+		for tag := range tags {
+			list = append(list, release(tag))
+		}
+		_ = json.NewEncoder(w).Encode(list)
+	})
+	mux.HandleFunc("/releases/tags/{tag}", func(w http.ResponseWriter, r *http.Request) {
+		tag := r.PathValue("tag")
+		if _, ok := tags[tag]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(release(tag))
+	})
+	mux.HandleFunc("/download/{tag}/{name}", func(w http.ResponseWriter, r *http.Request) {
+		tag := r.PathValue("tag")
+		if r.PathValue("name") == checksumsAssetName {
+			_, _ = w.Write([]byte(checksumLine(asset, archive(tag))))
+			return
+		}
+		_, _ = w.Write(archive(tag))
+	})
+	return server.URL + "/releases"
+}
+
+func TestRunSelfUpdate(t *testing.T) {
+	origVersion := Version
+	Version = "v1.2.0"
+	defer func() { Version = origVersion }()
+	// Values are whether the tag is a pre-release.
+	releasesURL := updateTestServer(t, map[string]bool{"v1.1.0": false, "v1.2.0": false, "v1.3.0": false, "v1.4.0-rc.1": true})
+
+	installed := func(t *testing.T, binary string) string {
+		t.Helper()
+		content, err := os.ReadFile(binary)
+		require.NoError(t, err)
+		return string(content)
+	}
+
+	t.Run("lists newer releases and installs the newest on confirm", func(t *testing.T) {
+		binary := writeTestBinary(t, "old")
+		var out bytes.Buffer
+		opts := UpdateOptions{Channel: "stable", In: strings.NewReader("y\n"), Out: &out}
+
+		require.NoError(t, runSelfUpdate(opts, releasesURL, binary))
+		assert.Contains(t, out.String(), "Notes for v1.3.0")
+		assert.NotContains(t, out.String(), "v1.4.0-rc.1")
+		assert.Contains(t, out.String(), "Install v1.3.0 over v1.2.0? [y/N]")
+		assert.Equal(t, "srepd v1.3.0", installed(t, binary))
+	})
+
+	t.Run("prerelease channel", func(t *testing.T) {
+		binary := writeTestBinary(t, "old")
+		opts := UpdateOptions{Channel: "prerelease", Yes: true, In: strings.NewReader(""), Out: &bytes.Buffer{}}
+
+		require.NoError(t, runSelfUpdate(opts, releasesURL, binary))
+		assert.Equal(t, "srepd v1.4.0-rc.1", installed(t, binary))
+	})
+
+	t.Run("declined", func(t *testing.T) {
+		binary := writeTestBinary(t, "old")
+		var out bytes.Buffer
+		opts := UpdateOptions{In: strings.NewReader("n\n"), Out: &out}
+
+		require.NoError(t, runSelfUpdate(opts, releasesURL, binary))
+		assert.Contains(t, out.String(), "Update cancelled.")
+		assert.Equal(t, "old", installed(t, binary))
+	})
+
+	t.Run("pinned older version", func(t *testing.T) {
+		binary := writeTestBinary(t, "old")
+		var out bytes.Buffer
+		opts := UpdateOptions{Version: "1.1.0", In: strings.NewReader("yes\n"), Out: &out}
+
+		require.NoError(t, runSelfUpdate(opts, releasesURL, binary))
+		assert.Contains(t, out.String(), "Notes for v1.1.0")
+		assert.Equal(t, "srepd v1.1.0", installed(t, binary))
+	})
+
+	t.Run("pinned unknown version", func(t *testing.T) {
+		binary := writeTestBinary(t, "old")
+		opts := UpdateOptions{Version: "v9.9.9", Yes: true, Out: &bytes.Buffer{}}
+
+		err := runSelfUpdate(opts, releasesURL, binary)
+		assert.ErrorContains(t, err, "release v9.9.9: not found")
+		assert.Equal(t, "old", installed(t, binary))
+	})
+
+	t.Run("up to date", func(t *testing.T) {
+		Version = "v1.3.0"
+		defer func() { Version = "v1.2.0" }()
+		var out bytes.Buffer
+		require.NoError(t, runSelfUpdate(UpdateOptions{Out: &out}, releasesURL, writeTestBinary(t, "old")))
+		assert.Equal(t, "srepd v1.3.0 is up to date on the stable channel.\n", out.String())
+	})
+}
+
+func TestUpdateCommand_Skip(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	configFile := filepath.Join(home, pkgconfig.CfgFileDir, pkgconfig.CfgFileName)
+	require.NoError(t, os.MkdirAll(filepath.Dir(configFile), 0700))
+	require.NoError(t, os.WriteFile(configFile, []byte("token: abc\n"), 0600))
+
+	m := createTestModel()
+	assert.True(t, isUpdateCommand(":update skip"))
+	m.dispatchUpdateCommand(":update skip")
+	assert.Equal(t, "no update to skip", m.status)
+
+	m.updateAvailable = true
+	m.updateVersion = "v2.0.0"
+	m.dispatchUpdateCommand(":update")
+	assert.Contains(t, m.status, "v2.0.0 is out on the stable channel")
+
+	cmd := m.dispatchUpdateCommand(":update skip")
+	require.NotNil(t, cmd)
+	assert.False(t, m.updateAvailable, "the banner hides right away")
+	msg := cmd().(updateSkippedMsg)
+	require.NoError(t, msg.err)
+	assert.Equal(t, "v2.0.0", viper.GetString("update_skip_version"))
+	data, err := os.ReadFile(configFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "update_skip_version: v2.0.0")
+	assert.Contains(t, string(data), "token: abc")
+
+	result, _ := m.Update(msg)
+	assert.Equal(t, "skipped v2.0.0 — the banner returns for the next release", result.(model).status)
+
+	m.dispatchUpdateCommand(":update now")
+	assert.Equal(t, "usage: :update [skip]", m.status)
+}
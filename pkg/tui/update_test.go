@@ -4,6 +4,10 @@ import (
 	"archive/tar"
 	"bytes"
 	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -17,7 +21,9 @@ import (
 	"github.com/charmbracelet/log"
 	"github.com/clcollins/srepd/pkg/launcher"
 	"github.com/clcollins/srepd/pkg/pd"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // captureLog redirects the global logger to a buffer for the duration of the
@@ -268,7 +274,7 @@ func TestCheckForUpdate_WithMockServer(t *testing.T) {
 	t.Run("detects newer version from GitHub API", func(t *testing.T) {
 		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.Header().Set("Content-Type", "application/json")
-			_, _ = fmt.Fprintln(w, `{"tag_name": "v2.0.0", "html_url": "https://github.com/clcollins/srepd/releases/tag/v2.0.0"}`)
+			_, _ = fmt.Fprintln(w, `[{"tag_name": "v2.0.0", "html_url": "https://github.com/clcollins/srepd/releases/tag/v2.0.0"}]`)
 		}))
 		defer server.Close()
 
@@ -288,7 +294,7 @@ func TestCheckForUpdate_WithMockServer(t *testing.T) {
 
 		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.Header().Set("Content-Type", "application/json")
-			_, _ = fmt.Fprintln(w, `{"tag_name": "v1.5.0", "html_url": "https://github.com/clcollins/srepd/releases/tag/v1.5.0"}`)
+			_, _ = fmt.Fprintln(w, `[{"tag_name": "v1.5.0", "html_url": "https://github.com/clcollins/srepd/releases/tag/v1.5.0"}]`)
 		}))
 		defer server.Close()
 
@@ -298,6 +304,33 @@ func TestCheckForUpdate_WithMockServer(t *testing.T) {
 		assert.Nil(t, msg, "should return nil when no update available")
 	})
 
+	t.Run("respects the channel and the skipped version", func(t *testing.T) {
+		origVersion := Version
+		Version = "v1.5.0"
+		defer func() { Version = origVersion }()
+		defer viper.Reset()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = fmt.Fprintln(w, `[
+				{"tag_name": "v1.7.0-rc.1", "prerelease": true},
+				{"tag_name": "v1.8.0", "draft": true},
+				{"tag_name": "v1.6.0"},
+				{"tag_name": "v1.5.1"}
+			]`)
+		}))
+		defer server.Close()
+
+		msg := checkForUpdate(false, server.URL)()
+		assert.Equal(t, "v1.6.0", msg.(updateAvailableMsg).latest, "stable skips drafts and pre-releases")
+
+		viper.Set("update_channel", "prerelease")
+		msg = checkForUpdate(false, server.URL)()
+		assert.Equal(t, "v1.7.0-rc.1", msg.(updateAvailableMsg).latest)
+
+		viper.Set("update_skip_version", "v1.7.0-rc.1")
+		assert.Nil(t, checkForUpdate(false, server.URL)(), "the skipped version shows no banner")
+	})
+
 	t.Run("returns nil on API error", func(t *testing.T) {
 		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusInternalServerError)
@@ -360,6 +393,48 @@ func TestIsNewerVersion(t *testing.T) {
 			latest:   "v1.0.0",
 			expected: false,
 		},
+		{
+			name:     "release after its pre-release",
+			current:  "v1.3.0-rc.1",
+			latest:   "v1.3.0",
+			expected: true,
+		},
+		{
+			name:     "pre-release of the running version",
+			current:  "v1.3.0",
+			latest:   "v1.3.0-rc.2",
+			expected: false,
+		},
+		{
+			name:     "pre-release of a newer version",
+			current:  "v1.2.0",
+			latest:   "v1.3.0-rc.1",
+			expected: true,
+		},
+		{
+			name:     "later pre-release",
+			current:  "v1.3.0-rc.1",
+			latest:   "v1.3.0-rc.2",
+			expected: true,
+		},
+		{
+			name:     "pre-release numbers compare as numbers",
+			current:  "v1.3.0-rc.9",
+			latest:   "v1.3.0-rc.10",
+			expected: true,
+		},
+		{
+			name:     "earlier pre-release with a longer number",
+			current:  "v1.3.0-rc.10",
+			latest:   "v1.3.0-rc.9",
+			expected: false,
+		},
+		{
+			name:     "release candidate after beta",
+			current:  "v1.3.0-beta.2",
+			latest:   "v1.3.0-rc.1",
+			expected: true,
+		},
 		{
 			name:     "dev is always outdated",
 			current:  "dev",
@@ -442,46 +517,216 @@ func TestAssetName(t *testing.T) {
 	}
 }
 
-func TestSelfUpdate_WithMockServer(t *testing.T) {
-	t.Run("downloads and extracts binary from tar.gz", func(t *testing.T) {
-		tarData := createTestTarGz(t, "srepd", []byte("#!/bin/sh\necho updated"))
+// testRelease serves a release's assets from an httptest server: files maps
+// asset names to their contents.
+func testRelease(t *testing.T, files map[string][]byte) githubRelease {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, ok := files[strings.TrimPrefix(r.URL.Path, "/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		_, _ = w.Write(data)
+	}))
+	t.Cleanup(server.Close)
+
+	release := githubRelease{TagName: "v1.2.0"}
+	for name := range files {
+		release.Assets = append(release.Assets, releaseAsset{Name: name, DownloadURL: server.URL + "/" + name})
+	}
+	return release
+}
 
-		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			w.Header().Set("Content-Type", "application/octet-stream")
-			_, _ = w.Write(tarData)
-		}))
-		defer server.Close()
+func checksumLine(name string, data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]) + "  " + name + "\n"
+}
 
-		tmpBinary := filepath.Join(t.TempDir(), "srepd")
-		err := os.WriteFile(tmpBinary, []byte("old binary"), 0755)
-		assert.NoError(t, err)
+func writeTestBinary(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "srepd")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0755))
+	return path
+}
 
-		err = selfUpdate(server.URL, tmpBinary)
-		assert.NoError(t, err)
+func TestSelfUpdate_WithMockServer(t *testing.T) {
+	const asset = "srepd_Linux_x86_64.tar.gz"
+	tarData := createTestTarGz(t, "srepd", []byte("#!/bin/sh\necho updated"))
+	checksums := []byte(checksumLine("srepd_Darwin_arm64.tar.gz", []byte("other")) + checksumLine(asset, tarData))
+
+	t.Run("verifies, extracts and keeps a backup", func(t *testing.T) {
+		release := testRelease(t, map[string][]byte{asset: tarData, checksumsAssetName: checksums})
+		binary := writeTestBinary(t, "old binary")
+
+		require.NoError(t, selfUpdate(release, asset, binary, ""))
 
-		updated, err := os.ReadFile(tmpBinary)
-		assert.NoError(t, err)
+		updated, err := os.ReadFile(binary)
+		require.NoError(t, err)
 		assert.Equal(t, "#!/bin/sh\necho updated", string(updated))
+		backup, err := os.ReadFile(backupPath(binary))
+		require.NoError(t, err)
+		assert.Equal(t, "old binary", string(backup))
 	})
 
+	failures := []struct {
+		name    string
+		files   map[string][]byte
+		wantErr string
+	}{
+		{
+			name:    "tampered asset",
+			files:   map[string][]byte{asset: createTestTarGz(t, "srepd", []byte("evil")), checksumsAssetName: checksums},
+			wantErr: "checksum mismatch",
+		},
+		{
+			name:    "missing checksums file",
+			files:   map[string][]byte{asset: tarData},
+			wantErr: "has no checksums.txt",
+		},
+		{
+			name:    "asset not in checksums",
+			files:   map[string][]byte{asset: tarData, checksumsAssetName: []byte(checksumLine("srepd_Darwin_arm64.tar.gz", []byte("other")))},
+			wantErr: "lists no checksum for " + asset,
+		},
+		{
+			name:    "asset missing from release",
+			files:   map[string][]byte{checksumsAssetName: checksums},
+			wantErr: "no release asset found",
+		},
+	}
+	for _, tt := range failures {
+		t.Run(tt.name, func(t *testing.T) {
+			release := testRelease(t, tt.files)
+			binary := writeTestBinary(t, "old")
+
+			err := selfUpdate(release, asset, binary, "")
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+
+			content, _ := os.ReadFile(binary)
+			assert.Equal(t, "old", string(content), "original binary should be untouched on error")
+			assert.NoFileExists(t, backupPath(binary))
+			assert.NoFileExists(t, binary+".tmp")
+		})
+	}
+
 	t.Run("returns error on download failure", func(t *testing.T) {
-		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			w.WriteHeader(http.StatusNotFound)
-		}))
-		defer server.Close()
+		release := testRelease(t, map[string][]byte{checksumsAssetName: checksums})
+		release.Assets = append(release.Assets, releaseAsset{Name: asset, DownloadURL: release.Assets[0].DownloadURL + "/notfound"})
+		binary := writeTestBinary(t, "old")
+
+		err := selfUpdate(release, asset, binary, "")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "status 404")
+		content, _ := os.ReadFile(binary)
+		assert.Equal(t, "old", string(content))
+	})
+}
+
+func TestSelfUpdate_Signature(t *testing.T) {
+	const asset = "srepd_Linux_x86_64.tar.gz"
+	tarData := createTestTarGz(t, "srepd", []byte("new"))
+	checksums := []byte(checksumLine(asset, tarData))
 
-		tmpBinary := filepath.Join(t.TempDir(), "srepd")
-		err := os.WriteFile(tmpBinary, []byte("old"), 0755)
-		assert.NoError(t, err)
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	encodedKey := base64.StdEncoding.EncodeToString(publicKey)
+	signature := ed25519.Sign(privateKey, checksums)
+	_, otherKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
 
-		err = selfUpdate(server.URL+"/notfound", tmpBinary)
-		assert.Error(t, err)
+	tests := []struct {
+		name      string
+		signature []byte
+		wantErr   string
+	}{
+		{name: "raw signature", signature: signature},
+		{name: "base64 signature", signature: []byte(base64.StdEncoding.EncodeToString(signature) + "\n")},
+		{name: "wrong key", signature: ed25519.Sign(otherKey, checksums), wantErr: "does not match the update public key"},
+		{name: "missing signature", wantErr: "has no checksums.txt.sig"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := map[string][]byte{asset: tarData, checksumsAssetName: checksums}
+			if tt.signature != nil {
+				files[checksumsSignatureName] = tt.signature
+			}
+			binary := writeTestBinary(t, "old")
+
+			err := selfUpdate(testRelease(t, files), asset, binary, encodedKey)
+			content, _ := os.ReadFile(binary)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				assert.Equal(t, "old", string(content))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "new", string(content))
+		})
+	}
 
-		content, _ := os.ReadFile(tmpBinary)
-		assert.Equal(t, "old", string(content), "original binary should be untouched on error")
+	t.Run("checksums edited after signing", func(t *testing.T) {
+		files := map[string][]byte{
+			asset:                  tarData,
+			checksumsAssetName:     append([]byte(checksumLine("extra", nil)), checksums...),
+			checksumsSignatureName: signature,
+		}
+		err := selfUpdate(testRelease(t, files), asset, writeTestBinary(t, "old"), encodedKey)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "does not match")
 	})
 }
 
+func TestRollback(t *testing.T) {
+	binary := writeTestBinary(t, "current")
+
+	err := rollback(binary)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no previous binary")
+
+	require.NoError(t, os.WriteFile(backupPath(binary), []byte("previous"), 0755))
+	require.NoError(t, rollback(binary))
+	content, _ := os.ReadFile(binary)
+	assert.Equal(t, "previous", string(content))
+
+	// Rolling back again returns to the update.
+	var out bytes.Buffer
+	require.NoError(t, runRollback(&out, binary))
+	content, _ = os.ReadFile(binary)
+	assert.Equal(t, "current", string(content))
+	assert.Equal(t, "Restored the previous srepd binary; run `srepd update --rollback` again to undo.\n", out.String())
+}
+
+func TestRollback_FailureKeepsBackup(t *testing.T) {
+	binary := writeTestBinary(t, "current")
+	require.NoError(t, os.WriteFile(backupPath(binary), []byte("previous"), 0755))
+	require.NoError(t, os.Remove(binary))
+
+	err := rollback(binary)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "roll back failed")
+	content, err := os.ReadFile(backupPath(binary))
+	require.NoError(t, err, "the backup is still there")
+	assert.Equal(t, "previous", string(content))
+	_, err = os.Stat(binary + ".tmp")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestReleaseChecksum(t *testing.T) {
+	checksums := []byte("ABC123  srepd_Linux_x86_64.tar.gz\ndef456 *srepd_Darwin_arm64.tar.gz\n\n")
+	sum, ok := releaseChecksum(checksums, "srepd_Linux_x86_64.tar.gz")
+	assert.True(t, ok)
+	assert.Equal(t, "abc123", sum)
+	sum, ok = releaseChecksum(checksums, "srepd_Darwin_arm64.tar.gz")
+	assert.True(t, ok)
+	assert.Equal(t, "def456", sum)
+	_, ok = releaseChecksum(checksums, "srepd_Linux_arm64.tar.gz")
+	assert.False(t, ok)
+}
+
 func TestFindAssetURL(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -6,6 +6,10 @@ var (
 	Version = "dev"
 	// GitSHA is the short git commit hash, set at build time
 	GitSHA = "dev"
+	// UpdatePublicKey is the base64 ed25519 key `srepd update` checks the
+	// release checksums signature with, set at build time. Empty skips the
+	// signature check; the checksums are verified either way.
+	UpdatePublicKey = ""
 )
 
 // LogDestination is set by cmd/root.go before model creation.
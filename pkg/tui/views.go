@@ -94,6 +94,10 @@ func (m model) View() string {
 		fmt.Fprintf(&s, "  Select incident to merge %s into (Enter=select, Esc=cancel, t=toggle team):\n", m.mergeSourceIncident.ID)
 		s.WriteString(m.styles.TableContainer.Render(m.mergeTable.View()))
 
+	case m.sessionsMode:
+		s.WriteString("  Cluster sessions (Enter=jump, x=close tmux window, Esc=back):\n")
+		s.WriteString(m.styles.TableContainer.Render(m.sessionsTable.View()))
+
 	case m.viewingDocs:
 		tabBar := m.renderDocsTabBar()
 		s.WriteString(tabBar)
@@ -130,6 +134,13 @@ func (m model) View() string {
 		}
 	}
 
+	if m.showsTerminalPane() {
+		if !strings.HasSuffix(s.String(), "\n") {
+			s.WriteString("\n")
+		}
+		s.WriteString(m.renderTerminalPanes())
+	}
+
 	// Choose the appropriate keymap based on focus mode — skip srepd help in config mode
 	// (the huh form renders its own help internally)
 	var helpView string
@@ -140,7 +151,7 @@ func (m model) View() string {
 		if m.err != nil {
 			helpKeyMap = errorViewKeyMap
 		} else if m.chordHelpActive {
-			helpKeyMap = chordKeymap{prefix: m.chordPrefix}
+			helpKeyMap = chordKeymap{prefix: m.chordPrefix, actions: m.customActions}
 		} else if m.chatMode {
 			helpKeyMap = chatModeKeyMap
 		} else if m.input.Focused() {
@@ -450,6 +461,10 @@ func (m model) renderTabContent() (string, bool, error) {
 		return m.renderClusterReportsTab()
 	case tabPDHistory:
 		content, err = m.renderPDHistoryTab()
+	case tabJobs:
+		content, err = m.renderJobsTab()
+	case tabOutput:
+		content, err = m.renderOutputTab()
 	}
 	return content, false, err
 }
@@ -487,6 +502,8 @@ func (m model) renderDetailsTab(summary incidentSummary) (string, error) {
 	}
 
 	content += m.renderFlagConditionsSection()
+	content += m.renderDuplicatesSection()
+	content += m.renderSessionsSection()
 
 	return content, nil
 }
@@ -500,6 +517,7 @@ func (m model) renderAlertsTab(summary incidentSummary) (string, error) {
 	}
 
 	var content strings.Builder
+	selected := m.selectedAlertIndex()
 	for i, a := range summary.Alerts {
 		tmpl, err := template.New("alert").Funcs(funcMap).Parse(alertTabTemplate)
 		if err != nil {
@@ -515,7 +533,13 @@ func (m model) renderAlertsTab(summary incidentSummary) (string, error) {
 		if err != nil {
 			return "", err
 		}
-		content.WriteString(o.String())
+		rendered := o.String()
+		// Mark the target of the per-alert actions when there is a choice
+		if i == selected && len(summary.Alerts) > 1 {
+			heading := fmt.Sprintf("### Alert %d/%d", i+1, len(summary.Alerts))
+			rendered = strings.Replace(rendered, heading, heading+" ◀ selected", 1)
+		}
+		content.WriteString(rendered)
 		if i < len(summary.Alerts)-1 {
 			content.WriteString("\n---\n")
 		}
@@ -589,6 +613,10 @@ func (m model) renderClusterTab() (string, error) {
 		fmt.Fprintf(&content, "* CCS: %v\n", info.CCS)
 		fmt.Fprintf(&content, "* Organization: %s\n", info.Organization)
 		fmt.Fprintf(&content, "* Organization ID: %s\n", info.OrganizationID)
+		if info.Environment != "" && info.Environment != ocm.ProductionEnvironment {
+			fmt.Fprintf(&content, "* OCM Environment: %s\n", info.Environment)
+		}
+		content.WriteString(m.renderClusterDetails(id, info))
 		if i < len(clusters)-1 {
 			content.WriteString("\n---\n")
 		}
@@ -596,6 +624,71 @@ func (m model) renderClusterTab() (string, error) {
 	return content.String(), nil
 }
 
+// renderClusterDetails renders the lazily fetched sections of a cluster:
+// support level, machine pools, add-ons and upgrade policies.
+func (m model) renderClusterDetails(id string, info *ocm.ClusterInfo) string {
+	var b strings.Builder
+	err := m.clusterDetailsErrors[id]
+	d := info.Details
+	if d == nil {
+		switch {
+		case err != nil:
+			b.WriteString(m.renderEnrichmentError("cluster details", map[string]error{id: err}))
+		case m.clusterDetailsInFlight[id]:
+			b.WriteString("\n_Loading machine pools, add-ons and upgrades..._\n")
+		}
+		return b.String()
+	}
+
+	supportLevel := d.SupportLevel
+	if supportLevel == "" {
+		supportLevel = "unknown"
+	}
+	fmt.Fprintf(&b, "* Support Level: %s\n", tableCell(supportLevel))
+
+	poolsTitle := "Machine Pools"
+	if info.Hypershift {
+		poolsTitle = "Node Pools"
+	}
+	fmt.Fprintf(&b, "\n#### %s (%d)\n\n", poolsTitle, len(d.MachinePools))
+	if len(d.MachinePools) > 0 {
+		b.WriteString("| ID | Instance Type | Replicas | Zones |\n|---|---|---|---|\n")
+		for _, p := range d.MachinePools {
+			replicas := fmt.Sprintf("%d", p.Replicas)
+			if p.Autoscaling() {
+				replicas = fmt.Sprintf("%d (autoscale %d–%d)", p.Replicas, p.MinReplicas, p.MaxReplicas)
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", tableCell(p.ID), tableCell(p.InstanceType), replicas, tableCell(strings.Join(p.AvailabilityZones, ", ")))
+		}
+	}
+
+	fmt.Fprintf(&b, "\n#### Add-ons (%d)\n\n", len(d.AddOns))
+	if len(d.AddOns) > 0 {
+		b.WriteString("| Add-on | Version | State |\n|---|---|---|\n")
+		for _, a := range d.AddOns {
+			fmt.Fprintf(&b, "| %s | %s | %s |\n", tableCell(a.Name), tableCell(a.Version), tableCell(a.State))
+		}
+	}
+
+	fmt.Fprintf(&b, "\n#### Upgrade Policies (%d)\n\n", len(d.UpgradePolicies))
+	if len(d.UpgradePolicies) > 0 {
+		b.WriteString("| Version | Schedule | Next Run | State |\n|---|---|---|---|\n")
+		for _, u := range d.UpgradePolicies {
+			schedule := tableCell(u.ScheduleType)
+			if u.Schedule != "" {
+				schedule = fmt.Sprintf("%s (`%s`)", schedule, tableCell(strings.ReplaceAll(u.Schedule, "`", "")))
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", tableCell(u.Version), schedule, tableCell(u.NextRun), tableCell(u.State))
+		}
+	}
+
+	// A partial failure still shows what loaded
+	if err != nil {
+		b.WriteString(m.renderEnrichmentError("some cluster details", map[string]error{id: err}))
+	}
+	return b.String()
+}
+
 func (m model) renderEnrichmentError(section string, errs map[string]error) string {
 	var msgs []string
 	for _, err := range errs {
@@ -672,23 +765,33 @@ func (m model) renderLimitedSupportTab() (string, bool, error) {
 		return "\n_No limited support history_\n", false, nil
 	}
 
-	var allReasons []ocm.LimitedSupportReason
-	for _, id := range m.sortedClusterIDs() {
-		allReasons = append(allReasons, m.limitedSupportCache[id]...)
+	entries := m.lsHistoryEntries()
+	selected := m.selectedLSIndex(entries)
+	var reasons, templates []lsHistoryEntry
+	for _, e := range entries {
+		if e.reason != nil {
+			reasons = append(reasons, e)
+		} else {
+			templates = append(templates, e)
+		}
 	}
-
-	if len(allReasons) == 0 {
-		return "\n_No limited support history_\n", false, nil
+	mark := func(i int) string {
+		if i == selected && len(entries) > 1 {
+			return " ◀ selected"
+		}
+		return ""
 	}
 
-	slices.SortFunc(allReasons, func(a, b ocm.LimitedSupportReason) int {
-		return strings.Compare(b.CreatedAt, a.CreatedAt)
-	})
-
-	total := len(allReasons)
 	var content strings.Builder
-	for i, r := range allReasons {
-		fmt.Fprintf(&content, "### Limited Support %d/%d\n\n", i+1, total)
+	if len(reasons) == 0 {
+		content.WriteString("\n_No limited support history_\n")
+	}
+	total := len(reasons)
+	for i, e := range reasons {
+		r := e.reason
+		fmt.Fprintf(&content, "### Limited Support %d/%d%s\n\n", i+1, total, mark(i))
+		fmt.Fprintf(&content, "* Cluster: %s\n", clusterDisplayName(e.clusterID, m.clusterCache[e.clusterID]))
+		fmt.Fprintf(&content, "* Reason ID: %s\n", r.ID)
 		fmt.Fprintf(&content, "* Summary: %s\n", r.Summary)
 		fmt.Fprintf(&content, "* Detection: %s\n", r.DetectionType)
 		fmt.Fprintf(&content, "* Created: %s\n", r.CreatedAt)
@@ -699,6 +802,17 @@ func (m model) renderLimitedSupportTab() (string, bool, error) {
 			content.WriteString("\n---\n")
 		}
 	}
+
+	if len(templates) > 0 {
+		if total > 0 {
+			content.WriteString("\n---\n")
+		}
+		content.WriteString("\n### Add a Reason\n\n")
+		for i, e := range templates {
+			fmt.Fprintf(&content, "* %s%s\n", m.lsEntryLabel(e), mark(total+i))
+		}
+	}
+	content.WriteString("\n_Select a reason or template with `[`/`]` and press `L` to remove or add it_\n")
 	return content.String(), false, nil
 }
 
@@ -950,6 +1064,67 @@ type alertSummary struct {
 	Namespace   string
 	Description string
 	ClusterName string
+	FiringCount int
+	Firing      []firingSummary
+}
+
+// labelPair is one row of a label or annotation table.
+type labelPair struct {
+	Key   string
+	Value string
+}
+
+// firingSummary is one alert of a (possibly grouped) firing text.
+type firingSummary struct {
+	Labels       []labelPair
+	Annotations  []labelPair
+	StartsAt     string
+	GeneratorURL string
+}
+
+// Grouped reports whether the alert is an Alertmanager group of several
+// firing alerts, which the Alerts tab lists one by one.
+func (a alertSummary) Grouped() bool {
+	return len(a.Firing) > 1 || a.FiringCount > 1
+}
+
+// tableCell makes s safe for a markdown table cell.
+func tableCell(s string) string {
+	s = stripControl(s)
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// sortedLabelPairs returns m's entries sorted by key, escaped for a table.
+func sortedLabelPairs(m map[string]string) []labelPair {
+	pairs := make([]labelPair, 0, len(m))
+	for k, v := range m {
+		pairs = append(pairs, labelPair{Key: tableCell(k), Value: tableCell(v)})
+	}
+	slices.SortFunc(pairs, func(a, b labelPair) int { return strings.Compare(a.Key, b.Key) })
+	return pairs
+}
+
+// prometheusURL returns u when it is an http(s) URL, so a malformed or
+// hostile generatorURL never becomes a link.
+func prometheusURL(u string) string {
+	if strings.HasPrefix(u, "https://") || strings.HasPrefix(u, "http://") {
+		return stripControl(u)
+	}
+	return ""
+}
+
+func summarizeFiring(alerts []alert.FiringAlert) []firingSummary {
+	var s []firingSummary
+	for _, f := range alerts {
+		s = append(s, firingSummary{
+			Labels:       sortedLabelPairs(f.Labels),
+			Annotations:  sortedLabelPairs(f.Annotations),
+			StartsAt:     stripControl(f.StartsAt),
+			GeneratorURL: prometheusURL(f.GeneratorURL),
+		})
+	}
+	return s
 }
 
 func summarizeAlerts(a []pagerduty.IncidentAlert, clusterCache map[string]*ocm.ClusterInfo) []alertSummary {
@@ -1005,6 +1180,8 @@ func summarizeAlerts(a []pagerduty.IncidentAlert, clusterCache map[string]*ocm.C
 			AlertType:   stripControl(normalized.AlertType),
 			Namespace:   stripControl(normalized.Namespace),
 			Description: stripControl(normalized.Description),
+			FiringCount: normalized.FiringCount,
+			Firing:      summarizeFiring(normalized.FiringAlerts),
 		})
 
 	}
@@ -1073,6 +1250,14 @@ var funcMap = template.FuncMap{
 	"ToLink": func(s, link string) string {
 		return fmt.Sprintf("[%s](%s)", s, link)
 	},
+	// ToQueryLink is ToLink for URLs carrying a query string (Prometheus
+	// generatorURLs): html/template would escape "&" to "&amp;", which
+	// glamour keeps in the link target. The URL is pre-sanitized by
+	// prometheusURL; characters that would end the markdown link are encoded.
+	"ToQueryLink": func(s, link string) template.HTML {
+		link = strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29", "<", "%3C", ">", "%3E").Replace(link)
+		return template.HTML(fmt.Sprintf("[%s](%s)", template.HTMLEscapeString(s), link))
+	},
 	"ToUpper": strings.ToUpper,
 	"Last": func(i, length int) bool {
 		return i == length-1
@@ -1126,7 +1311,9 @@ const (
 	tabLimitedSupport = 5
 	tabReports        = 6
 	tabPDHistory      = 7
-	tabCount          = 8
+	tabJobs           = 8
+	tabOutput         = 9
+	tabCount          = 10
 )
 
 func tabBorderWithBottom(left, middle, right string) lipgloss.Border {
@@ -1231,6 +1418,26 @@ func (m model) renderTabBar() string {
 		tabLabels[tabPDHistory] = fmt.Sprintf("PD History (%d)", priorCount)
 	}
 
+	var jobs []*managedJobRun
+	if m.selectedIncident != nil {
+		jobs = m.managedJobs[m.selectedIncident.ID]
+	}
+	if slices.ContainsFunc(jobs, func(r *managedJobRun) bool { return !r.job.Finished() && r.pollFailures < jobMaxPollFailures }) {
+		tabLabels[tabJobs] = fmt.Sprintf("Jobs %s", spin)
+	} else {
+		tabLabels[tabJobs] = fmt.Sprintf("Jobs (%d)", len(jobs))
+	}
+
+	var runs []*commandRun
+	if m.selectedIncident != nil {
+		runs = m.commandRuns[m.selectedIncident.ID]
+	}
+	if slices.ContainsFunc(runs, func(r *commandRun) bool { return r.capture.Running() }) {
+		tabLabels[tabOutput] = fmt.Sprintf("Output %s", spin)
+	} else {
+		tabLabels[tabOutput] = fmt.Sprintf("Output (%d)", len(runs))
+	}
+
 	var renderedTabs []string
 	for i, label := range tabLabels {
 		var style lipgloss.Style
@@ -1311,6 +1518,30 @@ const alertTabTemplate = `
 {{ if .Alert.Description }}
 > {{ .Alert.Description }}
 {{ end -}}
+{{ if and .Alert.Grouped (gt .Alert.FiringCount (len .Alert.Firing)) }}
+_Showing {{ len .Alert.Firing }} of {{ .Alert.FiringCount }} firing alerts_
+{{ end -}}
+{{ range $i, $f := .Alert.Firing }}
+{{ if $.Alert.Grouped }}#### Firing {{ add1 $i }}/{{ len $.Alert.Firing }}
+
+{{ end -}}
+{{ if $f.StartsAt }}* Started: {{ $f.StartsAt }}
+{{ end -}}
+{{ if $f.GeneratorURL }}* Prometheus: {{ ToQueryLink "query" $f.GeneratorURL }}
+{{ end -}}
+{{ if $f.Labels }}
+| Label | Value |
+|-------|-------|
+{{ range $f.Labels }}| {{ .Key }} | {{ .Value }} |
+{{ end -}}
+{{ end -}}
+{{ if $f.Annotations }}
+| Annotation | Value |
+|------------|-------|
+{{ range $f.Annotations }}| {{ .Key }} | {{ .Value }} |
+{{ end -}}
+{{ end -}}
+{{ end -}}
 `
 
 // noteTabTemplate renders a single note with navigation header (kept for backward compatibility)
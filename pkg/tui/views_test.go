@@ -1508,3 +1508,53 @@ func TestHelpLine_NoOverflow(t *testing.T) {
 		})
 	}
 }
+
+func firingAlert(firing, numFiring string) pagerduty.IncidentAlert {
+	return pagerduty.IncidentAlert{
+		APIObject: pagerduty.APIObject{ID: "A1"},
+		Service:   pagerduty.APIObject{Summary: "osd-c1-hive-cluster"},
+		Status:    "triggered",
+		Body: map[string]interface{}{"details": map[string]interface{}{
+			"alert_name": "KubePodCrashLooping",
+			"cluster_id": "c1",
+			"num_firing": numFiring,
+			"firing":     firing,
+		}},
+	}
+}
+
+func TestRenderAlertsTab_LabelAndAnnotationTables(t *testing.T) {
+	alerts := []pagerduty.IncidentAlert{firingAlert("Labels:\n - pod = p|0\n - alertname = KubePodCrashLooping\nAnnotations:\n - summary = crash looping\nStartsAt: 2026-05-29 10:00:00 +0000 UTC\nSource: https://prom.example.com/graph?g0.expr=up&g0.tab=1\n", "1")}
+
+	content := renderAlertsTestContent(t, incidentSummary{Alerts: summarizeAlerts(alerts, nil)})
+
+	assert.Contains(t, content, "| Label | Value |")
+	assert.Contains(t, content, "| alertname | KubePodCrashLooping |\n| pod | p\\|0 |", "sorted by key, pipes escaped")
+	assert.Contains(t, content, "| Annotation | Value |")
+	assert.Contains(t, content, "| summary | crash looping |")
+	assert.Contains(t, content, "* Started: 2026-05-29 10:00:00")
+	assert.Contains(t, content, "* Prometheus: [query](https://prom.example.com/graph?g0.expr=up&g0.tab=1)", "query string is not HTML-escaped")
+	assert.NotContains(t, content, "#### Firing", "a single alert is not a group")
+}
+
+func TestRenderAlertsTab_FiringGroup(t *testing.T) {
+	block := func(pod string) string {
+		return "Labels:\n - pod = " + pod + "\nAnnotations:\n - summary = crash\nSource: https://prom.example.com/graph\n"
+	}
+	alerts := []pagerduty.IncidentAlert{firingAlert(block("p0")+block("p1"), "3")}
+
+	content := renderAlertsTestContent(t, incidentSummary{Alerts: summarizeAlerts(alerts, nil)})
+
+	assert.Contains(t, content, "_Showing 2 of 3 firing alerts_")
+	assert.Contains(t, content, "#### Firing 1/2")
+	assert.Contains(t, content, "#### Firing 2/2")
+	assert.Contains(t, content, "| pod | p1 |")
+}
+
+func TestSummarizeFiring_RejectsNonHTTPGeneratorURL(t *testing.T) {
+	alerts := []pagerduty.IncidentAlert{firingAlert("Labels:\n - a = b\nSource: javascript:alert(1)\n", "1")}
+
+	s := summarizeAlerts(alerts, nil)
+	require.Len(t, s[0].Firing, 1)
+	assert.Empty(t, s[0].Firing[0].GeneratorURL)
+}
@@ -25,23 +25,26 @@ const (
 )
 
 type markers struct {
-	flag    string
-	watcher string
-	agent   string
+	flag      string
+	duplicate string
+	watcher   string
+	agent     string
 }
 
 func resolveMarkers(useEmoji bool) markers {
 	if useEmoji {
 		return markers{
-			flag:    emojiFlagMarker,
-			watcher: emojiWatcherMarker,
-			agent:   emojiAgentMarker,
+			flag:      emojiFlagMarker,
+			duplicate: emojiDuplicateMarker,
+			watcher:   emojiWatcherMarker,
+			agent:     emojiAgentMarker,
 		}
 	}
 	return markers{
-		flag:    noEmojiFlagMarker,
-		watcher: noEmojiWatcherMarker,
-		agent:   noEmojiAgentMarker,
+		flag:      noEmojiFlagMarker,
+		duplicate: noEmojiDuplicateMarker,
+		watcher:   noEmojiWatcherMarker,
+		agent:     noEmojiAgentMarker,
 	}
 }
 
@@ -296,7 +299,7 @@ func (m *model) runDetectors(changes []delta.Change) []tea.Cmd {
 			m.watcherQueryStart = time.Now()
 			m.watcherQueryTimeout = watcherSynthesisTimeout
 
-			if m.toolRunnerFactory != nil && m.toolRegistry != nil && isAnthropicFamily(m.aiProvider.Name()) {
+			if m.toolLoop != nil && m.toolRegistry != nil {
 				m.watcherQueryTimeout = m.investigationCfg.timeout
 				contextStr := buildObservationContext(m, obs)
 				changesNarrative := delta.Narrate(changes, time.Now())
@@ -304,7 +307,7 @@ func (m *model) runDetectors(changes []delta.Change) []tea.Cmd {
 					contextStr = changesNarrative + "\n\n" + contextStr
 				}
 				cmds = append(cmds, watcherInvestigateCmd(
-					m.toolRunnerFactory,
+					m.toolLoop,
 					m.toolRegistry,
 					m.investigationCfg,
 					m.watcherSystemPrompt,
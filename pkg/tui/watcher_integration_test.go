@@ -933,7 +933,7 @@ func TestRunDetectors_ModelPlumbing(t *testing.T) {
 	m := createTestModel()
 	m.aiProvider = &bedrockProvider{model: resolvedModel}
 	m.aiHealth = aiHealthOK
-	m.toolRunnerFactory = factory
+	m.toolLoop = anthropicToolLoop{factory: factory}
 	m.toolRegistry = reg
 	m.investigationCfg = investigationConfig{
 		maxToolTurns: 2,